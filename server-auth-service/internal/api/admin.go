@@ -0,0 +1,199 @@
+package api
+
+import (
+	"auth-service/internal/middleware"
+	"auth-service/internal/model/request"
+	"auth-service/internal/model/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+type AdminApi struct {
+}
+
+// MergeUsers 合并两个 SSO 账号（超级管理员）
+// POST /api/admin/users/merge
+func (h *AdminApi) MergeUsers(c *gin.Context) {
+	var req request.MergeUsersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	result, err := adminService.MergeUsers(c,
+		middleware.GetUserUUID(c),
+		uuid.FromStringOrNil(req.SourceUUID),
+		uuid.FromStringOrNil(req.TargetUUID),
+	)
+	if err != nil {
+		response.Error(c, 3001, err.Error())
+		return
+	}
+
+	response.SuccessMsg(c, "账号已合并", result)
+}
+
+// ListAppSecrets 列出应用密钥（仅返回尾号，不含明文）
+// GET /api/admin/apps/:app_key/secrets
+func (h *AdminApi) ListAppSecrets(c *gin.Context) {
+	secrets, err := appSecretService.List(c.Param("app_key"))
+	if err != nil {
+		response.Error(c, 3002, err.Error())
+		return
+	}
+
+	response.Success(c, secrets)
+}
+
+// RotateAppSecret 签发新的应用密钥，旧密钥在宽限期内仍然有效。明文仅在此处返回一次
+// POST /api/admin/apps/:app_key/secrets
+func (h *AdminApi) RotateAppSecret(c *gin.Context) {
+	var req request.RotateAppSecretRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.BadRequest(c, "参数错误: "+err.Error())
+			return
+		}
+	}
+
+	issued, err := appSecretService.Rotate(c, middleware.GetUserUUID(c), c.Param("app_key"), req.GracePeriod)
+	if err != nil {
+		response.Error(c, 3002, err.Error())
+		return
+	}
+
+	response.SuccessMsg(c, "已签发新密钥，请妥善保存", issued)
+}
+
+// ListApplications 获取全部应用
+// GET /api/admin/apps
+func (h *AdminApi) ListApplications(c *gin.Context) {
+	apps, err := applicationService.ListApplications()
+	if err != nil {
+		response.Error(c, 3003, err.Error())
+		return
+	}
+
+	response.Success(c, apps)
+}
+
+// GetApplication 获取应用详情
+// GET /api/admin/apps/:app_key
+func (h *AdminApi) GetApplication(c *gin.Context) {
+	app, err := applicationService.GetApplication(c.Param("app_key"))
+	if err != nil {
+		response.Error(c, 3003, err.Error())
+		return
+	}
+
+	response.Success(c, app)
+}
+
+// CreateApplication 创建应用，返回的密钥明文仅显示这一次
+// POST /api/admin/apps
+func (h *AdminApi) CreateApplication(c *gin.Context) {
+	var req request.CreateApplicationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	result, err := applicationService.CreateApplication(c, middleware.GetUserUUID(c), req)
+	if err != nil {
+		response.Error(c, 3003, err.Error())
+		return
+	}
+
+	response.SuccessMsg(c, "应用已创建，请妥善保存密钥", result)
+}
+
+// UpdateApplication 更新应用配置（只修改传入的字段，应用标识不可修改）
+// PUT /api/admin/apps/:app_key
+func (h *AdminApi) UpdateApplication(c *gin.Context) {
+	var req request.UpdateApplicationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	app, err := applicationService.UpdateApplication(c, middleware.GetUserUUID(c), c.Param("app_key"), req)
+	if err != nil {
+		response.Error(c, 3003, err.Error())
+		return
+	}
+
+	response.SuccessMsg(c, "应用已更新", app)
+}
+
+// EnableApplication 启用应用
+// POST /api/admin/apps/:app_key/enable
+func (h *AdminApi) EnableApplication(c *gin.Context) {
+	err := applicationService.SetApplicationStatus(c, middleware.GetUserUUID(c), c.Param("app_key"), middleware.GetAppID(c), true)
+	if err != nil {
+		response.Error(c, 3003, err.Error())
+		return
+	}
+
+	response.SuccessMsg(c, "应用已启用", nil)
+}
+
+// DisableApplication 禁用应用，应用的在线设备全部下线
+// POST /api/admin/apps/:app_key/disable
+func (h *AdminApi) DisableApplication(c *gin.Context) {
+	err := applicationService.SetApplicationStatus(c, middleware.GetUserUUID(c), c.Param("app_key"), middleware.GetAppID(c), false)
+	if err != nil {
+		response.Error(c, 3003, err.Error())
+		return
+	}
+
+	response.SuccessMsg(c, "应用已禁用", nil)
+}
+
+// DeleteApplication 删除应用
+// DELETE /api/admin/apps/:app_key
+func (h *AdminApi) DeleteApplication(c *gin.Context) {
+	err := applicationService.DeleteApplication(c, middleware.GetUserUUID(c), c.Param("app_key"), middleware.GetAppID(c))
+	if err != nil {
+		response.Error(c, 3003, err.Error())
+		return
+	}
+
+	response.SuccessMsg(c, "应用已删除", nil)
+}
+
+// GetApplicationUsers 分页获取应用的活跃用户
+// GET /api/admin/apps/:app_key/users
+func (h *AdminApi) GetApplicationUsers(c *gin.Context) {
+	var params request.AppMemberQueryParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	result, err := applicationService.GetApplicationUsers(c.Param("app_key"), params)
+	if err != nil {
+		response.Error(c, 3003, err.Error())
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// GetApplicationDevices 分页获取应用的在线设备
+// GET /api/admin/apps/:app_key/devices
+func (h *AdminApi) GetApplicationDevices(c *gin.Context) {
+	var params request.AppMemberQueryParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	result, err := applicationService.GetApplicationDevices(c.Param("app_key"), params)
+	if err != nil {
+		response.Error(c, 3003, err.Error())
+		return
+	}
+
+	response.Success(c, result)
+}
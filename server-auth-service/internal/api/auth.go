@@ -2,12 +2,15 @@ package api
 
 import (
 	"auth-service/internal/middleware"
+	"auth-service/internal/model/appTypes"
 	"auth-service/internal/model/request"
 	"auth-service/internal/model/response"
 	"auth-service/internal/service"
 	customerrors "auth-service/pkg/errors"
 	"auth-service/pkg/global"
 	"auth-service/pkg/utils"
+	"crypto/subtle"
+	"errors"
 	"fmt"
 	"net/url"
 	"strings"
@@ -64,71 +67,126 @@ func (h *AuthApi) Login(c *gin.Context) {
 	// 从state中提取其他参数
 	req.DeviceID = stateData.DeviceID
 	req.RedirectURI = stateData.RedirectURI
+	req.ReturnURL = stateData.ReturnURL
 
-	// 判断登录方式：密码登录或验证码登录
-	if req.Password != "" {
-		// 密码登录：需要图片验证码
+	if req.Password == "" && req.VerificationCode == "" {
+		response.BadRequest(c, "请提供密码或邮箱验证码")
+		return
+	}
+
+	// 防暴力破解：账号、IP 或网段被锁定、处于等待期时直接拒绝
+	captchaRequired, err := authService.CheckLoginAttempt(req.Email, c.ClientIP())
+	if err != nil {
+		respondLoginGuardError(c, err)
+		return
+	}
+
+	// 密码登录始终需要图片验证码；验证码登录在失败次数达到阈值后也需要
+	if req.Password != "" || captchaRequired {
 		if req.CaptchaID == "" || req.Captcha == "" {
-			response.BadRequest(c, "密码登录需要图片验证码")
+			if req.Password != "" {
+				response.BadRequest(c, "密码登录需要图片验证码")
+				return
+			}
+			c.JSON(200, gin.H{
+				"code":             1021,
+				"message":          service.ErrCaptchaRequired.Error(),
+				"captcha_required": true,
+			})
 			return
 		}
-		// 验证图片验证码
-		if !captchaStore.Verify(req.CaptchaID, req.Captcha, true) {
+		if !ApiGroupApp.CaptchaApi.VerifyCaptcha(req.CaptchaID, req.Captcha) {
 			response.Error(c, 1000, "验证码错误")
 			return
 		}
-	} else if req.VerificationCode != "" {
-		// 验证码登录：不需要图片验证码
-		// 验证码验证在service层进行
-	} else {
-		response.BadRequest(c, "请提供密码或邮箱验证码")
-		return
 	}
 
 	// 登录验证
-	resp, err := authService.Login(c, req)
+	resp, challenge, err := authService.Login(c, req)
 	if err != nil {
-		response.Error(c, 1002, err.Error())
+		respondLoginGuardError(c, err)
 		return
 	}
 
-	// ✅ SSO: 设置全局 Session Cookie（跨应用单点登录）
-	session := sessions.Default(c)
-	session.Set("user_uuid", resp.UserInfo.UUID)
-	session.Set("sso_device_id", req.DeviceID)           // 存储 SSO 设备 ID
-	session.Set("user_agent", c.GetHeader("User-Agent")) // 安全检测
-	session.Set("device_name", req.DeviceName)
-	session.Set("device_type", req.DeviceType)
-	session.Set("ip_address", c.ClientIP()) // 安全检测
-	session.Set("logged_in", true)
-	session.Set("logged_in_at", time.Now().Unix())
-	if err := session.Save(); err != nil {
-		global.Log.Error("保存 Session 失败", zap.Error(err))
+	// 需要两步验证：返回挑战，由 /auth/mfa/verify 完成登录
+	if challenge != nil {
+		response.Success(c, challenge)
+		return
+	}
+
+	data, err := finishLogin(c, resp, req, false)
+	if err != nil {
+		response.Error(c, 1003, err.Error())
+		return
+	}
+	response.Success(c, data)
+}
+
+// respondLoginGuardError 返回登录失败：锁定和等待期附带剩余秒数，供前端倒计时
+func respondLoginGuardError(c *gin.Context, err error) {
+	var lockedErr *customerrors.LockedError
+	var cooldownErr *customerrors.CooldownError
+	switch {
+	case errors.As(err, &lockedErr):
+		c.JSON(200, gin.H{
+			"code":              1022,
+			"message":           lockedErr.Error(),
+			"remaining_seconds": lockedErr.RemainingSeconds,
+		})
+	case errors.As(err, &cooldownErr):
+		c.JSON(200, gin.H{
+			"code":              1013,
+			"message":           cooldownErr.Error(),
+			"remaining_seconds": cooldownErr.RemainingSeconds,
+		})
+	default:
+		response.Error(c, 1002, err.Error())
 	}
+}
+
+// finishLogin 设置 SSO Session，并按应用返回 Token（管理后台）或授权码
+func finishLogin(c *gin.Context, resp *response.TokenResponse, req request.LoginRequest, mfaVerified bool) (gin.H, error) {
+	setLoginSession(c, resp, req, mfaVerified)
 
 	// 检查是否是管理后台登录
 	if req.AppID == "manage" {
 		// 管理后台登录：直接返回Token，不走OAuth流程
-		response.Success(c, gin.H{
+		return gin.H{
 			"access_token":  resp.AccessToken,
 			"refresh_token": resp.RefreshToken,
 			"token_type":    "Bearer",
 			"expires_in":    7200, // 2小时
 			"redirect_uri":  "http://localhost:3001/manage",
-		})
-	} else {
-		// ✅ OAuth 2.0: 生成授权码（使用UUID）
-		code, err := service.GenerateAuthorizationCodeByUUID(resp.UserInfo.UUID, req.AppID, req.RedirectURI, resp.AccessToken, resp.RefreshToken)
-		if err != nil {
-			response.Error(c, 1003, "生成授权码失败")
-			return
-		}
+		}, nil
+	}
 
-		response.Success(c, gin.H{
-			"code":         code,
-			"redirect_uri": stateData.RedirectURI,
-			"return_url":   stateData.ReturnURL,
-		})
+	// ✅ OAuth 2.0: 生成授权码（使用UUID）
+	code, err := service.GenerateAuthorizationCodeByUUID(resp.UserInfo.UUID, req.AppID, req.RedirectURI, resp.AccessToken, resp.RefreshToken)
+	if err != nil {
+		return nil, errors.New("生成授权码失败")
+	}
+
+	return gin.H{
+		"code":         code,
+		"redirect_uri": req.RedirectURI,
+		"return_url":   req.ReturnURL,
+	}, nil
+}
+
+// setLoginSession 设置全局 SSO Session（跨应用单点登录）
+func setLoginSession(c *gin.Context, resp *response.TokenResponse, req request.LoginRequest, mfaVerified bool) {
+	session := sessions.Default(c)
+	session.Set("user_uuid", resp.UserInfo.UUID)
+	session.Set("sso_device_id", req.DeviceID)           // 存储 SSO 设备 ID
+	session.Set("user_agent", c.GetHeader("User-Agent")) // 安全检测
+	session.Set("device_name", req.DeviceName)
+	session.Set("device_type", req.DeviceType)
+	session.Set("ip_address", c.ClientIP()) // 安全检测
+	session.Set("logged_in", true)
+	session.Set("logged_in_at", time.Now().Unix())
+	session.Set("mfa_verified", mfaVerified) // 静默登录强制两步验证的应用时检查
+	if err := session.Save(); err != nil {
+		global.Log.Error("保存 Session 失败", zap.Error(err))
 	}
 }
 
@@ -142,7 +200,7 @@ func (h *AuthApi) RefreshToken(c *gin.Context) {
 
 	// 从数据库验证 client_id 和 client_secret
 	app, err := authService.GetAppByKey(req.ClientID)
-	if err != nil || app.AppSecret != req.ClientSecret {
+	if err != nil || !appSecretService.Verify(app, req.ClientSecret) {
 		response.Unauthorized(c, "client_id或client_secret错误")
 		return
 	}
@@ -171,7 +229,7 @@ func (h *AuthApi) RefreshToken(c *gin.Context) {
 			ClientID:     req.ClientID,
 			ClientSecret: req.ClientSecret,
 		}
-		resp, err := authService.RefreshToken(refreshReq)
+		resp, err := authService.RefreshToken(c, refreshReq)
 		if err != nil {
 			response.Error(c, 1003, err.Error())
 			return
@@ -287,107 +345,136 @@ func (h *AuthApi) GetUserByUUID(c *gin.Context) {
 	response.Success(c, userInfo)
 }
 
-// QQLogin QQ登录
-func (h *AuthApi) QQLogin(c *gin.Context) {
-	var req request.QQLoginRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "参数错误: "+err.Error())
+// OAuthLogin 获取第三方登录授权地址
+// GET /api/auth/oauth/:provider/login?app_id=xxx&state=xxx[&oidc_request=xxx]
+func (h *AuthApi) OAuthLogin(c *gin.Context) {
+	provider, err := service.GetOAuthProvider(c.Param("provider"))
+	if err != nil {
+		response.Error(c, 1012, err.Error())
 		return
 	}
 
-	// 获取客户端信息
-	ipAddress := c.ClientIP()
-	userAgent := c.GetHeader("User-Agent")
+	appID := c.Query("app_id")
+	if strings.TrimSpace(appID) == "" {
+		response.BadRequest(c, "缺少app_id")
+		return
+	}
+	state := c.Query("state")
+	if state == "" {
+		response.BadRequest(c, "缺少state参数")
+		return
+	}
 
-	// 调用QQ登录服务
-	resp, err := authService.QQLogin(c, req, ipAddress, userAgent)
+	// 验证state参数（在跳转前消费nonce，回调时使用暂存的参数）
+	stateData, err := utils.ValidateState(state)
 	if err != nil {
-		response.Error(c, 1009, err.Error())
+		response.BadRequest(c, "state验证失败: "+err.Error())
 		return
 	}
-
-	// ✅ SSO: 设置全局 Session Cookie（跨应用单点登录）
-	session := sessions.Default(c)
-	session.Set("user_uuid", resp.UserInfo.UUID)
-	session.Set("sso_device_id", req.DeviceID)           // 存储 SSO 设备 ID
-	session.Set("user_agent", c.GetHeader("User-Agent")) // 安全检测
-	session.Set("device_name", req.DeviceName)
-	session.Set("device_type", req.DeviceType)
-	session.Set("ip_address", c.ClientIP()) // 安全检测
-	session.Set("logged_in", true)
-	session.Set("logged_in_at", time.Now().Unix())
-	if err := session.Save(); err != nil {
-		global.Log.Error("保存 Session 失败", zap.Error(err))
+	if stateData.AppID != appID {
+		response.BadRequest(c, "app_id不匹配")
+		return
 	}
 
-	// ✅ OAuth 2.0: 生成授权码（使用UUID）
-	code, err := service.GenerateAuthorizationCodeByUUID(resp.UserInfo.UUID, req.AppID, req.RedirectURI, resp.AccessToken, resp.RefreshToken)
+	authURL, err := oauthService.BeginLogin(provider, stateData, c.Query("oidc_request"))
 	if err != nil {
-		response.Error(c, 1003, "生成授权码失败")
+		response.Error(c, 1012, err.Error())
 		return
 	}
-
-	response.Success(c, gin.H{
-		"code":         code,
-		"redirect_uri": req.RedirectURI,
-	})
+	response.Success(c, gin.H{"url": authURL})
 }
 
-// QQCallback QQ授权回调（GET方式，QQ服务端回调）
-func (h *AuthApi) QQCallback(c *gin.Context) {
-	code := c.Query("code")
-	appID := c.Query("app_id")
-	state := c.Query("state")
-	if code == "" {
-		response.BadRequest(c, "缺少code")
-		return
+// checkLinkSession 绑定回调必须来自发起绑定的浏览器：Session 用户与绑定目标一致且随机数匹配（一次性）
+func checkLinkSession(c *gin.Context, stateData *appTypes.OAuthState) error {
+	session := sessions.Default(c)
+	nonce, _ := session.Get(oauthLinkNonceKey).(string)
+	session.Delete(oauthLinkNonceKey)
+	session.Save()
+
+	userUUID, _, loggedIn := ssoSessionUser(session)
+	if !loggedIn || userUUID != stateData.UserUUID {
+		return errors.New("绑定请求与当前登录账号不一致")
 	}
-	if strings.TrimSpace(appID) == "" {
-		response.BadRequest(c, "缺少app_id")
+	if nonce == "" || stateData.LinkNonce == "" || subtle.ConstantTimeCompare([]byte(nonce), []byte(stateData.LinkNonce)) != 1 {
+		return errors.New("绑定请求已失效，请重新发起")
+	}
+	return nil
+}
+
+// OAuthCallback 第三方授权回调（GET方式，第三方服务端回调）
+// GET /api/auth/oauth/:provider/callback?code=xxx&state=xxx
+func (h *AuthApi) OAuthCallback(c *gin.Context) {
+	provider, err := service.GetOAuthProvider(c.Param("provider"))
+	if err != nil {
+		response.Error(c, 1012, err.Error())
 		return
 	}
-	if state == "" {
-		response.BadRequest(c, "缺少state参数")
+
+	code := c.Query("code")
+	if code == "" {
+		response.BadRequest(c, "缺少code，可能已取消授权")
 		return
 	}
 
-	// 验证state参数
-	stateData, err := utils.ValidateState(state)
+	stateData, err := oauthService.ConsumeState(provider, c.Query("state"))
 	if err != nil {
 		response.BadRequest(c, "state验证失败: "+err.Error())
 		return
 	}
 
-	// 验证app_id是否匹配
-	if stateData.AppID != appID {
-		response.BadRequest(c, "app_id不匹配")
+	// 已登录用户绑定第三方账号：完成后回到账号安全页
+	if stateData.Action == appTypes.OAuthActionLink {
+		resultURL := "/manage/security?oauth_link=" + provider.Name()
+		if err := checkLinkSession(c, stateData); err != nil {
+			resultURL += "&error=" + url.QueryEscape(err.Error())
+		} else if err := oauthService.Link(c, provider, code, uuid.FromStringOrNil(stateData.UserUUID)); err != nil {
+			resultURL += "&error=" + url.QueryEscape(err.Error())
+		}
+		c.Redirect(302, resultURL)
 		return
 	}
 
-	// 客户端信息
-	ipAddress := c.ClientIP()
-	userAgent := c.GetHeader("User-Agent")
-
-	// 从 User-Agent 解析设备名称
-	deviceName := parseDeviceNameFromUserAgent(userAgent)
-
-	// 组装请求
-	req := request.QQLoginRequest{
-		Code:        code,
+	// 组装登录请求，设备名称从 User-Agent 解析
+	req := request.LoginRequest{
 		AppID:       stateData.AppID,
 		RedirectURI: stateData.RedirectURI,
+		ReturnURL:   stateData.ReturnURL,
 		DeviceID:    stateData.DeviceID,
+		DeviceName:  parseDeviceNameFromUserAgent(c.GetHeader("User-Agent")),
 		DeviceType:  "web",
-		DeviceName:  deviceName,
+	}
+	if req.DeviceID == "" {
+		req.DeviceID = uuid.Must(uuid.NewV4()).String()
 	}
 
-	// 调用服务登录
-	resp, err := authService.QQLogin(c, req, ipAddress, userAgent)
+	resp, challenge, err := oauthService.Login(c, provider, code, req)
 	if err != nil {
 		response.Error(c, 1009, err.Error())
 		return
 	}
 
+	// 需要两步验证：回到登录页，由 /auth/mfa/verify 完成登录
+	if challenge != nil {
+		loginURL := fmt.Sprintf("/login?app_id=%s&redirect_uri=%s&mfa_token=%s",
+			url.QueryEscape(req.AppID),
+			url.QueryEscape(req.RedirectURI),
+			url.QueryEscape(challenge.MFAToken),
+		)
+		if stateData.OIDCRequest != "" {
+			loginURL += "&oidc_request=" + url.QueryEscape(stateData.OIDCRequest)
+		}
+		c.Redirect(302, loginURL)
+		return
+	}
+
+	setLoginSession(c, resp, req, false)
+
+	// OIDC 授权请求发起的登录：回到授权端点继续授权
+	if stateData.OIDCRequest != "" {
+		c.Redirect(302, "/api/oauth/authorize?request_id="+url.QueryEscape(stateData.OIDCRequest))
+		return
+	}
+
 	// 生成授权码并重定向到 redirect_uri?code=...
 	authCode, err := service.GenerateAuthorizationCodeByUUID(resp.UserInfo.UUID, req.AppID, req.RedirectURI, resp.AccessToken, resp.RefreshToken)
 	if err != nil {
@@ -395,9 +482,9 @@ func (h *AuthApi) QQCallback(c *gin.Context) {
 		return
 	}
 	// 重定向到回调地址，携带code和return_url
-	redirectURL := fmt.Sprintf("%s?code=%s", stateData.RedirectURI, authCode)
-	if stateData.ReturnURL != "" {
-		redirectURL = redirectURL + "&return_url=" + url.QueryEscape(stateData.ReturnURL)
+	redirectURL := fmt.Sprintf("%s?code=%s", req.RedirectURI, authCode)
+	if req.ReturnURL != "" {
+		redirectURL = redirectURL + "&return_url=" + url.QueryEscape(req.ReturnURL)
 	}
 	c.Redirect(302, redirectURL)
 }
@@ -452,35 +539,6 @@ func (h *AuthApi) ForgotPassword(c *gin.Context) {
 	response.SuccessMsg(c, "密码重置成功", nil)
 }
 
-// QQLoginURL 获取QQ登录URL
-func (h *AuthApi) QQLoginURL(c *gin.Context) {
-	if !global.Config.QQ.Enable {
-		response.Error(c, 1012, "QQ登录未启用")
-		return
-	}
-	// 允许前端传入 app_id，拼接到 redirect_uri，确保 QQ 回调时可携带 app_id
-	appID := c.Query("app_id")
-	if strings.TrimSpace(appID) == "" {
-		response.BadRequest(c, "缺少app_id")
-		return
-	}
-	// 支持state参数传递（用于传递设备ID等信息）
-	state := c.Query("state")
-
-	redirectURI := global.Config.QQ.RedirectURI
-	redirectURI = redirectURI + "?app_id=" + url.QueryEscape(appID)
-
-	authURL := "https://graph.qq.com/oauth2.0/authorize?" +
-		"response_type=code&" +
-		"client_id=" + global.Config.QQ.AppID + "&" +
-		"redirect_uri=" + url.QueryEscape(redirectURI)
-	// state参数只需要在授权URL中传递一次，QQ会原样返回
-	if state != "" {
-		authURL = authURL + "&state=" + state
-	}
-	response.Success(c, gin.H{"url": authURL})
-}
-
 // parseDeviceNameFromUserAgent 从 User-Agent 解析设备名称
 func parseDeviceNameFromUserAgent(userAgent string) string {
 	if userAgent == "" {
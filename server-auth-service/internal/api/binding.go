@@ -0,0 +1,99 @@
+package api
+
+import (
+	"auth-service/internal/middleware"
+	"auth-service/internal/model/request"
+	"auth-service/internal/model/response"
+	"auth-service/internal/service"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+type BindingApi struct {
+}
+
+// oauthLinkNonceKey Session 中保存绑定流程随机数的键
+const oauthLinkNonceKey = "oauth_link_nonce"
+
+// ListBindings 获取第三方账号绑定情况
+func (h *BindingApi) ListBindings(c *gin.Context) {
+	userUUID := middleware.GetUserUUID(c)
+	if userUUID == uuid.Nil {
+		response.Error(c, 1001, "用户未登录")
+		return
+	}
+
+	list, err := oauthService.ListBindings(userUUID)
+	if err != nil {
+		response.Error(c, 2007, "获取绑定信息失败")
+		return
+	}
+
+	response.Success(c, list)
+}
+
+// Link 获取绑定第三方账号的授权地址，授权完成后回调到 /auth/oauth/:provider/callback
+func (h *BindingApi) Link(c *gin.Context) {
+	userUUID := middleware.GetUserUUID(c)
+	if userUUID == uuid.Nil {
+		response.Error(c, 1001, "用户未登录")
+		return
+	}
+
+	var req request.OAuthProviderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	provider, err := service.GetOAuthProvider(req.Provider)
+	if err != nil {
+		response.Error(c, 1012, err.Error())
+		return
+	}
+
+	// 绑定必须在同一个 SSO Session 中完成，回调时校验
+	session := sessions.Default(c)
+	sessionUUID, _, loggedIn := ssoSessionUser(session)
+	if !loggedIn || sessionUUID != userUUID.String() {
+		response.Unauthorized(c, "登录状态已失效，请重新登录后再绑定")
+		return
+	}
+
+	authURL, nonce, err := oauthService.BeginLink(provider, userUUID)
+	if err != nil {
+		response.Error(c, 2007, err.Error())
+		return
+	}
+	session.Set(oauthLinkNonceKey, nonce)
+	if err := session.Save(); err != nil {
+		response.Error(c, 2007, "保存绑定状态失败")
+		return
+	}
+
+	response.Success(c, gin.H{"url": authURL})
+}
+
+// Unlink 解绑第三方账号
+func (h *BindingApi) Unlink(c *gin.Context) {
+	userUUID := middleware.GetUserUUID(c)
+	if userUUID == uuid.Nil {
+		response.Error(c, 1001, "用户未登录")
+		return
+	}
+
+	var req request.OAuthProviderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	if err := oauthService.Unlink(c, userUUID, req.Provider); err != nil {
+		response.Error(c, 2007, err.Error())
+		return
+	}
+
+	response.SuccessMsg(c, "解绑成功", nil)
+}
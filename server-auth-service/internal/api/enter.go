@@ -3,15 +3,27 @@ package api
 import "auth-service/internal/service"
 
 type ApiGroup struct {
+	AdminApi
 	AuthApi
+	BindingApi
 	CaptchaApi
 	DeviceApi
 	ManageApi
+	MFAApi
 	OAuthApi
+	OIDCApi
+	WebAuthnApi
 }
 
 var ApiGroupApp = new(ApiGroup)
 
+var adminService = service.ServiceGroupApp.AdminService
+var appSecretService = service.ServiceGroupApp.AppSecretService
+var applicationService = service.ServiceGroupApp.ApplicationService
 var authService = service.ServiceGroupApp.AuthService
 var deviceService = service.ServiceGroupApp.DeviceService
 var manageService = service.ServiceGroupApp.ManageService
+var mfaService = service.ServiceGroupApp.MFAService
+var oauthService = service.ServiceGroupApp.OAuthService
+var oidcService = service.ServiceGroupApp.OIDCService
+var webauthnService = service.ServiceGroupApp.WebAuthnService
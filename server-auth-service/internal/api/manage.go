@@ -34,7 +34,7 @@ func (h *ManageApi) GetDevices(c *gin.Context) {
 	}
 	token = token[7:]
 
-	claims, err := jwt.ParseAccessToken(token, global.RSAPublicKey)
+	claims, err := jwt.ParseAccessToken(token, global.SigningKeys)
 	if err != nil {
 		response.Error(c, 1001, "Token解析失败")
 		return
@@ -75,7 +75,7 @@ func (h *ManageApi) KickDevice(c *gin.Context) {
 	// 检查是否尝试踢出自己
 	token := c.GetHeader("Authorization")
 	if len(token) > 7 && token[:7] == "Bearer " {
-		claims, err := jwt.ParseAccessToken(token[7:], global.RSAPublicKey)
+		claims, err := jwt.ParseAccessToken(token[7:], global.SigningKeys)
 		if err == nil && claims.DeviceID == req.DeviceID {
 			response.Error(c, 2002, "不能踢出当前设备")
 			return
@@ -113,7 +113,7 @@ func (h *ManageApi) Logout(c *gin.Context) {
 		return
 	}
 
-	claims, err := jwt.ParseAccessToken(token[7:], global.RSAPublicKey)
+	claims, err := jwt.ParseAccessToken(token[7:], global.SigningKeys)
 	if err != nil {
 		response.Error(c, 1001, "Token解析失败")
 		return
@@ -159,7 +159,7 @@ func (h *ManageApi) SSOLogout(c *gin.Context) {
 		return
 	}
 
-	claims, err := jwt.ParseAccessToken(token[7:], global.RSAPublicKey)
+	claims, err := jwt.ParseAccessToken(token[7:], global.SigningKeys)
 	if err != nil {
 		response.Error(c, 1001, "Token解析失败")
 		return
@@ -0,0 +1,170 @@
+package api
+
+import (
+	"auth-service/internal/middleware"
+	"auth-service/internal/model/request"
+	"auth-service/internal/model/response"
+	customerrors "auth-service/pkg/errors"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+type MFAApi struct {
+}
+
+// VerifyLogin 两步验证登录（第二步）：校验动态码或恢复码后完成登录
+func (h *MFAApi) VerifyLogin(c *gin.Context) {
+	var req request.MFAVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+	if req.Code == "" && req.RecoveryCode == "" {
+		response.BadRequest(c, "请输入动态码或恢复码")
+		return
+	}
+
+	resp, loginReq, recoveryCodes, err := authService.CompleteMFALogin(c, req)
+	if err != nil {
+		var lockedErr *customerrors.LockedError
+		var cooldownErr *customerrors.CooldownError
+		if errors.As(err, &lockedErr) || errors.As(err, &cooldownErr) {
+			respondLoginGuardError(c, err)
+			return
+		}
+		response.Error(c, 1017, err.Error())
+		return
+	}
+
+	data, err := finishLogin(c, resp, *loginReq, true)
+	if err != nil {
+		response.Error(c, 1003, err.Error())
+		return
+	}
+	// 登录时完成绑定的，恢复码只在此处返回一次
+	if len(recoveryCodes) > 0 {
+		data["recovery_codes"] = recoveryCodes
+	}
+	response.Success(c, data)
+}
+
+// SetupLogin 登录时绑定身份验证器（应用强制两步验证且用户尚未绑定）
+func (h *MFAApi) SetupLogin(c *gin.Context) {
+	var req request.MFASetupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	info, err := mfaService.SetupChallenge(req.MFAToken)
+	if err != nil {
+		response.Error(c, 1017, err.Error())
+		return
+	}
+
+	response.Success(c, info)
+}
+
+// GetStatus 获取两步验证状态
+func (h *MFAApi) GetStatus(c *gin.Context) {
+	userUUID := middleware.GetUserUUID(c)
+	if userUUID == uuid.Nil {
+		response.Error(c, 1001, "用户未登录")
+		return
+	}
+
+	status, err := mfaService.GetStatus(userUUID)
+	if err != nil {
+		response.Error(c, 2005, "获取两步验证状态失败")
+		return
+	}
+
+	response.Success(c, status)
+}
+
+// BeginSetup 生成绑定二维码信息
+func (h *MFAApi) BeginSetup(c *gin.Context) {
+	userUUID := middleware.GetUserUUID(c)
+	if userUUID == uuid.Nil {
+		response.Error(c, 1001, "用户未登录")
+		return
+	}
+
+	info, err := mfaService.BeginSetup(userUUID)
+	if err != nil {
+		response.Error(c, 2005, err.Error())
+		return
+	}
+
+	response.Success(c, info)
+}
+
+// Enable 确认动态码并启用两步验证
+func (h *MFAApi) Enable(c *gin.Context) {
+	userUUID := middleware.GetUserUUID(c)
+	if userUUID == uuid.Nil {
+		response.Error(c, 1001, "用户未登录")
+		return
+	}
+
+	var req request.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Code == "" {
+		response.BadRequest(c, "请输入动态码")
+		return
+	}
+
+	codes, err := mfaService.Enable(c, userUUID, req.Code)
+	if err != nil {
+		response.Error(c, 2005, err.Error())
+		return
+	}
+
+	response.SuccessMsg(c, "两步验证已开启", gin.H{"recovery_codes": codes})
+}
+
+// Disable 停用两步验证
+func (h *MFAApi) Disable(c *gin.Context) {
+	userUUID := middleware.GetUserUUID(c)
+	if userUUID == uuid.Nil {
+		response.Error(c, 1001, "用户未登录")
+		return
+	}
+
+	var req request.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	if err := mfaService.Disable(c, userUUID, req); err != nil {
+		response.Error(c, 2005, err.Error())
+		return
+	}
+
+	response.SuccessMsg(c, "两步验证已停用", nil)
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码
+func (h *MFAApi) RegenerateRecoveryCodes(c *gin.Context) {
+	userUUID := middleware.GetUserUUID(c)
+	if userUUID == uuid.Nil {
+		response.Error(c, 1001, "用户未登录")
+		return
+	}
+
+	var req request.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Code == "" {
+		response.BadRequest(c, "请输入动态码")
+		return
+	}
+
+	codes, err := mfaService.RegenerateRecoveryCodes(c, userUUID, req.Code)
+	if err != nil {
+		response.Error(c, 2005, err.Error())
+		return
+	}
+
+	response.Success(c, gin.H{"recovery_codes": codes})
+}
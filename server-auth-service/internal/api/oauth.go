@@ -34,6 +34,12 @@ func (h *OAuthApi) GetPublicApplications(c *gin.Context) {
 // Authorize OAuth 2.0 授权端点（检查 Session，实现静默登录）
 // GET /api/oauth/authorize?app_id=blog&redirect_uri=xxx&state=xxx
 func (h *OAuthApi) Authorize(c *gin.Context) {
+	// 标准 OIDC 授权请求（或登录后继续授权）
+	if c.Query("response_type") != "" || c.Query("request_id") != "" {
+		ApiGroupApp.OIDCApi.Authorize(c)
+		return
+	}
+
 	appID := c.Query("app_id")
 	redirectURI := c.Query("redirect_uri")
 	state := c.Query("state")
@@ -123,6 +129,20 @@ func (h *OAuthApi) Authorize(c *gin.Context) {
 			return
 		}
 
+		// 应用强制两步验证，而当前 Session 未经过两步验证：需重新登录
+		if app.RequireMFA == 1 && session.Get("mfa_verified") != true {
+			global.Log.Info("应用要求两步验证，静默登录转为重新登录",
+				zap.String("user_uuid", userUUIDStr),
+				zap.String("app_id", appID),
+			)
+			loginURL := fmt.Sprintf("/login?app_id=%s&redirect_uri=%s", appID, url.QueryEscape(redirectURI))
+			if state != "" {
+				loginURL += "&state=" + url.QueryEscape(state)
+			}
+			c.Redirect(302, loginURL)
+			return
+		}
+
 		// 检查设备过期状态（滑动过期）
 		// 必须传入 user_uuid 和 app_id，避免查询到其他用户或应用的同名设备
 		err = authService.CheckDeviceExpiry(userUUIDParsed, app.ID, ssoDeviceIDStr)
@@ -0,0 +1,326 @@
+package api
+
+import (
+	"auth-service/internal/middleware"
+	"auth-service/internal/model/appTypes"
+	"auth-service/internal/model/request"
+	"auth-service/internal/model/response"
+	"auth-service/internal/service"
+	"auth-service/pkg/global"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+)
+
+type OIDCApi struct {
+}
+
+// Discovery OIDC 发现文档
+// GET /.well-known/openid-configuration
+func (h *OIDCApi) Discovery(c *gin.Context) {
+	c.JSON(http.StatusOK, oidcService.Discovery())
+}
+
+// JWKS 签名公钥集合
+// GET /.well-known/jwks.json
+func (h *OIDCApi) JWKS(c *gin.Context) {
+	c.JSON(http.StatusOK, oidcService.JWKS())
+}
+
+// Authorize OIDC 授权端点（由 /api/oauth/authorize 在携带 response_type 或 request_id 时转入）
+// GET /api/oauth/authorize?response_type=code&client_id=xxx&redirect_uri=xxx&scope=openid&state=xxx&nonce=xxx&code_challenge=xxx
+// GET /api/oauth/authorize?request_id=xxx（登录后继续授权）
+func (h *OIDCApi) Authorize(c *gin.Context) {
+	var authReq *appTypes.OIDCAuthRequest
+	var err error
+	if requestID := c.Query("request_id"); requestID != "" {
+		authReq, err = oidcService.GetAuthRequest(requestID)
+		if err != nil {
+			response.BadRequest(c, err.Error())
+			return
+		}
+	} else {
+		var req request.OIDCAuthorizeRequest
+		if err := c.ShouldBindQuery(&req); err != nil {
+			response.BadRequest(c, "参数错误: "+err.Error())
+			return
+		}
+		authReq, err = oidcService.NewAuthRequest(req)
+		if err != nil {
+			var oidcErr *service.OIDCError
+			if errors.As(err, &oidcErr) {
+				redirectAuthorizeError(c, req.RedirectURI, req.State, oidcErr.Code, oidcErr.Description)
+				return
+			}
+			response.BadRequest(c, "授权请求无效: "+err.Error())
+			return
+		}
+	}
+
+	app, err := authService.GetAppByKey(authReq.ClientID)
+	if err != nil {
+		redirectAuthorizeError(c, authReq.RedirectURI, authReq.State, "access_denied", err.Error())
+		return
+	}
+
+	session := sessions.Default(c)
+	userUUID, deviceID, loggedIn := ssoSessionUser(session)
+
+	// 应用强制两步验证而当前 Session 未经过两步验证，视为未登录
+	if loggedIn && app.RequireMFA == 1 && session.Get("mfa_verified") != true {
+		loggedIn = false
+	}
+	// 设备已过期则清除 Session（首次访问该应用的设备会在签发授权码时登记）
+	if loggedIn {
+		err := authService.CheckDeviceExpiry(uuid.FromStringOrNil(userUUID), app.ID, deviceID)
+		if err != nil && !errors.Is(err, service.ErrDeviceNotFound) {
+			session.Clear()
+			session.Save()
+			loggedIn = false
+		}
+	}
+
+	// prompt=login 要求在本次授权请求发起之后重新登录，登录完成后凭 request_id 回到授权端点
+	if loggedIn && service.HasPrompt(authReq.Prompt, "login") {
+		loggedInAt, _ := session.Get("logged_in_at").(int64)
+		loggedIn = loggedInAt > authReq.CreatedAt.Unix()
+	}
+
+	if !loggedIn {
+		if service.HasPrompt(authReq.Prompt, "none") {
+			redirectAuthorizeError(c, authReq.RedirectURI, authReq.State, "login_required", "用户未登录")
+			return
+		}
+
+		loginURL := fmt.Sprintf("/login?app_id=%s&redirect_uri=%s&oidc_request=%s",
+			url.QueryEscape(authReq.ClientID),
+			url.QueryEscape(authReq.RedirectURI),
+			authReq.ID,
+		)
+		c.Redirect(http.StatusFound, loginURL)
+		return
+	}
+
+	if service.HasPrompt(authReq.Prompt, "consent") || oidcService.RequiresConsent(app, userUUID, authReq.Scopes) {
+		if service.HasPrompt(authReq.Prompt, "none") {
+			redirectAuthorizeError(c, authReq.RedirectURI, authReq.State, "consent_required", "需要用户同意授权")
+			return
+		}
+
+		// 同意页只允许当前用户处理该请求
+		authReq.UserUUID = userUUID
+		if err := oidcService.SaveAuthRequest(authReq); err != nil {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		c.Redirect(http.StatusFound, "/consent?request_id="+authReq.ID)
+		return
+	}
+
+	redirectURL, err := issueAuthorizationCode(c, session, authReq, userUUID, deviceID)
+	if err != nil {
+		var oidcErr *service.OIDCError
+		if errors.As(err, &oidcErr) {
+			redirectAuthorizeError(c, authReq.RedirectURI, authReq.State, oidcErr.Code, oidcErr.Description)
+			return
+		}
+		response.Error(c, 1016, "生成授权码失败")
+		return
+	}
+	c.Redirect(http.StatusFound, redirectURL)
+}
+
+// GetConsent 获取授权同意页信息
+// GET /api/oauth/consent?request_id=xxx
+func (h *OIDCApi) GetConsent(c *gin.Context) {
+	authReq, ok := consentRequest(c, c.Query("request_id"))
+	if !ok {
+		return
+	}
+
+	info, err := oidcService.ConsentInfo(authReq)
+	if err != nil {
+		response.Error(c, 1019, err.Error())
+		return
+	}
+	response.Success(c, info)
+}
+
+// Consent 用户同意或拒绝授权，返回应跳转的应用回调地址
+// POST /api/oauth/consent
+func (h *OIDCApi) Consent(c *gin.Context) {
+	var req request.OIDCConsentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	authReq, ok := consentRequest(c, req.RequestID)
+	if !ok {
+		return
+	}
+
+	if !req.Approve {
+		oidcService.DeleteAuthRequest(authReq.ID)
+		response.Success(c, gin.H{
+			"redirect_url": service.AuthorizationRedirect(authReq.RedirectURI, map[string]string{
+				"error":             "access_denied",
+				"error_description": "用户拒绝授权",
+				"state":             authReq.State,
+			}),
+		})
+		return
+	}
+
+	session := sessions.Default(c)
+	userUUID, deviceID, _ := ssoSessionUser(session)
+	if err := oidcService.GrantConsent(c, uuid.FromStringOrNil(userUUID), authReq.ClientID, authReq.Scopes); err != nil {
+		response.Error(c, 1020, err.Error())
+		return
+	}
+
+	redirectURL, err := issueAuthorizationCode(c, session, authReq, userUUID, deviceID)
+	if err != nil {
+		var oidcErr *service.OIDCError
+		if errors.As(err, &oidcErr) {
+			response.Success(c, gin.H{
+				"redirect_url": service.AuthorizationRedirect(authReq.RedirectURI, map[string]string{
+					"error":             oidcErr.Code,
+					"error_description": oidcErr.Description,
+					"state":             authReq.State,
+				}),
+			})
+			return
+		}
+		response.Error(c, 1016, "生成授权码失败")
+		return
+	}
+	response.Success(c, gin.H{"redirect_url": redirectURL})
+}
+
+// Token OIDC token端点（authorization_code + PKCE、refresh_token），按 RFC 6749 返回
+// POST /api/oauth/token
+func (h *OIDCApi) Token(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+
+	var req request.OIDCTokenRequest
+	if err := c.ShouldBind(&req); err != nil {
+		response.OAuthError(c, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+
+	// 客户端认证：优先 Basic 认证头，其次表单参数
+	clientID, clientSecret := req.ClientID, req.ClientSecret
+	if id, secret, ok := c.Request.BasicAuth(); ok {
+		clientID, _ = url.QueryUnescape(id)
+		clientSecret, _ = url.QueryUnescape(secret)
+	}
+	// 公共客户端兑换授权码可不带密钥，由 ExchangeCode 强制校验 PKCE；机密客户端始终校验密钥
+	app, err := authService.GetAppByKey(clientID)
+	publicExchange := err == nil && app.PublicClient == 1 && req.GrantType == "authorization_code" && clientSecret == ""
+	if err != nil || (!publicExchange && !appSecretService.Verify(app, clientSecret)) {
+		response.OAuthError(c, http.StatusUnauthorized, "invalid_client", "client_id或client_secret错误")
+		return
+	}
+
+	var resp *response.OIDCTokenResponse
+	switch req.GrantType {
+	case "authorization_code":
+		resp, err = oidcService.ExchangeCode(app, req)
+	case "refresh_token":
+		resp, err = oidcService.Refresh(c, app, clientSecret, req.RefreshToken)
+	default:
+		response.OAuthError(c, http.StatusBadRequest, "unsupported_grant_type", "不支持的grant_type")
+		return
+	}
+	if err != nil {
+		var oidcErr *service.OIDCError
+		if errors.As(err, &oidcErr) {
+			response.OAuthError(c, http.StatusBadRequest, oidcErr.Code, oidcErr.Description)
+			return
+		}
+		global.Log.Error("OIDC 签发 Token 失败", zap.String("client_id", clientID), zap.Error(err))
+		response.OAuthError(c, http.StatusInternalServerError, "server_error", "签发Token失败")
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// UserInfo OIDC 用户信息端点（需 AccessToken）
+// GET/POST /api/oauth/userinfo
+func (h *OIDCApi) UserInfo(c *gin.Context) {
+	scope := middleware.GetScope(c)
+	if scope != "" && !service.HasScope(scope, "openid") {
+		response.OAuthError(c, http.StatusForbidden, "insufficient_scope", "AccessToken未包含openid授权")
+		return
+	}
+
+	info, err := oidcService.UserInfo(middleware.GetUserUUID(c), scope)
+	if err != nil {
+		response.OAuthError(c, http.StatusUnauthorized, "invalid_token", err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, info)
+}
+
+// ssoSessionUser 读取全局 SSO Session 中已登录的用户和 SSO 设备 ID
+func ssoSessionUser(session sessions.Session) (userUUID, deviceID string, ok bool) {
+	if session.Get("logged_in") != true {
+		return "", "", false
+	}
+	userUUID, _ = session.Get("user_uuid").(string)
+	deviceID, _ = session.Get("sso_device_id").(string)
+	if userUUID == "" || deviceID == "" {
+		return "", "", false
+	}
+	return userUUID, deviceID, true
+}
+
+// consentRequest 读取待同意的授权请求，并校验其属于当前 Session 用户
+func consentRequest(c *gin.Context, requestID string) (*appTypes.OIDCAuthRequest, bool) {
+	userUUID, _, loggedIn := ssoSessionUser(sessions.Default(c))
+	if !loggedIn {
+		response.Unauthorized(c, "请先登录")
+		return nil, false
+	}
+
+	authReq, err := oidcService.GetAuthRequest(requestID)
+	if err != nil || authReq.UserUUID == "" {
+		response.Error(c, 1019, service.ErrOIDCRequestInvalid.Error())
+		return nil, false
+	}
+	if authReq.UserUUID != userUUID {
+		response.Forbidden(c, "无权处理该授权请求")
+		return nil, false
+	}
+	return authReq, true
+}
+
+// issueAuthorizationCode 按 Session 中的设备信息签发授权码
+func issueAuthorizationCode(c *gin.Context, session sessions.Session, authReq *appTypes.OIDCAuthRequest, userUUID, deviceID string) (string, error) {
+	if deviceName, _ := session.Get("device_name").(string); deviceName != "" {
+		c.Set("session_device_name", deviceName)
+	}
+	if deviceType, _ := session.Get("device_type").(string); deviceType != "" {
+		c.Set("session_device_type", deviceType)
+	}
+	authTime, _ := session.Get("logged_in_at").(int64)
+
+	return oidcService.IssueCode(c, authReq, userUUID, deviceID, authTime)
+}
+
+// redirectAuthorizeError 将授权错误回调给应用
+func redirectAuthorizeError(c *gin.Context, redirectURI, state, code, description string) {
+	c.Redirect(http.StatusFound, service.AuthorizationRedirect(redirectURI, map[string]string{
+		"error":             code,
+		"error_description": description,
+		"state":             state,
+	}))
+}
@@ -0,0 +1,180 @@
+package api
+
+import (
+	"auth-service/internal/middleware"
+	"auth-service/internal/model/request"
+	"auth-service/internal/model/response"
+	"auth-service/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+type WebAuthnApi struct {
+}
+
+// BeginRegistration 获取通行密钥注册参数（需已登录）
+func (h *WebAuthnApi) BeginRegistration(c *gin.Context) {
+	userUUID := middleware.GetUserUUID(c)
+	if userUUID == uuid.Nil {
+		response.Error(c, 1001, "用户未登录")
+		return
+	}
+
+	options, err := webauthnService.BeginRegistration(userUUID)
+	if err != nil {
+		response.Error(c, 2006, err.Error())
+		return
+	}
+
+	response.Success(c, options)
+}
+
+// FinishRegistration 提交认证器注册结果，保存通行密钥
+func (h *WebAuthnApi) FinishRegistration(c *gin.Context) {
+	userUUID := middleware.GetUserUUID(c)
+	if userUUID == uuid.Nil {
+		response.Error(c, 1001, "用户未登录")
+		return
+	}
+
+	var req request.WebAuthnRegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+	if req.Name == "" {
+		req.Name = parseDeviceNameFromUserAgent(c.GetHeader("User-Agent"))
+	}
+
+	passkey, err := webauthnService.FinishRegistration(c, userUUID, req.Name, req.Credential)
+	if err != nil {
+		response.Error(c, 2006, err.Error())
+		return
+	}
+
+	response.SuccessMsg(c, "通行密钥已添加", passkey)
+}
+
+// BeginLogin 获取通行密钥登录参数
+func (h *WebAuthnApi) BeginLogin(c *gin.Context) {
+	var req request.WebAuthnLoginBeginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	options, err := webauthnService.BeginLogin(req.Email)
+	if err != nil {
+		response.Error(c, 1018, err.Error())
+		return
+	}
+
+	response.Success(c, options)
+}
+
+// FinishLogin 提交认证器签名完成登录，后续流程与密码登录一致
+func (h *WebAuthnApi) FinishLogin(c *gin.Context) {
+	var req request.WebAuthnLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 验证state参数
+	stateData, err := utils.ValidateState(req.State)
+	if err != nil {
+		response.BadRequest(c, "state验证失败: "+err.Error())
+		return
+	}
+	if stateData.AppID != req.AppID {
+		response.BadRequest(c, "app_id参数与state中的app_id不一致")
+		return
+	}
+
+	loginReq := request.LoginRequest{
+		AppID:       req.AppID,
+		DeviceID:    stateData.DeviceID,
+		RedirectURI: stateData.RedirectURI,
+		ReturnURL:   stateData.ReturnURL,
+		DeviceName:  req.DeviceName,
+		DeviceType:  req.DeviceType,
+	}
+	if loginReq.DeviceID == "" {
+		loginReq.DeviceID = uuid.Must(uuid.NewV4()).String()
+	}
+
+	resp, err := webauthnService.FinishLogin(c, req, loginReq.DeviceID)
+	if err != nil {
+		response.Error(c, 1018, err.Error())
+		return
+	}
+
+	data, err := finishLogin(c, resp, loginReq, true)
+	if err != nil {
+		response.Error(c, 1003, err.Error())
+		return
+	}
+	response.Success(c, data)
+}
+
+// ListPasskeys 获取通行密钥列表
+func (h *WebAuthnApi) ListPasskeys(c *gin.Context) {
+	userUUID := middleware.GetUserUUID(c)
+	if userUUID == uuid.Nil {
+		response.Error(c, 1001, "用户未登录")
+		return
+	}
+
+	list, err := webauthnService.ListPasskeys(userUUID)
+	if err != nil {
+		response.Error(c, 2006, "获取通行密钥列表失败")
+		return
+	}
+
+	response.Success(c, list)
+}
+
+// RenamePasskey 重命名通行密钥
+func (h *WebAuthnApi) RenamePasskey(c *gin.Context) {
+	userUUID := middleware.GetUserUUID(c)
+	if userUUID == uuid.Nil {
+		response.Error(c, 1001, "用户未登录")
+		return
+	}
+
+	var req request.PasskeyRenameRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	if err := webauthnService.RenamePasskey(userUUID, req.ID, req.Name); err != nil {
+		response.Error(c, 2006, err.Error())
+		return
+	}
+
+	response.SuccessMsg(c, "重命名成功", nil)
+}
+
+// RevokePasskey 删除通行密钥
+func (h *WebAuthnApi) RevokePasskey(c *gin.Context) {
+	userUUID := middleware.GetUserUUID(c)
+	if userUUID == uuid.Nil {
+		response.Error(c, 1001, "用户未登录")
+		return
+	}
+
+	var req request.PasskeyRevokeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	if err := webauthnService.RevokePasskey(c, userUUID, req.ID); err != nil {
+		response.Error(c, 2006, err.Error())
+		return
+	}
+
+	response.SuccessMsg(c, "通行密钥已删除", nil)
+}
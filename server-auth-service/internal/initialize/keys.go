@@ -0,0 +1,48 @@
+package initialize
+
+import (
+	"auth-service/pkg/global"
+	"auth-service/pkg/jwt"
+	"crypto/rsa"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// keyReloadInterval 重新读取密钥目录的间隔，执行 --generate-key 后各实例在该时间内发布新公钥，
+// 到达计划的生效时间后同样在该时间内切换签名密钥
+const keyReloadInterval = time.Minute
+
+// InitSigningKeys 加载JWT签名密钥集合，并定期重新读取密钥目录
+func InitSigningKeys() *jwt.KeySet {
+	conf := global.Config.JWT
+
+	// 配置文件中的单一私钥：未轮换过时作为签名密钥，轮换后保留用于验证旧Token
+	var legacy *rsa.PrivateKey
+	if conf.PrivateKeyPath != "" {
+		key, err := jwt.LoadPrivateKey(conf.PrivateKeyPath)
+		if err != nil {
+			if conf.KeysDir == "" {
+				global.Log.Fatal("加载私钥失败", zap.Error(err))
+			}
+			global.Log.Warn("加载私钥失败，仅使用密钥目录中的密钥", zap.Error(err))
+		}
+		legacy = key
+	}
+
+	keys, err := jwt.NewKeySet(conf.KeysDir, legacy)
+	if err != nil {
+		global.Log.Fatal("加载签名密钥失败", zap.Error(err))
+	}
+
+	if conf.KeysDir != "" {
+		go func() {
+			for range time.Tick(keyReloadInterval) {
+				if err := keys.Reload(); err != nil {
+					global.Log.Error("重新加载签名密钥失败", zap.Error(err))
+				}
+			}
+		}()
+	}
+	return keys
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"auth-service/internal/model/database"
+	"auth-service/internal/model/response"
+	"auth-service/pkg/global"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SuperAdminMiddleware 超级管理员中间件（需在 AuthMiddleware 之后使用）
+func SuperAdminMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userUUID := GetUserUUID(c)
+
+		// 每次从数据库读取，撤销管理员权限后立即生效
+		var user database.SSOUser
+		err := global.DB.Select("is_super_admin", "status").Where("uuid = ?", userUUID).First(&user).Error
+		if err != nil || user.Status != 1 || !user.IsSuperAdmin {
+			response.Forbidden(c, "需要超级管理员权限")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
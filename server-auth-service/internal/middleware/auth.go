@@ -40,7 +40,7 @@ func AuthMiddleware() gin.HandlerFunc {
 		}
 
 		// 解析token
-		claims, err := jwt.ParseAccessToken(token, global.RSAPublicKey)
+		claims, err := jwt.ParseAccessToken(token, global.SigningKeys)
 		if err != nil {
 			if err == jwt.ErrTokenExpired {
 				response.Unauthorized(c, "token已过期")
@@ -63,6 +63,7 @@ func AuthMiddleware() gin.HandlerFunc {
 		c.Set("user_uuid", claims.UserUUID)
 		c.Set("app_id", claims.AppID)
 		c.Set("device_id", claims.DeviceID)
+		c.Set("scope", claims.Scope)
 
 		c.Next()
 	}
@@ -91,3 +92,11 @@ func GetAppID(c *gin.Context) string {
 	}
 	return ""
 }
+
+// GetScope 从context获取OIDC授权范围（自有应用登录签发的Token为空）
+func GetScope(c *gin.Context) string {
+	if scope, exists := c.Get("scope"); exists {
+		return scope.(string)
+	}
+	return ""
+}
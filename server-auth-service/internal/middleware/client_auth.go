@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"auth-service/internal/model/database"
+	"auth-service/internal/service"
 	"auth-service/pkg/global"
 	"auth-service/internal/model/response"
 
@@ -29,8 +30,8 @@ func ClientAuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		// 验证Secret
-		if app.AppSecret != clientSecret {
+		// 验证Secret（哈希比对，常量时间）
+		if !service.ServiceGroupApp.AppSecretService.Verify(&app, clientSecret) {
 			response.Unauthorized(c, "客户端认证失败")
 			c.Abort()
 			return
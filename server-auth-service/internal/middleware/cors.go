@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"auth-service/internal/service"
 	"auth-service/pkg/global"
 	"net/http"
 	"strings"
@@ -17,15 +18,20 @@ func Cors() gin.HandlerFunc {
 		// 判断是否允许该来源
 		allowOrigin := "*"
 		if !global.Config.System.AllowAllOrigins {
-			// 生产环境：检查白名单
+			// 生产环境：检查配置文件白名单，再检查各应用的白名单（管理员修改后无需重启）
 			allowed := false
 			for _, allowedOrigin := range global.Config.System.AllowedOrigins {
 				if origin == allowedOrigin || strings.HasPrefix(origin, allowedOrigin) {
 					allowed = true
-					allowOrigin = origin
 					break
 				}
 			}
+			if !allowed && origin != "" {
+				allowed = service.ServiceGroupApp.ApplicationService.IsOriginAllowed(origin)
+			}
+			if allowed {
+				allowOrigin = origin
+			}
 			if !allowed {
 				c.AbortWithStatus(http.StatusForbidden)
 				return
@@ -0,0 +1,18 @@
+package appTypes
+
+import "time"
+
+// MFAChallenge 两步验证挑战存储结构（第一步登录成功后写入 Redis）
+type MFAChallenge struct {
+	Token              string
+	UserUUID           string
+	AppID              string
+	RedirectURI        string
+	ReturnURL          string
+	DeviceID           string
+	DeviceName         string
+	DeviceType         string
+	EnrollmentRequired bool   // 应用强制两步验证但用户尚未绑定
+	PendingSecret      string // 登录时绑定生成的待确认密钥
+	ExpiresAt          time.Time
+}
@@ -0,0 +1,19 @@
+package appTypes
+
+// OAuth 跳转第三方授权的用途
+const (
+	OAuthActionLogin = "login" // 第三方登录
+	OAuthActionLink  = "link"  // 已登录用户绑定第三方账号
+)
+
+// OAuthState 跳转第三方授权前暂存的参数（写入 Redis，回调时按 Action 区分登录与绑定）
+type OAuthState struct {
+	Action      string
+	AppID       string // 登录：目标应用
+	DeviceID    string // 登录：设备ID
+	RedirectURI string // 登录：应用回调地址
+	ReturnURL   string // 登录：用户目标页面
+	OIDCRequest string // 登录：OIDC 授权请求 ID，登录完成后继续授权而不是直接回调应用
+	UserUUID    string // 绑定：当前登录用户
+	LinkNonce   string // 绑定：同时写入发起绑定的浏览器 Session，回调时比对，防止他人代为完成授权
+}
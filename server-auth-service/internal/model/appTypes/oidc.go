@@ -0,0 +1,33 @@
+package appTypes
+
+import "time"
+
+// OIDCAuthRequest OIDC 授权请求（写入 Redis，登录或用户同意后凭 ID 继续授权）
+type OIDCAuthRequest struct {
+	ID                  string
+	ClientID            string // 应用 app_key
+	RedirectURI         string
+	Scopes              []string // 已按应用允许范围过滤
+	State               string
+	Nonce               string
+	Prompt              string
+	CodeChallenge       string
+	CodeChallengeMethod string
+	UserUUID            string    // 等待用户同意时绑定的用户，防止他人代为同意
+	CreatedAt           time.Time // prompt=login 时要求 Session 的登录时间晚于此时间
+	ExpiresAt           time.Time
+}
+
+// OIDCAuthCode OIDC 授权码存储结构（一次性使用，Token 在兑换时签发）
+type OIDCAuthCode struct {
+	ClientID            string
+	RedirectURI         string
+	UserUUID            string
+	DeviceID            string
+	Scopes              []string
+	Nonce               string
+	AuthTime            int64
+	CodeChallenge       string
+	CodeChallengeMethod string
+	ExpiresAt           time.Time
+}
@@ -0,0 +1,10 @@
+package appTypes
+
+import "time"
+
+// WebAuthnSession 通行密钥注册 / 登录挑战存储结构（写入 Redis，一次性使用）
+type WebAuthnSession struct {
+	Challenge string
+	UserUUID  string // 注册时为当前用户；登录时指定邮箱则为该邮箱对应用户，否则为空
+	ExpiresAt time.Time
+}
@@ -0,0 +1,18 @@
+package database
+
+import "time"
+
+// SSOAppSecret 应用密钥表（只保存哈希）。轮换后旧密钥在宽限期内仍然有效
+type SSOAppSecret struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	AppID      uint       `json:"app_id" gorm:"index;not null;comment:关联sso_applications.id"`
+	SecretHash string     `json:"-" gorm:"size:64;not null;comment:密钥SHA-256"`
+	Hint       string     `json:"hint" gorm:"size:8;comment:密钥末4位，便于识别"`
+	ExpiresAt  *time.Time `json:"expires_at" gorm:"comment:失效时间，为空表示长期有效"`
+	LastUsedAt *time.Time `json:"last_used_at" gorm:"comment:最近一次认证成功时间"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func (SSOAppSecret) TableName() string {
+	return "sso_app_secrets"
+}
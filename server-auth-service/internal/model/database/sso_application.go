@@ -9,12 +9,16 @@ type SSOApplication struct {
 	global.MODEL
 	AppKey         string `json:"app_key" gorm:"uniqueIndex;size:50;not null;comment:应用标识如blog/admin"`
 	AppName        string `json:"app_name" gorm:"size:100;not null"`
-	AppSecret      string `json:"-" gorm:"size:255;not null"`
+	AppSecret      string `json:"-" gorm:"size:255;not null;default:'';comment:已废弃的明文密钥，--migrate-secrets 迁移到sso_app_secrets后清空"`
 	RedirectURIs   string `json:"redirect_uris" gorm:"type:text;comment:允许的回调地址，逗号分隔"`
 	MaxDevices     int    `json:"max_devices" gorm:"default:5;comment:单应用最大设备数"`
 	AllowedOrigins string `json:"allowed_origins" gorm:"type:text;comment:CORS白名单，逗号分隔"`
 	IsPublic       int    `json:"is_public" gorm:"default:0;comment:是否公开应用 1是 0否"`
 	Icon           string `json:"icon" gorm:"size:500;comment:应用图标URL"`
+	RequireMFA     int    `json:"require_mfa" gorm:"default:0;comment:是否强制两步验证 1是 0否"`
+	Scopes         string `json:"scopes" gorm:"size:255;default:'openid,profile,email';comment:允许申请的OIDC授权范围，逗号分隔"`
+	RequireConsent int    `json:"require_consent" gorm:"default:1;comment:OIDC授权是否需要用户同意 1是 0否"`
+	PublicClient   int    `json:"public_client" gorm:"default:0;comment:是否公共客户端（浏览器或移动端，无法保存密钥，授权码兑换改用PKCE） 1是 0否"`
 	Status         int    `json:"status" gorm:"default:1;comment:1启用 0禁用"`
 }
 
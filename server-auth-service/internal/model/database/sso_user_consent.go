@@ -0,0 +1,19 @@
+package database
+
+import (
+	"auth-service/pkg/global"
+
+	"github.com/gofrs/uuid"
+)
+
+// SSOUserConsent 用户对应用的 OIDC 授权同意记录
+type SSOUserConsent struct {
+	global.MODEL
+	UserUUID uuid.UUID `json:"user_uuid" gorm:"type:char(36);uniqueIndex:idx_user_app;comment:关联sso_users.uuid"`
+	AppID    uint      `json:"app_id" gorm:"uniqueIndex:idx_user_app;comment:关联sso_applications.id"`
+	Scopes   string    `json:"scopes" gorm:"size:255;comment:已同意的授权范围，空格分隔"`
+}
+
+func (SSOUserConsent) TableName() string {
+	return "sso_user_consents"
+}
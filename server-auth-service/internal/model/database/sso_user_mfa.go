@@ -0,0 +1,35 @@
+package database
+
+import (
+	"auth-service/pkg/global"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// SSOUserMFA 两步验证（TOTP）配置表
+type SSOUserMFA struct {
+	global.MODEL
+	UserUUID     uuid.UUID  `json:"user_uuid" gorm:"type:char(36);uniqueIndex;comment:关联sso_users.uuid"`
+	Secret       string     `json:"-" gorm:"size:64;not null;comment:TOTP密钥（Base32）"`
+	Enabled      bool       `json:"enabled" gorm:"default:false;comment:是否已启用"`
+	LastUsedStep int64      `json:"-" gorm:"default:0;comment:最近一次使用的时间步，防止动态码重放"`
+	ConfirmedAt  *time.Time `json:"confirmed_at" gorm:"comment:绑定确认时间"`
+}
+
+func (SSOUserMFA) TableName() string {
+	return "sso_user_mfa"
+}
+
+// SSOMFARecoveryCode 两步验证恢复码表（只保存哈希）
+type SSOMFARecoveryCode struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserUUID  uuid.UUID  `json:"user_uuid" gorm:"type:char(36);index;comment:关联sso_users.uuid"`
+	CodeHash  string     `json:"-" gorm:"size:64;not null;comment:恢复码SHA-256"`
+	UsedAt    *time.Time `json:"used_at" gorm:"comment:使用时间，为空表示未使用"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func (SSOMFARecoveryCode) TableName() string {
+	return "sso_mfa_recovery_codes"
+}
@@ -0,0 +1,28 @@
+package database
+
+import (
+	"auth-service/pkg/global"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// SSOWebAuthnCredential 通行密钥（WebAuthn 凭证）表
+type SSOWebAuthnCredential struct {
+	global.MODEL
+	UserUUID       uuid.UUID  `json:"user_uuid" gorm:"type:char(36);index;comment:关联sso_users.uuid"`
+	UserHandle     []byte     `json:"-" gorm:"type:varbinary(64);comment:注册时写入认证器的用户句柄，合并账号后与user_uuid不同"`
+	CredentialID   string     `json:"credential_id" gorm:"size:255;uniqueIndex;not null;comment:凭证ID（base64url）"`
+	PublicKey      []byte     `json:"-" gorm:"type:blob;not null;comment:COSE格式公钥"`
+	Algorithm      int64      `json:"algorithm" gorm:"comment:签名算法（COSE算法标识）"`
+	SignCount      uint32     `json:"-" gorm:"default:0;comment:签名计数器，用于发现克隆的认证器"`
+	Transports     string     `json:"transports" gorm:"size:100;comment:传输方式，逗号分隔"`
+	AAGUID         string     `json:"aaguid" gorm:"size:36;comment:认证器型号标识"`
+	BackupEligible bool       `json:"backup_eligible" gorm:"default:false;comment:是否为可同步的通行密钥"`
+	Name           string     `json:"name" gorm:"size:64;comment:通行密钥名称"`
+	LastUsedAt     *time.Time `json:"last_used_at" gorm:"comment:最近使用时间"`
+}
+
+func (SSOWebAuthnCredential) TableName() string {
+	return "sso_webauthn_credentials"
+}
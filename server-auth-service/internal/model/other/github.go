@@ -0,0 +1,25 @@
+package other
+
+// GithubAccessTokenResponse GitHub 授权码换取 Access Token 的返回结构
+type GithubAccessTokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	Scope            string `json:"scope"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// GithubUserResponse GitHub 用户信息
+type GithubUserResponse struct {
+	ID        int64  `json:"id"`         // 用户唯一ID
+	Login     string `json:"login"`      // 用户名
+	Name      string `json:"name"`       // 显示名称
+	AvatarURL string `json:"avatar_url"` // 头像URL
+}
+
+// GithubEmailResponse GitHub 用户邮箱（/user/emails）
+type GithubEmailResponse struct {
+	Email    string `json:"email"`
+	Primary  bool   `json:"primary"`
+	Verified bool   `json:"verified"`
+}
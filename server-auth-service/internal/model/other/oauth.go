@@ -0,0 +1,18 @@
+package other
+
+// OAuthToken 第三方授权令牌（部分提供方在换取令牌时即返回 openid）
+type OAuthToken struct {
+	AccessToken string
+	OpenID      string
+	UnionID     string
+}
+
+// OAuthIdentity 归一化后的第三方用户身份
+type OAuthIdentity struct {
+	Provider string // qq/wechat/github
+	OpenID   string // 提供方内的用户唯一标识
+	UnionID  string // 同一开放平台下多个应用共用的标识（微信）
+	Nickname string
+	Avatar   string
+	Email    string // 已验证的邮箱，没有则为空
+}
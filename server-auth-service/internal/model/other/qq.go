@@ -2,10 +2,12 @@ package other
 
 // AccessTokenResponse 表示通过授权码获取的 Access Token 返回结构
 type AccessTokenResponse struct {
-	AccessToken  string `json:"access_token"`  // 授权令牌
-	ExpiresIn    string `json:"expires_in"`    // 该 access token 的有效期，单位为秒
-	RefreshToken string `json:"refresh_token"` // 刷新 token
-	Openid       string `json:"openid"`        // 用户的 Openid
+	AccessToken      string `json:"access_token"`      // 授权令牌
+	ExpiresIn        string `json:"expires_in"`        // 该 access token 的有效期，单位为秒
+	RefreshToken     string `json:"refresh_token"`     // 刷新 token
+	Openid           string `json:"openid"`            // 用户的 Openid
+	Error            int    `json:"error"`             // 错误码
+	ErrorDescription string `json:"error_description"` // 错误描述
 }
 
 // UserInfoResponse 表示获取用户信息的返回结构
@@ -20,6 +22,3 @@ type UserInfoResponse struct {
 	FigureurlQQ1 string `json:"figureurl_qq_1"` // 40x40 QQ头像URL
 	FigureurlQQ2 string `json:"figureurl_qq_2"` // 100x100 QQ头像URL
 }
-
-
-
@@ -0,0 +1,23 @@
+package other
+
+// WechatAccessTokenResponse 微信开放平台授权码换取 Access Token 的返回结构
+type WechatAccessTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+	Openid       string `json:"openid"`
+	Scope        string `json:"scope"`
+	Unionid      string `json:"unionid"` // 绑定开放平台账号后才返回
+	Errcode      int    `json:"errcode"`
+	Errmsg       string `json:"errmsg"`
+}
+
+// WechatUserInfoResponse 微信用户信息
+type WechatUserInfoResponse struct {
+	Openid     string `json:"openid"`
+	Nickname   string `json:"nickname"`
+	Headimgurl string `json:"headimgurl"`
+	Unionid    string `json:"unionid"`
+	Errcode    int    `json:"errcode"`
+	Errmsg     string `json:"errmsg"`
+}
@@ -18,6 +18,7 @@ type LoginRequest struct {
 	AppID            string `json:"app_id" binding:"required"` // 必填：应用ID
 	RedirectURI      string `json:"redirect_uri"`              // 从state中提取
 	DeviceID         string `json:"device_id"`                 // 从state中提取
+	ReturnURL        string `json:"return_url"`                // 从state中提取
 	DeviceName       string `json:"device_name"`
 	DeviceType       string `json:"device_type"`
 	CaptchaID        string `json:"captcha_id"` // 密码登录时必填
@@ -27,26 +28,26 @@ type LoginRequest struct {
 // RefreshTokenRequest 刷新Token请求
 // OAuth 2.0 标准字段映射：
 //   - client_id     -> sso_applications.app_key（字符串标识，如 "mcp"、"blog"）
-//   - client_secret -> sso_applications.app_secret
+//   - client_secret -> sso_app_secrets（哈希比对）
 //
 // 注意：数据库中设备关联使用的是 sso_applications.id（数字），不是 app_key
 type RefreshTokenRequest struct {
 	GrantType    string `json:"grant_type" binding:"required"`
 	RefreshToken string `json:"refresh_token" binding:"required"`
 	ClientID     string `json:"client_id" binding:"required"`     // 对应 app_key，不是数字 ID
-	ClientSecret string `json:"client_secret" binding:"required"` // 对应 app_secret
+	ClientSecret string `json:"client_secret" binding:"required"` // 应用密钥
 }
 
 // TokenExchangeRequest OAuth 2.0 token端点请求（支持authorization_code和refresh_token）
 // OAuth 2.0 标准字段映射：
 //   - client_id     -> sso_applications.app_key（字符串标识）
-//   - client_secret -> sso_applications.app_secret
+//   - client_secret -> sso_app_secrets（哈希比对）
 type TokenExchangeRequest struct {
 	GrantType    string `json:"grant_type" binding:"required"`
 	Code         string `json:"code"`                             // authorization_code模式需要
 	RefreshToken string `json:"refresh_token"`                    // refresh_token模式需要
 	ClientID     string `json:"client_id" binding:"required"`     // 对应 app_key
-	ClientSecret string `json:"client_secret" binding:"required"` // 对应 app_secret
+	ClientSecret string `json:"client_secret" binding:"required"` // 应用密钥
 	RedirectURI  string `json:"redirect_uri"`                     // authorization_code模式需要
 }
 
@@ -64,16 +65,6 @@ type UpdateUserInfoRequest struct {
 	Signature string `json:"signature"`
 }
 
-// QQLoginRequest QQ登录请求
-type QQLoginRequest struct {
-	Code        string `json:"code" binding:"required"`         // QQ授权码
-	AppID       string `json:"app_id" binding:"required"`       // 应用ID
-	RedirectURI string `json:"redirect_uri" binding:"required"` // 回调URI
-	DeviceID    string `json:"device_id"`                       // 设备ID
-	DeviceName  string `json:"device_name"`                     // 设备名称
-	DeviceType  string `json:"device_type"`                     // 设备类型
-}
-
 // SendEmailVerificationCodeRequest 发送邮箱验证码请求
 type SendEmailVerificationCodeRequest struct {
 	Email     string `json:"email" binding:"required,email"`
@@ -97,3 +88,157 @@ type LogQueryParams struct {
 	StartTime string `form:"start_time"` // 开始时间
 	EndTime   string `form:"end_time"`   // 结束时间
 }
+
+// MFAVerifyRequest 两步验证登录请求（第二步）
+type MFAVerifyRequest struct {
+	MFAToken     string `json:"mfa_token" binding:"required"`
+	Code         string `json:"code"`          // 身份验证器动态码
+	RecoveryCode string `json:"recovery_code"` // 恢复码（二选一）
+}
+
+// MFASetupRequest 登录时绑定两步验证请求（应用强制两步验证且用户尚未绑定）
+type MFASetupRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+}
+
+// MFACodeRequest 两步验证动态码请求（启用、停用、重新生成恢复码）
+type MFACodeRequest struct {
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+// WebAuthnCredential 浏览器 PublicKeyCredential.toJSON() 的结果，二进制字段均为 base64url
+type WebAuthnCredential struct {
+	ID       string                     `json:"id" binding:"required"`
+	Type     string                     `json:"type"`
+	Response WebAuthnCredentialResponse `json:"response" binding:"required"`
+}
+
+// WebAuthnCredentialResponse 认证器响应（注册与登录共用，按场景使用不同字段）
+type WebAuthnCredentialResponse struct {
+	ClientDataJSON    string   `json:"clientDataJSON" binding:"required"`
+	AttestationObject string   `json:"attestationObject"` // 注册
+	Transports        []string `json:"transports"`        // 注册
+	AuthenticatorData string   `json:"authenticatorData"` // 登录
+	Signature         string   `json:"signature"`         // 登录
+	UserHandle        string   `json:"userHandle"`        // 登录
+}
+
+// WebAuthnRegisterRequest 完成通行密钥注册请求
+type WebAuthnRegisterRequest struct {
+	Name       string             `json:"name" binding:"max=64"` // 通行密钥名称，留空则按设备生成
+	Credential WebAuthnCredential `json:"credential" binding:"required"`
+}
+
+// WebAuthnLoginBeginRequest 开始通行密钥登录请求
+type WebAuthnLoginBeginRequest struct {
+	Email string `json:"email" binding:"omitempty,email"` // 可选：只允许该账号的通行密钥
+}
+
+// WebAuthnLoginRequest 完成通行密钥登录请求
+type WebAuthnLoginRequest struct {
+	SessionID  string             `json:"session_id" binding:"required"`
+	AppID      string             `json:"app_id" binding:"required"`
+	State      string             `json:"state" binding:"required"` // OAuth state参数（包含redirect_uri等）
+	DeviceName string             `json:"device_name"`
+	DeviceType string             `json:"device_type"`
+	Credential WebAuthnCredential `json:"credential" binding:"required"`
+}
+
+// PasskeyRenameRequest 重命名通行密钥请求
+type PasskeyRenameRequest struct {
+	ID   uint   `json:"id" binding:"required"`
+	Name string `json:"name" binding:"required,max=64"`
+}
+
+// PasskeyRevokeRequest 删除通行密钥请求
+type PasskeyRevokeRequest struct {
+	ID uint `json:"id" binding:"required"`
+}
+
+// OAuthProviderRequest 绑定 / 解绑第三方账号请求
+type OAuthProviderRequest struct {
+	Provider string `json:"provider" binding:"required,oneof=qq wechat github"`
+}
+
+// MergeUsersRequest 合并账号请求（管理员）
+type MergeUsersRequest struct {
+	SourceUUID string `json:"source_uuid" binding:"required,uuid"` // 被合并的账号，合并后注销
+	TargetUUID string `json:"target_uuid" binding:"required,uuid"` // 保留的账号
+}
+
+// RotateAppSecretRequest 签发新的应用密钥请求（管理员）
+type RotateAppSecretRequest struct {
+	GracePeriod string `json:"grace_period"` // 旧密钥继续有效的时长，如 24h、7d，默认 24h；填 0 表示旧密钥立即失效
+}
+
+// ApplicationRequest 应用配置（管理员），创建应用时使用
+type ApplicationRequest struct {
+	AppName        string   `json:"app_name" binding:"required,max=100"`
+	RedirectURIs   []string `json:"redirect_uris" binding:"required,min=1,dive,required"` // 正式环境地址在前，测试环境地址在后
+	AllowedOrigins []string `json:"allowed_origins" binding:"dive,required"`              // CORS白名单，只包含协议、域名和端口
+	MaxDevices     int      `json:"max_devices" binding:"min=1,max=100"`
+	IsPublic       int      `json:"is_public" binding:"oneof=0 1"`
+	Icon           string   `json:"icon" binding:"omitempty,url,max=500"`
+	RequireMFA     int      `json:"require_mfa" binding:"oneof=0 1"`
+	Scopes         []string `json:"scopes"` // 为空时取 openid,profile,email
+	RequireConsent int      `json:"require_consent" binding:"oneof=0 1"`
+	PublicClient   int      `json:"public_client" binding:"oneof=0 1"` // 公共客户端必须使用 PKCE，兑换授权码无需 client_secret
+}
+
+// CreateApplicationRequest 创建应用请求（管理员）
+type CreateApplicationRequest struct {
+	AppKey string `json:"app_key" binding:"required,max=50"` // 小写字母、数字和连字符，创建后不可修改
+	ApplicationRequest
+}
+
+// UpdateApplicationRequest 更新应用请求（管理员），只修改传入的字段，未传的字段保持原值
+type UpdateApplicationRequest struct {
+	AppName        *string   `json:"app_name" binding:"omitempty,min=1,max=100"`
+	RedirectURIs   *[]string `json:"redirect_uris" binding:"omitempty,min=1,dive,required"`
+	AllowedOrigins *[]string `json:"allowed_origins" binding:"omitempty,dive,required"` // 传空数组表示清空
+	MaxDevices     *int      `json:"max_devices" binding:"omitempty,min=1,max=100"`
+	IsPublic       *int      `json:"is_public" binding:"omitempty,oneof=0 1"`
+	Icon           *string   `json:"icon" binding:"omitempty,max=500"` // 传空字符串表示清空
+	RequireMFA     *int      `json:"require_mfa" binding:"omitempty,oneof=0 1"`
+	Scopes         *[]string `json:"scopes" binding:"omitempty,min=1"`
+	RequireConsent *int      `json:"require_consent" binding:"omitempty,oneof=0 1"`
+	PublicClient   *int      `json:"public_client" binding:"omitempty,oneof=0 1"`
+}
+
+// AppMemberQueryParams 应用活跃用户 / 设备查询参数（管理员）
+type AppMemberQueryParams struct {
+	Page     int `form:"page"`
+	PageSize int `form:"page_size" binding:"omitempty,max=100"`
+}
+
+// OIDCAuthorizeRequest OIDC 授权端点请求（查询参数）
+type OIDCAuthorizeRequest struct {
+	ResponseType        string `form:"response_type"`
+	ClientID            string `form:"client_id"` // 对应 app_key
+	RedirectURI         string `form:"redirect_uri"`
+	Scope               string `form:"scope"` // 空格分隔
+	State               string `form:"state"`
+	Nonce               string `form:"nonce"`
+	Prompt              string `form:"prompt"` // none/login/consent
+	CodeChallenge       string `form:"code_challenge"`
+	CodeChallengeMethod string `form:"code_challenge_method"` // S256/plain
+}
+
+// OIDCTokenRequest OIDC token端点请求（application/x-www-form-urlencoded）
+// 客户端凭证可放在 Basic 认证头（client_secret_basic）或表单中（client_secret_post）
+type OIDCTokenRequest struct {
+	GrantType    string `form:"grant_type"`
+	Code         string `form:"code"`
+	RedirectURI  string `form:"redirect_uri"`
+	CodeVerifier string `form:"code_verifier"`
+	RefreshToken string `form:"refresh_token"`
+	ClientID     string `form:"client_id"`
+	ClientSecret string `form:"client_secret"`
+}
+
+// OIDCConsentRequest 用户同意 / 拒绝授权请求
+type OIDCConsentRequest struct {
+	RequestID string `json:"request_id" binding:"required"`
+	Approve   bool   `json:"approve"`
+}
@@ -10,9 +10,221 @@ type TokenResponse struct {
 	RefreshToken string    `json:"refresh_token,omitempty"`
 	TokenType    string    `json:"token_type"`
 	ExpiresIn    int       `json:"expires_in"`
+	Scope        string    `json:"scope,omitempty"` // OIDC 授权范围
 	UserInfo     *UserInfo `json:"user_info,omitempty"`
 }
 
+// MFAChallenge 两步验证挑战（登录第一步通过后返回，代替 TokenResponse）
+type MFAChallenge struct {
+	MFARequired        bool     `json:"mfa_required"`
+	MFAToken           string   `json:"mfa_token"`
+	Methods            []string `json:"methods"`             // totp/recovery_code
+	EnrollmentRequired bool     `json:"enrollment_required"` // 需先绑定身份验证器
+	ExpiresIn          int      `json:"expires_in"`
+}
+
+// MFASetupInfo 两步验证绑定信息
+type MFASetupInfo struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"` // otpauth:// 地址，前端生成二维码
+}
+
+// MFAStatus 两步验证状态
+type MFAStatus struct {
+	Enabled           bool       `json:"enabled"`
+	ConfirmedAt       *time.Time `json:"confirmed_at"`
+	RecoveryCodesLeft int64      `json:"recovery_codes_left"`
+}
+
+// WebAuthnCreationOptions 通行密钥注册参数（PublicKeyCredentialCreationOptions 的 JSON 形式，
+// 前端可直接交给 PublicKeyCredential.parseCreationOptionsFromJSON）
+type WebAuthnCreationOptions struct {
+	Challenge              string                         `json:"challenge"`
+	RP                     WebAuthnRelyingParty           `json:"rp"`
+	User                   WebAuthnUser                   `json:"user"`
+	PubKeyCredParams       []WebAuthnCredentialParam      `json:"pubKeyCredParams"`
+	Timeout                int                            `json:"timeout"`
+	ExcludeCredentials     []WebAuthnCredentialDescriptor `json:"excludeCredentials"`
+	AuthenticatorSelection WebAuthnAuthenticatorSelection `json:"authenticatorSelection"`
+	Attestation            string                         `json:"attestation"`
+}
+
+// WebAuthnRequestOptions 通行密钥登录参数（PublicKeyCredentialRequestOptions 的 JSON 形式）
+type WebAuthnRequestOptions struct {
+	Challenge        string                         `json:"challenge"`
+	Timeout          int                            `json:"timeout"`
+	RPID             string                         `json:"rpId"`
+	AllowCredentials []WebAuthnCredentialDescriptor `json:"allowCredentials"`
+	UserVerification string                         `json:"userVerification"`
+}
+
+// WebAuthnLoginOptions 开始通行密钥登录的响应
+type WebAuthnLoginOptions struct {
+	SessionID string                 `json:"session_id"`
+	PublicKey WebAuthnRequestOptions `json:"public_key"`
+}
+
+// WebAuthnRelyingParty 依赖方信息
+type WebAuthnRelyingParty struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// WebAuthnUser 注册时的用户信息（ID 为用户 UUID 的 base64url）
+type WebAuthnUser struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+// WebAuthnCredentialParam 支持的公钥算法
+type WebAuthnCredentialParam struct {
+	Type string `json:"type"`
+	Alg  int64  `json:"alg"`
+}
+
+// WebAuthnCredentialDescriptor 凭证描述
+type WebAuthnCredentialDescriptor struct {
+	Type       string   `json:"type"`
+	ID         string   `json:"id"`
+	Transports []string `json:"transports,omitempty"`
+}
+
+// WebAuthnAuthenticatorSelection 认证器要求
+type WebAuthnAuthenticatorSelection struct {
+	ResidentKey      string `json:"residentKey"`
+	UserVerification string `json:"userVerification"`
+}
+
+// OAuthBindingInfo 第三方账号绑定信息
+type OAuthBindingInfo struct {
+	Provider     string     `json:"provider"`      // qq/wechat/github
+	ProviderName string     `json:"provider_name"` // 展示名称
+	Enabled      bool       `json:"enabled"`       // 是否允许新绑定
+	Bound        bool       `json:"bound"`
+	BoundAt      *time.Time `json:"bound_at"`
+}
+
+// UserMergeResult 合并账号结果（转移的记录数）
+type UserMergeResult struct {
+	Bindings     int64 `json:"bindings"`
+	Passkeys     int64 `json:"passkeys"`
+	Devices      int64 `json:"devices"`
+	AppRelations int64 `json:"app_relations"`
+	Logs         int64 `json:"logs"`
+}
+
+// ApplicationDetail 应用详情（管理员）
+type ApplicationDetail struct {
+	ID             uint      `json:"id"`
+	AppKey         string    `json:"app_key"`
+	AppName        string    `json:"app_name"`
+	RedirectURIs   []string  `json:"redirect_uris"`
+	AllowedOrigins []string  `json:"allowed_origins"`
+	MaxDevices     int       `json:"max_devices"`
+	IsPublic       int       `json:"is_public"`
+	Icon           string    `json:"icon"`
+	RequireMFA     int       `json:"require_mfa"`
+	Scopes         []string  `json:"scopes"`
+	RequireConsent int       `json:"require_consent"`
+	PublicClient   int       `json:"public_client"`
+	Status         int       `json:"status"`
+	ActiveUsers    int64     `json:"active_users"`
+	ActiveDevices  int64     `json:"active_devices"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// ApplicationCreated 创建应用结果，Secret 为明文密钥，仅返回这一次
+type ApplicationCreated struct {
+	Application ApplicationDetail `json:"application"`
+	Secret      string            `json:"secret"`
+}
+
+// AppUserInfo 应用的活跃用户
+type AppUserInfo struct {
+	UUID         string    `json:"uuid"`
+	Email        string    `json:"email"`
+	Nickname     string    `json:"nickname"`
+	Devices      int64     `json:"devices"`
+	LastActiveAt time.Time `json:"last_active_at"`
+}
+
+// AppDeviceInfo 应用的在线设备
+type AppDeviceInfo struct {
+	DeviceInfo
+	UserUUID string `json:"user_uuid"`
+	Email    string `json:"email"`
+}
+
+// AppSecretInfo 应用密钥信息（不含密钥本身）
+type AppSecretInfo struct {
+	ID         uint       `json:"id"`
+	Hint       string     `json:"hint"`
+	Active     bool       `json:"active"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// AppSecretIssued 新签发的应用密钥，明文只返回这一次
+type AppSecretIssued struct {
+	Secret string        `json:"secret"`
+	Info   AppSecretInfo `json:"info"`
+}
+
+// PasskeyInfo 通行密钥信息
+type PasskeyInfo struct {
+	ID             uint       `json:"id"`
+	Name           string     `json:"name"`
+	Transports     []string   `json:"transports"`
+	BackupEligible bool       `json:"backup_eligible"`
+	CreatedAt      time.Time  `json:"created_at"`
+	LastUsedAt     *time.Time `json:"last_used_at"`
+}
+
+// OIDCDiscovery OpenID Connect 发现文档
+type OIDCDiscovery struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserinfoEndpoint                  string   `json:"userinfo_endpoint"`
+	JwksURI                           string   `json:"jwks_uri"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	ScopesSupported                   []string `json:"scopes_supported"`
+	ClaimsSupported                   []string `json:"claims_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+	PromptValuesSupported             []string `json:"prompt_values_supported"`
+}
+
+// OIDCTokenResponse OIDC token端点响应（按 RFC 6749 直接返回，不包装为统一响应结构）
+type OIDCTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	IDToken      string `json:"id_token,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+}
+
+// OIDCConsentInfo 授权同意页展示信息
+type OIDCConsentInfo struct {
+	ClientID string          `json:"client_id"`
+	AppName  string          `json:"app_name"`
+	Icon     string          `json:"icon"`
+	Scopes   []OIDCScopeInfo `json:"scopes"`
+}
+
+// OIDCScopeInfo 授权范围说明
+type OIDCScopeInfo struct {
+	Scope       string `json:"scope"`
+	Description string `json:"description"`
+}
+
 // UserInfo 用户信息
 type UserInfo struct {
 	UUID           string  `json:"uuid"`
@@ -87,3 +87,11 @@ func InternalError(c *gin.Context, message string) {
 		Message: message,
 	})
 }
+
+// OAuthError OAuth 2.0 / OIDC 标准错误响应（RFC 6749 5.2）
+func OAuthError(c *gin.Context, status int, code, description string) {
+	c.JSON(status, gin.H{
+		"error":             code,
+		"error_description": description,
+	})
+}
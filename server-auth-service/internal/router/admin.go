@@ -0,0 +1,42 @@
+package router
+
+import (
+	"auth-service/internal/api"
+	"auth-service/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminRouter struct{}
+
+func (a *AdminRouter) InitAdminRouter(apiGroup *gin.RouterGroup) {
+	// 仅超级管理员可访问
+	admin := apiGroup.Group("/admin", middleware.AuthMiddleware(), middleware.SuperAdminMiddleware())
+	{
+		adminApi := api.ApiGroupApp.AdminApi
+
+		// 用户管理
+		users := admin.Group("/users")
+		{
+			users.POST("/merge", adminApi.MergeUsers)
+		}
+
+		// 应用管理
+		apps := admin.Group("/apps")
+		{
+			apps.GET("", adminApi.ListApplications)
+			apps.POST("", adminApi.CreateApplication)
+			apps.GET("/:app_key", adminApi.GetApplication)
+			apps.PUT("/:app_key", adminApi.UpdateApplication)
+			apps.DELETE("/:app_key", adminApi.DeleteApplication)
+			apps.POST("/:app_key/enable", adminApi.EnableApplication)
+			apps.POST("/:app_key/disable", adminApi.DisableApplication)
+			apps.GET("/:app_key/users", adminApi.GetApplicationUsers)
+			apps.GET("/:app_key/devices", adminApi.GetApplicationDevices)
+
+			// 应用密钥
+			apps.GET("/:app_key/secrets", adminApi.ListAppSecrets)
+			apps.POST("/:app_key/secrets", adminApi.RotateAppSecret)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package router
 
 import (
 	"auth-service/internal/api"
+	"auth-service/internal/middleware"
 
 	"github.com/gin-gonic/gin"
 )
@@ -15,9 +16,24 @@ func (a *AuthRouter) InitAuthRouter(apiGroup *gin.RouterGroup) {
 		auth.POST("/register", authApi.Register)
 		auth.POST("/login", authApi.Login)
 		auth.POST("/token", authApi.RefreshToken)
-		auth.POST("/oauth/qq/login", authApi.QQLogin)
-		auth.GET("/oauth/qq/callback", authApi.QQCallback)
+		auth.GET("/oauth/:provider/login", authApi.OAuthLogin)
+		auth.GET("/oauth/:provider/callback", authApi.OAuthCallback)
 		auth.POST("/sendEmailVerificationCode", authApi.SendEmailVerificationCode)
 		auth.POST("/forgotPassword", authApi.ForgotPassword)
+
+		// 两步验证登录（第二步）
+		mfaApi := api.ApiGroupApp.MFAApi
+		auth.POST("/mfa/verify", mfaApi.VerifyLogin)
+		auth.POST("/mfa/setup", mfaApi.SetupLogin)
+
+		// 通行密钥：登录无需鉴权，注册需已登录
+		webauthnApi := api.ApiGroupApp.WebAuthnApi
+		webauthn := auth.Group("/webauthn")
+		{
+			webauthn.POST("/login/begin", webauthnApi.BeginLogin)
+			webauthn.POST("/login/finish", webauthnApi.FinishLogin)
+			webauthn.POST("/register/begin", middleware.AuthMiddleware(), webauthnApi.BeginRegistration)
+			webauthn.POST("/register/finish", middleware.AuthMiddleware(), webauthnApi.FinishRegistration)
+		}
 	}
 }
@@ -13,8 +13,5 @@ func (b *BaseRouter) InitBaseRouter(apiGroup *gin.RouterGroup) {
 	{
 		captchaApi := api.ApiGroupApp.CaptchaApi
 		base.GET("/captcha", captchaApi.GetCaptcha)
-
-		authApi := api.ApiGroupApp.AuthApi
-		base.GET("/qqLoginURL", authApi.QQLoginURL)
 	}
 }
@@ -6,6 +6,7 @@ type RouterGroup struct {
 	OAuthRouter
 	UserRouter
 	ManageRouter
+	AdminRouter
 }
 
 var RouterGroupApp = new(RouterGroup)
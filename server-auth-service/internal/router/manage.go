@@ -27,6 +27,35 @@ func (m *ManageRouter) InitManageRouter(apiGroup *gin.RouterGroup) {
 				devices.POST("/logout-all", manageApi.LogoutAllDevices)
 			}
 
+			// 两步验证
+			mfa := manage.Group("/mfa")
+			{
+				mfaApi := api.ApiGroupApp.MFAApi
+				mfa.GET("/status", mfaApi.GetStatus)
+				mfa.POST("/setup", mfaApi.BeginSetup)
+				mfa.POST("/enable", mfaApi.Enable)
+				mfa.POST("/disable", mfaApi.Disable)
+				mfa.POST("/recovery-codes", mfaApi.RegenerateRecoveryCodes)
+			}
+
+			// 通行密钥
+			passkeys := manage.Group("/passkeys")
+			{
+				webauthnApi := api.ApiGroupApp.WebAuthnApi
+				passkeys.GET("/list", webauthnApi.ListPasskeys)
+				passkeys.POST("/rename", webauthnApi.RenamePasskey)
+				passkeys.POST("/revoke", webauthnApi.RevokePasskey)
+			}
+
+			// 第三方账号绑定
+			bindings := manage.Group("/bindings")
+			{
+				bindingApi := api.ApiGroupApp.BindingApi
+				bindings.GET("/list", bindingApi.ListBindings)
+				bindings.POST("/link", bindingApi.Link)
+				bindings.POST("/unlink", bindingApi.Unlink)
+			}
+
 			// 日志和用户信息
 			manage.GET("/logs", manageApi.GetLogs)
 			manage.GET("/profile", manageApi.GetProfile)
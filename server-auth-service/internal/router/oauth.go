@@ -2,6 +2,7 @@ package router
 
 import (
 	"auth-service/internal/api"
+	"auth-service/internal/middleware"
 
 	"github.com/gin-gonic/gin"
 )
@@ -14,5 +15,23 @@ func (o *OAuthRouter) InitOAuthRouter(apiGroup *gin.RouterGroup) {
 		oauthApi := api.ApiGroupApp.OAuthApi
 		oauth.GET("/applications", oauthApi.GetPublicApplications)
 		oauth.GET("/authorize", oauthApi.Authorize)
+
+		// OpenID Connect
+		oidcApi := api.ApiGroupApp.OIDCApi
+		oauth.POST("/token", oidcApi.Token)
+		oauth.GET("/userinfo", middleware.AuthMiddleware(), oidcApi.UserInfo)
+		oauth.POST("/userinfo", middleware.AuthMiddleware(), oidcApi.UserInfo)
+		oauth.GET("/consent", oidcApi.GetConsent)
+		oauth.POST("/consent", oidcApi.Consent)
+	}
+}
+
+// InitWellKnownRouter OIDC 发现文档与签名公钥（按规范位于站点根路径）
+func (o *OAuthRouter) InitWellKnownRouter(r *gin.RouterGroup) {
+	wellKnown := r.Group("/.well-known")
+	{
+		oidcApi := api.ApiGroupApp.OIDCApi
+		wellKnown.GET("/openid-configuration", oidcApi.Discovery)
+		wellKnown.GET("/jwks.json", oidcApi.JWKS)
 	}
 }
@@ -63,8 +63,11 @@ func Setup(r *gin.Engine) {
 		RouterGroupApp.OAuthRouter.InitOAuthRouter(apiGroup)
 		RouterGroupApp.UserRouter.InitUserRouter(apiGroup)
 		RouterGroupApp.ManageRouter.InitManageRouter(apiGroup)
+		RouterGroupApp.AdminRouter.InitAdminRouter(apiGroup)
 	}
 
+	RouterGroupApp.OAuthRouter.InitWellKnownRouter(&r.RouterGroup)
+
 	// 健康检查
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{"status": "ok"})
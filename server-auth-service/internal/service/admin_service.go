@@ -0,0 +1,231 @@
+package service
+
+import (
+	database "auth-service/internal/model/database"
+	"auth-service/internal/model/response"
+	"auth-service/pkg/global"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type AdminService struct{}
+
+// MergeUsers 合并账号：把源账号的第三方绑定、通行密钥、设备、应用权限和日志转移到目标账号（同一平台的绑定冲突时保留目标账号的）
+// 目标账号缺少邮箱或密码时沿用源账号的，源账号标记为注销；源账号的 Token 全部吊销，设备需重新登录
+func (s *AdminService) MergeUsers(c *gin.Context, operatorUUID, sourceUUID, targetUUID uuid.UUID) (*response.UserMergeResult, error) {
+	if sourceUUID == targetUUID {
+		return nil, errors.New("不能合并同一个账号")
+	}
+
+	var source, target database.SSOUser
+	if err := global.DB.Where("uuid = ?", sourceUUID).First(&source).Error; err != nil {
+		return nil, errors.New("源账号不存在")
+	}
+	if err := global.DB.Where("uuid = ?", targetUUID).First(&target).Error; err != nil {
+		return nil, errors.New("目标账号不存在")
+	}
+	if source.Status == 3 {
+		return nil, errors.New("源账号已注销")
+	}
+	if target.Status != 1 {
+		return nil, errors.New("目标账号已被禁用或注销")
+	}
+	if source.IsSuperAdmin {
+		return nil, errors.New("不能合并超级管理员账号")
+	}
+
+	// 合并后需要下线的源账号设备
+	var sourceDevices []database.SSODevice
+	if err := global.DB.Where("user_uuid = ?", sourceUUID).Find(&sourceDevices).Error; err != nil {
+		return nil, fmt.Errorf("查询源账号设备失败: %w", err)
+	}
+
+	result := &response.UserMergeResult{}
+	err := global.DB.Transaction(func(tx *gorm.DB) error {
+		// 1. 第三方绑定：同一平台冲突时保留目标账号的绑定
+		bindings, err := s.mergeBindings(tx, sourceUUID, targetUUID)
+		if err != nil {
+			return err
+		}
+		result.Bindings = bindings
+
+		// 通行密钥：认证器中保存的用户句柄仍是源账号UUID，先补全句柄再转移
+		res := tx.Unscoped().Model(&database.SSOWebAuthnCredential{}).
+			Where("user_uuid = ? AND (user_handle IS NULL OR LENGTH(user_handle) = 0)", sourceUUID).
+			Update("user_handle", sourceUUID.Bytes())
+		if res.Error != nil {
+			return fmt.Errorf("转移通行密钥失败: %w", res.Error)
+		}
+		res = tx.Unscoped().Model(&database.SSOWebAuthnCredential{}).
+			Where("user_uuid = ?", sourceUUID).Update("user_uuid", targetUUID)
+		if res.Error != nil {
+			return fmt.Errorf("转移通行密钥失败: %w", res.Error)
+		}
+		result.Passkeys = res.RowsAffected
+
+		// 2. 两步验证只保留目标账号自己的配置
+		if err := tx.Unscoped().Where("user_uuid = ?", sourceUUID).Delete(&database.SSOUserMFA{}).Error; err != nil {
+			return fmt.Errorf("清理两步验证失败: %w", err)
+		}
+		if err := tx.Where("user_uuid = ?", sourceUUID).Delete(&database.SSOMFARecoveryCode{}).Error; err != nil {
+			return fmt.Errorf("清理恢复码失败: %w", err)
+		}
+
+		// 3. 设备：同一应用下设备ID冲突时保留目标账号的记录，其余转移并置为离线
+		devices, err := s.mergeDevices(tx, sourceUUID, targetUUID)
+		if err != nil {
+			return err
+		}
+		result.Devices = devices
+
+		// 4. 应用权限：同一应用冲突时保留目标账号的记录
+		relations, err := s.mergeAppRelations(tx, sourceUUID, targetUUID)
+		if err != nil {
+			return err
+		}
+		result.AppRelations = relations
+
+		// 5. 登录日志
+		res = tx.Model(&database.SSOLoginLog{}).
+			Where("user_uuid = ?", sourceUUID).Update("user_uuid", targetUUID)
+		if res.Error != nil {
+			return fmt.Errorf("转移日志失败: %w", res.Error)
+		}
+		result.Logs = res.RowsAffected
+
+		// 6. 源账号注销（先释放邮箱唯一索引），目标账号补全登录方式
+		if err := tx.Model(&source).Updates(map[string]interface{}{"email": nil, "status": 3}).Error; err != nil {
+			return fmt.Errorf("注销源账号失败: %w", err)
+		}
+		fill := map[string]interface{}{}
+		if target.Email == nil && source.Email != nil {
+			fill["email"] = *source.Email
+		}
+		if target.PasswordHash == nil && source.PasswordHash != nil {
+			fill["password_hash"] = *source.PasswordHash
+		}
+		if len(fill) > 0 {
+			if err := tx.Model(&target).Updates(fill).Error; err != nil {
+				return fmt.Errorf("更新目标账号失败: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 源账号已签发的 Token 全部失效：吊销 RefreshToken 并拉黑设备
+	for _, d := range sourceDevices {
+		revokeRefreshFamily(sourceUUID, d.AppID, d.DeviceID)
+		global.Redis.Set("device:blacklist:"+d.DeviceID, "1", 7*24*time.Hour)
+	}
+
+	global.Log.Info("合并账号",
+		zap.String("operator", operatorUUID.String()),
+		zap.String("source", sourceUUID.String()),
+		zap.String("target", targetUUID.String()),
+	)
+	authService := &AuthService{}
+	authService.LogActionWithContext(c, targetUUID, 0, "user_merge", "", "合并账号 "+sourceUUID.String(), 1)
+
+	return result, nil
+}
+
+func (s *AdminService) mergeBindings(tx *gorm.DB, sourceUUID, targetUUID uuid.UUID) (int64, error) {
+	var targetBindings, sourceBindings []database.SSOOAuthBinding
+	if err := tx.Unscoped().Where("user_uuid = ?", targetUUID).Find(&targetBindings).Error; err != nil {
+		return 0, err
+	}
+	if err := tx.Unscoped().Where("user_uuid = ?", sourceUUID).Find(&sourceBindings).Error; err != nil {
+		return 0, err
+	}
+
+	exists := make(map[string]bool, len(targetBindings))
+	for _, b := range targetBindings {
+		exists[b.Provider] = true
+	}
+
+	var moved int64
+	for _, b := range sourceBindings {
+		if exists[b.Provider] {
+			if err := tx.Unscoped().Delete(&b).Error; err != nil {
+				return 0, fmt.Errorf("清理冲突第三方绑定失败: %w", err)
+			}
+			continue
+		}
+		if err := tx.Unscoped().Model(&b).Update("user_uuid", targetUUID).Error; err != nil {
+			return 0, fmt.Errorf("转移第三方绑定失败: %w", err)
+		}
+		exists[b.Provider] = true
+		moved++
+	}
+	return moved, nil
+}
+
+func (s *AdminService) mergeDevices(tx *gorm.DB, sourceUUID, targetUUID uuid.UUID) (int64, error) {
+	var targetDevices, sourceDevices []database.SSODevice
+	if err := tx.Unscoped().Where("user_uuid = ?", targetUUID).Find(&targetDevices).Error; err != nil {
+		return 0, err
+	}
+	if err := tx.Unscoped().Where("user_uuid = ?", sourceUUID).Find(&sourceDevices).Error; err != nil {
+		return 0, err
+	}
+
+	exists := make(map[string]bool, len(targetDevices))
+	for _, d := range targetDevices {
+		exists[fmt.Sprintf("%d:%s", d.AppID, d.DeviceID)] = true
+	}
+
+	var moved int64
+	for _, d := range sourceDevices {
+		if exists[fmt.Sprintf("%d:%s", d.AppID, d.DeviceID)] {
+			if err := tx.Unscoped().Delete(&d).Error; err != nil {
+				return 0, fmt.Errorf("清理冲突设备失败: %w", err)
+			}
+			continue
+		}
+		err := tx.Unscoped().Model(&d).Updates(map[string]interface{}{"user_uuid": targetUUID, "status": 0}).Error
+		if err != nil {
+			return 0, fmt.Errorf("转移设备失败: %w", err)
+		}
+		moved++
+	}
+	return moved, nil
+}
+
+func (s *AdminService) mergeAppRelations(tx *gorm.DB, sourceUUID, targetUUID uuid.UUID) (int64, error) {
+	var targetRelations, sourceRelations []database.UserAppRelation
+	if err := tx.Unscoped().Where("user_uuid = ?", targetUUID).Find(&targetRelations).Error; err != nil {
+		return 0, err
+	}
+	if err := tx.Unscoped().Where("user_uuid = ?", sourceUUID).Find(&sourceRelations).Error; err != nil {
+		return 0, err
+	}
+
+	exists := make(map[uint]bool, len(targetRelations))
+	for _, r := range targetRelations {
+		exists[r.AppID] = true
+	}
+
+	var moved int64
+	for _, r := range sourceRelations {
+		if exists[r.AppID] {
+			if err := tx.Unscoped().Delete(&r).Error; err != nil {
+				return 0, fmt.Errorf("清理冲突应用权限失败: %w", err)
+			}
+			continue
+		}
+		if err := tx.Unscoped().Model(&r).Update("user_uuid", targetUUID).Error; err != nil {
+			return 0, fmt.Errorf("转移应用权限失败: %w", err)
+		}
+		moved++
+	}
+	return moved, nil
+}
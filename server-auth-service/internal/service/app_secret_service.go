@@ -0,0 +1,218 @@
+package service
+
+import (
+	"auth-service/internal/model/database"
+	"auth-service/internal/model/response"
+	"auth-service/pkg/crypto"
+	"auth-service/pkg/global"
+	"auth-service/pkg/utils"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type AppSecretService struct{}
+
+const (
+	defaultSecretGracePeriod = 24 * time.Hour
+	secretLastUsedPrecision  = time.Minute // 最近使用时间的更新粒度，避免每次认证都写库
+)
+
+// Verify 校验应用密钥：依次比对所有未过期的哈希（常量时间），命中后记录最近使用时间。
+// 尚未执行 --migrate-secrets 的应用仍按明文密钥校验
+func (s *AppSecretService) Verify(app *database.SSOApplication, secret string) bool {
+	if app == nil || secret == "" {
+		return false
+	}
+
+	var secrets []database.SSOAppSecret
+	err := global.DB.Where("app_id = ? AND (expires_at IS NULL OR expires_at > ?)", app.ID, time.Now()).
+		Find(&secrets).Error
+	if err != nil {
+		global.Log.Error("查询应用密钥失败", zap.String("app_key", app.AppKey), zap.Error(err))
+		return false
+	}
+
+	// 不提前退出，校验耗时与命中第几个密钥无关
+	var matched *database.SSOAppSecret
+	for i := range secrets {
+		if crypto.CompareClientSecret(secret, secrets[i].SecretHash) && matched == nil {
+			matched = &secrets[i]
+		}
+	}
+
+	if matched == nil {
+		if app.AppSecret != "" && crypto.CompareSecretPlain(secret, app.AppSecret) {
+			global.Log.Warn("应用仍在使用明文密钥，请执行 --migrate-secrets", zap.String("app_key", app.AppKey))
+			return true
+		}
+		return false
+	}
+
+	now := time.Now()
+	if matched.LastUsedAt == nil || now.Sub(*matched.LastUsedAt) >= secretLastUsedPrecision {
+		global.DB.Model(&database.SSOAppSecret{}).Where("id = ?", matched.ID).Update("last_used_at", now)
+	}
+	return true
+}
+
+// List 列出应用的全部密钥（含已过期的）
+func (s *AppSecretService) List(appKey string) ([]response.AppSecretInfo, error) {
+	app, err := s.findApp(appKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var secrets []database.SSOAppSecret
+	if err := global.DB.Where("app_id = ?", app.ID).Order("id DESC").Find(&secrets).Error; err != nil {
+		return nil, err
+	}
+
+	list := make([]response.AppSecretInfo, 0, len(secrets))
+	for _, secret := range secrets {
+		list = append(list, appSecretInfo(&secret))
+	}
+	return list, nil
+}
+
+// Rotate 签发新密钥，当前有效的旧密钥在宽限期后失效（gracePeriod 为空取 24h，"0" 表示立即失效）
+func (s *AppSecretService) Rotate(c *gin.Context, operatorUUID uuid.UUID, appKey, gracePeriod string) (*response.AppSecretIssued, error) {
+	grace := defaultSecretGracePeriod
+	if gracePeriod == "0" {
+		grace = 0
+	} else if gracePeriod != "" {
+		d, err := utils.ParseDuration(gracePeriod)
+		if err != nil || d < 0 {
+			return nil, errors.New("宽限期格式错误")
+		}
+		grace = d
+	}
+
+	app, err := s.findApp(appKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var issued *response.AppSecretIssued
+	err = global.DB.Transaction(func(tx *gorm.DB) error {
+		// 尚未迁移的明文密钥先转存为哈希，同样享有宽限期
+		if err := migrateAppSecret(tx, app); err != nil {
+			return err
+		}
+
+		// 旧密钥的失效时间只会提前，不会延后
+		expiresAt := time.Now().Add(grace)
+		err := tx.Model(&database.SSOAppSecret{}).
+			Where("app_id = ? AND (expires_at IS NULL OR expires_at > ?)", app.ID, expiresAt).
+			Update("expires_at", expiresAt).Error
+		if err != nil {
+			return fmt.Errorf("更新旧密钥失败: %w", err)
+		}
+
+		secret, record, err := createAppSecret(tx, app.ID)
+		if err != nil {
+			return err
+		}
+
+		issued = &response.AppSecretIssued{Secret: secret, Info: appSecretInfo(record)}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	authService := &AuthService{}
+	authService.LogActionWithContext(c, operatorUUID, app.ID, "secret_rotate", "",
+		fmt.Sprintf("签发应用密钥 %s（旧密钥宽限期 %s）", issued.Info.Hint, grace), 1)
+	return issued, nil
+}
+
+// MigratePlaintextSecrets 将应用表中的明文密钥转存为哈希并清空明文，返回迁移的应用数
+func (s *AppSecretService) MigratePlaintextSecrets() (int, error) {
+	var apps []database.SSOApplication
+	if err := global.DB.Where("app_secret <> ''").Find(&apps).Error; err != nil {
+		return 0, err
+	}
+
+	for i := range apps {
+		app := &apps[i]
+		err := global.DB.Transaction(func(tx *gorm.DB) error {
+			return migrateAppSecret(tx, app)
+		})
+		if err != nil {
+			return 0, fmt.Errorf("迁移应用 %s 的密钥失败: %w", app.AppKey, err)
+		}
+	}
+	return len(apps), nil
+}
+
+func (s *AppSecretService) findApp(appKey string) (*database.SSOApplication, error) {
+	var app database.SSOApplication
+	if err := global.DB.Where("app_key = ?", appKey).First(&app).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("应用不存在")
+		}
+		return nil, err
+	}
+	return &app, nil
+}
+
+// migrateAppSecret 将应用的明文密钥转存为哈希并清空明文（没有明文密钥时不做处理）
+func migrateAppSecret(tx *gorm.DB, app *database.SSOApplication) error {
+	if app.AppSecret == "" {
+		return nil
+	}
+	record := database.SSOAppSecret{
+		AppID:      app.ID,
+		SecretHash: crypto.HashClientSecret(app.AppSecret),
+		Hint:       secretHint(app.AppSecret),
+	}
+	if err := tx.Create(&record).Error; err != nil {
+		return fmt.Errorf("保存密钥失败: %w", err)
+	}
+	if err := tx.Model(&database.SSOApplication{}).Where("id = ?", app.ID).Update("app_secret", "").Error; err != nil {
+		return fmt.Errorf("清除明文密钥失败: %w", err)
+	}
+	app.AppSecret = ""
+	return nil
+}
+
+// createAppSecret 生成并保存新密钥，返回明文
+func createAppSecret(tx *gorm.DB, appID uint) (string, *database.SSOAppSecret, error) {
+	secret, err := crypto.GenerateClientSecret()
+	if err != nil {
+		return "", nil, fmt.Errorf("生成密钥失败: %w", err)
+	}
+	record := &database.SSOAppSecret{
+		AppID:      appID,
+		SecretHash: crypto.HashClientSecret(secret),
+		Hint:       secretHint(secret),
+	}
+	if err := tx.Create(record).Error; err != nil {
+		return "", nil, fmt.Errorf("保存密钥失败: %w", err)
+	}
+	return secret, record, nil
+}
+
+func secretHint(secret string) string {
+	if len(secret) <= 4 {
+		return "****"
+	}
+	return "****" + secret[len(secret)-4:]
+}
+
+func appSecretInfo(secret *database.SSOAppSecret) response.AppSecretInfo {
+	return response.AppSecretInfo{
+		ID:         secret.ID,
+		Hint:       secret.Hint,
+		Active:     secret.ExpiresAt == nil || secret.ExpiresAt.After(time.Now()),
+		ExpiresAt:  secret.ExpiresAt,
+		LastUsedAt: secret.LastUsedAt,
+		CreatedAt:  secret.CreatedAt,
+	}
+}
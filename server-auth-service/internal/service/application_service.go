@@ -2,12 +2,23 @@ package service
 
 import (
 	"auth-service/internal/model/database"
+	"auth-service/internal/model/request"
 	"auth-service/internal/model/response"
 	"auth-service/pkg/core"
 	"auth-service/pkg/global"
+	"auth-service/pkg/utils"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 type ApplicationService struct{}
@@ -72,3 +83,584 @@ func (s *ApplicationService) GetPublicApplications() ([]response.PublicApplicati
 
 	return result, nil
 }
+
+// appOriginsTTL 应用CORS白名单的缓存时长。本实例修改应用后立即失效，其他实例最迟在此时长后生效
+const appOriginsTTL = 30 * time.Second
+
+// appOrigins 已启用应用的CORS白名单缓存
+var appOrigins struct {
+	sync.RWMutex
+	origins  map[string]bool
+	loadedAt time.Time
+}
+
+// IsOriginAllowed 判断来源是否在某个已启用应用的CORS白名单中
+func (s *ApplicationService) IsOriginAllowed(origin string) bool {
+	normalized, err := utils.NormalizeOrigin(origin)
+	if err != nil {
+		return false
+	}
+
+	appOrigins.RLock()
+	origins, fresh := appOrigins.origins, time.Since(appOrigins.loadedAt) < appOriginsTTL
+	appOrigins.RUnlock()
+	if !fresh {
+		origins = loadAppOrigins()
+	}
+	return origins[normalized]
+}
+
+// loadAppOrigins 从数据库重新加载CORS白名单，失败时沿用旧缓存
+func loadAppOrigins() map[string]bool {
+	var apps []database.SSOApplication
+	err := global.DB.Select("allowed_origins").Where("status = 1 AND allowed_origins <> ''").Find(&apps).Error
+
+	appOrigins.Lock()
+	defer appOrigins.Unlock()
+	appOrigins.loadedAt = time.Now()
+	if err != nil {
+		global.Log.Error("加载应用CORS白名单失败", zap.Error(err))
+		return appOrigins.origins
+	}
+
+	origins := make(map[string]bool)
+	for _, app := range apps {
+		for _, origin := range splitList(app.AllowedOrigins) {
+			if normalized, err := utils.NormalizeOrigin(origin); err == nil {
+				origins[normalized] = true
+			}
+		}
+	}
+	appOrigins.origins = origins
+	return origins
+}
+
+// invalidateAppOrigins 应用变更后清除CORS白名单缓存
+func invalidateAppOrigins() {
+	appOrigins.Lock()
+	appOrigins.loadedAt = time.Time{}
+	appOrigins.Unlock()
+}
+
+var appKeyPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,49}$`)
+
+// ListApplications 获取全部应用（含已禁用的），附带活跃用户数和在线设备数（管理员）
+func (s *ApplicationService) ListApplications() ([]response.ApplicationDetail, error) {
+	var apps []database.SSOApplication
+	if err := global.DB.Order("id ASC").Find(&apps).Error; err != nil {
+		return nil, fmt.Errorf("查询应用列表失败: %w", err)
+	}
+
+	var stats []struct {
+		AppID   uint
+		Users   int64
+		Devices int64
+	}
+	err := global.DB.Model(&database.SSODevice{}).
+		Select("app_id, COUNT(DISTINCT user_uuid) AS users, COUNT(*) AS devices").
+		Where("status = 1").
+		Group("app_id").
+		Scan(&stats).Error
+	if err != nil {
+		return nil, fmt.Errorf("统计应用设备失败: %w", err)
+	}
+	statMap := make(map[uint]int, len(stats))
+	for i, stat := range stats {
+		statMap[stat.AppID] = i
+	}
+
+	result := make([]response.ApplicationDetail, len(apps))
+	for i := range apps {
+		result[i] = applicationDetail(&apps[i])
+		if j, ok := statMap[apps[i].ID]; ok {
+			result[i].ActiveUsers = stats[j].Users
+			result[i].ActiveDevices = stats[j].Devices
+		}
+	}
+	return result, nil
+}
+
+// GetApplication 获取应用详情（管理员）
+func (s *ApplicationService) GetApplication(appKey string) (*response.ApplicationDetail, error) {
+	app, err := s.findApplication(appKey)
+	if err != nil {
+		return nil, err
+	}
+	info := applicationDetail(app)
+	if err := countAppMembers(app.ID, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// CreateApplication 创建应用并签发首个密钥（管理员）
+func (s *ApplicationService) CreateApplication(c *gin.Context, operatorUUID uuid.UUID, req request.CreateApplicationRequest) (*response.ApplicationCreated, error) {
+	appKey := strings.TrimSpace(req.AppKey)
+	if !appKeyPattern.MatchString(appKey) {
+		return nil, errors.New("应用标识只能包含小写字母、数字和连字符，长度2-50")
+	}
+
+	var count int64
+	global.DB.Unscoped().Model(&database.SSOApplication{}).Where("app_key = ?", appKey).Count(&count)
+	if count > 0 {
+		return nil, errors.New("应用标识已被使用（含已删除的应用）")
+	}
+
+	app := database.SSOApplication{AppKey: appKey, Status: 1}
+	if err := applyApplicationRequest(&app, &req.ApplicationRequest); err != nil {
+		return nil, err
+	}
+
+	var secret string
+	err := global.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&app).Error; err != nil {
+			return fmt.Errorf("创建应用失败: %w", err)
+		}
+		// 零值字段会被 gorm 默认值覆盖，显式写回
+		err := tx.Model(&app).Select("is_public", "require_mfa", "require_consent", "public_client").Updates(map[string]interface{}{
+			"is_public":       app.IsPublic,
+			"require_mfa":     app.RequireMFA,
+			"require_consent": app.RequireConsent,
+			"public_client":   app.PublicClient,
+		}).Error
+		if err != nil {
+			return fmt.Errorf("创建应用失败: %w", err)
+		}
+		secret, _, err = createAppSecret(tx, app.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	invalidateAppOrigins()
+	authService := &AuthService{}
+	authService.LogActionWithContext(c, operatorUUID, app.ID, "app_create", "", "创建应用 "+app.AppKey, 1)
+
+	return &response.ApplicationCreated{Application: applicationDetail(&app), Secret: secret}, nil
+}
+
+// UpdateApplication 更新应用配置（管理员），只修改请求中传入的字段，立即生效
+func (s *ApplicationService) UpdateApplication(c *gin.Context, operatorUUID uuid.UUID, appKey string, req request.UpdateApplicationRequest) (*response.ApplicationDetail, error) {
+	app, err := s.findApplication(appKey)
+	if err != nil {
+		return nil, err
+	}
+	merged := mergeApplicationUpdate(app, &req)
+	if err := applyApplicationRequest(app, &merged); err != nil {
+		return nil, err
+	}
+
+	err = global.DB.Model(app).Select(
+		"app_name", "redirect_uris", "allowed_origins", "max_devices", "is_public",
+		"icon", "require_mfa", "scopes", "require_consent", "public_client",
+	).Updates(app).Error
+	if err != nil {
+		return nil, fmt.Errorf("更新应用失败: %w", err)
+	}
+
+	invalidateAppOrigins()
+	authService := &AuthService{}
+	authService.LogActionWithContext(c, operatorUUID, app.ID, "app_update", "", "更新应用 "+app.AppKey, 1)
+
+	info := applicationDetail(app)
+	if err := countAppMembers(app.ID, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// SetApplicationStatus 启用或禁用应用（管理员）。禁用后新登录和刷新Token会被拒绝，在线设备全部下线
+// currentAppKey 为管理员当前会话所属应用，禁止禁用自己正在使用的应用
+func (s *ApplicationService) SetApplicationStatus(c *gin.Context, operatorUUID uuid.UUID, appKey, currentAppKey string, enabled bool) error {
+	app, err := s.findApplication(appKey)
+	if err != nil {
+		return err
+	}
+
+	status, action, message := 1, "app_enable", "启用应用 "+app.AppKey
+	if !enabled {
+		if app.AppKey == currentAppKey {
+			return errors.New("不能禁用当前登录所用的应用")
+		}
+		status, action, message = 0, "app_disable", "禁用应用 "+app.AppKey
+	}
+	if app.Status == status {
+		return nil
+	}
+
+	if err := global.DB.Model(app).Update("status", status).Error; err != nil {
+		return fmt.Errorf("更新应用状态失败: %w", err)
+	}
+	if !enabled {
+		kickAppDevices(app, "app_disabled", "应用已被禁用")
+	}
+
+	invalidateAppOrigins()
+	authService := &AuthService{}
+	authService.LogActionWithContext(c, operatorUUID, app.ID, action, "", message, 1)
+	return nil
+}
+
+// DeleteApplication 删除应用（管理员）：在线设备全部下线，密钥和用户权限一并删除，操作日志保留
+func (s *ApplicationService) DeleteApplication(c *gin.Context, operatorUUID uuid.UUID, appKey, currentAppKey string) error {
+	app, err := s.findApplication(appKey)
+	if err != nil {
+		return err
+	}
+	if app.AppKey == currentAppKey {
+		return errors.New("不能删除当前登录所用的应用")
+	}
+
+	kickAppDevices(app, "app_deleted", "应用已被删除")
+
+	err = global.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("app_id = ?", app.ID).Delete(&database.SSOAppSecret{}).Error; err != nil {
+			return fmt.Errorf("删除应用密钥失败: %w", err)
+		}
+		if err := tx.Where("app_id = ?", app.ID).Delete(&database.UserAppRelation{}).Error; err != nil {
+			return fmt.Errorf("删除应用权限失败: %w", err)
+		}
+		if err := tx.Where("app_id = ?", app.ID).Delete(&database.SSODevice{}).Error; err != nil {
+			return fmt.Errorf("删除应用设备失败: %w", err)
+		}
+		if err := tx.Delete(app).Error; err != nil {
+			return fmt.Errorf("删除应用失败: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	invalidateAppOrigins()
+	authService := &AuthService{}
+	authService.LogActionWithContext(c, operatorUUID, app.ID, "app_delete", "", "删除应用 "+app.AppKey, 1)
+	return nil
+}
+
+// GetApplicationUsers 分页获取应用的活跃用户（有在线设备的用户），按最近活跃排序（管理员）
+func (s *ApplicationService) GetApplicationUsers(appKey string, params request.AppMemberQueryParams) (*response.PageResponse, error) {
+	app, err := s.findApplication(appKey)
+	if err != nil {
+		return nil, err
+	}
+	page, pageSize := pageParams(params)
+
+	base := global.DB.Model(&database.SSODevice{}).Where("app_id = ? AND status = 1", app.ID)
+
+	var total int64
+	if err := base.Session(&gorm.Session{}).Distinct("user_uuid").Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("查询活跃用户总数失败: %w", err)
+	}
+
+	var rows []struct {
+		UserUUID     uuid.UUID
+		Devices      int64
+		LastActiveAt time.Time
+	}
+	err = base.Session(&gorm.Session{}).
+		Select("user_uuid, COUNT(*) AS devices, MAX(last_active_at) AS last_active_at").
+		Group("user_uuid").
+		Order("last_active_at DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("查询活跃用户失败: %w", err)
+	}
+
+	uuids := make([]uuid.UUID, len(rows))
+	for i, row := range rows {
+		uuids[i] = row.UserUUID
+	}
+	users, err := findUsersByUUID(uuids)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]response.AppUserInfo, len(rows))
+	for i, row := range rows {
+		list[i] = response.AppUserInfo{
+			UUID:         row.UserUUID.String(),
+			Devices:      row.Devices,
+			LastActiveAt: row.LastActiveAt,
+		}
+		if user, ok := users[row.UserUUID]; ok {
+			list[i].Email = userEmail(user)
+			list[i].Nickname = user.Nickname
+		}
+	}
+
+	return &response.PageResponse{List: list, Total: total, Page: page, PageSize: pageSize}, nil
+}
+
+// GetApplicationDevices 分页获取应用的在线设备，按最近活跃排序（管理员）
+func (s *ApplicationService) GetApplicationDevices(appKey string, params request.AppMemberQueryParams) (*response.PageResponse, error) {
+	app, err := s.findApplication(appKey)
+	if err != nil {
+		return nil, err
+	}
+	page, pageSize := pageParams(params)
+
+	query := global.DB.Model(&database.SSODevice{}).Where("app_id = ? AND status = 1", app.ID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("查询在线设备总数失败: %w", err)
+	}
+
+	var devices []database.SSODevice
+	err = query.Order("last_active_at DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&devices).Error
+	if err != nil {
+		return nil, fmt.Errorf("查询在线设备失败: %w", err)
+	}
+
+	uuids := make([]uuid.UUID, len(devices))
+	for i, device := range devices {
+		uuids[i] = device.UserUUID
+	}
+	users, err := findUsersByUUID(uuids)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]response.AppDeviceInfo, len(devices))
+	for i, device := range devices {
+		list[i] = response.AppDeviceInfo{
+			DeviceInfo: response.DeviceInfo{
+				ID:           device.ID,
+				DeviceID:     device.DeviceID,
+				DeviceName:   device.DeviceName,
+				DeviceType:   device.DeviceType,
+				IPAddress:    device.IPAddress,
+				LastActiveAt: device.LastActiveAt,
+				Status:       device.Status,
+				CreatedAt:    device.CreatedAt,
+				AppName:      app.AppName,
+				AppKey:       app.AppKey,
+			},
+			UserUUID: device.UserUUID.String(),
+		}
+		if user, ok := users[device.UserUUID]; ok {
+			list[i].Email = userEmail(user)
+		}
+	}
+
+	return &response.PageResponse{List: list, Total: total, Page: page, PageSize: pageSize}, nil
+}
+
+func (s *ApplicationService) findApplication(appKey string) (*database.SSOApplication, error) {
+	var app database.SSOApplication
+	if err := global.DB.Where("app_key = ?", appKey).First(&app).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("应用不存在")
+		}
+		return nil, err
+	}
+	return &app, nil
+}
+
+// mergeApplicationUpdate 以应用当前配置为基础，覆盖请求中传入的字段
+func mergeApplicationUpdate(app *database.SSOApplication, req *request.UpdateApplicationRequest) request.ApplicationRequest {
+	merged := request.ApplicationRequest{
+		AppName:        app.AppName,
+		RedirectURIs:   splitList(app.RedirectURIs),
+		AllowedOrigins: splitList(app.AllowedOrigins),
+		MaxDevices:     app.MaxDevices,
+		IsPublic:       app.IsPublic,
+		Icon:           app.Icon,
+		RequireMFA:     app.RequireMFA,
+		Scopes:         splitList(app.Scopes),
+		RequireConsent: app.RequireConsent,
+		PublicClient:   app.PublicClient,
+	}
+	if req.AppName != nil {
+		merged.AppName = *req.AppName
+	}
+	if req.RedirectURIs != nil {
+		merged.RedirectURIs = *req.RedirectURIs
+	}
+	if req.AllowedOrigins != nil {
+		merged.AllowedOrigins = *req.AllowedOrigins
+	}
+	if req.MaxDevices != nil {
+		merged.MaxDevices = *req.MaxDevices
+	}
+	if req.IsPublic != nil {
+		merged.IsPublic = *req.IsPublic
+	}
+	if req.Icon != nil {
+		merged.Icon = *req.Icon
+	}
+	if req.RequireMFA != nil {
+		merged.RequireMFA = *req.RequireMFA
+	}
+	if req.Scopes != nil {
+		merged.Scopes = *req.Scopes
+	}
+	if req.RequireConsent != nil {
+		merged.RequireConsent = *req.RequireConsent
+	}
+	if req.PublicClient != nil {
+		merged.PublicClient = *req.PublicClient
+	}
+	return merged
+}
+
+// applyApplicationRequest 校验配置并写入应用（回调地址、来源、授权范围）
+func applyApplicationRequest(app *database.SSOApplication, req *request.ApplicationRequest) error {
+	redirectURIs := make([]string, 0, len(req.RedirectURIs))
+	for _, uri := range req.RedirectURIs {
+		uri = strings.TrimSpace(uri)
+		if err := utils.CheckRedirectURIPattern(uri); err != nil {
+			return err
+		}
+		redirectURIs = append(redirectURIs, uri)
+	}
+
+	origins := make([]string, 0, len(req.AllowedOrigins))
+	seen := make(map[string]bool, len(req.AllowedOrigins))
+	for _, origin := range req.AllowedOrigins {
+		normalized, err := utils.NormalizeOrigin(origin)
+		if err != nil {
+			return err
+		}
+		if !seen[normalized] {
+			seen[normalized] = true
+			origins = append(origins, normalized)
+		}
+	}
+
+	scopes := req.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{"openid", "profile", "email"}
+	}
+	for _, scope := range scopes {
+		supported := false
+		for _, item := range oidcScopes {
+			if item.Scope == scope {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			return fmt.Errorf("不支持的授权范围: %s", scope)
+		}
+	}
+
+	// 更新时允许传空字符串清空图标，因此在这里而不是绑定标签里校验地址格式
+	icon := strings.TrimSpace(req.Icon)
+	if icon != "" {
+		if u, err := url.ParseRequestURI(icon); err != nil || u.Scheme == "" || u.Host == "" {
+			return errors.New("应用图标地址无效")
+		}
+	}
+
+	app.AppName = strings.TrimSpace(req.AppName)
+	app.RedirectURIs = strings.Join(redirectURIs, ",")
+	app.AllowedOrigins = strings.Join(origins, ",")
+	app.MaxDevices = req.MaxDevices
+	app.IsPublic = req.IsPublic
+	app.Icon = icon
+	app.RequireMFA = req.RequireMFA
+	app.Scopes = strings.Join(scopes, ",")
+	app.RequireConsent = req.RequireConsent
+	app.PublicClient = req.PublicClient
+	return nil
+}
+
+// kickAppDevices 下线应用的全部在线设备
+func kickAppDevices(app *database.SSOApplication, action, message string) {
+	var devices []database.SSODevice
+	if err := global.DB.Where("app_id = ? AND status = 1", app.ID).Find(&devices).Error; err != nil {
+		global.Log.Error("查询应用在线设备失败", zap.String("app_key", app.AppKey), zap.Error(err))
+		return
+	}
+
+	authService := &AuthService{}
+	for _, device := range devices {
+		if err := authService.kickDeviceInternal(device.UserUUID, device.DeviceID, app.ID, action, message); err != nil {
+			global.Log.Error("下线设备失败", zap.String("app_key", app.AppKey), zap.String("device_id", device.DeviceID), zap.Error(err))
+		}
+	}
+	global.Log.Info("应用设备已全部下线", zap.String("app_key", app.AppKey), zap.Int("count", len(devices)))
+}
+
+func countAppMembers(appID uint, info *response.ApplicationDetail) error {
+	query := global.DB.Model(&database.SSODevice{}).Where("app_id = ? AND status = 1", appID)
+	if err := query.Session(&gorm.Session{}).Count(&info.ActiveDevices).Error; err != nil {
+		return fmt.Errorf("统计应用设备失败: %w", err)
+	}
+	if err := query.Session(&gorm.Session{}).Distinct("user_uuid").Count(&info.ActiveUsers).Error; err != nil {
+		return fmt.Errorf("统计应用用户失败: %w", err)
+	}
+	return nil
+}
+
+func findUsersByUUID(uuids []uuid.UUID) (map[uuid.UUID]*database.SSOUser, error) {
+	users := make(map[uuid.UUID]*database.SSOUser, len(uuids))
+	if len(uuids) == 0 {
+		return users, nil
+	}
+	var list []database.SSOUser
+	if err := global.DB.Where("uuid IN ?", uuids).Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("查询用户失败: %w", err)
+	}
+	for i := range list {
+		users[list[i].UUID] = &list[i]
+	}
+	return users, nil
+}
+
+func userEmail(user *database.SSOUser) string {
+	if user.Email == nil {
+		return ""
+	}
+	return *user.Email
+}
+
+func pageParams(params request.AppMemberQueryParams) (int, int) {
+	page, pageSize := params.Page, params.PageSize
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	return page, pageSize
+}
+
+func applicationDetail(app *database.SSOApplication) response.ApplicationDetail {
+	return response.ApplicationDetail{
+		ID:             app.ID,
+		AppKey:         app.AppKey,
+		AppName:        app.AppName,
+		RedirectURIs:   splitList(app.RedirectURIs),
+		AllowedOrigins: splitList(app.AllowedOrigins),
+		MaxDevices:     app.MaxDevices,
+		IsPublic:       app.IsPublic,
+		Icon:           app.Icon,
+		RequireMFA:     app.RequireMFA,
+		Scopes:         splitList(app.Scopes),
+		RequireConsent: app.RequireConsent,
+		PublicClient:   app.PublicClient,
+		Status:         app.Status,
+		CreatedAt:      app.CreatedAt,
+		UpdatedAt:      app.UpdatedAt,
+	}
+}
+
+// splitList 拆分逗号分隔的配置项，忽略空项
+func splitList(value string) []string {
+	list := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
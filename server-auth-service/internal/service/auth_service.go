@@ -14,7 +14,6 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/gin-contrib/sessions"
 	"github.com/gin-gonic/gin"
 	"github.com/gofrs/uuid"
 	"go.uber.org/zap"
@@ -24,7 +23,8 @@ import (
 type AuthService struct{}
 
 var (
-	ErrDeviceNotFound = errors.New("设备不存在或已离线")
+	ErrDeviceNotFound     = errors.New("设备不存在或已离线")
+	ErrRefreshTokenReused = errors.New("refresh_token已被使用，该设备的登录已失效，请重新登录")
 )
 
 // GetAppByKey 通过app_key获取应用信息
@@ -43,18 +43,14 @@ func (s *AuthService) GetAppByKey(appKey string) (*database.SSOApplication, erro
 // Register 用户注册
 func (s *AuthService) Register(req request.RegisterRequest) error {
 	// 验证邮箱验证码
-	key := fmt.Sprintf("email_verification_code:register:%s", req.Email)
-	storedCode, err := global.Redis.Get(key).Result()
-	if err != nil {
-		return errors.New("验证码已过期或不存在")
-	}
-	if storedCode != req.VerificationCode {
-		return errors.New("验证码错误")
+	key := emailCodeKey("register", req.Email)
+	if err := s.verifyEmailCode("register", req.Email, req.VerificationCode); err != nil {
+		return err
 	}
 
 	// 检查邮箱是否已注册
 	var existUser database.SSOUser
-	err = global.DB.Where("email = ?", req.Email).First(&existUser).Error
+	err := global.DB.Where("email = ?", req.Email).First(&existUser).Error
 	if err == nil {
 		return errors.New("邮箱已被注册")
 	}
@@ -119,60 +115,77 @@ func (s *AuthService) Register(req request.RegisterRequest) error {
 	}
 
 	// 注册成功后删除验证码，防止重复使用
-	global.Redis.Del(key)
+	global.Redis.Del(key, emailCodeAttemptsKey("register", req.Email))
 
 	return nil
 }
 
 // Login 用户登录
-func (s *AuthService) Login(c *gin.Context, req request.LoginRequest) (*response.TokenResponse, error) {
-	// 获取客户端信息
-	ipAddress := c.ClientIP()
-	userAgent := c.GetHeader("User-Agent")
+// 用户已开启两步验证或应用强制两步验证时，不签发 Token，而是返回两步验证挑战
+func (s *AuthService) Login(c *gin.Context, req request.LoginRequest) (*response.TokenResponse, *response.MFAChallenge, error) {
 	// 查询用户
 	var user database.SSOUser
 	err := global.DB.Where("email = ?", req.Email).First(&user).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("邮箱或密码错误")
+			// 账号不存在同样计入失败次数，避免通过锁定与否探测账号
+			return nil, nil, s.loginFailed(c, req.Email, nil, errors.New("邮箱或密码错误"))
 		}
-		return nil, err
+		return nil, nil, err
 	}
 
 	// 根据登录方式验证
 	if req.Password != "" {
 		// 密码登录：验证密码
 		if user.PasswordHash == nil || !crypto.CheckPassword(req.Password, *user.PasswordHash) {
-			return nil, errors.New("邮箱或密码错误")
+			return nil, nil, s.loginFailed(c, req.Email, &user, errors.New("邮箱或密码错误"))
 		}
 	} else if req.VerificationCode != "" {
-		// 验证码登录：验证邮箱验证码
-		key := fmt.Sprintf("email_verification_code:login:%s", req.Email)
-		storedCode, err := global.Redis.Get(key).Result()
-		if err != nil {
-			return nil, errors.New("验证码已过期或不存在")
-		}
-		if storedCode != req.VerificationCode {
-			return nil, errors.New("验证码错误")
+		// 验证码登录：验证邮箱验证码，输错达到上限后验证码作废
+		if err := s.verifyEmailCode("login", req.Email, req.VerificationCode); err != nil {
+			if errors.Is(err, ErrEmailCodeExpired) {
+				return nil, nil, err
+			}
+			return nil, nil, s.loginFailed(c, req.Email, &user, err)
 		}
 		// 验证成功后删除验证码（一次性使用）
-		global.Redis.Del(key)
+		global.Redis.Del(emailCodeKey("login", req.Email), emailCodeAttemptsKey("login", req.Email))
 	} else {
-		return nil, errors.New("请提供密码或邮箱验证码")
+		return nil, nil, errors.New("请提供密码或邮箱验证码")
+	}
+
+	// 需要两步验证时第一步通过不清除失败计数，第二步成功后再清除，
+	// 否则每次输对密码都会重置计数，可无限次猜测动态码
+	resp, challenge, err := s.loginUser(c, &user, req)
+	if err == nil && challenge == nil {
+		s.clearLoginFailures(req.Email)
+	}
+	return resp, challenge, err
+}
+
+// loginFailed 记录登录失败，本次失败触发锁定时返回锁定错误，否则返回原错误
+func (s *AuthService) loginFailed(c *gin.Context, email string, user *database.SSOUser, err error) error {
+	if lockErr := s.recordLoginFailure(c, email, user); lockErr != nil {
+		return lockErr
 	}
+	return err
+}
 
+// loginUser 身份验证通过后的统一登录流程：检查账号与应用权限，
+// 需要两步验证时返回挑战，否则登记设备并签发 Token（密码、验证码、第三方登录共用）
+func (s *AuthService) loginUser(c *gin.Context, user *database.SSOUser, req request.LoginRequest) (*response.TokenResponse, *response.MFAChallenge, error) {
 	// 检查用户状态
 	if user.Status == 2 {
-		return nil, errors.New("账号已被禁用，请联系管理员")
+		return nil, nil, errors.New("账号已被禁用，请联系管理员")
 	}
 	if user.Status == 3 {
-		return nil, errors.New("账号已注销")
+		return nil, nil, errors.New("账号已注销")
 	}
 
 	// 查询应用
 	app, err := s.GetAppByKey(req.AppID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// 检查应用权限，不存在则自动创建
@@ -187,15 +200,106 @@ func (s *AuthService) Login(c *gin.Context, req request.LoginRequest) (*response
 				Status:   1, // 1=可访问
 			}
 			if err := global.DB.Create(&userAppRelation).Error; err != nil {
-				return nil, fmt.Errorf("创建用户应用关联失败: %w", err)
+				return nil, nil, fmt.Errorf("创建用户应用关联失败: %w", err)
 			}
 		} else {
-			return nil, err
+			return nil, nil, err
 		}
 	}
 	if userAppRelation.Status == 2 {
-		return nil, errors.New("您无权访问此应用")
+		return nil, nil, errors.New("您无权访问此应用")
+	}
+
+	// 两步验证：已开启的用户必须验证；应用强制但用户未绑定时，需先完成绑定
+	mfaService := &MFAService{}
+	mfaEnabled, err := mfaService.IsEnabled(user.UUID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if mfaEnabled || app.RequireMFA == 1 {
+		if err := mfaService.CheckLocked(user.UUID); err != nil {
+			return nil, nil, err
+		}
+		challenge, err := mfaService.CreateChallenge(user, req, !mfaEnabled)
+		if err != nil {
+			return nil, nil, fmt.Errorf("创建两步验证失败: %w", err)
+		}
+		return nil, challenge, nil
+	}
+
+	resp, err := s.completeLogin(c, user, app, req)
+	return resp, nil, err
+}
+
+// CompleteMFALogin 两步验证通过后完成登录，返回 Token、原登录请求（用于后续授权码流程）
+// 以及登录时绑定生成的恢复码
+func (s *AuthService) CompleteMFALogin(c *gin.Context, req request.MFAVerifyRequest) (*response.TokenResponse, *request.LoginRequest, []string, error) {
+	mfaService := &MFAService{}
+	pending, err := mfaService.getChallenge(req.MFAToken)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var user database.SSOUser
+	if err := global.DB.Where("uuid = ?", pending.UserUUID).First(&user).Error; err != nil {
+		return nil, nil, nil, errors.New("用户不存在")
+	}
+	if user.Status != 1 {
+		return nil, nil, nil, errors.New("账号已被禁用或注销")
+	}
+
+	// 与密码登录共用防暴力破解的计数：锁定或等待期内不再校验动态码，每次输错都计入失败
+	account := mfaLoginAccount(&user)
+	if _, err := s.CheckLoginAttempt(account, c.ClientIP()); err != nil {
+		return nil, nil, nil, err
+	}
+	if err := mfaService.CheckLocked(user.UUID); err != nil {
+		return nil, nil, nil, err
+	}
+
+	challenge, recoveryCodes, err := mfaService.VerifyChallenge(c, req)
+	if err != nil {
+		var lockedErr *customerrors.LockedError
+		if errors.Is(err, ErrMFACodeInvalid) || errors.Is(err, ErrMFAAttemptsExceeded) || errors.As(err, &lockedErr) {
+			return nil, nil, nil, s.loginFailed(c, account, &user, err)
+		}
+		return nil, nil, nil, err
+	}
+	s.clearLoginFailures(account)
+
+	app, err := s.GetAppByKey(challenge.AppID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	loginReq := request.LoginRequest{
+		AppID:       challenge.AppID,
+		RedirectURI: challenge.RedirectURI,
+		ReturnURL:   challenge.ReturnURL,
+		DeviceID:    challenge.DeviceID,
+		DeviceName:  challenge.DeviceName,
+		DeviceType:  challenge.DeviceType,
+	}
+	resp, err := s.completeLogin(c, &user, app, loginReq)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return resp, &loginReq, recoveryCodes, nil
+}
+
+// mfaLoginAccount 两步验证失败计入的账号维度：取邮箱，与密码登录的计数一致；未绑定邮箱的第三方账号取 UUID
+func mfaLoginAccount(user *database.SSOUser) string {
+	if user.Email != nil && *user.Email != "" {
+		return *user.Email
 	}
+	return user.UUID.String()
+}
+
+// completeLogin 登记设备、签发 Token 并记录登录日志
+func (s *AuthService) completeLogin(c *gin.Context, user *database.SSOUser, app *database.SSOApplication, req request.LoginRequest) (*response.TokenResponse, error) {
+	// 获取客户端信息
+	ipAddress := c.ClientIP()
+	userAgent := c.GetHeader("User-Agent")
 
 	// 生成设备ID（如果前端没提供）
 	deviceID := req.DeviceID
@@ -205,7 +309,7 @@ func (s *AuthService) Login(c *gin.Context, req request.LoginRequest) (*response
 
 	// 检查该设备是否已存在（用户+应用+设备的组合唯一）
 	var existDevice database.SSODevice
-	err = global.DB.Where("user_uuid = ? AND app_id = ? AND device_id = ?", user.UUID, app.ID, deviceID).First(&existDevice).Error
+	err := global.DB.Where("user_uuid = ? AND app_id = ? AND device_id = ?", user.UUID, app.ID, deviceID).First(&existDevice).Error
 	isNewDevice := errors.Is(err, gorm.ErrRecordNotFound)
 
 	if isNewDevice {
@@ -256,21 +360,29 @@ func (s *AuthService) Login(c *gin.Context, req request.LoginRequest) (*response
 		user.UUID,
 		req.AppID,
 		deviceID,
+		"",
 		accessTokenDuration,
 		global.Config.JWT.Issuer,
-		global.RSAPrivateKey,
+		global.SigningKeys.SigningKey(),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("生成AccessToken失败: %w", err)
 	}
 
+	familyID, jti, err := startRefreshFamily(user.UUID, app.ID, deviceID, refreshTokenDuration)
+	if err != nil {
+		return nil, err
+	}
 	refreshToken, err := jwt.CreateRefreshToken(
 		user.UUID,
 		req.AppID,
 		deviceID,
+		"",
+		familyID,
+		jti,
 		refreshTokenDuration,
 		global.Config.JWT.Issuer,
-		global.RSAPrivateKey,
+		global.SigningKeys.SigningKey(),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("生成RefreshToken失败: %w", err)
@@ -306,14 +418,17 @@ func (s *AuthService) Login(c *gin.Context, req request.LoginRequest) (*response
 }
 
 // RefreshToken 刷新Token
-func (s *AuthService) RefreshToken(req request.RefreshTokenRequest) (*response.TokenResponse, error) {
+// 每次刷新都会轮换 RefreshToken，已轮换过的旧 Token 再次使用视为泄露，吊销整个家族并踢出设备。
+// 轮换后 refreshReuseGrace 内的并发刷新仍被接受，签发沿用家族当前 jti 的 Token；
+// 旧版本签发的无家族 Token 首次刷新时开启新家族
+func (s *AuthService) RefreshToken(c *gin.Context, req request.RefreshTokenRequest) (*response.TokenResponse, error) {
 	// 0. 检查 refresh_token 是否为空
 	if req.RefreshToken == "" {
 		return nil, errors.New("refresh_token不能为空")
 	}
 
 	// 1. 先解析 RefreshToken，获取 claims
-	claims, err := jwt.ParseRefreshToken(req.RefreshToken, global.RSAPublicKey)
+	claims, err := jwt.ParseRefreshToken(req.RefreshToken, global.SigningKeys)
 	if err != nil {
 		if err == jwt.ErrTokenExpired {
 			return nil, errors.New("refresh_token已过期，请重新登录")
@@ -336,7 +451,8 @@ func (s *AuthService) RefreshToken(req request.RefreshTokenRequest) (*response.T
 	if err != nil {
 		return nil, err
 	}
-	if app.AppSecret != req.ClientSecret {
+	appSecretService := &AppSecretService{}
+	if !appSecretService.Verify(app, req.ClientSecret) {
 		return nil, errors.New("client_secret错误")
 	}
 
@@ -357,29 +473,63 @@ func (s *AuthService) RefreshToken(req request.RefreshTokenRequest) (*response.T
 		return nil, errors.New("设备已被移除")
 	}
 
+	// Token轮换：校验该 RefreshToken 是家族中最新的一个，并登记新的 jti
+	refreshTokenDuration, _ := utils.ParseDuration(global.Config.JWT.RefreshTokenExpiryTime)
+	familyID, newJTI := claims.FamilyID, newTokenID()
+	var result int
+	if familyID == "" || claims.ID == "" {
+		// 旧版本签发的 RefreshToken 不属于任何家族：接受一次并开启新家族，避免上线后所有用户被登出
+		var tokenTTL time.Duration
+		if claims.ExpiresAt != nil {
+			tokenTTL = time.Until(claims.ExpiresAt.Time)
+		}
+		result, familyID, newJTI, err = adoptLegacyRefreshToken(req.RefreshToken, user.UUID, app.ID, claims.DeviceID, tokenTTL, refreshTokenDuration)
+	} else {
+		result, newJTI, err = rotateRefreshFamily(user.UUID, app.ID, claims.DeviceID, familyID, claims.ID, newJTI, refreshTokenDuration)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("刷新Token失败: %w", err)
+	}
+	switch result {
+	case refreshReused:
+		global.Log.Warn("检测到 RefreshToken 重复使用，吊销设备登录",
+			zap.String("user_uuid", user.UUID.String()),
+			zap.String("app_id", claims.AppID),
+			zap.String("device_id", claims.DeviceID),
+			zap.String("family_id", claims.FamilyID),
+		)
+		s.KickDevice(c, user.UUID, claims.DeviceID, app.ID, "refresh_reuse", "检测到RefreshToken重复使用，已吊销该设备的登录")
+		return nil, ErrRefreshTokenReused
+	case refreshInvalid:
+		return nil, errors.New("refresh_token已失效，请重新登录")
+	}
+
 	// 生成新的AccessToken
 	accessTokenDuration, _ := utils.ParseDuration(global.Config.JWT.AccessTokenExpiryTime)
 	accessToken, err := jwt.CreateAccessToken(
 		user.UUID,
 		claims.AppID,
 		claims.DeviceID,
+		claims.Scope,
 		accessTokenDuration,
 		global.Config.JWT.Issuer,
-		global.RSAPrivateKey,
+		global.SigningKeys.SigningKey(),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("生成AccessToken失败: %w", err)
 	}
 
-	// Token轮换：生成新的RefreshToken（可选，更安全）
-	refreshTokenDuration, _ := utils.ParseDuration(global.Config.JWT.RefreshTokenExpiryTime)
+	// 生成新的RefreshToken，沿用原家族
 	newRefreshToken, err := jwt.CreateRefreshToken(
 		user.UUID,
 		claims.AppID,
 		claims.DeviceID,
+		claims.Scope,
+		familyID,
+		newJTI,
 		refreshTokenDuration,
 		global.Config.JWT.Issuer,
-		global.RSAPrivateKey,
+		global.SigningKeys.SigningKey(),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("生成RefreshToken失败: %w", err)
@@ -393,13 +543,14 @@ func (s *AuthService) RefreshToken(req request.RefreshTokenRequest) (*response.T
 		RefreshToken: newRefreshToken,
 		TokenType:    "Bearer",
 		ExpiresIn:    int(accessTokenDuration.Seconds()),
+		Scope:        claims.Scope,
 	}, nil
 }
 
 // Logout 登出
 func (s *AuthService) Logout(accessToken, ipAddress, userAgent string) error {
 	// 解析Token
-	claims, err := jwt.ParseAccessTokenIgnoreExpiry(accessToken, global.RSAPublicKey)
+	claims, err := jwt.ParseAccessTokenIgnoreExpiry(accessToken, global.SigningKeys)
 	if err != nil {
 		return errors.New("Token无效")
 	}
@@ -419,7 +570,8 @@ func (s *AuthService) Logout(accessToken, ipAddress, userAgent string) error {
 		logAppID = app.ID
 	}
 
-	// 更新设备状态（必须包含 app_id，避免误踢其他应用的同名设备）
+	// 吊销 RefreshToken，更新设备状态（必须包含 app_id，避免误踢其他应用的同名设备）
+	revokeRefreshFamily(claims.UserUUID, logAppID, claims.DeviceID)
 	global.DB.Model(&database.SSODevice{}).
 		Where("user_uuid = ? AND device_id = ? AND app_id = ?", claims.UserUUID, claims.DeviceID, logAppID).
 		Update("status", 0)
@@ -530,72 +682,6 @@ func (s *AuthService) GetUserByUUID(userUUIDStr string) (*response.UserInfo, err
 	}, nil
 }
 
-// QQLogin QQ登录
-func (s *AuthService) QQLogin(c *gin.Context, req request.QQLoginRequest, ipAddress, userAgent string) (*response.TokenResponse, error) {
-	// 获取QQ access token
-	qqService := &QQService{}
-	accessTokenResp, err := qqService.GetAccessTokenByCode(req.Code)
-	if err != nil || accessTokenResp.Openid == "" {
-		return nil, errors.New("获取QQ授权失败")
-	}
-
-	// 获取QQ用户信息
-	qqUserInfoResp, err := qqService.GetUserInfoByAccessTokenAndOpenid(accessTokenResp.AccessToken, accessTokenResp.Openid)
-	if err != nil {
-		return nil, errors.New("获取QQ用户信息失败")
-	}
-
-	// 将QQ用户信息转换为map
-	qqUserInfo := map[string]interface{}{
-		"nickname":       qqUserInfoResp.Nickname,
-		"figureurl_qq_2": qqUserInfoResp.FigureurlQQ2,
-	}
-
-	// 调用QQ登录服务
-	deviceID := req.DeviceID
-	if deviceID == "" {
-		deviceID = uuid.Must(uuid.NewV4()).String()
-	}
-
-	// 调用QQ登录服务
-	tokenResponse, err := qqService.QQLogin(
-		accessTokenResp.Openid,
-		req.AppID,
-		deviceID,
-		req.DeviceName,
-		req.DeviceType,
-		ipAddress,
-		userAgent,
-		qqUserInfo,
-	)
-	if err != nil {
-		return nil, err
-	}
-
-	// 获取用户信息
-	if tokenResponse.UserInfo == nil {
-		return nil, fmt.Errorf("用户信息不存在")
-	}
-
-	// 设置SSO session
-	session := sessions.Default(c)
-	session.Set("user_uuid", tokenResponse.UserInfo.UUID)
-	session.Set("sso_device_id", deviceID)
-	session.Set("device_name", req.DeviceName)
-	session.Set("device_type", req.DeviceType)
-	session.Set("user_agent", userAgent)
-	session.Set("ip_address", ipAddress)
-	session.Set("logged_in", true)
-
-	// 保存session
-	if err := session.Save(); err != nil {
-		global.Log.Error("保存session失败", zap.Error(err))
-		return nil, fmt.Errorf("保存session失败: %w", err)
-	}
-
-	return tokenResponse, nil
-}
-
 // SendEmailVerificationCode 发送邮箱验证码
 func (s *AuthService) SendEmailVerificationCode(email, scene string) error {
 	// 检查发送冷却时间（60秒）
@@ -616,11 +702,12 @@ func (s *AuthService) SendEmailVerificationCode(email, scene string) error {
 	verificationCode := utils.GenerateVerificationCode(6)
 	expireTime := 5 * time.Minute
 
-	// 存储到Redis，key格式：email_verification_code:{scene}:{email}
-	key := fmt.Sprintf("email_verification_code:%s:%s", scene, email)
+	// 存储到Redis，key格式：email_verification_code:{scene}:{email}，新验证码重新计算输错次数
+	key := emailCodeKey(scene, email)
 	if err := global.Redis.Set(key, verificationCode, expireTime).Err(); err != nil {
 		return fmt.Errorf("存储验证码失败: %w", err)
 	}
+	global.Redis.Del(emailCodeAttemptsKey(scene, email))
 
 	// 发送邮件
 	subject := "您的邮箱验证码"
@@ -660,16 +747,10 @@ func (s *AuthService) SendEmailVerificationCode(email, scene string) error {
 
 // ForgotPassword 忘记密码
 func (s *AuthService) ForgotPassword(req request.ForgotPasswordRequest) error {
-	// 从Redis获取验证码
-	key := fmt.Sprintf("email_verification_code:forgot_password:%s", req.Email)
-	storedCode, err := global.Redis.Get(key).Result()
-	if err != nil {
-		return errors.New("验证码已过期或不存在")
-	}
-
-	// 验证验证码
-	if storedCode != req.VerificationCode {
-		return errors.New("验证码错误")
+	// 验证验证码，输错达到上限后验证码作废
+	key := emailCodeKey("forgot_password", req.Email)
+	if err := s.verifyEmailCode("forgot_password", req.Email, req.VerificationCode); err != nil {
+		return err
 	}
 
 	// 查询用户
@@ -693,34 +774,43 @@ func (s *AuthService) ForgotPassword(req request.ForgotPasswordRequest) error {
 	}
 
 	// 删除验证码
-	global.Redis.Del(key)
+	global.Redis.Del(key, emailCodeAttemptsKey("forgot_password", req.Email))
 
 	return nil
 }
 
-// GenerateTokensForUser 为已登录用户生成新的 Token（用于 SSO 静默登录）
+// GenerateTokensForUser 为已认证用户生成新的 Token（用于 SSO 静默登录、通行密钥登录）
 func (s *AuthService) GenerateTokensForUser(c *gin.Context, userUUIDStr, appID, deviceID string) (*response.TokenResponse, error) {
+	user, app, deviceID, err := s.prepareSessionDevice(c, userUUIDStr, appID, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	return s.issueTokens(user, app, deviceID, "")
+}
+
+// prepareSessionDevice 检查用户及应用权限，并登记（或恢复）SSO 会话对应的设备，返回用户、应用和实际使用的设备 ID
+func (s *AuthService) prepareSessionDevice(c *gin.Context, userUUIDStr, appID, deviceID string) (*database.SSOUser, *database.SSOApplication, string, error) {
 	// 解析 UUID
 	userUUID, err := uuid.FromString(userUUIDStr)
 	if err != nil {
-		return nil, errors.New("无效的用户 UUID")
+		return nil, nil, "", errors.New("无效的用户 UUID")
 	}
 
 	// 查询用户
 	var user database.SSOUser
 	if err := global.DB.Where("uuid = ?", userUUID).First(&user).Error; err != nil {
-		return nil, errors.New("用户不存在")
+		return nil, nil, "", errors.New("用户不存在")
 	}
 
 	// 检查用户状态
 	if user.Status != 1 {
-		return nil, errors.New("用户已被禁用或注销")
+		return nil, nil, "", errors.New("用户已被禁用或注销")
 	}
 
 	// 查询应用
 	app, err := s.GetAppByKey(appID)
 	if err != nil {
-		return nil, err
+		return nil, nil, "", err
 	}
 
 	// 检查应用权限，不存在则自动创建
@@ -735,14 +825,14 @@ func (s *AuthService) GenerateTokensForUser(c *gin.Context, userUUIDStr, appID,
 				Status:   1, // 1=可访问
 			}
 			if err := global.DB.Create(&userAppRelation).Error; err != nil {
-				return nil, fmt.Errorf("创建用户应用关联失败: %w", err)
+				return nil, nil, "", fmt.Errorf("创建用户应用关联失败: %w", err)
 			}
 		} else {
-			return nil, err
+			return nil, nil, "", err
 		}
 	}
 	if userAppRelation.Status == 2 {
-		return nil, errors.New("您无权访问此应用")
+		return nil, nil, "", errors.New("您无权访问此应用")
 	}
 
 	// 使用传入的 device_id（如果为空，生成新的）
@@ -759,7 +849,7 @@ func (s *AuthService) GenerateTokensForUser(c *gin.Context, userUUIDStr, appID,
 		// 新设备，检查设备数量限制并自动踢出最早的设备
 		err = s.handleDeviceLimit(user.UUID, app.ID, app.MaxDevices)
 		if err != nil {
-			return nil, fmt.Errorf("处理设备限制失败: %w", err)
+			return nil, nil, "", fmt.Errorf("处理设备限制失败: %w", err)
 		}
 
 		sessionDeviceName := "SSO 设备"
@@ -794,29 +884,41 @@ func (s *AuthService) GenerateTokensForUser(c *gin.Context, userUUIDStr, appID,
 		})
 	}
 
-	// 生成 Token
+	return &user, app, deviceID, nil
+}
+
+// issueTokens 为已登记的设备签发 AccessToken 和 RefreshToken，并开启新的 RefreshToken 家族
+func (s *AuthService) issueTokens(user *database.SSOUser, app *database.SSOApplication, deviceID, scope string) (*response.TokenResponse, error) {
 	accessTokenDuration, _ := utils.ParseDuration(global.Config.JWT.AccessTokenExpiryTime)
 	refreshTokenDuration, _ := utils.ParseDuration(global.Config.JWT.RefreshTokenExpiryTime)
 
 	accessToken, err := jwt.CreateAccessToken(
 		user.UUID,
-		appID,
+		app.AppKey,
 		deviceID,
+		scope,
 		accessTokenDuration,
 		global.Config.JWT.Issuer,
-		global.RSAPrivateKey,
+		global.SigningKeys.SigningKey(),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("生成 AccessToken 失败: %w", err)
 	}
 
+	familyID, jti, err := startRefreshFamily(user.UUID, app.ID, deviceID, refreshTokenDuration)
+	if err != nil {
+		return nil, err
+	}
 	refreshToken, err := jwt.CreateRefreshToken(
 		user.UUID,
-		appID,
+		app.AppKey,
 		deviceID,
+		scope,
+		familyID,
+		jti,
 		refreshTokenDuration,
 		global.Config.JWT.Issuer,
-		global.RSAPrivateKey,
+		global.SigningKeys.SigningKey(),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("生成 RefreshToken 失败: %w", err)
@@ -827,6 +929,15 @@ func (s *AuthService) GenerateTokensForUser(c *gin.Context, userUUIDStr, appID,
 		RefreshToken: refreshToken,
 		TokenType:    "Bearer",
 		ExpiresIn:    int(accessTokenDuration.Seconds()),
+		Scope:        scope,
+		UserInfo: &response.UserInfo{
+			UUID:      user.UUID.String(),
+			Nickname:  user.Nickname,
+			Avatar:    user.Avatar,
+			Email:     user.Email,
+			Address:   user.Address,
+			Signature: user.Signature,
+		},
 	}, nil
 }
 
@@ -873,7 +984,8 @@ func (s *AuthService) KickDevice(c *gin.Context, userUUID uuid.UUID, deviceID st
 	deviceBlacklistKey := "device:blacklist:" + deviceID
 	global.Redis.Set(deviceBlacklistKey, "1", 7*24*time.Hour) // 7天后自动过期
 
-	// 2. 更新设备状态（必须包含 app_id，避免误踢其他应用的同名设备）
+	// 2. 吊销 RefreshToken，并更新设备状态（必须包含 app_id，避免误踢其他应用的同名设备）
+	revokeRefreshFamily(userUUID, appID, deviceID)
 	result := global.DB.Model(&database.SSODevice{}).
 		Where("user_uuid = ? AND device_id = ? AND app_id = ?", userUUID, deviceID, appID).
 		Update("status", 0)
@@ -897,7 +1009,8 @@ func (s *AuthService) kickDeviceInternal(userUUID uuid.UUID, deviceID string, ap
 	deviceBlacklistKey := "device:blacklist:" + deviceID
 	global.Redis.Set(deviceBlacklistKey, "1", 7*24*time.Hour) // 7天后自动过期
 
-	// 2. 更新设备状态（必须包含 app_id，避免误踢其他应用的同名设备）
+	// 2. 吊销 RefreshToken，并更新设备状态（必须包含 app_id，避免误踢其他应用的同名设备）
+	revokeRefreshFamily(userUUID, appID, deviceID)
 	result := global.DB.Model(&database.SSODevice{}).
 		Where("user_uuid = ? AND device_id = ? AND app_id = ?", userUUID, deviceID, appID).
 		Update("status", 0)
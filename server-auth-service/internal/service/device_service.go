@@ -5,7 +5,6 @@ import (
 	"auth-service/internal/model/response"
 	"auth-service/pkg/global"
 	"errors"
-	"fmt"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gofrs/uuid"
@@ -60,9 +59,8 @@ func (s *DeviceService) KickDevice(c *gin.Context, userUUID uuid.UUID, deviceID,
 		return errors.New("设备不存在或无权操作")
 	}
 
-	// 将该设备的refresh_token从Redis删除（基于 UUID）
-	refreshTokenKey := fmt.Sprintf("refresh_token:%s:%s", userUUID.String(), deviceID)
-	global.Redis.Del(refreshTokenKey)
+	// 吊销该设备的 RefreshToken
+	revokeRefreshFamily(userUUID, device.AppID, deviceID)
 
 	// 将设备标记为黑名单
 	deviceBlacklistKey := "device:blacklist:" + deviceID
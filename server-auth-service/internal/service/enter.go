@@ -1,10 +1,15 @@
 package service
 
 type ServiceGroup struct {
+	AdminService
+	AppSecretService
 	AuthService
 	DeviceService
 	ManageService
-	QQService
+	MFAService
+	WebAuthnService
+	OAuthService
+	OIDCService
 	ApplicationService
 }
 
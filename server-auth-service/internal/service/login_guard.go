@@ -0,0 +1,256 @@
+package service
+
+import (
+	"auth-service/internal/model/database"
+	customerrors "auth-service/pkg/errors"
+	"auth-service/pkg/global"
+	"auth-service/pkg/utils"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrCaptchaRequired    = errors.New("登录失败次数过多，请输入图片验证码")
+	ErrEmailCodeExpired   = errors.New("验证码已过期或不存在")
+	ErrEmailCodeInvalid   = errors.New("验证码错误")
+	ErrEmailCodeExhausted = errors.New("验证码错误次数过多，请重新获取")
+)
+
+// loginGuardSettings 生效的防暴力破解参数（配置为空时取默认值）
+type loginGuardSettings struct {
+	window          time.Duration
+	captchaAfter    int
+	delayAfter      int
+	maxDelay        time.Duration
+	accountLimit    int
+	ipLimit         int
+	subnetLimit     int
+	lockDuration    time.Duration
+	codeMaxAttempts int
+}
+
+func loginGuardConfig() loginGuardSettings {
+	conf := global.Config.LoginGuard
+	orInt := func(v, def int) int {
+		if v > 0 {
+			return v
+		}
+		return def
+	}
+	orDuration := func(v string, def time.Duration) time.Duration {
+		if d, err := utils.ParseDuration(v); err == nil && d > 0 {
+			return d
+		}
+		return def
+	}
+	return loginGuardSettings{
+		window:          orDuration(conf.Window, 15*time.Minute),
+		captchaAfter:    orInt(conf.CaptchaAfter, 3),
+		delayAfter:      orInt(conf.DelayAfter, 3),
+		maxDelay:        orDuration(conf.MaxDelay, 30*time.Second),
+		accountLimit:    orInt(conf.AccountLimit, 10),
+		ipLimit:         orInt(conf.IPLimit, 30),
+		subnetLimit:     orInt(conf.SubnetLimit, 100),
+		lockDuration:    orDuration(conf.LockDuration, 15*time.Minute),
+		codeMaxAttempts: orInt(conf.CodeMaxAttempts, 5),
+	}
+}
+
+// loginSubject 失败次数的统计维度：账号、IP、网段
+type loginSubject struct {
+	kind  string // account/ip/subnet
+	id    string
+	limit int
+	label string // 日志中的描述
+}
+
+func loginSubjects(email, ip string, cfg loginGuardSettings) []loginSubject {
+	subjects := []loginSubject{
+		{kind: "account", id: strings.ToLower(email), limit: cfg.accountLimit, label: "账号"},
+		{kind: "ip", id: ip, limit: cfg.ipLimit, label: "IP " + ip},
+	}
+	if subnet := ipSubnet(ip); subnet != "" {
+		subjects = append(subjects, loginSubject{kind: "subnet", id: subnet, limit: cfg.subnetLimit, label: "网段 " + subnet})
+	}
+	return subjects
+}
+
+// ipSubnet 返回 IP 所在网段：IPv4 取 /24，IPv6 取 /64
+func ipSubnet(ip string) string {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return ""
+	}
+	if v4 := parsed.To4(); v4 != nil {
+		return (&net.IPNet{IP: v4.Mask(net.CIDRMask(24, 32)), Mask: net.CIDRMask(24, 32)}).String()
+	}
+	return (&net.IPNet{IP: parsed.Mask(net.CIDRMask(64, 128)), Mask: net.CIDRMask(64, 128)}).String()
+}
+
+func loginFailKey(subject loginSubject) string {
+	return fmt.Sprintf("login_fail:%s:%s", subject.kind, subject.id)
+}
+
+func loginLockKey(subject loginSubject) string {
+	return fmt.Sprintf("login_lock:%s:%s", subject.kind, subject.id)
+}
+
+func loginDelayKey(email string) string {
+	return fmt.Sprintf("login_delay:%s", strings.ToLower(email))
+}
+
+// countLoginFailures 统计滑动窗口内的失败次数（有序集合，score 为失败时间毫秒数）
+func countLoginFailures(subject loginSubject, window time.Duration) int64 {
+	key := loginFailKey(subject)
+	min := strconv.FormatInt(time.Now().Add(-window).UnixNano()/int64(time.Millisecond), 10)
+	var card *redis.IntCmd
+	_, err := global.Redis.Pipelined(func(pipe redis.Pipeliner) error {
+		pipe.ZRemRangeByScore(key, "-inf", "("+min)
+		card = pipe.ZCard(key)
+		return nil
+	})
+	if err != nil {
+		global.Log.Warn("统计登录失败次数失败", zap.String("key", key), zap.Error(err))
+		return 0
+	}
+	return card.Val()
+}
+
+// addLoginFailure 记录一次失败并返回窗口内的失败次数
+func addLoginFailure(subject loginSubject, window time.Duration) int64 {
+	key := loginFailKey(subject)
+	now := time.Now()
+	nowMs := now.UnixNano() / int64(time.Millisecond)
+	min := strconv.FormatInt(now.Add(-window).UnixNano()/int64(time.Millisecond), 10)
+	var card *redis.IntCmd
+	_, err := global.Redis.TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(key, redis.Z{Score: float64(nowMs), Member: strconv.FormatInt(now.UnixNano(), 10)})
+		pipe.ZRemRangeByScore(key, "-inf", "("+min)
+		card = pipe.ZCard(key)
+		pipe.Expire(key, window)
+		return nil
+	})
+	if err != nil {
+		global.Log.Warn("记录登录失败次数失败", zap.String("key", key), zap.Error(err))
+		return 0
+	}
+	return card.Val()
+}
+
+// CheckLoginAttempt 登录前检查：账号、IP 或网段处于锁定期时返回 LockedError，
+// 账号处于递增等待期时返回 CooldownError；返回值表示是否需要图片验证码
+func (s *AuthService) CheckLoginAttempt(email, ip string) (bool, error) {
+	cfg := loginGuardConfig()
+	subjects := loginSubjects(email, ip, cfg)
+
+	for _, subject := range subjects {
+		if ttl, err := global.Redis.TTL(loginLockKey(subject)).Result(); err == nil && ttl > 0 {
+			return false, customerrors.NewLockedError(int(ttl.Seconds()))
+		}
+	}
+	if ttl, err := global.Redis.PTTL(loginDelayKey(email)).Result(); err == nil && ttl > 0 {
+		return false, customerrors.NewCooldownError(int((ttl + time.Second - 1) / time.Second))
+	}
+
+	// 账号或 IP 失败次数达到阈值后要求图片验证码
+	captchaRequired := countLoginFailures(subjects[0], cfg.window) >= int64(cfg.captchaAfter) ||
+		countLoginFailures(subjects[1], cfg.window) >= int64(cfg.captchaAfter)
+	return captchaRequired, nil
+}
+
+// recordLoginFailure 记录一次登录失败：达到上限的维度进入锁定期，账号失败次数超过阈值后设置递增等待。
+// user 为 nil 表示账号不存在；触发锁定时返回 LockedError，并记录到该用户的操作日志
+func (s *AuthService) recordLoginFailure(c *gin.Context, email string, user *database.SSOUser) error {
+	cfg := loginGuardConfig()
+	var lockErr error
+
+	for _, subject := range loginSubjects(email, c.ClientIP(), cfg) {
+		count := addLoginFailure(subject, cfg.window)
+
+		if subject.kind == "account" && count >= int64(cfg.delayAfter) {
+			delay := cfg.maxDelay
+			if exp := count - int64(cfg.delayAfter); exp < 16 && time.Second<<uint(exp) < delay {
+				delay = time.Second << uint(exp)
+			}
+			global.Redis.Set(loginDelayKey(email), "1", delay)
+		}
+
+		if count < int64(subject.limit) {
+			continue
+		}
+
+		// 锁定后清空计数，锁定期满重新统计
+		global.Redis.Set(loginLockKey(subject), "1", cfg.lockDuration)
+		global.Redis.Del(loginFailKey(subject))
+		lockErr = customerrors.NewLockedError(int(cfg.lockDuration.Seconds()))
+
+		message := fmt.Sprintf("%s登录失败 %d 次，锁定 %s", subject.label, count, cfg.lockDuration)
+		global.Log.Warn("登录失败次数过多，已临时锁定",
+			zap.String("subject", subject.kind),
+			zap.String("id", subject.id),
+			zap.String("email", email),
+			zap.String("ip", c.ClientIP()),
+		)
+		if user != nil {
+			s.LogActionWithContext(c, user.UUID, 0, "login_locked", "", message, 0)
+		}
+	}
+	return lockErr
+}
+
+// clearLoginFailures 登录成功后清除账号的失败计数和等待；IP 与网段计数保留，避免用自己的账号刷新计数
+func (s *AuthService) clearLoginFailures(email string) {
+	account := loginSubject{kind: "account", id: strings.ToLower(email)}
+	global.Redis.Del(loginFailKey(account), loginDelayKey(email))
+}
+
+func emailCodeKey(scene, email string) string {
+	return fmt.Sprintf("email_verification_code:%s:%s", scene, email)
+}
+
+func emailCodeAttemptsKey(scene, email string) string {
+	return fmt.Sprintf("email_verification_attempts:%s:%s", scene, email)
+}
+
+// verifyEmailCode 校验邮箱验证码，输错次数达到上限后验证码作废。校验通过后由调用方删除验证码
+// 比较前先原子递增尝试次数，并发请求也不能超过上限
+func (s *AuthService) verifyEmailCode(scene, email, code string) error {
+	key := emailCodeKey(scene, email)
+	storedCode, err := global.Redis.Get(key).Result()
+	if err != nil {
+		return ErrEmailCodeExpired
+	}
+
+	maxAttempts := int64(loginGuardConfig().codeMaxAttempts)
+	attemptsKey := emailCodeAttemptsKey(scene, email)
+	attempts, err := global.Redis.Incr(attemptsKey).Result()
+	if err != nil {
+		return fmt.Errorf("记录验证码尝试次数失败: %w", err)
+	}
+	if attempts == 1 {
+		if ttl, err := global.Redis.TTL(key).Result(); err == nil && ttl > 0 {
+			global.Redis.Expire(attemptsKey, ttl)
+		}
+	}
+	if attempts > maxAttempts {
+		global.Redis.Del(key, attemptsKey)
+		return ErrEmailCodeExhausted
+	}
+
+	if storedCode == code {
+		return nil
+	}
+	if attempts == maxAttempts {
+		global.Redis.Del(key, attemptsKey)
+		return ErrEmailCodeExhausted
+	}
+	return ErrEmailCodeInvalid
+}
@@ -109,9 +109,8 @@ func (s *ManageService) SSOLogout(c *gin.Context, userUUID uuid.UUID, deviceID s
 		return fmt.Errorf("查询设备失败: %w", err)
 	}
 
-	// 1. 删除 RefreshToken
-	refreshTokenKey := fmt.Sprintf("refresh_token:%s:%s", userUUID.String(), deviceID)
-	global.Redis.Del(refreshTokenKey)
+	// 1. 吊销 RefreshToken
+	revokeRefreshFamily(userUUID, device.AppID, deviceID)
 
 	// 2. 更新设备状态
 	result := global.DB.Model(&database.SSODevice{}).
@@ -147,9 +146,8 @@ func (s *ManageService) AppLogout(c *gin.Context, userUUID uuid.UUID, deviceID s
 		return fmt.Errorf("查询设备失败: %w", err)
 	}
 
-	// 1. 删除 RefreshToken（让当前会话失效）
-	refreshTokenKey := fmt.Sprintf("refresh_token:%s:%s", userUUID.String(), deviceID)
-	global.Redis.Del(refreshTokenKey)
+	// 1. 吊销 RefreshToken（让当前会话失效）
+	revokeRefreshFamily(userUUID, device.AppID, deviceID)
 
 	// 注意：应用退出不删除设备记录，设备仍然存在，只是当前会话结束
 	// 只有"踢出设备"或"SSO退出"才需要更新设备状态
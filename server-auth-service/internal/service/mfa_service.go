@@ -0,0 +1,466 @@
+package service
+
+import (
+	"auth-service/internal/model/appTypes"
+	database "auth-service/internal/model/database"
+	"auth-service/internal/model/request"
+	"auth-service/internal/model/response"
+	"auth-service/pkg/crypto"
+	customerrors "auth-service/pkg/errors"
+	"auth-service/pkg/global"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type MFAService struct{}
+
+const (
+	mfaChallengeExpiry   = 5 * time.Minute  // 两步验证挑战有效期
+	mfaSetupExpiry       = 10 * time.Minute // 待确认密钥有效期
+	mfaMaxAttempts       = 5                // 单个挑战允许的错误次数
+	mfaRecoveryCodeCount = 10               // 恢复码数量
+)
+
+var (
+	ErrMFAChallengeInvalid = errors.New("两步验证已过期，请重新登录")
+	ErrMFACodeInvalid      = errors.New("动态码或恢复码错误")
+	ErrMFANotEnabled       = errors.New("未开启两步验证")
+	ErrMFAAttemptsExceeded = errors.New("两步验证错误次数过多，请重新登录")
+)
+
+// mfaSubject 按用户统计两步验证失败次数。重新输入密码会换一个挑战，
+// 单个挑战的次数限制挡不住反复登录猜码，因此在滑动窗口内按用户累计并锁定
+func mfaSubject(userUUID string, cfg loginGuardSettings) loginSubject {
+	return loginSubject{kind: "mfa", id: userUUID, limit: cfg.accountLimit, label: "两步验证"}
+}
+
+// CheckLocked 用户两步验证处于锁定期时返回 LockedError
+func (s *MFAService) CheckLocked(userUUID uuid.UUID) error {
+	subject := mfaSubject(userUUID.String(), loginGuardConfig())
+	if ttl, err := global.Redis.TTL(loginLockKey(subject)).Result(); err == nil && ttl > 0 {
+		return customerrors.NewLockedError(int(ttl.Seconds()))
+	}
+	return nil
+}
+
+// recordFailure 记录一次两步验证失败，达到上限时锁定并返回 LockedError
+func (s *MFAService) recordFailure(c *gin.Context, challenge *appTypes.MFAChallenge, userUUID uuid.UUID) error {
+	cfg := loginGuardConfig()
+	subject := mfaSubject(challenge.UserUUID, cfg)
+
+	authService := &AuthService{}
+	authService.LogActionWithContext(c, userUUID, 0, "mfa_failed", challenge.DeviceID, "两步验证失败", 0)
+
+	count := addLoginFailure(subject, cfg.window)
+	if count < int64(subject.limit) {
+		return nil
+	}
+
+	// 锁定后清空计数，锁定期满重新统计
+	global.Redis.Set(loginLockKey(subject), "1", cfg.lockDuration)
+	global.Redis.Del(loginFailKey(subject))
+	global.Log.Warn("两步验证失败次数过多，已临时锁定",
+		zap.String("user_uuid", challenge.UserUUID),
+		zap.String("ip", c.ClientIP()),
+	)
+	message := fmt.Sprintf("两步验证失败 %d 次，锁定 %s", count, cfg.lockDuration)
+	authService.LogActionWithContext(c, userUUID, 0, "mfa_locked", challenge.DeviceID, message, 0)
+	return customerrors.NewLockedError(int(cfg.lockDuration.Seconds()))
+}
+
+// IsEnabled 判断用户是否已启用两步验证
+func (s *MFAService) IsEnabled(userUUID uuid.UUID) (bool, error) {
+	var count int64
+	err := global.DB.Model(&database.SSOUserMFA{}).
+		Where("user_uuid = ? AND enabled = ?", userUUID, true).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// GetStatus 获取两步验证状态
+func (s *MFAService) GetStatus(userUUID uuid.UUID) (*response.MFAStatus, error) {
+	var mfa database.SSOUserMFA
+	err := global.DB.Where("user_uuid = ? AND enabled = ?", userUUID, true).First(&mfa).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &response.MFAStatus{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var left int64
+	global.DB.Model(&database.SSOMFARecoveryCode{}).
+		Where("user_uuid = ? AND used_at IS NULL", userUUID).
+		Count(&left)
+
+	return &response.MFAStatus{
+		Enabled:           true,
+		ConfirmedAt:       mfa.ConfirmedAt,
+		RecoveryCodesLeft: left,
+	}, nil
+}
+
+// BeginSetup 生成待确认的 TOTP 密钥（已登录用户在账号中心绑定）
+func (s *MFAService) BeginSetup(userUUID uuid.UUID) (*response.MFASetupInfo, error) {
+	enabled, err := s.IsEnabled(userUUID)
+	if err != nil {
+		return nil, err
+	}
+	if enabled {
+		return nil, errors.New("已开启两步验证")
+	}
+
+	var user database.SSOUser
+	if err := global.DB.Where("uuid = ?", userUUID).First(&user).Error; err != nil {
+		return nil, errors.New("用户不存在")
+	}
+
+	secret, err := crypto.GenerateTOTPSecret()
+	if err != nil {
+		return nil, fmt.Errorf("生成密钥失败: %w", err)
+	}
+
+	key := fmt.Sprintf("mfa_setup:%s", userUUID.String())
+	if err := global.Redis.Set(key, secret, mfaSetupExpiry).Err(); err != nil {
+		return nil, fmt.Errorf("存储密钥失败: %w", err)
+	}
+
+	return setupInfo(&user, secret), nil
+}
+
+// Enable 校验动态码后启用两步验证，返回新生成的恢复码
+func (s *MFAService) Enable(c *gin.Context, userUUID uuid.UUID, code string) ([]string, error) {
+	key := fmt.Sprintf("mfa_setup:%s", userUUID.String())
+	secret, err := global.Redis.Get(key).Result()
+	if err != nil {
+		return nil, errors.New("绑定已过期，请重新获取二维码")
+	}
+
+	step, ok := crypto.ValidateTOTP(secret, code, time.Now())
+	if !ok {
+		return nil, ErrMFACodeInvalid
+	}
+
+	codes, err := s.enable(userUUID, secret, step)
+	if err != nil {
+		return nil, err
+	}
+	global.Redis.Del(key)
+
+	authService := &AuthService{}
+	authService.LogActionWithContext(c, userUUID, 0, "mfa_enable", "", "开启两步验证", 1)
+
+	return codes, nil
+}
+
+// Disable 校验动态码或恢复码后停用两步验证
+func (s *MFAService) Disable(c *gin.Context, userUUID uuid.UUID, req request.MFACodeRequest) error {
+	if err := s.Verify(userUUID, req.Code, req.RecoveryCode); err != nil {
+		return err
+	}
+
+	err := global.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("user_uuid = ?", userUUID).Delete(&database.SSOUserMFA{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_uuid = ?", userUUID).Delete(&database.SSOMFARecoveryCode{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("停用两步验证失败: %w", err)
+	}
+
+	authService := &AuthService{}
+	authService.LogActionWithContext(c, userUUID, 0, "mfa_disable", "", "停用两步验证", 1)
+	return nil
+}
+
+// RegenerateRecoveryCodes 校验动态码后重新生成恢复码（旧恢复码全部失效）
+func (s *MFAService) RegenerateRecoveryCodes(c *gin.Context, userUUID uuid.UUID, code string) ([]string, error) {
+	if err := s.Verify(userUUID, code, ""); err != nil {
+		return nil, err
+	}
+
+	var codes []string
+	err := global.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		codes, err = replaceRecoveryCodes(tx, userUUID)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("生成恢复码失败: %w", err)
+	}
+
+	authService := &AuthService{}
+	authService.LogActionWithContext(c, userUUID, 0, "mfa_recovery", "", "重新生成恢复码", 1)
+	return codes, nil
+}
+
+// Verify 校验动态码（同一时间步只能使用一次）或恢复码（使用后失效）
+func (s *MFAService) Verify(userUUID uuid.UUID, code, recoveryCode string) error {
+	var mfa database.SSOUserMFA
+	if err := global.DB.Where("user_uuid = ? AND enabled = ?", userUUID, true).First(&mfa).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrMFANotEnabled
+		}
+		return err
+	}
+
+	if code != "" {
+		step, ok := crypto.ValidateTOTP(mfa.Secret, code, time.Now())
+		if !ok {
+			return ErrMFACodeInvalid
+		}
+		// 条件更新保证并发请求中只有一个能使用该动态码
+		result := global.DB.Model(&database.SSOUserMFA{}).
+			Where("id = ? AND last_used_step < ?", mfa.ID, step).
+			Update("last_used_step", step)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("动态码已使用，请等待下一个动态码")
+		}
+		return nil
+	}
+
+	if recoveryCode != "" {
+		now := time.Now()
+		result := global.DB.Model(&database.SSOMFARecoveryCode{}).
+			Where("user_uuid = ? AND code_hash = ? AND used_at IS NULL", userUUID, crypto.HashRecoveryCode(recoveryCode)).
+			Limit(1).
+			Update("used_at", &now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrMFACodeInvalid
+		}
+		return nil
+	}
+
+	return errors.New("请输入动态码或恢复码")
+}
+
+// CreateChallenge 为已通过第一步验证的登录创建两步验证挑战
+func (s *MFAService) CreateChallenge(user *database.SSOUser, req request.LoginRequest, enrollmentRequired bool) (*response.MFAChallenge, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+
+	challenge := &appTypes.MFAChallenge{
+		Token:              hex.EncodeToString(buf),
+		UserUUID:           user.UUID.String(),
+		AppID:              req.AppID,
+		RedirectURI:        req.RedirectURI,
+		ReturnURL:          req.ReturnURL,
+		DeviceID:           req.DeviceID,
+		DeviceName:         req.DeviceName,
+		DeviceType:         req.DeviceType,
+		EnrollmentRequired: enrollmentRequired,
+		ExpiresAt:          time.Now().Add(mfaChallengeExpiry),
+	}
+	if err := s.saveChallenge(challenge); err != nil {
+		return nil, err
+	}
+
+	methods := []string{"totp", "recovery_code"}
+	if enrollmentRequired {
+		methods = []string{"totp"}
+	}
+	return &response.MFAChallenge{
+		MFARequired:        true,
+		MFAToken:           challenge.Token,
+		Methods:            methods,
+		EnrollmentRequired: enrollmentRequired,
+		ExpiresIn:          int(mfaChallengeExpiry.Seconds()),
+	}, nil
+}
+
+// SetupChallenge 登录过程中绑定身份验证器（仅限应用强制两步验证且用户尚未绑定）
+func (s *MFAService) SetupChallenge(token string) (*response.MFASetupInfo, error) {
+	challenge, err := s.getChallenge(token)
+	if err != nil {
+		return nil, err
+	}
+	if !challenge.EnrollmentRequired {
+		return nil, errors.New("已开启两步验证，无需重新绑定")
+	}
+
+	var user database.SSOUser
+	if err := global.DB.Where("uuid = ?", challenge.UserUUID).First(&user).Error; err != nil {
+		return nil, errors.New("用户不存在")
+	}
+
+	secret, err := crypto.GenerateTOTPSecret()
+	if err != nil {
+		return nil, fmt.Errorf("生成密钥失败: %w", err)
+	}
+	challenge.PendingSecret = secret
+	if err := s.saveChallenge(challenge); err != nil {
+		return nil, err
+	}
+
+	return setupInfo(&user, secret), nil
+}
+
+// VerifyChallenge 校验两步验证挑战，成功后挑战失效；登录时绑定的会同时返回恢复码
+func (s *MFAService) VerifyChallenge(c *gin.Context, req request.MFAVerifyRequest) (*appTypes.MFAChallenge, []string, error) {
+	challenge, err := s.getChallenge(req.MFAToken)
+	if err != nil {
+		return nil, nil, err
+	}
+	userUUID, err := uuid.FromString(challenge.UserUUID)
+	if err != nil {
+		return nil, nil, ErrMFAChallengeInvalid
+	}
+
+	// 锁定期内直接作废挑战，不再校验动态码
+	if err := s.CheckLocked(userUUID); err != nil {
+		s.deleteChallenge(challenge.Token)
+		return nil, nil, err
+	}
+
+	var recoveryCodes []string
+	if challenge.EnrollmentRequired {
+		if challenge.PendingSecret == "" {
+			return nil, nil, errors.New("请先绑定身份验证器")
+		}
+		step, ok := crypto.ValidateTOTP(challenge.PendingSecret, req.Code, time.Now())
+		if !ok {
+			err = ErrMFACodeInvalid
+		} else {
+			recoveryCodes, err = s.enable(userUUID, challenge.PendingSecret, step)
+		}
+	} else {
+		err = s.Verify(userUUID, req.Code, req.RecoveryCode)
+	}
+
+	if err != nil {
+		if lockErr := s.recordFailure(c, challenge, userUUID); lockErr != nil {
+			s.deleteChallenge(challenge.Token)
+			return nil, nil, lockErr
+		}
+
+		// 超过错误次数后作废挑战，需重新输入密码
+		attemptsKey := fmt.Sprintf("mfa_challenge_attempts:%s", challenge.Token)
+		attempts, _ := global.Redis.Incr(attemptsKey).Result()
+		global.Redis.Expire(attemptsKey, mfaChallengeExpiry)
+		if attempts >= mfaMaxAttempts {
+			s.deleteChallenge(challenge.Token)
+			return nil, nil, ErrMFAAttemptsExceeded
+		}
+		return nil, nil, err
+	}
+
+	s.deleteChallenge(challenge.Token)
+	global.Redis.Del(loginFailKey(mfaSubject(challenge.UserUUID, loginGuardConfig())))
+	return challenge, recoveryCodes, nil
+}
+
+// enable 保存密钥并启用两步验证，同时生成恢复码
+func (s *MFAService) enable(userUUID uuid.UUID, secret string, step int64) ([]string, error) {
+	var codes []string
+	err := global.DB.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		mfa := database.SSOUserMFA{
+			UserUUID:     userUUID,
+			Secret:       secret,
+			Enabled:      true,
+			LastUsedStep: step,
+			ConfirmedAt:  &now,
+		}
+		if err := tx.Unscoped().Where("user_uuid = ?", userUUID).Delete(&database.SSOUserMFA{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&mfa).Error; err != nil {
+			return err
+		}
+
+		var err error
+		codes, err = replaceRecoveryCodes(tx, userUUID)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("开启两步验证失败: %w", err)
+	}
+	return codes, nil
+}
+
+// replaceRecoveryCodes 删除旧恢复码并生成新的恢复码
+func replaceRecoveryCodes(tx *gorm.DB, userUUID uuid.UUID) ([]string, error) {
+	codes, err := crypto.GenerateRecoveryCodes(mfaRecoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Where("user_uuid = ?", userUUID).Delete(&database.SSOMFARecoveryCode{}).Error; err != nil {
+		return nil, err
+	}
+
+	records := make([]database.SSOMFARecoveryCode, len(codes))
+	for i, code := range codes {
+		records[i] = database.SSOMFARecoveryCode{
+			UserUUID: userUUID,
+			CodeHash: crypto.HashRecoveryCode(code),
+		}
+	}
+	if err := tx.Create(&records).Error; err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// setupInfo 组装绑定信息，账号名优先使用邮箱
+func setupInfo(user *database.SSOUser, secret string) *response.MFASetupInfo {
+	account := user.Username
+	if user.Email != nil && *user.Email != "" {
+		account = *user.Email
+	}
+	issuer := global.Config.MFA.Issuer
+	if issuer == "" {
+		issuer = global.Config.JWT.Issuer
+	}
+	return &response.MFASetupInfo{
+		Secret:          secret,
+		ProvisioningURI: crypto.TOTPProvisioningURI(issuer, account, secret),
+	}
+}
+
+func (s *MFAService) saveChallenge(challenge *appTypes.MFAChallenge) error {
+	value, err := json.Marshal(challenge)
+	if err != nil {
+		return err
+	}
+	ttl := time.Until(challenge.ExpiresAt)
+	if ttl <= 0 {
+		return ErrMFAChallengeInvalid
+	}
+	key := fmt.Sprintf("mfa_challenge:%s", challenge.Token)
+	return global.Redis.Set(key, value, ttl).Err()
+}
+
+func (s *MFAService) getChallenge(token string) (*appTypes.MFAChallenge, error) {
+	value, err := global.Redis.Get(fmt.Sprintf("mfa_challenge:%s", token)).Result()
+	if err != nil {
+		return nil, ErrMFAChallengeInvalid
+	}
+	var challenge appTypes.MFAChallenge
+	if err := json.Unmarshal([]byte(value), &challenge); err != nil {
+		return nil, ErrMFAChallengeInvalid
+	}
+	return &challenge, nil
+}
+
+func (s *MFAService) deleteChallenge(token string) {
+	global.Redis.Del(fmt.Sprintf("mfa_challenge:%s", token))
+	global.Redis.Del(fmt.Sprintf("mfa_challenge_attempts:%s", token))
+}
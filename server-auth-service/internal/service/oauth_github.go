@@ -0,0 +1,90 @@
+package service
+
+import (
+	"auth-service/internal/model/appTypes"
+	"auth-service/internal/model/other"
+	"auth-service/pkg/global"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// githubProvider GitHub OAuth App 登录
+type githubProvider struct{}
+
+func (p *githubProvider) Name() string                    { return "github" }
+func (p *githubProvider) DisplayName() string             { return "GitHub" }
+func (p *githubProvider) Source() appTypes.RegisterSource { return appTypes.Github }
+func (p *githubProvider) Enabled() bool                   { return global.Config.OAuth.Github.Enable }
+
+// AuthURL 生成GitHub授权URL
+func (p *githubProvider) AuthURL(state string) string {
+	cfg := global.Config.OAuth.Github
+	params := url.Values{}
+	params.Set("client_id", cfg.ClientID)
+	params.Set("redirect_uri", cfg.RedirectURI)
+	params.Set("state", state)
+	params.Set("scope", "read:user user:email")
+	params.Set("allow_signup", "false")
+	return "https://github.com/login/oauth/authorize?" + params.Encode()
+}
+
+// Exchange 通过授权码获取Access Token
+func (p *githubProvider) Exchange(code string) (*other.OAuthToken, error) {
+	cfg := global.Config.OAuth.Github
+	data := other.GithubAccessTokenResponse{}
+	headers := map[string]string{"Accept": "application/json"}
+	params := map[string]string{
+		"client_id":     cfg.ClientID,
+		"client_secret": cfg.ClientSecret,
+		"code":          code,
+		"redirect_uri":  cfg.RedirectURI,
+	}
+	if err := oauthRequest("https://github.com/login/oauth/access_token", "POST", headers, params, &data); err != nil {
+		return nil, err
+	}
+	if data.Error != "" || data.AccessToken == "" {
+		return nil, fmt.Errorf("GitHub授权失败: %s %s", data.Error, data.ErrorDescription)
+	}
+	return &other.OAuthToken{AccessToken: data.AccessToken}, nil
+}
+
+// FetchIdentity 获取GitHub用户信息及已验证的主邮箱
+func (p *githubProvider) FetchIdentity(token *other.OAuthToken) (*other.OAuthIdentity, error) {
+	headers := map[string]string{
+		"Accept":        "application/vnd.github+json",
+		"Authorization": "Bearer " + token.AccessToken,
+	}
+
+	user := other.GithubUserResponse{}
+	if err := oauthRequest("https://api.github.com/user", "GET", headers, nil, &user); err != nil {
+		return nil, err
+	}
+	if user.ID == 0 {
+		return nil, errors.New("获取GitHub用户信息失败")
+	}
+
+	nickname := user.Name
+	if nickname == "" {
+		nickname = user.Login
+	}
+	identity := &other.OAuthIdentity{
+		Provider: p.Name(),
+		OpenID:   strconv.FormatInt(user.ID, 10),
+		Nickname: nickname,
+		Avatar:   user.AvatarURL,
+	}
+
+	// 邮箱仅作参考，获取失败不影响登录
+	var emails []other.GithubEmailResponse
+	if err := oauthRequest("https://api.github.com/user/emails", "GET", headers, nil, &emails); err == nil {
+		for _, e := range emails {
+			if e.Primary && e.Verified {
+				identity.Email = e.Email
+				break
+			}
+		}
+	}
+	return identity, nil
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"auth-service/internal/model/appTypes"
+	"auth-service/internal/model/other"
+	"auth-service/pkg/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// OAuthProvider 第三方登录提供方
+type OAuthProvider interface {
+	// Name 提供方标识，与路由参数及 SSOOAuthBinding.Provider 一致
+	Name() string
+	// DisplayName 展示名称，用于提示信息和默认昵称
+	DisplayName() string
+	// Source 通过该提供方首次登录时记录的注册来源
+	Source() appTypes.RegisterSource
+	// Enabled 是否已在配置中启用
+	Enabled() bool
+	// AuthURL 生成跳转到提供方的授权地址
+	AuthURL(state string) string
+	// Exchange 使用授权码换取访问令牌
+	Exchange(code string) (*other.OAuthToken, error)
+	// FetchIdentity 获取用户资料并转换为统一身份
+	FetchIdentity(token *other.OAuthToken) (*other.OAuthIdentity, error)
+}
+
+var ErrOAuthProviderNotFound = errors.New("不支持的登录方式")
+
+var oauthProviders = map[string]OAuthProvider{
+	"qq":     &qqProvider{},
+	"github": &githubProvider{},
+	"wechat": &wechatProvider{},
+}
+
+// GetOAuthProvider 获取已启用的第三方登录提供方
+func GetOAuthProvider(name string) (OAuthProvider, error) {
+	provider, ok := oauthProviders[name]
+	if !ok {
+		return nil, ErrOAuthProviderNotFound
+	}
+	if !provider.Enabled() {
+		return nil, fmt.Errorf("%s登录未启用", provider.DisplayName())
+	}
+	return provider, nil
+}
+
+// oauthRequest 请求提供方接口并解析 JSON 响应
+func oauthRequest(urlStr, method string, headers, params map[string]string, v interface{}) error {
+	res, err := utils.HttpRequest(urlStr, method, headers, params, nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("request failed with status code: %d", res.StatusCode)
+	}
+
+	byteData, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(byteData, v)
+}
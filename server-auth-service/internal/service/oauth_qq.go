@@ -0,0 +1,78 @@
+package service
+
+import (
+	"auth-service/internal/model/appTypes"
+	"auth-service/internal/model/other"
+	"auth-service/pkg/global"
+	"fmt"
+	"net/url"
+)
+
+// qqProvider QQ互联登录
+type qqProvider struct{}
+
+func (p *qqProvider) Name() string                    { return "qq" }
+func (p *qqProvider) DisplayName() string             { return "QQ" }
+func (p *qqProvider) Source() appTypes.RegisterSource { return appTypes.QQ }
+func (p *qqProvider) Enabled() bool                   { return global.Config.OAuth.QQ.Enable }
+
+// AuthURL 生成QQ登录URL
+func (p *qqProvider) AuthURL(state string) string {
+	cfg := global.Config.OAuth.QQ
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", cfg.ClientID)
+	params.Set("redirect_uri", cfg.RedirectURI)
+	params.Set("state", state)
+	params.Set("scope", "get_user_info")
+	return "https://graph.qq.com/oauth2.0/authorize?" + params.Encode()
+}
+
+// Exchange 通过Authorization Code获取Access Token（同时返回openid）
+func (p *qqProvider) Exchange(code string) (*other.OAuthToken, error) {
+	cfg := global.Config.OAuth.QQ
+	data := other.AccessTokenResponse{}
+	params := map[string]string{
+		"grant_type":    "authorization_code",
+		"client_id":     cfg.ClientID,
+		"client_secret": cfg.ClientSecret,
+		"code":          code,
+		"redirect_uri":  cfg.RedirectURI,
+		"fmt":           "json",
+		"need_openid":   "1",
+	}
+	if err := oauthRequest("https://graph.qq.com/oauth2.0/token", "GET", nil, params, &data); err != nil {
+		return nil, err
+	}
+	if data.Error != 0 || data.AccessToken == "" || data.Openid == "" {
+		return nil, fmt.Errorf("QQ授权失败: %d %s", data.Error, data.ErrorDescription)
+	}
+	return &other.OAuthToken{AccessToken: data.AccessToken, OpenID: data.Openid}, nil
+}
+
+// FetchIdentity 获取登录用户信息
+func (p *qqProvider) FetchIdentity(token *other.OAuthToken) (*other.OAuthIdentity, error) {
+	data := other.UserInfoResponse{}
+	params := map[string]string{
+		"access_token":       token.AccessToken,
+		"oauth_consumer_key": global.Config.OAuth.QQ.ClientID,
+		"openid":             token.OpenID,
+	}
+	if err := oauthRequest("https://graph.qq.com/user/get_user_info", "GET", nil, params, &data); err != nil {
+		return nil, err
+	}
+	if data.Ret != 0 {
+		return nil, fmt.Errorf("获取QQ用户信息失败: %d %s", data.Ret, data.Msg)
+	}
+
+	avatar := data.FigureurlQQ2
+	if avatar == "" {
+		avatar = data.FigureurlQQ1
+	}
+	return &other.OAuthIdentity{
+		Provider: p.Name(),
+		OpenID:   token.OpenID,
+		Nickname: data.Nickname,
+		Avatar:   avatar,
+	}, nil
+}
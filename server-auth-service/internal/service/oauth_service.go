@@ -0,0 +1,364 @@
+package service
+
+import (
+	"auth-service/internal/model/appTypes"
+	database "auth-service/internal/model/database"
+	"auth-service/internal/model/other"
+	"auth-service/internal/model/request"
+	"auth-service/internal/model/response"
+	"auth-service/pkg/global"
+	"auth-service/pkg/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type OAuthService struct{}
+
+const (
+	oauthStateExpiry    = 10 * time.Minute // 跳转第三方授权到回调的最长时间
+	defaultOAuthAvatar  = "https://image.hsk423.cn/blog/aaca0f5eb4d2d98a6ce6dffa99f8254b-20251108151238.jpg"
+	oauthNicknameSuffix = "用户"
+)
+
+var (
+	ErrOAuthStateInvalid = errors.New("登录已过期，请重新发起")
+	ErrLastLoginMethod   = errors.New("这是账号唯一的登录方式，请先绑定邮箱或其他登录方式")
+)
+
+// BeginLogin 暂存已校验的 state，返回第三方授权地址
+// 原始 state 较长（微信限制 128 字节），传给第三方的只是随机短标识，回调时换回原始参数
+// oidcRequest 非空表示由 OIDC 授权请求发起的登录，完成后继续授权
+func (s *OAuthService) BeginLogin(provider OAuthProvider, stateData *utils.StateData, oidcRequest string) (string, error) {
+	return s.begin(provider, &appTypes.OAuthState{
+		Action:      appTypes.OAuthActionLogin,
+		AppID:       stateData.AppID,
+		DeviceID:    stateData.DeviceID,
+		RedirectURI: stateData.RedirectURI,
+		ReturnURL:   stateData.ReturnURL,
+		OIDCRequest: oidcRequest,
+	})
+}
+
+// BeginLink 已登录用户绑定第三方账号，返回第三方授权地址
+func (s *OAuthService) BeginLink(provider OAuthProvider, userUUID uuid.UUID) (authURL, nonce string, err error) {
+	var count int64
+	global.DB.Model(&database.SSOOAuthBinding{}).
+		Where("user_uuid = ? AND provider = ?", userUUID, provider.Name()).
+		Count(&count)
+	if count > 0 {
+		return "", "", fmt.Errorf("已绑定%s账号，请先解绑", provider.DisplayName())
+	}
+
+	nonce = strings.ReplaceAll(uuid.Must(uuid.NewV4()).String(), "-", "")
+	authURL, err = s.begin(provider, &appTypes.OAuthState{
+		Action:    appTypes.OAuthActionLink,
+		UserUUID:  userUUID.String(),
+		LinkNonce: nonce,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return authURL, nonce, nil
+}
+
+func (s *OAuthService) begin(provider OAuthProvider, state *appTypes.OAuthState) (string, error) {
+	value, err := json.Marshal(state)
+	if err != nil {
+		return "", err
+	}
+
+	id := strings.ReplaceAll(uuid.Must(uuid.NewV4()).String(), "-", "")
+	key := fmt.Sprintf("oauth_state:%s:%s", provider.Name(), id)
+	if err := global.Redis.Set(key, value, oauthStateExpiry).Err(); err != nil {
+		return "", fmt.Errorf("保存登录状态失败: %w", err)
+	}
+	return provider.AuthURL(id), nil
+}
+
+// ConsumeState 取出并删除暂存的参数（一次性使用）
+func (s *OAuthService) ConsumeState(provider OAuthProvider, id string) (*appTypes.OAuthState, error) {
+	if id == "" {
+		return nil, ErrOAuthStateInvalid
+	}
+	key := fmt.Sprintf("oauth_state:%s:%s", provider.Name(), id)
+	value, err := global.Redis.Get(key).Result()
+	if err != nil {
+		return nil, ErrOAuthStateInvalid
+	}
+	if n, _ := global.Redis.Del(key).Result(); n == 0 {
+		return nil, ErrOAuthStateInvalid
+	}
+
+	var state appTypes.OAuthState
+	if err := json.Unmarshal([]byte(value), &state); err != nil {
+		return nil, ErrOAuthStateInvalid
+	}
+	return &state, nil
+}
+
+// Login 第三方登录：换取第三方身份，查找或创建用户后走统一登录流程（含两步验证）
+func (s *OAuthService) Login(c *gin.Context, provider OAuthProvider, code string, req request.LoginRequest) (*response.TokenResponse, *response.MFAChallenge, error) {
+	identity, err := s.fetchIdentity(provider, code)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user, isNewUser, err := s.findOrCreateUser(provider, identity)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	authService := &AuthService{}
+	resp, challenge, err := authService.loginUser(c, user, req)
+	if err != nil || challenge != nil {
+		return resp, challenge, err
+	}
+
+	message := provider.DisplayName() + "登录成功"
+	if isNewUser {
+		message += "（新用户）"
+	}
+	if app, err := authService.GetAppByKey(req.AppID); err == nil {
+		authService.LogActionWithContext(c, user.UUID, app.ID, provider.Name()+"_login", req.DeviceID, message, 1)
+	}
+	return resp, nil, nil
+}
+
+// Link 将第三方账号绑定到当前用户（每个提供方只能绑定一个账号）
+func (s *OAuthService) Link(c *gin.Context, provider OAuthProvider, code string, userUUID uuid.UUID) error {
+	identity, err := s.fetchIdentity(provider, code)
+	if err != nil {
+		return err
+	}
+
+	var existing database.SSOOAuthBinding
+	err = global.DB.Where("provider = ? AND open_id = ?", identity.Provider, identity.OpenID).First(&existing).Error
+	if err == nil {
+		if existing.UserUUID == userUUID {
+			return nil
+		}
+		return fmt.Errorf("该%s账号已绑定其他账号，如需合并请联系管理员", provider.DisplayName())
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	var count int64
+	global.DB.Model(&database.SSOOAuthBinding{}).
+		Where("user_uuid = ? AND provider = ?", userUUID, identity.Provider).
+		Count(&count)
+	if count > 0 {
+		return fmt.Errorf("已绑定%s账号，请先解绑", provider.DisplayName())
+	}
+
+	binding := database.SSOOAuthBinding{
+		UserUUID: userUUID,
+		Provider: identity.Provider,
+		OpenID:   identity.OpenID,
+		UnionID:  identity.UnionID,
+	}
+	if err := global.DB.Create(&binding).Error; err != nil {
+		return fmt.Errorf("创建OAuth绑定失败: %w", err)
+	}
+
+	authService := &AuthService{}
+	authService.LogActionWithContext(c, userUUID, 0, "oauth_link", "", "绑定"+provider.DisplayName()+"账号", 1)
+	return nil
+}
+
+// Unlink 解绑第三方账号，至少保留一种登录方式
+func (s *OAuthService) Unlink(c *gin.Context, userUUID uuid.UUID, providerName string) error {
+	var binding database.SSOOAuthBinding
+	err := global.DB.Where("user_uuid = ? AND provider = ?", userUUID, providerName).First(&binding).Error
+	if err != nil {
+		return errors.New("未绑定该第三方账号")
+	}
+
+	methods, err := countLoginMethods(userUUID)
+	if err != nil {
+		return err
+	}
+	if methods <= 1 {
+		return ErrLastLoginMethod
+	}
+
+	// 物理删除，便于该第三方账号重新绑定
+	if err := global.DB.Unscoped().Delete(&binding).Error; err != nil {
+		return err
+	}
+
+	displayName := providerName
+	if provider, ok := oauthProviders[providerName]; ok {
+		displayName = provider.DisplayName()
+	}
+	authService := &AuthService{}
+	authService.LogActionWithContext(c, userUUID, 0, "oauth_unlink", "", "解绑"+displayName+"账号", 1)
+	return nil
+}
+
+// ListBindings 获取第三方账号绑定情况（包含已启用但未绑定的提供方）
+func (s *OAuthService) ListBindings(userUUID uuid.UUID) ([]response.OAuthBindingInfo, error) {
+	var bindings []database.SSOOAuthBinding
+	if err := global.DB.Where("user_uuid = ?", userUUID).Find(&bindings).Error; err != nil {
+		return nil, err
+	}
+	bound := make(map[string]database.SSOOAuthBinding, len(bindings))
+	for _, b := range bindings {
+		bound[b.Provider] = b
+	}
+
+	list := make([]response.OAuthBindingInfo, 0, len(oauthProviders))
+	for _, name := range []string{"qq", "wechat", "github"} {
+		provider := oauthProviders[name]
+		binding, isBound := bound[name]
+		if !isBound && !provider.Enabled() {
+			continue
+		}
+		info := response.OAuthBindingInfo{
+			Provider:     name,
+			ProviderName: provider.DisplayName(),
+			Enabled:      provider.Enabled(),
+			Bound:        isBound,
+		}
+		if isBound {
+			info.BoundAt = &binding.CreatedAt
+		}
+		list = append(list, info)
+	}
+	return list, nil
+}
+
+// countLoginMethods 统计账号可用的登录方式：邮箱（密码或验证码）、第三方账号、通行密钥
+func countLoginMethods(userUUID uuid.UUID) (int64, error) {
+	var user database.SSOUser
+	if err := global.DB.Where("uuid = ?", userUUID).First(&user).Error; err != nil {
+		return 0, errors.New("用户不存在")
+	}
+
+	var methods int64
+	if user.Email != nil && *user.Email != "" {
+		methods++
+	}
+
+	var bindings, passkeys int64
+	global.DB.Model(&database.SSOOAuthBinding{}).Where("user_uuid = ?", userUUID).Count(&bindings)
+	global.DB.Model(&database.SSOWebAuthnCredential{}).Where("user_uuid = ?", userUUID).Count(&passkeys)
+	return methods + bindings + passkeys, nil
+}
+
+// fetchIdentity 使用授权码换取第三方身份
+func (s *OAuthService) fetchIdentity(provider OAuthProvider, code string) (*other.OAuthIdentity, error) {
+	token, err := provider.Exchange(code)
+	if err != nil {
+		global.Log.Error("第三方登录换取令牌失败", zap.String("provider", provider.Name()), zap.Error(err))
+		return nil, fmt.Errorf("获取%s授权失败", provider.DisplayName())
+	}
+
+	identity, err := provider.FetchIdentity(token)
+	if err != nil {
+		global.Log.Error("第三方登录获取用户信息失败", zap.String("provider", provider.Name()), zap.Error(err))
+		return nil, fmt.Errorf("获取%s用户信息失败", provider.DisplayName())
+	}
+	return identity, nil
+}
+
+// findOrCreateUser 按绑定关系查找用户，首次登录时自动注册
+func (s *OAuthService) findOrCreateUser(provider OAuthProvider, identity *other.OAuthIdentity) (*database.SSOUser, bool, error) {
+	var binding database.SSOOAuthBinding
+	err := global.DB.Where("provider = ? AND open_id = ?", identity.Provider, identity.OpenID).First(&binding).Error
+	if err == nil {
+		if identity.UnionID != "" && binding.UnionID == "" {
+			global.DB.Model(&binding).Update("union_id", identity.UnionID)
+		}
+		user, err := s.getUser(binding.UserUUID)
+		return user, false, err
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, err
+	}
+
+	// 同一开放平台下的其他应用已绑定（unionid 相同），视为同一用户
+	if identity.UnionID != "" {
+		var sibling database.SSOOAuthBinding
+		err := global.DB.Where("provider = ? AND union_id = ?", identity.Provider, identity.UnionID).First(&sibling).Error
+		if err == nil {
+			binding = database.SSOOAuthBinding{
+				UserUUID: sibling.UserUUID,
+				Provider: identity.Provider,
+				OpenID:   identity.OpenID,
+				UnionID:  identity.UnionID,
+			}
+			if err := global.DB.Create(&binding).Error; err != nil {
+				return nil, false, fmt.Errorf("创建OAuth绑定失败: %w", err)
+			}
+			user, err := s.getUser(sibling.UserUUID)
+			return user, false, err
+		}
+	}
+
+	// 新用户，创建账号
+	nickname := identity.Nickname
+	if nickname == "" {
+		nickname = provider.DisplayName() + oauthNicknameSuffix
+	}
+	avatar := identity.Avatar
+	if avatar == "" {
+		avatar = defaultOAuthAvatar
+	}
+
+	user := database.SSOUser{
+		UUID:           uuid.Must(uuid.NewV4()),
+		Username:       nickname,
+		PasswordHash:   nil, // 第三方登录无密码
+		Nickname:       nickname,
+		Avatar:         avatar,
+		Status:         1,
+		RegisterSource: provider.Source(),
+		IsSuperAdmin:   false,
+	}
+	// 第三方已验证的邮箱未被占用时一并保存（不会自动关联到已有账号）
+	if identity.Email != "" {
+		var count int64
+		global.DB.Model(&database.SSOUser{}).Where("email = ?", identity.Email).Count(&count)
+		if count == 0 {
+			email := identity.Email
+			user.Email = &email
+		}
+	}
+
+	err = global.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return fmt.Errorf("创建用户失败: %w", err)
+		}
+		binding = database.SSOOAuthBinding{
+			UserUUID: user.UUID,
+			Provider: identity.Provider,
+			OpenID:   identity.OpenID,
+			UnionID:  identity.UnionID,
+		}
+		if err := tx.Create(&binding).Error; err != nil {
+			return fmt.Errorf("创建OAuth绑定失败: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return &user, true, nil
+}
+
+func (s *OAuthService) getUser(userUUID uuid.UUID) (*database.SSOUser, error) {
+	var user database.SSOUser
+	if err := global.DB.Where("uuid = ?", userUUID).First(&user).Error; err != nil {
+		return nil, errors.New("用户不存在")
+	}
+	return &user, nil
+}
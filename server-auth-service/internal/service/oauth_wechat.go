@@ -0,0 +1,76 @@
+package service
+
+import (
+	"auth-service/internal/model/appTypes"
+	"auth-service/internal/model/other"
+	"auth-service/pkg/global"
+	"fmt"
+	"net/url"
+)
+
+// wechatProvider 微信开放平台网站应用（扫码登录）
+type wechatProvider struct{}
+
+func (p *wechatProvider) Name() string                    { return "wechat" }
+func (p *wechatProvider) DisplayName() string             { return "微信" }
+func (p *wechatProvider) Source() appTypes.RegisterSource { return appTypes.Wechat }
+func (p *wechatProvider) Enabled() bool                   { return global.Config.OAuth.Wechat.Enable }
+
+// AuthURL 生成微信扫码登录URL（state 不超过 128 字节）
+func (p *wechatProvider) AuthURL(state string) string {
+	cfg := global.Config.OAuth.Wechat
+	params := url.Values{}
+	params.Set("appid", cfg.ClientID)
+	params.Set("redirect_uri", cfg.RedirectURI)
+	params.Set("response_type", "code")
+	params.Set("scope", "snsapi_login")
+	params.Set("state", state)
+	return "https://open.weixin.qq.com/connect/qrconnect?" + params.Encode() + "#wechat_redirect"
+}
+
+// Exchange 通过授权码获取Access Token（同时返回openid和unionid）
+func (p *wechatProvider) Exchange(code string) (*other.OAuthToken, error) {
+	cfg := global.Config.OAuth.Wechat
+	data := other.WechatAccessTokenResponse{}
+	params := map[string]string{
+		"appid":      cfg.ClientID,
+		"secret":     cfg.ClientSecret,
+		"code":       code,
+		"grant_type": "authorization_code",
+	}
+	if err := oauthRequest("https://api.weixin.qq.com/sns/oauth2/access_token", "GET", nil, params, &data); err != nil {
+		return nil, err
+	}
+	if data.Errcode != 0 || data.AccessToken == "" || data.Openid == "" {
+		return nil, fmt.Errorf("微信授权失败: %d %s", data.Errcode, data.Errmsg)
+	}
+	return &other.OAuthToken{AccessToken: data.AccessToken, OpenID: data.Openid, UnionID: data.Unionid}, nil
+}
+
+// FetchIdentity 获取微信用户信息
+func (p *wechatProvider) FetchIdentity(token *other.OAuthToken) (*other.OAuthIdentity, error) {
+	data := other.WechatUserInfoResponse{}
+	params := map[string]string{
+		"access_token": token.AccessToken,
+		"openid":       token.OpenID,
+		"lang":         "zh_CN",
+	}
+	if err := oauthRequest("https://api.weixin.qq.com/sns/userinfo", "GET", nil, params, &data); err != nil {
+		return nil, err
+	}
+	if data.Errcode != 0 {
+		return nil, fmt.Errorf("获取微信用户信息失败: %d %s", data.Errcode, data.Errmsg)
+	}
+
+	unionID := data.Unionid
+	if unionID == "" {
+		unionID = token.UnionID
+	}
+	return &other.OAuthIdentity{
+		Provider: p.Name(),
+		OpenID:   token.OpenID,
+		UnionID:  unionID,
+		Nickname: data.Nickname,
+		Avatar:   data.Headimgurl,
+	}, nil
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"auth-service/pkg/global"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/gofrs/uuid"
+)
+
+// RefreshToken 家族轮换结果
+const (
+	refreshRotated = 1  // 轮换成功
+	refreshGrace   = 2  // 宽限期内的并发刷新：沿用家族当前 jti，不再轮换
+	refreshReused  = 0  // 家族有效但 jti 已被轮换过：旧 Token 被重复使用
+	refreshInvalid = -1 // 家族不存在（已吊销、已过期或已被新登录替换）
+)
+
+// refreshReuseGrace 轮换后旧 jti 的宽限期。
+// 页面在 AccessToken 过期后同时发出多个请求时，它们会携带同一个 RefreshToken 并发刷新，
+// 宽限期内再次出示刚被轮换掉的 jti 不视为泄露
+const refreshReuseGrace = 10 * time.Second
+
+// rotateRefreshScript 原子地校验家族和 jti 并写入新 jti，避免并发刷新时两次都通过校验。
+// 轮换时记下旧 jti 和宽限截止时间，宽限期内出示旧 jti 返回家族当前 jti
+var rotateRefreshScript = redis.NewScript(`
+local fid = redis.call('HGET', KEYS[1], 'fid')
+if not fid or fid ~= ARGV[1] then
+	return {-1, ''}
+end
+local jti = redis.call('HGET', KEYS[1], 'jti')
+if jti == ARGV[2] then
+	redis.call('HMSET', KEYS[1], 'jti', ARGV[3], 'prev', ARGV[2], 'prev_until', ARGV[6])
+	redis.call('PEXPIRE', KEYS[1], ARGV[4])
+	return {1, ARGV[3]}
+end
+local prevUntil = tonumber(redis.call('HGET', KEYS[1], 'prev_until') or '0')
+if redis.call('HGET', KEYS[1], 'prev') == ARGV[2] and prevUntil >= tonumber(ARGV[5]) then
+	return {2, jti}
+end
+return {0, ''}
+`)
+
+// adoptLegacyScript 旧版本签发的 RefreshToken（无 fid/jti）只接受一次：首次出示时开启新家族，
+// 宽限期内的并发刷新沿用该家族当前 jti，之后再出示视为失效
+var adoptLegacyScript = redis.NewScript(`
+local adopted = redis.call('HGET', KEYS[1], 'fid')
+if not adopted then
+	redis.call('HMSET', KEYS[1], 'fid', ARGV[1], 'until', ARGV[6])
+	redis.call('PEXPIRE', KEYS[1], ARGV[3])
+	redis.call('DEL', KEYS[2])
+	redis.call('HMSET', KEYS[2], 'fid', ARGV[1], 'jti', ARGV[2])
+	redis.call('PEXPIRE', KEYS[2], ARGV[4])
+	return {1, ARGV[1], ARGV[2]}
+end
+local adoptedUntil = tonumber(redis.call('HGET', KEYS[1], 'until') or '0')
+if adoptedUntil >= tonumber(ARGV[5]) and redis.call('HGET', KEYS[2], 'fid') == adopted then
+	return {2, adopted, redis.call('HGET', KEYS[2], 'jti')}
+end
+return {-1, '', ''}
+`)
+
+// refreshFamilyKey 每个用户 + 应用 + 设备只保留一个 RefreshToken 家族，重新登录即替换旧家族
+func refreshFamilyKey(userUUID uuid.UUID, appID uint, deviceID string) string {
+	return fmt.Sprintf("refresh_family:%s:%d:%s", userUUID.String(), appID, deviceID)
+}
+
+func newTokenID() string {
+	return strings.ReplaceAll(uuid.Must(uuid.NewV4()).String(), "-", "")
+}
+
+// startRefreshFamily 登录时开启新的 RefreshToken 家族，返回家族 ID 和首个 jti
+func startRefreshFamily(userUUID uuid.UUID, appID uint, deviceID string, expiry time.Duration) (string, string, error) {
+	familyID, jti := newTokenID(), newTokenID()
+	key := refreshFamilyKey(userUUID, appID, deviceID)
+	_, err := global.Redis.TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.Del(key)
+		pipe.HMSet(key, map[string]interface{}{"fid": familyID, "jti": jti})
+		pipe.Expire(key, expiry)
+		return nil
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("登记RefreshToken失败: %w", err)
+	}
+	return familyID, jti, nil
+}
+
+// rotateRefreshFamily 校验 RefreshToken 是家族中最新的一个，并轮换为 newJTI，有效期随之顺延。
+// 返回轮换结果和新 RefreshToken 应使用的 jti（宽限期内为家族当前 jti）
+func rotateRefreshFamily(userUUID uuid.UUID, appID uint, deviceID, familyID, jti, newJTI string, expiry time.Duration) (int, string, error) {
+	key := refreshFamilyKey(userUUID, appID, deviceID)
+	now := time.Now()
+	res, err := rotateRefreshScript.Run(global.Redis, []string{key},
+		familyID, jti, newJTI, expiry.Milliseconds(), now.UnixMilli(), now.Add(refreshReuseGrace).UnixMilli()).Result()
+	if err != nil {
+		return 0, "", err
+	}
+	values, ok := res.([]interface{})
+	if !ok || len(values) != 2 {
+		return 0, "", fmt.Errorf("unexpected rotate result: %v", res)
+	}
+	result, _ := values[0].(int64)
+	current, _ := values[1].(string)
+	return int(result), current, nil
+}
+
+// adoptLegacyRefreshToken 为旧版本签发的 RefreshToken 开启新家族，返回轮换结果、家族 ID 和 jti。
+// 同一个旧 Token 在其剩余有效期内只会被接受一次（宽限期内的并发请求除外）
+func adoptLegacyRefreshToken(token string, userUUID uuid.UUID, appID uint, deviceID string, tokenTTL, expiry time.Duration) (int, string, string, error) {
+	sum := sha256.Sum256([]byte(token))
+	legacyKey := "refresh_legacy:" + hex.EncodeToString(sum[:])
+	if tokenTTL < refreshReuseGrace {
+		tokenTTL = refreshReuseGrace
+	}
+	now := time.Now()
+	res, err := adoptLegacyScript.Run(global.Redis, []string{legacyKey, refreshFamilyKey(userUUID, appID, deviceID)},
+		newTokenID(), newTokenID(), tokenTTL.Milliseconds(), expiry.Milliseconds(), now.UnixMilli(), now.Add(refreshReuseGrace).UnixMilli()).Result()
+	if err != nil {
+		return 0, "", "", err
+	}
+	values, ok := res.([]interface{})
+	if !ok || len(values) != 3 {
+		return 0, "", "", fmt.Errorf("unexpected adopt result: %v", res)
+	}
+	result, _ := values[0].(int64)
+	familyID, _ := values[1].(string)
+	jti, _ := values[2].(string)
+	return int(result), familyID, jti, nil
+}
+
+// revokeRefreshFamily 吊销设备当前的 RefreshToken 家族（登出、踢出设备、检测到重复使用时）
+func revokeRefreshFamily(userUUID uuid.UUID, appID uint, deviceID string) {
+	global.Redis.Del(refreshFamilyKey(userUUID, appID, deviceID))
+}
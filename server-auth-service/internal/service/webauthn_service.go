@@ -0,0 +1,439 @@
+package service
+
+import (
+	"auth-service/internal/model/appTypes"
+	database "auth-service/internal/model/database"
+	"auth-service/internal/model/request"
+	"auth-service/internal/model/response"
+	"auth-service/pkg/global"
+	"auth-service/pkg/webauthn"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type WebAuthnService struct{}
+
+const (
+	webauthnTimeout        = 5 * time.Minute // 注册 / 登录挑战有效期
+	webauthnMaxCredentials = 10              // 单个用户最多绑定的通行密钥数量
+)
+
+var (
+	ErrWebAuthnDisabled       = errors.New("未配置通行密钥登录")
+	ErrWebAuthnSessionInvalid = errors.New("通行密钥操作已过期，请重试")
+	ErrPasskeyNotFound        = errors.New("通行密钥不存在或已被删除")
+)
+
+// 允许保存的传输方式，其余值忽略
+var webauthnTransports = map[string]bool{
+	"usb": true, "nfc": true, "ble": true, "internal": true, "hybrid": true, "smart-card": true,
+}
+
+// BeginRegistration 生成通行密钥注册参数（需已登录）
+func (s *WebAuthnService) BeginRegistration(userUUID uuid.UUID) (*response.WebAuthnCreationOptions, error) {
+	cfg := global.Config.WebAuthn
+	if cfg.RPID == "" {
+		return nil, ErrWebAuthnDisabled
+	}
+
+	var user database.SSOUser
+	if err := global.DB.Where("uuid = ?", userUUID).First(&user).Error; err != nil {
+		return nil, errors.New("用户不存在")
+	}
+
+	var existing []database.SSOWebAuthnCredential
+	if err := global.DB.Where("user_uuid = ?", userUUID).Find(&existing).Error; err != nil {
+		return nil, err
+	}
+	if len(existing) >= webauthnMaxCredentials {
+		return nil, fmt.Errorf("最多只能添加 %d 个通行密钥", webauthnMaxCredentials)
+	}
+
+	challenge, err := webauthn.NewChallenge()
+	if err != nil {
+		return nil, err
+	}
+	session := &appTypes.WebAuthnSession{
+		Challenge: challenge,
+		UserUUID:  userUUID.String(),
+		ExpiresAt: time.Now().Add(webauthnTimeout),
+	}
+	if err := s.saveSession(fmt.Sprintf("webauthn_register:%s", userUUID), session); err != nil {
+		return nil, err
+	}
+
+	params := make([]response.WebAuthnCredentialParam, 0, len(webauthn.SupportedAlgorithms))
+	for _, alg := range webauthn.SupportedAlgorithms {
+		params = append(params, response.WebAuthnCredentialParam{Type: "public-key", Alg: alg})
+	}
+
+	// 第三方登录的用户可能没有邮箱，依次使用邮箱、用户名、UUID 作为账号名
+	accountName := user.Username
+	if user.Email != nil && *user.Email != "" {
+		accountName = *user.Email
+	}
+	if accountName == "" {
+		accountName = user.UUID.String()
+	}
+	displayName := user.Nickname
+	if displayName == "" {
+		displayName = accountName
+	}
+
+	return &response.WebAuthnCreationOptions{
+		Challenge: challenge,
+		RP: response.WebAuthnRelyingParty{
+			ID:   cfg.RPID,
+			Name: cfg.RPName,
+		},
+		User: response.WebAuthnUser{
+			ID:          webauthn.EncodeBase64URL(user.UUID.Bytes()),
+			Name:        accountName,
+			DisplayName: displayName,
+		},
+		PubKeyCredParams:   params,
+		Timeout:            int(webauthnTimeout.Milliseconds()),
+		ExcludeCredentials: credentialDescriptors(existing),
+		// 要求可发现凭证（无需输入账号即可登录）和用户验证（生物识别 / PIN）
+		AuthenticatorSelection: response.WebAuthnAuthenticatorSelection{
+			ResidentKey:      "required",
+			UserVerification: "required",
+		},
+		Attestation: "none",
+	}, nil
+}
+
+// FinishRegistration 校验注册响应并保存通行密钥
+func (s *WebAuthnService) FinishRegistration(c *gin.Context, userUUID uuid.UUID, name string, cred request.WebAuthnCredential) (*response.PasskeyInfo, error) {
+	session, err := s.consumeSession(fmt.Sprintf("webauthn_register:%s", userUUID))
+	if err != nil {
+		return nil, err
+	}
+	if session.UserUUID != userUUID.String() {
+		return nil, ErrWebAuthnSessionInvalid
+	}
+
+	clientData, err := webauthn.DecodeBase64URL(cred.Response.ClientDataJSON)
+	if err != nil {
+		return nil, webauthn.ErrClientData
+	}
+	attestation, err := webauthn.DecodeBase64URL(cred.Response.AttestationObject)
+	if err != nil || len(attestation) == 0 {
+		return nil, errors.New("缺少证明对象")
+	}
+
+	credential, err := webauthn.VerifyRegistration(s.relyingParty(), session.Challenge, clientData, attestation, true)
+	if err != nil {
+		return nil, err
+	}
+
+	credentialID := webauthn.EncodeBase64URL(credential.ID)
+	if strings.TrimRight(cred.ID, "=") != credentialID {
+		return nil, errors.New("凭证 ID 不一致")
+	}
+
+	var count int64
+	global.DB.Model(&database.SSOWebAuthnCredential{}).Where("credential_id = ?", credentialID).Count(&count)
+	if count > 0 {
+		return nil, errors.New("该通行密钥已添加")
+	}
+
+	transports := make([]string, 0, len(cred.Response.Transports))
+	for _, t := range cred.Response.Transports {
+		if webauthnTransports[t] {
+			transports = append(transports, t)
+		}
+	}
+
+	var aaguid string
+	if id, err := uuid.FromBytes(credential.AAGUID); err == nil {
+		aaguid = id.String()
+	}
+
+	record := database.SSOWebAuthnCredential{
+		UserUUID:       userUUID,
+		UserHandle:     userUUID.Bytes(),
+		CredentialID:   credentialID,
+		PublicKey:      credential.PublicKey,
+		Algorithm:      credential.Algorithm,
+		SignCount:      credential.SignCount,
+		Transports:     strings.Join(transports, ","),
+		AAGUID:         aaguid,
+		BackupEligible: credential.BackupEligible,
+		Name:           name,
+	}
+	if err := global.DB.Create(&record).Error; err != nil {
+		return nil, fmt.Errorf("保存通行密钥失败: %w", err)
+	}
+
+	authService := &AuthService{}
+	authService.LogActionWithContext(c, userUUID, 0, "passkey_add", "", "添加通行密钥: "+name, 1)
+
+	return toPasskeyInfo(&record), nil
+}
+
+// BeginLogin 生成通行密钥登录参数
+// 不指定邮箱时使用可发现凭证，由认证器选择账号；指定邮箱时只允许该账号的通行密钥
+func (s *WebAuthnService) BeginLogin(email string) (*response.WebAuthnLoginOptions, error) {
+	cfg := global.Config.WebAuthn
+	if cfg.RPID == "" {
+		return nil, ErrWebAuthnDisabled
+	}
+
+	challenge, err := webauthn.NewChallenge()
+	if err != nil {
+		return nil, err
+	}
+	session := &appTypes.WebAuthnSession{
+		Challenge: challenge,
+		ExpiresAt: time.Now().Add(webauthnTimeout),
+	}
+
+	allow := []response.WebAuthnCredentialDescriptor{}
+	if email != "" {
+		// 邮箱不存在时按未指定处理，避免通过该接口探测账号
+		var user database.SSOUser
+		if err := global.DB.Where("email = ?", email).First(&user).Error; err == nil {
+			var existing []database.SSOWebAuthnCredential
+			global.DB.Where("user_uuid = ?", user.UUID).Find(&existing)
+			if len(existing) > 0 {
+				session.UserUUID = user.UUID.String()
+				allow = credentialDescriptors(existing)
+			}
+		}
+	}
+
+	sessionID := uuid.Must(uuid.NewV4()).String()
+	if err := s.saveSession(fmt.Sprintf("webauthn_login:%s", sessionID), session); err != nil {
+		return nil, err
+	}
+
+	return &response.WebAuthnLoginOptions{
+		SessionID: sessionID,
+		PublicKey: response.WebAuthnRequestOptions{
+			Challenge:        challenge,
+			Timeout:          int(webauthnTimeout.Milliseconds()),
+			RPID:             cfg.RPID,
+			AllowCredentials: allow,
+			UserVerification: "required",
+		},
+	}, nil
+}
+
+// FinishLogin 校验登录响应，通过后与静默登录一样走设备数量限制并签发 Token
+// 通行密钥要求用户验证（生物识别 / PIN），本身即为多因素凭证，因此不再要求两步验证
+func (s *WebAuthnService) FinishLogin(c *gin.Context, req request.WebAuthnLoginRequest, deviceID string) (*response.TokenResponse, error) {
+	session, err := s.consumeSession(fmt.Sprintf("webauthn_login:%s", req.SessionID))
+	if err != nil {
+		return nil, err
+	}
+
+	var record database.SSOWebAuthnCredential
+	err = global.DB.Where("credential_id = ?", strings.TrimRight(req.Credential.ID, "=")).First(&record).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPasskeyNotFound
+		}
+		return nil, err
+	}
+	if session.UserUUID != "" && session.UserUUID != record.UserUUID.String() {
+		return nil, ErrPasskeyNotFound
+	}
+
+	// 可发现凭证会返回 userHandle，必须与注册时写入认证器的句柄一致（早期记录没有保存句柄，即用户UUID）
+	if req.Credential.Response.UserHandle != "" {
+		expected := record.UserHandle
+		if len(expected) == 0 {
+			expected = record.UserUUID.Bytes()
+		}
+		handle, err := webauthn.DecodeBase64URL(req.Credential.Response.UserHandle)
+		if err != nil || !bytes.Equal(handle, expected) {
+			return nil, ErrPasskeyNotFound
+		}
+	}
+
+	clientData, err := webauthn.DecodeBase64URL(req.Credential.Response.ClientDataJSON)
+	if err != nil {
+		return nil, webauthn.ErrClientData
+	}
+	rawAuthData, err := webauthn.DecodeBase64URL(req.Credential.Response.AuthenticatorData)
+	if err != nil {
+		return nil, webauthn.ErrAuthData
+	}
+	signature, err := webauthn.DecodeBase64URL(req.Credential.Response.Signature)
+	if err != nil {
+		return nil, webauthn.ErrSignature
+	}
+
+	authService := &AuthService{}
+	authData, err := webauthn.VerifyAssertion(s.relyingParty(), session.Challenge, record.PublicKey, clientData, rawAuthData, signature, true)
+	if err != nil {
+		authService.LogActionWithContext(c, record.UserUUID, 0, "passkey_failed", deviceID, "通行密钥验证失败: "+err.Error(), 0)
+		return nil, err
+	}
+
+	// 签名计数器：认证器支持计数时必须递增，否则可能是被克隆的凭证
+	if err := webauthn.CheckSignCount(record.SignCount, authData.SignCount); err != nil {
+		global.Log.Warn("通行密钥签名计数异常",
+			zap.String("user_uuid", record.UserUUID.String()),
+			zap.Uint("credential", record.ID),
+			zap.Uint32("stored", record.SignCount),
+			zap.Uint32("received", authData.SignCount),
+		)
+		authService.LogActionWithContext(c, record.UserUUID, 0, "passkey_failed", deviceID, "通行密钥签名计数异常", 0)
+		return nil, errors.New("通行密钥状态异常，请删除后重新添加")
+	}
+
+	now := time.Now()
+	global.DB.Model(&record).Updates(map[string]interface{}{
+		"sign_count":   authData.SignCount,
+		"last_used_at": now,
+	})
+
+	if req.DeviceName != "" {
+		c.Set("session_device_name", req.DeviceName)
+	}
+	if req.DeviceType != "" {
+		c.Set("session_device_type", req.DeviceType)
+	}
+
+	resp, err := authService.GenerateTokensForUser(c, record.UserUUID.String(), req.AppID, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	var appID uint
+	if app, err := authService.GetAppByKey(req.AppID); err == nil {
+		appID = app.ID
+	}
+	authService.LogActionWithContext(c, record.UserUUID, appID, "passkey_login", deviceID, "通行密钥登录成功", 1)
+
+	return resp, nil
+}
+
+// ListPasskeys 获取用户的通行密钥列表
+func (s *WebAuthnService) ListPasskeys(userUUID uuid.UUID) ([]*response.PasskeyInfo, error) {
+	var records []database.SSOWebAuthnCredential
+	err := global.DB.Where("user_uuid = ?", userUUID).Order("created_at DESC").Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*response.PasskeyInfo, 0, len(records))
+	for i := range records {
+		list = append(list, toPasskeyInfo(&records[i]))
+	}
+	return list, nil
+}
+
+// RenamePasskey 重命名通行密钥
+func (s *WebAuthnService) RenamePasskey(userUUID uuid.UUID, id uint, name string) error {
+	result := global.DB.Model(&database.SSOWebAuthnCredential{}).
+		Where("id = ? AND user_uuid = ?", id, userUUID).
+		Update("name", name)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrPasskeyNotFound
+	}
+	return nil
+}
+
+// RevokePasskey 删除通行密钥（物理删除，便于同一认证器重新添加），至少保留一种登录方式
+func (s *WebAuthnService) RevokePasskey(c *gin.Context, userUUID uuid.UUID, id uint) error {
+	var record database.SSOWebAuthnCredential
+	if err := global.DB.Where("id = ? AND user_uuid = ?", id, userUUID).First(&record).Error; err != nil {
+		return ErrPasskeyNotFound
+	}
+
+	methods, err := countLoginMethods(userUUID)
+	if err != nil {
+		return err
+	}
+	if methods <= 1 {
+		return ErrLastLoginMethod
+	}
+	if err := global.DB.Unscoped().Delete(&record).Error; err != nil {
+		return err
+	}
+
+	authService := &AuthService{}
+	authService.LogActionWithContext(c, userUUID, 0, "passkey_remove", "", "删除通行密钥: "+record.Name, 1)
+	return nil
+}
+
+func (s *WebAuthnService) relyingParty() webauthn.RelyingParty {
+	return webauthn.RelyingParty{
+		ID:      global.Config.WebAuthn.RPID,
+		Origins: global.Config.WebAuthn.Origins,
+	}
+}
+
+func (s *WebAuthnService) saveSession(key string, session *appTypes.WebAuthnSession) error {
+	value, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	return global.Redis.Set(key, value, time.Until(session.ExpiresAt)).Err()
+}
+
+// consumeSession 读取并删除挑战，保证每个挑战只能使用一次
+func (s *WebAuthnService) consumeSession(key string) (*appTypes.WebAuthnSession, error) {
+	value, err := global.Redis.Get(key).Result()
+	if err != nil {
+		return nil, ErrWebAuthnSessionInvalid
+	}
+	// 并发请求时只有删除成功的一方可以继续
+	if n, _ := global.Redis.Del(key).Result(); n == 0 {
+		return nil, ErrWebAuthnSessionInvalid
+	}
+
+	var session appTypes.WebAuthnSession
+	if err := json.Unmarshal([]byte(value), &session); err != nil {
+		return nil, ErrWebAuthnSessionInvalid
+	}
+	if time.Now().After(session.ExpiresAt) {
+		return nil, ErrWebAuthnSessionInvalid
+	}
+	return &session, nil
+}
+
+func credentialDescriptors(records []database.SSOWebAuthnCredential) []response.WebAuthnCredentialDescriptor {
+	list := make([]response.WebAuthnCredentialDescriptor, 0, len(records))
+	for _, record := range records {
+		list = append(list, response.WebAuthnCredentialDescriptor{
+			Type:       "public-key",
+			ID:         record.CredentialID,
+			Transports: splitTransports(record.Transports),
+		})
+	}
+	return list
+}
+
+func toPasskeyInfo(record *database.SSOWebAuthnCredential) *response.PasskeyInfo {
+	return &response.PasskeyInfo{
+		ID:             record.ID,
+		Name:           record.Name,
+		Transports:     splitTransports(record.Transports),
+		BackupEligible: record.BackupEligible,
+		CreatedAt:      record.CreatedAt,
+		LastUsedAt:     record.LastUsedAt,
+	}
+}
+
+func splitTransports(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
@@ -5,7 +5,6 @@ import (
 	"auth-service/internal/router"
 	"auth-service/pkg/core"
 	"auth-service/pkg/global"
-	"auth-service/pkg/jwt"
 	"auth-service/scripts/flag"
 	"fmt"
 
@@ -26,20 +25,10 @@ func main() {
 	// 4. 初始化Redis
 	global.Redis = initialize.InitRedis()
 
-	// 5. 加载RSA密钥
-	privateKey, err := jwt.LoadPrivateKey(global.Config.JWT.PrivateKeyPath)
-	if err != nil {
-		global.Log.Fatal("加载私钥失败", zap.Error(err))
-	}
-	global.RSAPrivateKey = privateKey
-
-	publicKey, err := jwt.LoadPublicKey(global.Config.JWT.PublicKeyPath)
-	if err != nil {
-		global.Log.Fatal("加载公钥失败", zap.Error(err))
-	}
-	global.RSAPublicKey = publicKey
+	// 5. 加载RSA签名密钥
+	global.SigningKeys = initialize.InitSigningKeys()
 
-	// 6. 处理命令行标志（如 --sql 进行数据库迁移）
+	// 6. 处理命令行标志（如 --sql 进行数据库迁移、--generate-key 轮换签名密钥）
 	flag.InitFlag()
 
 	// 7. 设置Gin模式
@@ -49,6 +38,11 @@ func main() {
 
 	// 8. 创建路由
 	r := gin.Default()
+	// 客户端 IP 用于登录限流与设备记录，只信任配置的代理转发的 X-Forwarded-For
+	if err := r.SetTrustedProxies(global.Config.Server.TrustedProxies); err != nil {
+		global.Log.Fatal("可信代理配置错误", zap.Error(err))
+	}
+	r.TrustedPlatform = global.Config.Server.TrustedPlatform
 	router.Setup(r)
 
 	// 9. 启动服务
@@ -2,15 +2,19 @@ package config
 
 // Config 应用配置
 type Config struct {
-	Captcha Captcha `yaml:"captcha"`
-	JWT     JWT     `yaml:"jwt"`
-	MySQL   MySQL   `yaml:"mysql"`
-	Redis   Redis   `yaml:"redis"`
-	QQ      QQ      `yaml:"qq"`
-	Email   Email   `yaml:"email"`
-	Server  Server  `yaml:"server"`
-	System  System  `yaml:"system"`
-	Zap     Zap     `yaml:"zap"`
+	Captcha    Captcha    `yaml:"captcha"`
+	JWT        JWT        `yaml:"jwt"`
+	OIDC       OIDC       `yaml:"oidc"`
+	MySQL      MySQL      `yaml:"mysql"`
+	Redis      Redis      `yaml:"redis"`
+	OAuth      OAuth      `yaml:"oauth"`
+	MFA        MFA        `yaml:"mfa"`
+	WebAuthn   WebAuthn   `yaml:"webauthn"`
+	LoginGuard LoginGuard `yaml:"login_guard"`
+	Email      Email      `yaml:"email"`
+	Server     Server     `yaml:"server"`
+	System     System     `yaml:"system"`
+	Zap        Zap        `yaml:"zap"`
 }
 
 // Captcha 验证码配置
@@ -25,13 +29,19 @@ type Captcha struct {
 // JWT 配置
 type JWT struct {
 	Algorithm              string `yaml:"algorithm"`
-	PrivateKeyPath         string `yaml:"private_key_path"`
-	PublicKeyPath          string `yaml:"public_key_path"`
+	PrivateKeyPath         string `yaml:"private_key_path"` // 初始私钥，轮换后仅用于验证旧Token
+	KeysDir                string `yaml:"keys_dir"`         // 轮换密钥目录（--generate-key 生成），为空则只使用 private_key_path
 	AccessTokenExpiryTime  string `yaml:"access_token_expiry_time"`
 	RefreshTokenExpiryTime string `yaml:"refresh_token_expiry_time"`
 	Issuer                 string `yaml:"issuer"`
 }
 
+// OIDC OpenID Connect 提供方配置
+type OIDC struct {
+	Issuer            string `yaml:"issuer"`               // 签发者地址（SSO 站点根地址，不含末尾斜杠），同时用于拼接各端点地址
+	IDTokenExpiryTime string `yaml:"id_token_expiry_time"` // ID Token 过期时间
+}
+
 // MySQL 配置
 type MySQL struct {
 	Host         string `yaml:"host"`
@@ -52,27 +62,53 @@ type Redis struct {
 	DB       int    `yaml:"db"`
 }
 
-// QQ 第三方登录配置
-type QQ struct {
-	Enable      bool   `yaml:"enable"`
-	AppID       string `yaml:"app_id"`
-	AppKey      string `yaml:"app_key"`
-	RedirectURI string `yaml:"redirect_uri"`
+// OAuth 第三方登录配置（每个提供方独立配置）
+type OAuth struct {
+	QQ     OAuthClient `yaml:"qq"`
+	Github OAuthClient `yaml:"github"`
+	Wechat OAuthClient `yaml:"wechat"`
+}
+
+// OAuthClient 第三方登录应用配置
+type OAuthClient struct {
+	Enable       bool   `yaml:"enable"`
+	ClientID     string `yaml:"client_id"`     // QQ 为 APP ID，微信为 AppID
+	ClientSecret string `yaml:"client_secret"` // QQ 为 APP Key，微信为 AppSecret
+	RedirectURI  string `yaml:"redirect_uri"`  // 回调地址：/api/auth/oauth/<provider>/callback
+}
+
+// MFA 两步验证配置
+type MFA struct {
+	Issuer string `yaml:"issuer"` // 身份验证器中显示的签发方名称
+}
+
+// WebAuthn 通行密钥配置
+type WebAuthn struct {
+	RPID    string   `yaml:"rp_id"`   // 依赖方 ID，填写 SSO 站点域名（不含协议和端口）
+	RPName  string   `yaml:"rp_name"` // 浏览器提示中显示的站点名称
+	Origins []string `yaml:"origins"` // 允许发起通行密钥操作的来源
 }
 
-// QQLoginURL 生成QQ登录URL
-func (qq QQ) QQLoginURL() string {
-	return "https://graph.qq.com/oauth2.0/authorize?" +
-		"response_type=code&" +
-		"client_id=" + qq.AppID + "&" +
-		"redirect_uri=" + qq.RedirectURI
+// LoginGuard 登录防暴力破解配置，失败次数均按滑动窗口统计，未配置（0 或空）时使用默认值
+type LoginGuard struct {
+	Window          string `yaml:"window"`            // 统计失败次数的滑动窗口，默认 15m
+	CaptchaAfter    int    `yaml:"captcha_after"`     // 账号或 IP 失败达到该次数后，验证码登录也需要图片验证码，默认 3
+	DelayAfter      int    `yaml:"delay_after"`       // 账号失败达到该次数后，每次失败需等待递增时间（1s、2s、4s……），默认 3
+	MaxDelay        string `yaml:"max_delay"`         // 递增等待的上限，默认 30s
+	AccountLimit    int    `yaml:"account_limit"`     // 单个账号失败达到该次数后锁定，默认 10
+	IPLimit         int    `yaml:"ip_limit"`          // 单个 IP 失败达到该次数后锁定，默认 30
+	SubnetLimit     int    `yaml:"subnet_limit"`      // 同一网段（IPv4 /24、IPv6 /64）失败达到该次数后锁定，默认 100
+	LockDuration    string `yaml:"lock_duration"`     // 锁定时长，默认 15m
+	CodeMaxAttempts int    `yaml:"code_max_attempts"` // 单个邮箱验证码允许输错的次数，超过后验证码作废，默认 5
 }
 
 // Server 服务器配置
 type Server struct {
-	Host string `yaml:"host"`
-	Port int    `yaml:"port"`
-	Mode string `yaml:"mode"`
+	Host            string   `yaml:"host"`
+	Port            int      `yaml:"port"`
+	Mode            string   `yaml:"mode"`
+	TrustedProxies  []string `yaml:"trusted_proxies"`  // 可信反向代理的 IP 或 CIDR，仅信任来自这些地址的 X-Forwarded-For，为空则不信任任何代理
+	TrustedPlatform string   `yaml:"trusted_platform"` // 由平台提供客户端 IP 的请求头（如 CF-Connecting-IP），设置后优先于 X-Forwarded-For
 }
 
 // System 系统配置
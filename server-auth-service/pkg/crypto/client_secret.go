@@ -0,0 +1,36 @@
+package crypto
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// GenerateClientSecret 生成应用密钥（32 字节随机数，URL 安全 Base64）
+func GenerateClientSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// HashClientSecret 计算应用密钥哈希。密钥本身是高熵随机串，SHA-256 即可，校验时无需 bcrypt 的开销
+func HashClientSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// CompareClientSecret 以常量时间比较密钥与哈希
+func CompareClientSecret(secret, hash string) bool {
+	return subtle.ConstantTimeCompare([]byte(HashClientSecret(secret)), []byte(hash)) == 1
+}
+
+// CompareSecretPlain 以常量时间比较两个明文密钥（用于尚未迁移的旧密钥）
+func CompareSecretPlain(secret, expected string) bool {
+	a := sha256.Sum256([]byte(secret))
+	b := sha256.Sum256([]byte(expected))
+	return subtle.ConstantTimeCompare(a[:], b[:]) == 1
+}
@@ -15,3 +15,17 @@ func (e *CooldownError) Error() string {
 func NewCooldownError(remainingSeconds int) *CooldownError {
 	return &CooldownError{RemainingSeconds: remainingSeconds}
 }
+
+// LockedError 登录失败次数过多导致的临时锁定错误，包含剩余秒数
+type LockedError struct {
+	RemainingSeconds int
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("登录失败次数过多，已临时锁定，请 %d 分钟后再试", (e.RemainingSeconds+59)/60)
+}
+
+// NewLockedError 创建锁定错误
+func NewLockedError(remainingSeconds int) *LockedError {
+	return &LockedError{RemainingSeconds: remainingSeconds}
+}
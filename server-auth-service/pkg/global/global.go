@@ -2,7 +2,7 @@ package global
 
 import (
 	"auth-service/pkg/config"
-	"crypto/rsa"
+	"auth-service/pkg/jwt"
 
 	"github.com/go-redis/redis"
 	"github.com/songzhibin97/gkit/cache/local_cache"
@@ -11,11 +11,10 @@ import (
 )
 
 var (
-	Config        *config.Config
-	Log           *zap.Logger
-	DB            *gorm.DB
-	Redis         *redis.Client
-	BlackCache    local_cache.Cache
-	RSAPrivateKey *rsa.PrivateKey // RSA私钥（签名）
-	RSAPublicKey  *rsa.PublicKey  // RSA公钥（验证）
+	Config      *config.Config
+	Log         *zap.Logger
+	DB          *gorm.DB
+	Redis       *redis.Client
+	BlackCache  local_cache.Cache
+	SigningKeys *jwt.KeySet // JWT签名密钥集合（当前签名密钥 + 轮换前的验证密钥）
 )
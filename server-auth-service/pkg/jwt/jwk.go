@@ -0,0 +1,56 @@
+package jwt
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+)
+
+// JWK RSA公钥的JSON Web Key表示（RFC 7517）
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// JWKSet JWKS端点返回的公钥集合
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// NewJWK 将RSA公钥转换为签名用途的JWK
+func NewJWK(publicKey *rsa.PublicKey) JWK {
+	n, e := encodeRSAPublicKey(publicKey)
+	return JWK{
+		Kty: "RSA",
+		Use: "sig",
+		Alg: "RS256",
+		Kid: KeyID(publicKey),
+		N:   n,
+		E:   e,
+	}
+}
+
+// KeyID 计算公钥的JWK指纹（RFC 7638），作为kid使用，密钥不变则kid不变
+func KeyID(publicKey *rsa.PublicKey) string {
+	n, e := encodeRSAPublicKey(publicKey)
+	// 指纹输入要求成员按字典序排列且无空白
+	thumbprint, _ := json.Marshal(struct {
+		E   string `json:"e"`
+		Kty string `json:"kty"`
+		N   string `json:"n"`
+	}{e, "RSA", n})
+	sum := sha256.Sum256(thumbprint)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func encodeRSAPublicKey(publicKey *rsa.PublicKey) (n, e string) {
+	n = base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+	e = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+	return n, e
+}
@@ -21,17 +21,21 @@ type AccessTokenClaims struct {
 	UserUUID  uuid.UUID `json:"user_uuid"`
 	AppID     string    `json:"app_id"` // app_key（字符串），不是数字 ID
 	DeviceID  string    `json:"device_id"`
-	TokenType string    `json:"token_type"` // access_token
+	TokenType string    `json:"token_type"`      // access_token
+	Scope     string    `json:"scope,omitempty"` // OIDC 授权范围（空格分隔），自有应用登录时为空
 	jwt.RegisteredClaims
 }
 
 // RefreshTokenClaims RefreshToken载荷
 // 注意：AppID 存储的是 app_key（字符串），不是数据库中的数字 ID
+// 每次刷新都会轮换 jti（RegisteredClaims.ID），同一次登录签发的 RefreshToken 共用 FamilyID
 type RefreshTokenClaims struct {
 	UserUUID  uuid.UUID `json:"user_uuid"`
 	AppID     string    `json:"app_id"` // app_key（字符串），不是数字 ID
 	DeviceID  string    `json:"device_id"`
-	TokenType string    `json:"token_type"` // refresh_token
+	TokenType string    `json:"token_type"`      // refresh_token
+	Scope     string    `json:"scope,omitempty"` // 刷新时沿用的授权范围
+	FamilyID  string    `json:"fid"`             // 令牌家族ID
 	jwt.RegisteredClaims
 }
 
@@ -40,6 +44,7 @@ func CreateAccessToken(
 	userUUID uuid.UUID,
 	appID string,
 	deviceID string,
+	scope string,
 	expiry time.Duration,
 	issuer string,
 	privateKey *rsa.PrivateKey,
@@ -50,6 +55,7 @@ func CreateAccessToken(
 		AppID:     appID,
 		DeviceID:  deviceID,
 		TokenType: "access_token",
+		Scope:     scope,
 		RegisteredClaims: jwt.RegisteredClaims{
 			Issuer:    issuer,
 			IssuedAt:  jwt.NewNumericDate(now),
@@ -58,15 +64,17 @@ func CreateAccessToken(
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
-	return token.SignedString(privateKey)
+	return sign(claims, privateKey)
 }
 
-// CreateRefreshToken 创建RefreshToken
+// CreateRefreshToken 创建RefreshToken，familyID 和 jti 由调用方生成并登记
 func CreateRefreshToken(
 	userUUID uuid.UUID,
 	appID string,
 	deviceID string,
+	scope string,
+	familyID string,
+	jti string,
 	expiry time.Duration,
 	issuer string,
 	privateKey *rsa.PrivateKey,
@@ -77,7 +85,10 @@ func CreateRefreshToken(
 		AppID:     appID,
 		DeviceID:  deviceID,
 		TokenType: "refresh_token",
+		Scope:     scope,
+		FamilyID:  familyID,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
 			Issuer:    issuer,
 			IssuedAt:  jwt.NewNumericDate(now),
 			ExpiresAt: jwt.NewNumericDate(now.Add(expiry)),
@@ -85,18 +96,19 @@ func CreateRefreshToken(
 		},
 	}
 
+	return sign(claims, privateKey)
+}
+
+// sign 使用RS256签名，并在头部写入kid便于验证方从JWKS中选择公钥
+func sign(claims jwt.Claims, privateKey *rsa.PrivateKey) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = KeyID(&privateKey.PublicKey)
 	return token.SignedString(privateKey)
 }
 
 // ParseAccessToken 解析AccessToken
-func ParseAccessToken(tokenString string, publicKey *rsa.PublicKey) (*AccessTokenClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &AccessTokenClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
-			return nil, ErrTokenInvalid
-		}
-		return publicKey, nil
-	})
+func ParseAccessToken(tokenString string, keys *KeySet) (*AccessTokenClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &AccessTokenClaims{}, keys.keyFunc)
 
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
@@ -113,13 +125,8 @@ func ParseAccessToken(tokenString string, publicKey *rsa.PublicKey) (*AccessToke
 }
 
 // ParseAccessTokenIgnoreExpiry 解析AccessToken（忽略过期）
-func ParseAccessTokenIgnoreExpiry(tokenString string, publicKey *rsa.PublicKey) (*AccessTokenClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &AccessTokenClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
-			return nil, ErrTokenInvalid
-		}
-		return publicKey, nil
-	}, jwt.WithoutClaimsValidation())
+func ParseAccessTokenIgnoreExpiry(tokenString string, keys *KeySet) (*AccessTokenClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &AccessTokenClaims{}, keys.keyFunc, jwt.WithoutClaimsValidation())
 
 	if err != nil {
 		return nil, ErrTokenInvalid
@@ -133,13 +140,8 @@ func ParseAccessTokenIgnoreExpiry(tokenString string, publicKey *rsa.PublicKey)
 }
 
 // ParseRefreshToken 解析RefreshToken
-func ParseRefreshToken(tokenString string, publicKey *rsa.PublicKey) (*RefreshTokenClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &RefreshTokenClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
-			return nil, ErrTokenInvalid
-		}
-		return publicKey, nil
-	})
+func ParseRefreshToken(tokenString string, keys *KeySet) (*RefreshTokenClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &RefreshTokenClaims{}, keys.keyFunc)
 
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
@@ -0,0 +1,265 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	// activeKeyFile 密钥目录中记录当前签名密钥 kid 的文件
+	activeKeyFile = "active"
+	// pendingKeyFile 密钥目录中记录待激活签名密钥的文件，内容为 "<kid> <生效时间Unix秒>"
+	pendingKeyFile = "pending"
+)
+
+var ErrKeyNotFound = errors.New("未找到对应kid的签名公钥")
+
+// KeySet 签名密钥集合：只有一把密钥用于签名，其余保留用于验证轮换前签发的Token
+//
+// 密钥目录结构：
+//
+//	<dir>/<kid>.pem  PKCS1 私钥，kid 为公钥的JWK指纹
+//	<dir>/active     当前签名密钥的 kid
+//	<dir>/pending    待激活的签名密钥及生效时间，到期后覆盖 active
+type KeySet struct {
+	mu        sync.RWMutex
+	dir       string
+	legacy    *rsa.PrivateKey // 配置文件中的单一私钥，未轮换过时作为签名密钥
+	signing   *rsa.PrivateKey
+	verifying map[string]*rsa.PublicKey
+}
+
+// NewKeySet 从密钥目录加载密钥集合，legacy 为配置文件中的私钥（可为 nil）
+func NewKeySet(dir string, legacy *rsa.PrivateKey) (*KeySet, error) {
+	ks := &KeySet{dir: dir, legacy: legacy}
+	if err := ks.Reload(); err != nil {
+		return nil, err
+	}
+	return ks, nil
+}
+
+// Reload 重新读取密钥目录，用于在不重启服务的情况下切换签名密钥
+func (ks *KeySet) Reload() error {
+	verifying := make(map[string]*rsa.PublicKey)
+	var signing *rsa.PrivateKey
+
+	if ks.legacy != nil {
+		verifying[KeyID(&ks.legacy.PublicKey)] = &ks.legacy.PublicKey
+		signing = ks.legacy
+	}
+
+	if ks.dir != "" {
+		keys, err := loadKeyDir(ks.dir)
+		if err != nil {
+			return err
+		}
+		for kid, key := range keys {
+			verifying[kid] = &key.PublicKey
+		}
+
+		activeKid, err := readActiveKid(ks.dir)
+		if err != nil {
+			return err
+		}
+		if activeKid != "" {
+			key, ok := keys[activeKid]
+			if !ok {
+				return fmt.Errorf("当前签名密钥 %s 不存在", activeKid)
+			}
+			signing = key
+		}
+
+		// 新密钥先随目录中的其他密钥发布到 JWKS，到达生效时间后各实例再切换签名
+		pendingKid, activateAt, err := readPendingKid(ks.dir)
+		if err != nil {
+			return err
+		}
+		if pendingKid != "" && !time.Now().Before(activateAt) {
+			key, ok := keys[pendingKid]
+			if !ok {
+				return fmt.Errorf("待激活签名密钥 %s 不存在", pendingKid)
+			}
+			signing = key
+		}
+	}
+
+	if signing == nil {
+		return errors.New("没有可用的签名密钥")
+	}
+
+	ks.mu.Lock()
+	ks.signing = signing
+	ks.verifying = verifying
+	ks.mu.Unlock()
+	return nil
+}
+
+// SigningKey 当前用于签名的私钥
+func (ks *KeySet) SigningKey() *rsa.PrivateKey {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	return ks.signing
+}
+
+// PublicKey 按 kid 查找验证公钥，kid 为空（轮换前签发的旧Token）时使用配置文件中的公钥
+func (ks *KeySet) PublicKey(kid string) (*rsa.PublicKey, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	if kid == "" {
+		if ks.legacy != nil {
+			return &ks.legacy.PublicKey, nil
+		}
+		return &ks.signing.PublicKey, nil
+	}
+	if key, ok := ks.verifying[kid]; ok {
+		return key, nil
+	}
+	return nil, ErrKeyNotFound
+}
+
+// JWKS 返回所有验证公钥，当前签名密钥排在最前
+func (ks *KeySet) JWKS() JWKSet {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	activeKid := KeyID(&ks.signing.PublicKey)
+	set := JWKSet{Keys: []JWK{NewJWK(&ks.signing.PublicKey)}}
+	for kid, key := range ks.verifying {
+		if kid != activeKid {
+			set.Keys = append(set.Keys, NewJWK(key))
+		}
+	}
+	sort.SliceStable(set.Keys[1:], func(i, j int) bool { return set.Keys[i+1].Kid < set.Keys[j+1].Kid })
+	return set
+}
+
+// keyFunc 供 jwt.ParseWithClaims 使用，按头部 kid 选择公钥
+func (ks *KeySet) keyFunc(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+		return nil, ErrTokenInvalid
+	}
+	kid, _ := token.Header["kid"].(string)
+	return ks.PublicKey(kid)
+}
+
+// GenerateSigningKey 在密钥目录中生成新的RSA私钥，返回其 kid（不会激活）
+func GenerateSigningKey(dir string, bits int) (string, error) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, bits)
+	if err != nil {
+		return "", fmt.Errorf("生成RSA密钥失败: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("创建密钥目录失败: %w", err)
+	}
+
+	kid := KeyID(&privateKey.PublicKey)
+	data := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(privateKey),
+	})
+	if err := os.WriteFile(filepath.Join(dir, kid+".pem"), data, 0600); err != nil {
+		return "", fmt.Errorf("写入私钥失败: %w", err)
+	}
+	return kid, nil
+}
+
+// ActivateSigningKey 立即将密钥目录中的指定密钥设为签名密钥，其余密钥继续用于验证；同时取消待激活的密钥
+func ActivateSigningKey(dir, kid string) error {
+	if _, err := LoadPrivateKey(filepath.Join(dir, kid+".pem")); err != nil {
+		return fmt.Errorf("加载密钥 %s 失败: %w", kid, err)
+	}
+	if err := writeKeyFile(dir, activeKeyFile, kid+"\n"); err != nil {
+		return err
+	}
+	if err := os.Remove(filepath.Join(dir, pendingKeyFile)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// ScheduleSigningKey 计划在 activateAt 将指定密钥设为签名密钥。
+// 生效前各实例重新加载密钥目录时只在 JWKS 中发布新公钥，资源服务有时间拉取；
+// 上一次计划已生效时先写入 active，避免被本次计划覆盖后回退
+func ScheduleSigningKey(dir, kid string, activateAt time.Time) error {
+	if _, err := LoadPrivateKey(filepath.Join(dir, kid+".pem")); err != nil {
+		return fmt.Errorf("加载密钥 %s 失败: %w", kid, err)
+	}
+	pendingKid, pendingAt, err := readPendingKid(dir)
+	if err != nil {
+		return err
+	}
+	if pendingKid != "" && !time.Now().Before(pendingAt) {
+		if err := writeKeyFile(dir, activeKeyFile, pendingKid+"\n"); err != nil {
+			return err
+		}
+	}
+	return writeKeyFile(dir, pendingKeyFile, fmt.Sprintf("%s %d\n", kid, activateAt.Unix()))
+}
+
+// writeKeyFile 先写临时文件再改名，避免运行中的服务读到半截内容
+func writeKeyFile(dir, name, content string) error {
+	tmp := filepath.Join(dir, name+".tmp")
+	if err := os.WriteFile(tmp, []byte(content), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(dir, name))
+}
+
+// loadKeyDir 读取目录中的全部私钥，目录不存在时返回空集合
+func loadKeyDir(dir string) (map[string]*rsa.PrivateKey, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	keys := make(map[string]*rsa.PrivateKey, len(files))
+	for _, file := range files {
+		key, err := LoadPrivateKey(file)
+		if err != nil {
+			return nil, fmt.Errorf("加载密钥 %s 失败: %w", filepath.Base(file), err)
+		}
+		keys[KeyID(&key.PublicKey)] = key
+	}
+	return keys, nil
+}
+
+func readActiveKid(dir string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(dir, activeKeyFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+func readPendingKid(dir string) (string, time.Time, error) {
+	data, err := os.ReadFile(filepath.Join(dir, pendingKeyFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", time.Time{}, nil
+	}
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) != 2 {
+		return "", time.Time{}, fmt.Errorf("待激活密钥文件格式错误: %q", strings.TrimSpace(string(data)))
+	}
+	unix, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("待激活密钥生效时间无效: %w", err)
+	}
+	return fields[0], time.Unix(unix, 0), nil
+}
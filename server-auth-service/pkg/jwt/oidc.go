@@ -0,0 +1,35 @@
+package jwt
+
+import (
+	"crypto/rsa"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// IDTokenClaims OpenID Connect ID Token载荷
+// sub 为用户UUID，aud 为应用的 app_key（即 client_id）
+type IDTokenClaims struct {
+	Nonce           string `json:"nonce,omitempty"`
+	AuthTime        int64  `json:"auth_time,omitempty"`
+	AuthorizedParty string `json:"azp,omitempty"`
+	Name            string `json:"name,omitempty"`    // scope=profile
+	Picture         string `json:"picture,omitempty"` // scope=profile
+	Email           string `json:"email,omitempty"`   // scope=email
+	EmailVerified   *bool  `json:"email_verified,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// CreateIDToken 创建ID Token，调用方负责填充 sub/aud/nonce 及按 scope 提供的用户声明
+func CreateIDToken(
+	claims IDTokenClaims,
+	expiry time.Duration,
+	issuer string,
+	privateKey *rsa.PrivateKey,
+) (string, error) {
+	now := time.Now()
+	claims.Issuer = issuer
+	claims.IssuedAt = jwt.NewNumericDate(now)
+	claims.ExpiresAt = jwt.NewNumericDate(now.Add(expiry))
+	return sign(claims, privateKey)
+}
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"auth-service/pkg/core"
 	"auth-service/pkg/global"
 	"encoding/base64"
 	"encoding/json"
@@ -197,3 +198,45 @@ func ValidateRedirectURI(appID, redirectURI string) error {
 
 	return fmt.Errorf("redirect_uri不在白名单中: %s", baseURI)
 }
+
+// CheckRedirectURIPattern 校验白名单中的回调地址：必须是绝对地址，不能包含通配符、查询参数、片段或用户信息。
+// http(s) 以外的 scheme 用于原生应用（如 com.example.app:/callback）
+func CheckRedirectURIPattern(uri string) error {
+	if strings.ContainsAny(uri, "*, \t") {
+		return fmt.Errorf("回调地址不能包含通配符、逗号或空白: %s", uri)
+	}
+	parsed, err := url.Parse(uri)
+	if err != nil || parsed.Scheme == "" {
+		return fmt.Errorf("回调地址必须是绝对地址: %s", uri)
+	}
+	if parsed.RawQuery != "" || parsed.Fragment != "" || parsed.User != nil {
+		return fmt.Errorf("回调地址不能包含查询参数、片段或用户信息: %s", uri)
+	}
+	switch parsed.Scheme {
+	case "https":
+	case "http":
+		// 正式环境的明文回调仅允许本机地址（原生应用回环重定向）
+		host := parsed.Hostname()
+		if core.IsProduction() && host != "localhost" && host != "127.0.0.1" && host != "::1" {
+			return fmt.Errorf("回调地址必须使用 https: %s", uri)
+		}
+	case "javascript", "data", "file", "vbscript":
+		return fmt.Errorf("不支持的回调地址协议: %s", uri)
+	}
+	if (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host == "" {
+		return fmt.Errorf("回调地址缺少域名: %s", uri)
+	}
+	return nil
+}
+
+// NormalizeOrigin 校验并规范化 CORS 来源，只保留 scheme://host[:port]
+func NormalizeOrigin(origin string) (string, error) {
+	parsed, err := url.Parse(strings.TrimSpace(origin))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return "", fmt.Errorf("来源格式错误: %s", origin)
+	}
+	if (parsed.Path != "" && parsed.Path != "/") || parsed.RawQuery != "" || parsed.Fragment != "" || parsed.User != nil {
+		return "", fmt.Errorf("来源只能包含协议、域名和端口: %s", origin)
+	}
+	return strings.ToLower(parsed.Scheme + "://" + parsed.Host), nil
+}
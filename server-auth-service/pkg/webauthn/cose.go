@@ -0,0 +1,146 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// COSE 算法标识
+const (
+	AlgES256 int64 = -7   // ECDSA P-256 + SHA-256
+	AlgEdDSA int64 = -8   // Ed25519
+	AlgRS256 int64 = -257 // RSASSA-PKCS1-v1_5 + SHA-256
+)
+
+// SupportedAlgorithms 注册时声明支持的算法（按优先级）
+var SupportedAlgorithms = []int64{AlgES256, AlgEdDSA, AlgRS256}
+
+// COSE 密钥类型
+const (
+	coseKtyOKP int64 = 1
+	coseKtyEC2 int64 = 2
+	coseKtyRSA int64 = 3
+
+	coseCrvP256    int64 = 1
+	coseCrvEd25519 int64 = 6
+)
+
+// coseKey COSE_Key 的通用字段，-1/-2/-3 的含义随密钥类型变化
+type coseKey struct {
+	Kty  int64           `cbor:"1,keyasint"`
+	Alg  int64           `cbor:"3,keyasint"`
+	Arg1 cbor.RawMessage `cbor:"-1,keyasint"`
+	Arg2 cbor.RawMessage `cbor:"-2,keyasint"`
+	Arg3 cbor.RawMessage `cbor:"-3,keyasint"`
+}
+
+// PublicKeyAlgorithm 解析 COSE 公钥并返回其算法，不支持的算法返回错误
+func PublicKeyAlgorithm(coseKeyBytes []byte) (int64, error) {
+	key, _, err := parsePublicKey(coseKeyBytes)
+	if err != nil {
+		return 0, err
+	}
+	return key.Alg, nil
+}
+
+// VerifySignature 使用 COSE 公钥校验签名
+func VerifySignature(coseKeyBytes, data, signature []byte) error {
+	key, pub, err := parsePublicKey(coseKeyBytes)
+	if err != nil {
+		return err
+	}
+
+	switch key.Alg {
+	case AlgES256:
+		digest := sha256.Sum256(data)
+		if !ecdsa.VerifyASN1(pub.(*ecdsa.PublicKey), digest[:], signature) {
+			return ErrSignature
+		}
+	case AlgRS256:
+		digest := sha256.Sum256(data)
+		if err := rsa.VerifyPKCS1v15(pub.(*rsa.PublicKey), crypto.SHA256, digest[:], signature); err != nil {
+			return ErrSignature
+		}
+	case AlgEdDSA:
+		if !ed25519.Verify(pub.(ed25519.PublicKey), data, signature) {
+			return ErrSignature
+		}
+	default:
+		return ErrUnsupportedPK
+	}
+	return nil
+}
+
+func parsePublicKey(coseKeyBytes []byte) (*coseKey, crypto.PublicKey, error) {
+	var key coseKey
+	if err := cbor.Unmarshal(coseKeyBytes, &key); err != nil {
+		return nil, nil, fmt.Errorf("解析凭证公钥失败: %w", err)
+	}
+
+	switch {
+	case key.Kty == coseKtyEC2 && key.Alg == AlgES256:
+		var crv int64
+		var x, y []byte
+		if err := decodeArgs(&key, &crv, &x, &y); err != nil || crv != coseCrvP256 || len(x) != 32 || len(y) != 32 {
+			return nil, nil, ErrUnsupportedPK
+		}
+		pub := &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}
+		if !pub.Curve.IsOnCurve(pub.X, pub.Y) {
+			return nil, nil, ErrUnsupportedPK
+		}
+		return &key, pub, nil
+
+	case key.Kty == coseKtyOKP && key.Alg == AlgEdDSA:
+		var crv int64
+		var x []byte
+		if err := decodeArgs(&key, &crv, &x, nil); err != nil || crv != coseCrvEd25519 || len(x) != ed25519.PublicKeySize {
+			return nil, nil, ErrUnsupportedPK
+		}
+		return &key, ed25519.PublicKey(x), nil
+
+	case key.Kty == coseKtyRSA && key.Alg == AlgRS256:
+		var n, e []byte
+		if err := decodeArgs(&key, &n, &e, nil); err != nil || len(n) < 256 || len(e) == 0 || len(e) > 4 {
+			return nil, nil, ErrUnsupportedPK
+		}
+		pub := &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+		return &key, pub, nil
+	}
+
+	return nil, nil, ErrUnsupportedPK
+}
+
+// decodeArgs 按密钥类型解码 -1/-2/-3 参数，传 nil 表示跳过
+func decodeArgs(key *coseKey, arg1, arg2, arg3 interface{}) error {
+	args := []struct {
+		raw cbor.RawMessage
+		dst interface{}
+	}{{key.Arg1, arg1}, {key.Arg2, arg2}, {key.Arg3, arg3}}
+	for _, arg := range args {
+		if arg.dst == nil {
+			continue
+		}
+		if len(arg.raw) == 0 {
+			return ErrUnsupportedPK
+		}
+		if err := cbor.Unmarshal(arg.raw, arg.dst); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+package webauthn
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// 认证器数据标志位
+const (
+	FlagUserPresent      byte = 0x01 // UP：用户在场
+	FlagUserVerified     byte = 0x04 // UV：已验证用户（生物识别 / PIN）
+	FlagBackupEligible   byte = 0x08 // BE：凭证可同步备份
+	FlagBackupState      byte = 0x10 // BS：凭证已同步备份
+	FlagAttestedCredData byte = 0x40 // AT：包含凭证数据（注册时）
+)
+
+var (
+	ErrClientData    = errors.New("客户端数据无效")
+	ErrChallenge     = errors.New("挑战不匹配或已过期")
+	ErrOrigin        = errors.New("来源不受信任")
+	ErrRPID          = errors.New("依赖方 ID 不匹配")
+	ErrUserPresence  = errors.New("未检测到用户操作")
+	ErrUserVerified  = errors.New("认证器未验证用户身份")
+	ErrAuthData      = errors.New("认证器数据无效")
+	ErrSignature     = errors.New("签名验证失败")
+	ErrUnsupportedPK = errors.New("不支持的公钥算法")
+	ErrSignCount     = errors.New("签名计数器未递增")
+)
+
+// RelyingParty 依赖方参数（注册与认证时校验）
+type RelyingParty struct {
+	ID      string   // 依赖方 ID，通常为站点域名
+	Origins []string // 允许的来源，例如 https://sso.example.com
+}
+
+// CollectedClientData 浏览器生成的 clientDataJSON
+type CollectedClientData struct {
+	Type        string `json:"type"`
+	Challenge   string `json:"challenge"`
+	Origin      string `json:"origin"`
+	CrossOrigin bool   `json:"crossOrigin"`
+}
+
+// AuthenticatorData 认证器数据
+type AuthenticatorData struct {
+	RPIDHash     []byte
+	Flags        byte
+	SignCount    uint32
+	AAGUID       []byte // 仅注册时存在
+	CredentialID []byte // 仅注册时存在
+	PublicKey    []byte // COSE 格式公钥，仅注册时存在
+}
+
+// Has 判断是否设置了指定标志位
+func (a *AuthenticatorData) Has(flag byte) bool {
+	return a.Flags&flag == flag
+}
+
+// Credential 注册成功后需要保存的凭证信息
+type Credential struct {
+	ID             []byte
+	PublicKey      []byte
+	Algorithm      int64
+	SignCount      uint32
+	AAGUID         []byte
+	BackupEligible bool
+}
+
+// attestationObject 注册时返回的证明对象（只使用 authData，证明声明不做校验）
+type attestationObject struct {
+	Fmt      string          `cbor:"fmt"`
+	AttStmt  cbor.RawMessage `cbor:"attStmt"`
+	AuthData []byte          `cbor:"authData"`
+}
+
+// NewChallenge 生成 32 字节随机挑战（base64url 编码）
+func NewChallenge() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return EncodeBase64URL(b), nil
+}
+
+// EncodeBase64URL 无填充 base64url 编码（WebAuthn JSON 序列化格式）
+func EncodeBase64URL(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeBase64URL 解码 base64url，兼容带填充的写法
+func DecodeBase64URL(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
+
+// VerifyRegistration 校验注册响应，返回需要保存的凭证
+// 证明格式统一按 none 处理：只信任认证器数据，不校验证明声明和证书链
+func VerifyRegistration(rp RelyingParty, challenge string, clientDataJSON, attestation []byte, requireUV bool) (*Credential, error) {
+	if err := verifyClientData(rp, "webauthn.create", challenge, clientDataJSON); err != nil {
+		return nil, err
+	}
+
+	var att attestationObject
+	if err := cbor.Unmarshal(attestation, &att); err != nil {
+		return nil, fmt.Errorf("解析证明对象失败: %w", err)
+	}
+
+	authData, err := ParseAuthenticatorData(att.AuthData)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyAuthData(rp, authData, requireUV); err != nil {
+		return nil, err
+	}
+	if !authData.Has(FlagAttestedCredData) {
+		return nil, ErrAuthData
+	}
+
+	alg, err := PublicKeyAlgorithm(authData.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Credential{
+		ID:             authData.CredentialID,
+		PublicKey:      authData.PublicKey,
+		Algorithm:      alg,
+		SignCount:      authData.SignCount,
+		AAGUID:         authData.AAGUID,
+		BackupEligible: authData.Has(FlagBackupEligible),
+	}, nil
+}
+
+// VerifyAssertion 校验认证响应（签名覆盖 authenticatorData || SHA-256(clientDataJSON)）
+func VerifyAssertion(rp RelyingParty, challenge string, publicKey, clientDataJSON, rawAuthData, signature []byte, requireUV bool) (*AuthenticatorData, error) {
+	if err := verifyClientData(rp, "webauthn.get", challenge, clientDataJSON); err != nil {
+		return nil, err
+	}
+
+	authData, err := ParseAuthenticatorData(rawAuthData)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyAuthData(rp, authData, requireUV); err != nil {
+		return nil, err
+	}
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signed := make([]byte, 0, len(rawAuthData)+len(clientDataHash))
+	signed = append(signed, rawAuthData...)
+	signed = append(signed, clientDataHash[:]...)
+	if err := VerifySignature(publicKey, signed, signature); err != nil {
+		return nil, err
+	}
+
+	return authData, nil
+}
+
+// CheckSignCount 校验签名计数器：认证器支持计数（任一方非 0）时必须严格递增，否则可能是被克隆的凭证
+func CheckSignCount(stored, received uint32) error {
+	if (received != 0 || stored != 0) && received <= stored {
+		return ErrSignCount
+	}
+	return nil
+}
+
+// ParseAuthenticatorData 解析认证器数据
+// 结构：rpIdHash(32) | flags(1) | signCount(4) | [aaguid(16) | credIdLen(2) | credId | COSE 公钥]
+func ParseAuthenticatorData(data []byte) (*AuthenticatorData, error) {
+	if len(data) < 37 {
+		return nil, ErrAuthData
+	}
+	authData := &AuthenticatorData{
+		RPIDHash:  data[:32],
+		Flags:     data[32],
+		SignCount: binary.BigEndian.Uint32(data[33:37]),
+	}
+	if !authData.Has(FlagAttestedCredData) {
+		return authData, nil
+	}
+
+	rest := data[37:]
+	if len(rest) < 18 {
+		return nil, ErrAuthData
+	}
+	authData.AAGUID = rest[:16]
+	idLen := int(binary.BigEndian.Uint16(rest[16:18]))
+	rest = rest[18:]
+	if idLen == 0 || idLen > 1023 || len(rest) < idLen {
+		return nil, ErrAuthData
+	}
+	authData.CredentialID = rest[:idLen]
+	rest = rest[idLen:]
+
+	// 公钥后面可能还有扩展数据，只截取第一个 CBOR 对象
+	var key cbor.RawMessage
+	if _, err := cbor.UnmarshalFirst(rest, &key); err != nil {
+		return nil, fmt.Errorf("解析凭证公钥失败: %w", err)
+	}
+	authData.PublicKey = key
+	return authData, nil
+}
+
+func verifyClientData(rp RelyingParty, ceremony, challenge string, clientDataJSON []byte) error {
+	var clientData CollectedClientData
+	if err := json.Unmarshal(clientDataJSON, &clientData); err != nil {
+		return ErrClientData
+	}
+	if clientData.Type != ceremony {
+		return ErrClientData
+	}
+	if challenge == "" || subtle.ConstantTimeCompare([]byte(strings.TrimRight(clientData.Challenge, "=")), []byte(challenge)) != 1 {
+		return ErrChallenge
+	}
+	for _, origin := range rp.Origins {
+		if clientData.Origin == origin {
+			return nil
+		}
+	}
+	return ErrOrigin
+}
+
+func verifyAuthData(rp RelyingParty, authData *AuthenticatorData, requireUV bool) error {
+	rpIDHash := sha256.Sum256([]byte(rp.ID))
+	if !bytes.Equal(authData.RPIDHash, rpIDHash[:]) {
+		return ErrRPID
+	}
+	if !authData.Has(FlagUserPresent) {
+		return ErrUserPresence
+	}
+	if requireUV && !authData.Has(FlagUserVerified) {
+		return ErrUserVerified
+	}
+	return nil
+}
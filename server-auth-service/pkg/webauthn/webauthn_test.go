@@ -0,0 +1,277 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+const (
+	testRPID      = "sso.example.com"
+	testOrigin    = "https://sso.example.com"
+	testChallenge = "dGVzdC1jaGFsbGVuZ2UtMDEyMzQ1Njc4OWFiY2RlZg"
+)
+
+var testRP = RelyingParty{ID: testRPID, Origins: []string{testOrigin}}
+
+// testAuthenticator 模拟认证器：持有私钥并按 WebAuthn 格式生成注册与认证响应
+type testAuthenticator struct {
+	alg     int64
+	coseKey []byte
+	sign    func(data []byte) []byte
+}
+
+func newTestAuthenticator(t *testing.T, alg int64) *testAuthenticator {
+	t.Helper()
+	a := &testAuthenticator{alg: alg}
+	var key map[int]interface{}
+
+	switch alg {
+	case AlgES256:
+		priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		key = map[int]interface{}{1: coseKtyEC2, 3: AlgES256, -1: coseCrvP256,
+			-2: priv.X.FillBytes(make([]byte, 32)), -3: priv.Y.FillBytes(make([]byte, 32))}
+		a.sign = func(data []byte) []byte {
+			digest := sha256.Sum256(data)
+			sig, err := ecdsa.SignASN1(rand.Reader, priv, digest[:])
+			if err != nil {
+				t.Fatal(err)
+			}
+			return sig
+		}
+	case AlgEdDSA:
+		pub, priv, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		key = map[int]interface{}{1: coseKtyOKP, 3: AlgEdDSA, -1: coseCrvEd25519, -2: []byte(pub)}
+		a.sign = func(data []byte) []byte { return ed25519.Sign(priv, data) }
+	case AlgRS256:
+		priv, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			t.Fatal(err)
+		}
+		key = map[int]interface{}{1: coseKtyRSA, 3: AlgRS256,
+			-1: priv.N.Bytes(), -2: big.NewInt(int64(priv.E)).Bytes()}
+		a.sign = func(data []byte) []byte {
+			digest := sha256.Sum256(data)
+			sig, err := rsa.SignPKCS1v15(rand.Reader, priv, crypto.SHA256, digest[:])
+			if err != nil {
+				t.Fatal(err)
+			}
+			return sig
+		}
+	default:
+		t.Fatalf("unsupported alg %d", alg)
+	}
+
+	coseKey, err := cbor.Marshal(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.coseKey = coseKey
+	return a
+}
+
+// authData 生成认证器数据，withCredential 为 true 时附带凭证数据（注册）
+func (a *testAuthenticator) authData(rpID string, flags byte, counter uint32, withCredential bool) []byte {
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	data := append([]byte{}, rpIDHash[:]...)
+	data = append(data, flags)
+	data = binary.BigEndian.AppendUint32(data, counter)
+	if withCredential {
+		credID := []byte("test-credential-id")
+		data = append(data, make([]byte, 16)...) // AAGUID
+		data = binary.BigEndian.AppendUint16(data, uint16(len(credID)))
+		data = append(data, credID...)
+		data = append(data, a.coseKey...)
+	}
+	return data
+}
+
+func clientDataJSON(t *testing.T, ceremony, challenge, origin string) []byte {
+	t.Helper()
+	data, err := json.Marshal(CollectedClientData{Type: ceremony, Challenge: challenge, Origin: origin})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func attestationNone(t *testing.T, authData []byte) []byte {
+	t.Helper()
+	data, err := cbor.Marshal(map[string]interface{}{
+		"fmt":      "none",
+		"attStmt":  map[string]interface{}{},
+		"authData": authData,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// assertion 生成认证响应：签名覆盖 authenticatorData || SHA-256(clientDataJSON)
+func (a *testAuthenticator) assertion(authData, clientData []byte) []byte {
+	hash := sha256.Sum256(clientData)
+	return a.sign(append(append([]byte{}, authData...), hash[:]...))
+}
+
+var algorithms = []struct {
+	name string
+	alg  int64
+}{
+	{"ES256", AlgES256},
+	{"EdDSA", AlgEdDSA},
+	{"RS256", AlgRS256},
+}
+
+func TestRegistrationAndAssertion(t *testing.T) {
+	for _, tc := range algorithms {
+		t.Run(tc.name, func(t *testing.T) {
+			a := newTestAuthenticator(t, tc.alg)
+
+			regClientData := clientDataJSON(t, "webauthn.create", testChallenge, testOrigin)
+			regAuthData := a.authData(testRPID, FlagUserPresent|FlagUserVerified|FlagAttestedCredData, 0, true)
+			cred, err := VerifyRegistration(testRP, testChallenge, regClientData, attestationNone(t, regAuthData), true)
+			if err != nil {
+				t.Fatalf("VerifyRegistration: %v", err)
+			}
+			if cred.Algorithm != tc.alg {
+				t.Fatalf("algorithm = %d, want %d", cred.Algorithm, tc.alg)
+			}
+			if string(cred.ID) != "test-credential-id" {
+				t.Fatalf("credential id = %q", cred.ID)
+			}
+
+			clientData := clientDataJSON(t, "webauthn.get", testChallenge, testOrigin)
+			authData := a.authData(testRPID, FlagUserPresent|FlagUserVerified, 1, false)
+			signature := a.assertion(authData, clientData)
+			got, err := VerifyAssertion(testRP, testChallenge, cred.PublicKey, clientData, authData, signature, true)
+			if err != nil {
+				t.Fatalf("VerifyAssertion: %v", err)
+			}
+			if got.SignCount != 1 {
+				t.Fatalf("sign count = %d, want 1", got.SignCount)
+			}
+
+			// 篡改认证器数据后签名不再有效
+			tampered := append([]byte{}, authData...)
+			tampered[36] = 2
+			if _, err := VerifyAssertion(testRP, testChallenge, cred.PublicKey, clientData, tampered, signature, true); !errors.Is(err, ErrSignature) {
+				t.Fatalf("tampered auth data: err = %v, want %v", err, ErrSignature)
+			}
+		})
+	}
+}
+
+func TestVerifyAssertionRejects(t *testing.T) {
+	a := newTestAuthenticator(t, AlgES256)
+	const okFlags = FlagUserPresent | FlagUserVerified
+
+	tests := []struct {
+		name      string
+		rpID      string
+		flags     byte
+		ceremony  string
+		challenge string
+		origin    string
+		requireUV bool
+		otherKey  bool
+		wantErr   error
+	}{
+		{name: "wrong rpIdHash", rpID: "evil.example.com", flags: okFlags, ceremony: "webauthn.get", challenge: testChallenge, origin: testOrigin, wantErr: ErrRPID},
+		{name: "wrong origin", rpID: testRPID, flags: okFlags, ceremony: "webauthn.get", challenge: testChallenge, origin: "https://evil.example.com", wantErr: ErrOrigin},
+		{name: "wrong challenge", rpID: testRPID, flags: okFlags, ceremony: "webauthn.get", challenge: "b3RoZXItY2hhbGxlbmdl", origin: testOrigin, wantErr: ErrChallenge},
+		{name: "wrong ceremony", rpID: testRPID, flags: okFlags, ceremony: "webauthn.create", challenge: testChallenge, origin: testOrigin, wantErr: ErrClientData},
+		{name: "missing UP flag", rpID: testRPID, flags: FlagUserVerified, ceremony: "webauthn.get", challenge: testChallenge, origin: testOrigin, wantErr: ErrUserPresence},
+		{name: "missing UV flag", rpID: testRPID, flags: FlagUserPresent, ceremony: "webauthn.get", challenge: testChallenge, origin: testOrigin, requireUV: true, wantErr: ErrUserVerified},
+		{name: "signed by another key", rpID: testRPID, flags: okFlags, ceremony: "webauthn.get", challenge: testChallenge, origin: testOrigin, otherKey: true, wantErr: ErrSignature},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			clientData := clientDataJSON(t, tc.ceremony, tc.challenge, tc.origin)
+			authData := a.authData(tc.rpID, tc.flags, 1, false)
+			signer := a
+			if tc.otherKey {
+				signer = newTestAuthenticator(t, AlgES256)
+			}
+			signature := signer.assertion(authData, clientData)
+
+			_, err := VerifyAssertion(testRP, testChallenge, a.coseKey, clientData, authData, signature, tc.requireUV)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyRegistrationRejects(t *testing.T) {
+	a := newTestAuthenticator(t, AlgEdDSA)
+	clientData := clientDataJSON(t, "webauthn.create", testChallenge, testOrigin)
+
+	tests := []struct {
+		name     string
+		authData []byte
+		wantErr  error
+	}{
+		{"wrong rpIdHash", a.authData("evil.example.com", FlagUserPresent|FlagAttestedCredData, 0, true), ErrRPID},
+		{"missing UP flag", a.authData(testRPID, FlagAttestedCredData, 0, true), ErrUserPresence},
+		{"missing credential data", a.authData(testRPID, FlagUserPresent, 0, false), ErrAuthData},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := VerifyRegistration(testRP, testChallenge, clientData, attestationNone(t, tc.authData), false)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+
+	// 来源不受信任
+	evilClientData := clientDataJSON(t, "webauthn.create", testChallenge, "https://evil.example.com")
+	authData := a.authData(testRPID, FlagUserPresent|FlagAttestedCredData, 0, true)
+	if _, err := VerifyRegistration(testRP, testChallenge, evilClientData, attestationNone(t, authData), false); !errors.Is(err, ErrOrigin) {
+		t.Fatalf("wrong origin: err = %v, want %v", err, ErrOrigin)
+	}
+}
+
+func TestCheckSignCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		stored   uint32
+		received uint32
+		wantErr  error
+	}{
+		{"counter unsupported", 0, 0, nil},
+		{"first use", 0, 1, nil},
+		{"incremented", 5, 6, nil},
+		{"repeated", 5, 5, ErrSignCount},
+		{"rollback", 5, 3, ErrSignCount},
+		{"reset to zero", 5, 0, ErrSignCount},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := CheckSignCount(tc.stored, tc.received); !errors.Is(err, tc.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -16,6 +16,18 @@ var (
 		Name:  "sql",
 		Usage: "Initializes the structure of the MySQL database table.",
 	}
+	generateKeyFlag = &cli.BoolFlag{
+		Name:  "generate-key",
+		Usage: "Generates a new JWT signing key, publishes it in JWKS and activates it after a delay. Old keys are kept for verification.",
+	}
+	activateKeyFlag = &cli.StringFlag{
+		Name:  "activate-key",
+		Usage: "Activates an existing JWT signing key by kid immediately and cancels any scheduled key.",
+	}
+	migrateSecretsFlag = &cli.BoolFlag{
+		Name:  "migrate-secrets",
+		Usage: "Hashes plaintext application secrets and clears the plaintext column.",
+	}
 )
 
 // Run 执行基于命令行标志的相应操作
@@ -36,6 +48,25 @@ func Run(c *cli.Context) {
 		} else {
 			global.Log.Info("Successfully created table structure")
 		}
+	case c.Bool(generateKeyFlag.Name):
+		if kid, activateAt, err := GenerateKey(); err != nil {
+			global.Log.Error("Failed to generate signing key:", zap.Error(err))
+		} else {
+			global.Log.Info("Successfully generated signing key, scheduled activation",
+				zap.String("kid", kid), zap.Time("activate_at", activateAt))
+		}
+	case c.String(activateKeyFlag.Name) != "":
+		if err := ActivateKey(c.String(activateKeyFlag.Name)); err != nil {
+			global.Log.Error("Failed to activate signing key:", zap.Error(err))
+		} else {
+			global.Log.Info("Successfully activated signing key", zap.String("kid", c.String(activateKeyFlag.Name)))
+		}
+	case c.Bool(migrateSecretsFlag.Name):
+		if count, err := MigrateSecrets(); err != nil {
+			global.Log.Error("Failed to migrate application secrets:", zap.Error(err))
+		} else {
+			global.Log.Info("Successfully migrated application secrets", zap.Int("count", count))
+		}
 	default:
 		err := cli.NewExitError("unknown command", 1)
 		global.Log.Error(err.Error(), zap.Error(err))
@@ -48,6 +79,9 @@ func NewApp() *cli.App {
 	app.Name = "SSO Auth Service"
 	app.Flags = []cli.Flag{
 		sqlFlag,
+		generateKeyFlag,
+		activateKeyFlag,
+		migrateSecretsFlag,
 	}
 	app.Action = Run
 	return app
@@ -0,0 +1,40 @@
+package flag
+
+import (
+	"errors"
+	"time"
+
+	"auth-service/pkg/global"
+	"auth-service/pkg/jwt"
+)
+
+const (
+	// signingKeyBits 新生成签名密钥的长度
+	signingKeyBits = 2048
+	// keyActivationDelay 新密钥从发布到用于签名的间隔：需长于各实例重新加载密钥目录的间隔（1 分钟），
+	// 并留出资源服务刷新 JWKS 缓存的时间，否则新 kid 签发的 Token 会被拒绝
+	keyActivationDelay = 5 * time.Minute
+)
+
+// GenerateKey 在密钥目录中生成新的签名密钥并计划在 keyActivationDelay 后激活，旧密钥保留用于验证
+func GenerateKey() (string, time.Time, error) {
+	dir := global.Config.JWT.KeysDir
+	if dir == "" {
+		return "", time.Time{}, errors.New("未配置 jwt.keys_dir")
+	}
+	kid, err := jwt.GenerateSigningKey(dir, signingKeyBits)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	activateAt := time.Now().Add(keyActivationDelay)
+	return kid, activateAt, jwt.ScheduleSigningKey(dir, kid, activateAt)
+}
+
+// ActivateKey 立即将密钥目录中已有的密钥设为签名密钥（用于回滚），并取消待激活的密钥
+func ActivateKey(kid string) error {
+	dir := global.Config.JWT.KeysDir
+	if dir == "" {
+		return errors.New("未配置 jwt.keys_dir")
+	}
+	return jwt.ActivateSigningKey(dir, kid)
+}
@@ -0,0 +1,8 @@
+package flag
+
+import "auth-service/internal/service"
+
+// MigrateSecrets 将应用表中的明文密钥转存为哈希，返回迁移的应用数
+func MigrateSecrets() (int, error) {
+	return service.ServiceGroupApp.AppSecretService.MigratePlaintextSecrets()
+}
@@ -11,8 +11,13 @@ func SQL() error {
 		&dbpkg.SSOUser{},
 		&dbpkg.SSOOAuthBinding{},
 		&dbpkg.SSOApplication{},
+		&dbpkg.SSOAppSecret{},
 		&dbpkg.UserAppRelation{},
 		&dbpkg.SSODevice{},
 		&dbpkg.SSOLoginLog{},
+		&dbpkg.SSOUserMFA{},
+		&dbpkg.SSOMFARecoveryCode{},
+		&dbpkg.SSOWebAuthnCredential{},
+		&dbpkg.SSOUserConsent{},
 	)
 }
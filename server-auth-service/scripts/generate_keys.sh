@@ -24,5 +24,5 @@ echo "  私钥: $KEYS_DIR/private.pem"
 echo "  公钥: $KEYS_DIR/public.pem"
 echo ""
 echo "⚠️  请妥善保管私钥文件！"
-echo "📋  公钥需要复制到博客服务(server/keys/public.pem)"
+echo "📋  博客服务通过 /.well-known/jwks.json 获取公钥；轮换密钥请使用 --generate-key"
 
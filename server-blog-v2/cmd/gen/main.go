@@ -57,7 +57,9 @@ func main() {
 		// 内容相关
 		g.GenerateModel("article_categories", gen.FieldType("deleted_at", "gorm.DeletedAt")),
 		g.GenerateModel("article_tags", gen.FieldType("deleted_at", "gorm.DeletedAt")),
-		g.GenerateModel("articles", gen.FieldType("deleted_at", "gorm.DeletedAt")),
+		g.GenerateModel("article_tag_aliases"),
+		// search_vector 为生成列，不能写入
+		g.GenerateModel("articles", gen.FieldType("deleted_at", "gorm.DeletedAt"), gen.FieldIgnore("search_vector")),
 		g.GenerateModel("article_likes", gen.FieldType("deleted_at", "gorm.DeletedAt")),
 		g.GenerateModel("article_views"),
 
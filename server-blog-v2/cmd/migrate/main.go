@@ -123,10 +123,11 @@ func showTableStatus(dbURL string) {
 	defer db.Close()
 
 	tables := []string{
-		"users", "article_categories", "article_tags", "articles", "article_likes",
+		"users", "article_categories", "article_tags", "article_tag_aliases", "articles", "article_likes",
 		"article_views", "comments", "comment_likes", "ai_chat_sessions", "ai_chat_messages",
 		"feedbacks", "links", "files", "advertisements", "footer_links", "emoji_groups", "emojis",
 		"emoji_sprites", "emoji_tasks", "resources", "resource_upload_tasks", "logins", "site_settings",
+		"storage_migration_checkpoints", "upload_policies", "upload_daily_usage", "media_folders", "media_items",
 	}
 
 	log.Println("Database tables status:")
@@ -0,0 +1,505 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"path"
+	"sort"
+	"strings"
+
+	"server-blog-v2/internal/repo"
+	"server-blog-v2/internal/repo/transcode"
+)
+
+// 对象类型
+const (
+	kindFile       = "file"       // files 表，file_hash 为 sha256
+	kindResource   = "resource"   // resources 表，file_hash 为 qetag
+	kindDerived    = "derived"    // 图片版本、资源转码/缩略图
+	kindEmoji      = "emoji"      // 表情图片、雪碧图
+	kindEmojiConf  = "emoji_conf" // 雪碧图配置（JSON，内容中的 URL 需改写）
+	kindPlaylist   = "playlist"   // HLS 播放列表（分片地址需改写）
+	defaultMimeBin = "application/octet-stream"
+)
+
+// 断点状态
+const (
+	statusDone   = "done"
+	statusFailed = "failed"
+)
+
+type object struct {
+	Key      string
+	Kind     string
+	Hash     string
+	Size     int64
+	MimeType string
+	// Deps 需要先迁移完成的对象（播放列表依赖其分片）
+	Deps []string
+}
+
+type copyStats struct {
+	copied  int
+	skipped int
+	failed  int
+}
+
+type migrator struct {
+	db         *sql.DB
+	source     repo.ObjectStore
+	target     repo.ObjectStore
+	sourceName string
+	targetName string
+	dryRun     bool
+}
+
+// collect 收集需要迁移的对象，按 Key 去重。
+func (m *migrator) collect(ctx context.Context) ([]object, error) {
+	var objects []object
+	seen := make(map[string]bool)
+	add := func(o object) {
+		if isAbsoluteURL(o.Key) {
+			o.Key = m.keyFromURL(o.Key)
+		}
+		if o.Key == "" || seen[o.Key] {
+			return
+		}
+		seen[o.Key] = true
+		objects = append(objects, o)
+	}
+
+	// files
+	rows, err := m.db.QueryContext(ctx, `SELECT key, COALESCE(file_hash, ''), COALESCE(size, 0), COALESCE(mime_type, '')
+		FROM files WHERE deleted_at IS NULL ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("query files: %w", err)
+	}
+	for rows.Next() {
+		o := object{Kind: kindFile}
+		if err := rows.Scan(&o.Key, &o.Hash, &o.Size, &o.MimeType); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		add(o)
+	}
+	rows.Close()
+
+	// 图片缩放与 WebP 版本
+	keys, err := m.queryStrings(ctx, `SELECT v->>'key' FROM files, jsonb_array_elements(COALESCE(files.variants, '[]')) v
+		WHERE files.deleted_at IS NULL`)
+	if err != nil {
+		return nil, fmt.Errorf("query file variants: %w", err)
+	}
+	for _, key := range keys {
+		add(object{Key: key, Kind: kindDerived})
+	}
+
+	// resources（含转码与缩略图）
+	rows, err = m.db.QueryContext(ctx, `SELECT file_key, COALESCE(file_hash, ''), file_size, mime_type,
+		COALESCE(transcode_key, ''), COALESCE(thumbnail_key, '')
+		FROM resources WHERE deleted_at IS NULL ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("query resources: %w", err)
+	}
+	var playlists []string
+	for rows.Next() {
+		o := object{Kind: kindResource}
+		var transcodeKey, thumbnailKey string
+		if err := rows.Scan(&o.Key, &o.Hash, &o.Size, &o.MimeType, &transcodeKey, &thumbnailKey); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		add(o)
+		add(object{Key: thumbnailKey, Kind: kindDerived})
+		if strings.HasSuffix(transcodeKey, ".m3u8") {
+			playlists = append(playlists, transcodeKey)
+		} else {
+			add(object{Key: transcodeKey, Kind: kindDerived})
+		}
+	}
+	rows.Close()
+
+	// HLS 分片不在数据库中，从源端播放列表读取；播放列表排在分片之后，内容改写依赖分片的迁移结果
+	var hlsPlaylists []object
+	for _, key := range playlists {
+		if isAbsoluteURL(key) {
+			key = m.keyFromURL(key)
+		}
+		if key == "" {
+			continue
+		}
+		segments := transcode.PlaylistSegments(ctx, m.source, key)
+		if len(segments) == 0 {
+			log.Printf("  ⚠️ playlist %s has no readable segments", key)
+		}
+		for _, segment := range segments {
+			add(object{Key: segment, Kind: kindDerived, MimeType: "video/mp2t"})
+		}
+		hlsPlaylists = append(hlsPlaylists, object{Key: key, Kind: kindPlaylist, MimeType: "application/vnd.apple.mpegurl", Deps: segments})
+	}
+	for _, o := range hlsPlaylists {
+		add(o)
+	}
+
+	// 表情图片与雪碧图
+	for _, q := range []string{
+		`SELECT cdn_url FROM emojis WHERE deleted_at IS NULL AND cdn_url <> ''`,
+		`SELECT cdn_url FROM emoji_sprites WHERE cdn_url <> ''`,
+	} {
+		keys, err := m.queryStrings(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range keys {
+			add(object{Key: key, Kind: kindEmoji})
+		}
+	}
+
+	// 雪碧图配置放在最后，内容改写依赖其它对象的迁移结果
+	keys, err = m.queryStrings(ctx, `SELECT sprite_conf_url FROM emoji_groups WHERE sprite_conf_url <> ''`)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		add(object{Key: key, Kind: kindEmojiConf, MimeType: "application/json"})
+	}
+
+	return objects, nil
+}
+
+// copyAll 逐个复制对象，已完成的对象直接跳过。
+func (m *migrator) copyAll(ctx context.Context, objects []object) (copyStats, error) {
+	var stats copyStats
+
+	done, err := m.doneKeys(ctx)
+	if err != nil {
+		return stats, err
+	}
+
+	var replacer *strings.Replacer
+	for _, o := range objects {
+		if done[o.Key] {
+			stats.skipped++
+			continue
+		}
+		if m.dryRun {
+			log.Printf("  would copy [%s] %s", o.Kind, o.Key)
+			stats.copied++
+			continue
+		}
+
+		if rewritesContent(o.Kind) && replacer == nil {
+			if replacer, err = m.urlReplacer(ctx, objects); err != nil {
+				return stats, err
+			}
+		}
+
+		err := m.checkDeps(o, done)
+		if err == nil {
+			err = m.copyObject(ctx, o, replacer)
+		}
+		if err != nil {
+			log.Printf("  ❌ [%s] %s: %v", o.Kind, o.Key, err)
+			stats.failed++
+			if err := m.checkpoint(ctx, o, statusFailed, err.Error()); err != nil {
+				return stats, err
+			}
+			continue
+		}
+
+		stats.copied++
+		done[o.Key] = true
+		if err := m.checkpoint(ctx, o, statusDone, ""); err != nil {
+			return stats, err
+		}
+		log.Printf("  ✅ [%s] %s", o.Kind, o.Key)
+	}
+
+	return stats, nil
+}
+
+// checkDeps 检查依赖对象均已迁移，避免播放列表改写后仍引用源端分片。
+func (m *migrator) checkDeps(o object, done map[string]bool) error {
+	for _, dep := range o.Deps {
+		if !done[dep] {
+			return fmt.Errorf("dependency not migrated: %s", dep)
+		}
+	}
+	return nil
+}
+
+// copyObject 复制单个对象并校验目标端内容。
+func (m *migrator) copyObject(ctx context.Context, o object, replacer *strings.Replacer) error {
+	body, size, err := m.source.Get(ctx, o.Key)
+	if err != nil {
+		return fmt.Errorf("read source: %w", err)
+	}
+	defer body.Close()
+
+	var data io.Reader = body
+	if rewritesContent(o.Kind) {
+		content, err := io.ReadAll(body)
+		if err != nil {
+			return fmt.Errorf("read source: %w", err)
+		}
+		if replacer != nil {
+			content = []byte(replacer.Replace(string(content)))
+		}
+		data, size = bytes.NewReader(content), int64(len(content))
+	} else if size < 0 {
+		size = o.Size
+	}
+
+	contentType := o.MimeType
+	if contentType == "" {
+		contentType = mime.TypeByExtension(path.Ext(o.Key))
+	}
+	if contentType == "" {
+		contentType = defaultMimeBin
+	}
+
+	if _, err := m.target.Upload(ctx, o.Key, data, size, contentType); err != nil {
+		return fmt.Errorf("write target: %w", err)
+	}
+
+	ok, err := m.verify(ctx, o)
+	if err != nil {
+		return fmt.Errorf("verify target: %w", err)
+	}
+	if !ok {
+		_ = m.target.Delete(ctx, o.Key)
+		return fmt.Errorf("hash mismatch")
+	}
+	return nil
+}
+
+// verify 按记录中的 hash 校验目标端对象：files 为 sha256，resources 为 qetag。
+func (m *migrator) verify(ctx context.Context, o object) (bool, error) {
+	if o.Hash == "" {
+		return true, nil
+	}
+
+	switch o.Kind {
+	case kindResource:
+		return m.target.VerifyHash(ctx, o.Hash, o.Key)
+	case kindFile:
+		body, _, err := m.target.Get(ctx, o.Key)
+		if err != nil {
+			return false, err
+		}
+		defer body.Close()
+
+		h := sha256.New()
+		if _, err := io.Copy(h, body); err != nil {
+			return false, err
+		}
+		return hex.EncodeToString(h.Sum(nil)) == o.Hash, nil
+	default:
+		return true, nil
+	}
+}
+
+// urlReplacer 构建旧 URL 到新 URL 的替换器，仅包含已迁移完成的对象（dry-run 时包含全部）。
+func (m *migrator) urlReplacer(ctx context.Context, objects []object) (*strings.Replacer, error) {
+	done, err := m.doneKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for _, o := range objects {
+		if m.dryRun || done[o.Key] {
+			keys = append(keys, o.Key)
+		}
+	}
+
+	// 长 Key 优先，避免 a.png 抢先匹配 a.png-thumb 这类前缀重叠的地址
+	sort.Slice(keys, func(i, j int) bool { return len(keys[i]) > len(keys[j]) })
+
+	pairs := make([]string, 0, len(keys)*2)
+	for _, key := range keys {
+		oldURL, newURL := m.source.GetURL(key), m.target.GetURL(key)
+		if oldURL != newURL {
+			pairs = append(pairs, oldURL, newURL)
+		}
+	}
+	return strings.NewReplacer(pairs...), nil
+}
+
+// rewriteURLs 改写数据库中引用旧存储的完整 URL，返回受影响的行数。
+// 表情相关字段通常保存相对 Key，由 urlutil 按当前存储类型拼接，无需改写。
+func (m *migrator) rewriteURLs(ctx context.Context, objects []object) (int, error) {
+	replacer, err := m.urlReplacer(ctx, objects)
+	if err != nil {
+		return 0, err
+	}
+
+	targets := []struct {
+		table   string
+		columns []string
+	}{
+		{"articles", []string{"content", "featured_image"}},
+		{"users", []string{"avatar"}},
+		{"advertisements", []string{"ad_image"}},
+		{"site_settings", []string{"setting_value"}}, // 轮播图等配置中的图片地址
+		{"links", []string{"logo"}},
+		{"emoji_groups", []string{"sprite_conf_url"}},
+		{"emoji_sprites", []string{"cdn_url"}},
+		{"emojis", []string{"cdn_url"}},
+	}
+
+	total := 0
+	for _, t := range targets {
+		n, err := m.rewriteTable(ctx, replacer, t.table, t.columns)
+		if err != nil {
+			return total, fmt.Errorf("rewrite %s: %w", t.table, err)
+		}
+		if n > 0 {
+			log.Printf("  %s: %d rows", t.table, n)
+		}
+		total += n
+	}
+	return total, nil
+}
+
+func (m *migrator) rewriteTable(ctx context.Context, replacer *strings.Replacer, table string, columns []string) (int, error) {
+	rows, err := m.db.QueryContext(ctx, fmt.Sprintf("SELECT id, %s FROM %s ORDER BY id", strings.Join(columns, ", "), table))
+	if err != nil {
+		return 0, err
+	}
+
+	type change struct {
+		id     int64
+		values []sql.NullString
+	}
+	var changes []change
+	for rows.Next() {
+		values := make([]sql.NullString, len(columns))
+		dest := []any{new(int64)}
+		for i := range values {
+			dest = append(dest, &values[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			rows.Close()
+			return 0, err
+		}
+
+		changed := false
+		for i, v := range values {
+			if !v.Valid {
+				continue
+			}
+			if replaced := replacer.Replace(v.String); replaced != v.String {
+				values[i].String = replaced
+				changed = true
+			}
+		}
+		if changed {
+			changes = append(changes, change{id: *dest[0].(*int64), values: values})
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	if m.dryRun {
+		return len(changes), nil
+	}
+
+	sets := make([]string, len(columns))
+	for i, col := range columns {
+		sets[i] = fmt.Sprintf("%s = $%d", col, i+1)
+	}
+	stmt := fmt.Sprintf("UPDATE %s SET %s WHERE id = $%d", table, strings.Join(sets, ", "), len(columns)+1)
+
+	for _, c := range changes {
+		args := make([]any, 0, len(columns)+1)
+		for _, v := range c.values {
+			args = append(args, v)
+		}
+		args = append(args, c.id)
+		if _, err := m.db.ExecContext(ctx, stmt, args...); err != nil {
+			return 0, err
+		}
+	}
+	return len(changes), nil
+}
+
+// doneKeys 查询当前迁移方向上已完成的对象。
+func (m *migrator) doneKeys(ctx context.Context) (map[string]bool, error) {
+	rows, err := m.db.QueryContext(ctx, `SELECT object_key FROM storage_migration_checkpoints
+		WHERE source = $1 AND target = $2 AND status = $3`, m.sourceName, m.targetName, statusDone)
+	if err != nil {
+		return nil, fmt.Errorf("query checkpoints: %w", err)
+	}
+	defer rows.Close()
+
+	done := make(map[string]bool)
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		done[key] = true
+	}
+	return done, rows.Err()
+}
+
+func (m *migrator) checkpoint(ctx context.Context, o object, status, errMsg string) error {
+	_, err := m.db.ExecContext(ctx, `INSERT INTO storage_migration_checkpoints (source, target, object_key, kind, status, error)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (source, target, object_key)
+		DO UPDATE SET status = EXCLUDED.status, error = EXCLUDED.error, updated_at = CURRENT_TIMESTAMP`,
+		m.sourceName, m.targetName, o.Key, o.Kind, status, errMsg)
+	if err != nil {
+		return fmt.Errorf("save checkpoint: %w", err)
+	}
+	return nil
+}
+
+func (m *migrator) queryStrings(ctx context.Context, query string) ([]string, error) {
+	rows, err := m.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []string
+	for rows.Next() {
+		var s string
+		if err := rows.Scan(&s); err != nil {
+			return nil, err
+		}
+		result = append(result, s)
+	}
+	return result, rows.Err()
+}
+
+// keyFromURL 从源存储的完整 URL 中解析对象 Key，非源存储地址返回空。
+func (m *migrator) keyFromURL(rawURL string) string {
+	const probe = "__probe__"
+	sample := m.source.GetURL(probe)
+	prefix := sample[:strings.Index(sample, probe)]
+	if !strings.HasPrefix(rawURL, prefix) {
+		return ""
+	}
+	key, _, _ := strings.Cut(strings.TrimPrefix(rawURL, prefix), "?")
+	return key
+}
+
+// rewritesContent 内容中包含其它对象完整 URL、复制时需要改写的对象类型。
+func rewritesContent(kind string) bool {
+	return kind == kindEmojiConf || kind == kindPlaylist
+}
+
+func isAbsoluteURL(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
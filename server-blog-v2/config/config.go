@@ -8,24 +8,43 @@ import (
 	"github.com/spf13/viper"
 )
 
+// 文章搜索引擎
+const (
+	SearchEngineLike     = "like"
+	SearchEnginePostgres = "postgres"
+)
+
+// 对象存储类型（system.oss_type）
+const (
+	OssTypeQiniu = "qiniu"
+	OssTypeLocal = "local"
+	OssTypeS3    = "s3"
+)
+
 type (
 	Config struct {
-		App      App      `mapstructure:"app"`
-		Log      Log      `mapstructure:"log"`
-		HTTP     HTTP     `mapstructure:"http"`
-		Postgres Postgres `mapstructure:"postgres"`
-		Redis    Redis    `mapstructure:"redis"`
-		ES       ES       `mapstructure:"elasticsearch"`
-		Qiniu    Qiniu    `mapstructure:"qiniu"`
-		SSO      SSO      `mapstructure:"sso"`
-		AI       AI       `mapstructure:"ai"`
-		Swagger  Swagger  `mapstructure:"swagger"`
-		Website  Website  `mapstructure:"website"`
-		Gaode    Gaode    `mapstructure:"gaode"`
-		System   System   `mapstructure:"system"`
-		Email    Email    `mapstructure:"email"`
-		QQ       QQ       `mapstructure:"qq"`
-		Jwt      Jwt      `mapstructure:"jwt"`
+		App       App       `mapstructure:"app"`
+		Log       Log       `mapstructure:"log"`
+		HTTP      HTTP      `mapstructure:"http"`
+		Postgres  Postgres  `mapstructure:"postgres"`
+		Redis     Redis     `mapstructure:"redis"`
+		ES        ES        `mapstructure:"elasticsearch"`
+		Search    Search    `mapstructure:"search"`
+		Qiniu     Qiniu     `mapstructure:"qiniu"`
+		Local     Local     `mapstructure:"local"`
+		S3        S3        `mapstructure:"s3"`
+		Image     Image     `mapstructure:"image"`
+		Upload    Upload    `mapstructure:"upload"`
+		Transcode Transcode `mapstructure:"transcode"`
+		SSO       SSO       `mapstructure:"sso"`
+		AI        AI        `mapstructure:"ai"`
+		Swagger   Swagger   `mapstructure:"swagger"`
+		Website   Website   `mapstructure:"website"`
+		Gaode     Gaode     `mapstructure:"gaode"`
+		System    System    `mapstructure:"system"`
+		Email     Email     `mapstructure:"email"`
+		QQ        QQ        `mapstructure:"qq"`
+		Jwt       Jwt       `mapstructure:"jwt"`
 	}
 
 	App struct {
@@ -67,6 +86,12 @@ type (
 		Password  string   `mapstructure:"password"`
 	}
 
+	// Search 文章搜索配置。
+	// Engine: like（默认，标题模糊匹配）| postgres（tsvector 全文检索，需执行 000005 迁移）
+	Search struct {
+		Engine string `mapstructure:"engine"`
+	}
+
 	Qiniu struct {
 		Zone          string `mapstructure:"zone"`
 		AccessKey     string `mapstructure:"access_key"`
@@ -76,6 +101,64 @@ type (
 		UseHTTPS      bool   `mapstructure:"use_https"`
 		UseCDNDomains bool   `mapstructure:"use_cdn_domains"`
 		PathPrefix    string `mapstructure:"path_prefix"`
+		// PrivateBucket/PrivateDomain 私有空间，存放 private/ 前缀的私有对象；为空时私有对象仍在公开空间中
+		PrivateBucket string `mapstructure:"private_bucket"`
+		PrivateDomain string `mapstructure:"private_domain"`
+	}
+
+	// Local 本地磁盘存储，文件由博客的 /api/static 路由提供访问。
+	Local struct {
+		Root       string `mapstructure:"root"`        // 存储根目录
+		BaseURL    string `mapstructure:"base_url"`    // 访问地址前缀，如 https://blog.example.com/api/static
+		SignSecret string `mapstructure:"sign_secret"` // URL 签名密钥，为空时使用 system.sessions_secret，至少 MinSignSecretLength 个字符
+	}
+
+	// S3 S3 兼容存储（AWS S3、MinIO 等）。
+	S3 struct {
+		Endpoint  string `mapstructure:"endpoint"` // 如 http://localhost:9000
+		Region    string `mapstructure:"region"`
+		AccessKey string `mapstructure:"access_key"`
+		SecretKey string `mapstructure:"secret_key"`
+		Bucket    string `mapstructure:"bucket"`
+		PathStyle bool   `mapstructure:"path_style"` // MinIO 需开启
+		PublicURL string `mapstructure:"public_url"` // 公开访问地址（CDN），为空时使用 endpoint
+		// PrivateBucket 存放 private/ 前缀私有对象的桶；为空时使用 bucket，需由桶策略禁止匿名读取 private/*
+		PrivateBucket string `mapstructure:"private_bucket"`
+	}
+
+	// Image 上传图片处理（纠正方向、去除 EXIF、生成缩放与 WebP 版本）。
+	Image struct {
+		Enabled   bool   `mapstructure:"enabled"`
+		Widths    []int  `mapstructure:"widths"`     // 缩放宽度档位，如 [480, 960, 1600]
+		Quality   int    `mapstructure:"quality"`    // JPEG/WebP 质量，默认 85
+		WebP      bool   `mapstructure:"webp"`       // 生成 WebP 版本，需要安装 cwebp
+		CWebPPath string `mapstructure:"cwebp_path"` // 为空时从 PATH 查找
+
+		MaxPixels   int64 `mapstructure:"max_pixels"`  // 允许处理的最大像素数，超过则拒绝上传，默认 40000000
+		Concurrency int   `mapstructure:"concurrency"` // 同时处理的图片数，默认 2
+	}
+
+	// Upload 分片上传任务清理与私有资源访问。
+	Upload struct {
+		ReaperEnabled  bool          `mapstructure:"reaper_enabled"`  // 定期清理过期未完成的上传任务
+		ReaperInterval time.Duration `mapstructure:"reaper_interval"` // 清理间隔，默认 10m
+		SignedURLTTL   time.Duration `mapstructure:"signed_url_ttl"`  // 私有资源签名地址有效期，默认 1h
+		// PrivateBaseURL 私有对象稳定地址前缀（校验登录后跳转到签名地址），如 https://blog.example.com/api/v1/media，默认 /api/v1/media
+		PrivateBaseURL string `mapstructure:"private_base_url"`
+	}
+
+	// Transcode 视频转码。
+	// Backend: qiniu（七牛云 pfop）| ffmpeg（本地 worker 生成 HLS）| none；为空时 oss_type 为七牛云用 qiniu，否则 none
+	Transcode struct {
+		Backend    string        `mapstructure:"backend"`
+		NotifyURL  string        `mapstructure:"notify_url"`  // 七牛云回调地址，为空时依赖空间工作流自动转码
+		Pipeline   string        `mapstructure:"pipeline"`    // 七牛云转码队列
+		FFmpegPath string        `mapstructure:"ffmpeg_path"` // 为空时从 PATH 查找
+		WorkDir    string        `mapstructure:"work_dir"`    // 临时目录，为空时使用系统临时目录
+		Workers    int           `mapstructure:"workers"`     // 并发转码数，默认 1
+		HLSTime    int           `mapstructure:"hls_time"`    // HLS 分片时长（秒），默认 6
+		MaxRetries int           `mapstructure:"max_retries"` // 失败重试次数，默认 3
+		RetryDelay time.Duration `mapstructure:"retry_delay"` // 首次重试间隔，之后翻倍，默认 30s
 	}
 
 	SSO struct {
@@ -123,7 +206,7 @@ type (
 	System struct {
 		UseMultipoint  bool   `mapstructure:"use_multipoint"`
 		SessionsSecret string `mapstructure:"sessions_secret"`
-		OssType        string `mapstructure:"oss_type"`
+		OssType        string `mapstructure:"oss_type"` // qiniu（默认）, local, s3
 	}
 
 	Email struct {
@@ -173,5 +256,26 @@ func NewConfig() (*Config, error) {
 		return nil, fmt.Errorf("config error: %w", err)
 	}
 
+	if cfg.Local.SignSecret == "" {
+		cfg.Local.SignSecret = cfg.System.SessionsSecret
+	}
+	if err := cfg.validateLocal(); err != nil {
+		return nil, fmt.Errorf("config error: %w", err)
+	}
+
 	return cfg, nil
 }
+
+// MinSignSecretLength 本地存储 URL 签名密钥的最短长度。
+const MinSignSecretLength = 16
+
+// validateLocal 本地存储的文件地址靠 HMAC 签名保护，密钥为空或过短时任何人都能伪造签名，拒绝启动。
+func (c *Config) validateLocal() error {
+	if c.System.OssType != OssTypeLocal {
+		return nil
+	}
+	if len(c.Local.SignSecret) < MinSignSecretLength {
+		return fmt.Errorf("local.sign_secret (or system.sessions_secret) must be at least %d characters when oss_type is local", MinSignSecretLength)
+	}
+	return nil
+}
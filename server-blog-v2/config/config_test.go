@@ -0,0 +1,27 @@
+package config
+
+import "testing"
+
+func TestValidateLocalSignSecret(t *testing.T) {
+	tests := []struct {
+		name    string
+		ossType string
+		secret  string
+		wantErr bool
+	}{
+		{name: "local without secret", ossType: OssTypeLocal, secret: "", wantErr: true},
+		{name: "local with short secret", ossType: OssTypeLocal, secret: "short", wantErr: true},
+		{name: "local with secret", ossType: OssTypeLocal, secret: "0123456789abcdef", wantErr: false},
+		{name: "qiniu ignores secret", ossType: OssTypeQiniu, secret: "", wantErr: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{}
+			cfg.System.OssType = tt.ossType
+			cfg.Local.SignSecret = tt.secret
+			if err := cfg.validateLocal(); (err != nil) != tt.wantErr {
+				t.Fatalf("validateLocal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -18,6 +18,7 @@ require (
 	github.com/rs/zerolog v1.33.0
 	github.com/spf13/viper v1.19.0
 	github.com/tidwall/gjson v1.18.0
+	golang.org/x/image v0.23.0
 	golang.org/x/sync v0.18.0
 	gorm.io/driver/postgres v1.5.11
 	gorm.io/gen v0.3.26
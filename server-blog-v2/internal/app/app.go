@@ -18,7 +18,17 @@ func Run(cfg *config.Config) {
 	}
 	defer cleanup()
 
+	// 七牛只能按空间设置私有：未配置私有空间时私有资源与公开资源在同一空间，知道 Key 即可直接访问
+	if (cfg.System.OssType == "" || cfg.System.OssType == config.OssTypeQiniu) &&
+		(cfg.Qiniu.PrivateBucket == "" || cfg.Qiniu.PrivateDomain == "") {
+		app.Logger.Warn("app - Run - qiniu.private_bucket or private_domain is not set, private media is stored in the public bucket %s and readable by anyone who knows its key", cfg.Qiniu.Bucket)
+	}
+
+	if app.Transcoder != nil {
+		app.Transcoder.Start()
+	}
 	app.HTTPServer.Start()
+	app.UploadReaper.Start()
 	app.Logger.Info("app - Run - started: %s v%s", app.Info.Name, app.Info.Version)
 
 	interrupt := make(chan os.Signal, 1)
@@ -31,9 +41,14 @@ func Run(cfg *config.Config) {
 		app.Logger.Error(fmt.Errorf("app - Run - httpServer.Notify: %w", err))
 	}
 
+	app.UploadReaper.Stop()
+
 	err = app.HTTPServer.Shutdown()
 	if err != nil {
 		app.Logger.Error(fmt.Errorf("app - Run - httpServer.Shutdown: %w", err))
 	}
+	if app.Transcoder != nil {
+		app.Transcoder.Stop()
+	}
 	app.Logger.Info("app - Run - stopped: %s v%s", app.Info.Name, app.Info.Version)
 }
@@ -17,9 +17,12 @@ import (
 	"server-blog-v2/config"
 	"server-blog-v2/internal/controller/http"
 	"server-blog-v2/internal/controller/http/middleware"
+	"server-blog-v2/internal/controller/job"
+	"server-blog-v2/internal/pkg/imageproc"
 	"server-blog-v2/internal/repo"
 	"server-blog-v2/internal/repo/persistence"
 	"server-blog-v2/internal/repo/storage"
+	"server-blog-v2/internal/repo/transcode"
 	"server-blog-v2/internal/repo/webapi"
 	"server-blog-v2/internal/usecase"
 	"server-blog-v2/internal/usecase/advertisement"
@@ -31,6 +34,8 @@ import (
 	"server-blog-v2/internal/usecase/feedback"
 	"server-blog-v2/internal/usecase/file"
 	"server-blog-v2/internal/usecase/link"
+	"server-blog-v2/internal/usecase/mediagc"
+	"server-blog-v2/internal/usecase/medialibrary"
 	"server-blog-v2/internal/usecase/resource"
 	"server-blog-v2/internal/usecase/setting"
 	"server-blog-v2/internal/usecase/user"
@@ -48,10 +53,6 @@ import (
 func InitializeApp(cfg *config.Config) (*App, func(), error) {
 	appInfo := NewAppInfo(cfg)
 	loggerInterface := NewLogger(cfg)
-	publicKey, err := NewPublicKey(cfg)
-	if err != nil {
-		return nil, nil, err
-	}
 	postgres, cleanup, err := NewPostgres(cfg)
 	if err != nil {
 		return nil, nil, err
@@ -63,7 +64,12 @@ func InitializeApp(cfg *config.Config) (*App, func(), error) {
 	categoryRepo := persistence.NewCategoryRepo(db)
 	articleLikeRepo := persistence.NewArticleLikeRepo(db)
 	articleViewRepo := persistence.NewArticleViewRepo(db)
-	content := NewContentUseCase(cfg, articleRepo, tagRepo, categoryRepo, articleLikeRepo, articleViewRepo, userRepo)
+	redis, cleanup2, err := NewRedis(cfg)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	content := NewContentUseCase(cfg, articleRepo, tagRepo, categoryRepo, articleLikeRepo, articleViewRepo, userRepo, redis)
 	commentRepo := persistence.NewCommentRepo(db)
 	comment := NewCommentUseCase(cfg, commentRepo, userRepo)
 	chatSessionRepo := persistence.NewChatSessionRepo(db)
@@ -77,16 +83,24 @@ func InitializeApp(cfg *config.Config) (*App, func(), error) {
 	linkRepo := persistence.NewLinkRepo(db)
 	link := NewLinkUseCase(cfg, linkRepo)
 	fileRepo := persistence.NewFileRepo(db)
-	objectStore := NewObjectStore(cfg)
-	file := NewFileUseCase(fileRepo, objectStore)
+	objectStore, err := NewObjectStore(cfg)
+	if err != nil {
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	file := NewFileUseCase(cfg, fileRepo, objectStore)
 	resourceRepo := persistence.NewResourceRepo(db)
 	resourceUploadTaskRepo := persistence.NewResourceUploadTaskRepo(db)
-	redis, cleanup2, err := NewRedis(cfg)
+	transcoder, err := NewTranscoder(cfg, objectStore, resourceRepo, redis, loggerInterface)
 	if err != nil {
+		cleanup2()
 		cleanup()
 		return nil, nil, err
 	}
-	resource := NewResourceUseCase(resourceRepo, resourceUploadTaskRepo, objectStore, redis)
+	uploadPolicyRepo := persistence.NewUploadPolicyRepo(db)
+	uploadUsageRepo := persistence.NewUploadUsageRepo(db)
+	resource := NewResourceUseCase(cfg, resourceRepo, resourceUploadTaskRepo, objectStore, transcoder, userRepo, uploadPolicyRepo, uploadUsageRepo, redis)
 	user := NewUserUseCase(cfg, userRepo)
 	siteSettingRepo := persistence.NewSiteSettingRepo(db)
 	setting := NewSettingUseCase(siteSettingRepo)
@@ -97,10 +111,22 @@ func InitializeApp(cfg *config.Config) (*App, func(), error) {
 	emoji := NewEmojiUseCase(cfg, emojiRepo, emojiSpriteRepo)
 	advertisementRepo := persistence.NewAdvertisementRepo(db)
 	advertisement := NewAdvertisementUseCase(cfg, advertisementRepo)
+	mediaReferenceRepo := persistence.NewMediaReferenceRepo(db)
+	mediaGC := NewMediaGCUseCase(fileRepo, resourceRepo, mediaReferenceRepo, objectStore, transcoder)
+	mediaLibraryRepo := persistence.NewMediaLibraryRepo(db)
+	mediaFolderRepo := persistence.NewMediaFolderRepo(db)
+	mediaLibrary := NewMediaLibraryUseCase(cfg, mediaLibraryRepo, mediaFolderRepo, fileRepo, resourceRepo, objectStore)
 	sessionManager := NewSessionManager(redis, loggerInterface)
 	ssoClient := NewSSOClient(cfg)
-	server := SetupHTTPServer(cfg, loggerInterface, publicKey, userRepo, content, comment, aiChat, aiModel, feedback, link, file, resource, user, setting, website, emoji, advertisement, sessionManager, ssoClient)
-	app := NewApp(appInfo, loggerInterface, server)
+	ssoKeySet, err := NewSSOKeySet(cfg, ssoClient, loggerInterface)
+	if err != nil {
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	server := SetupHTTPServer(cfg, loggerInterface, ssoKeySet, userRepo, content, comment, aiChat, aiModel, feedback, link, file, resource, user, setting, website, emoji, advertisement, mediaGC, mediaLibrary, sessionManager, ssoClient)
+	uploadReaper := NewUploadReaper(cfg, resource, redis, loggerInterface)
+	app := NewApp(appInfo, loggerInterface, server, uploadReaper, transcoder)
 	return app, func() {
 		cleanup2()
 		cleanup()
@@ -111,9 +137,11 @@ func InitializeApp(cfg *config.Config) (*App, func(), error) {
 
 // App 应用容器。
 type App struct {
-	Info       AppInfo
-	Logger     logger.Interface
-	HTTPServer *httpserver.Server
+	Info         AppInfo
+	Logger       logger.Interface
+	HTTPServer   *httpserver.Server
+	UploadReaper *job.UploadReaper
+	Transcoder   repo.Transcoder // 为 nil 时视频不转码
 }
 
 // AppInfo 应用信息。
@@ -123,11 +151,13 @@ type AppInfo struct {
 }
 
 // NewApp 创建 App。
-func NewApp(info AppInfo, l logger.Interface, srv *httpserver.Server) *App {
+func NewApp(info AppInfo, l logger.Interface, srv *httpserver.Server, uploadReaper *job.UploadReaper, transcoder repo.Transcoder) *App {
 	return &App{
-		Info:       info,
-		Logger:     l,
-		HTTPServer: srv,
+		Info:         info,
+		Logger:       l,
+		HTTPServer:   srv,
+		UploadReaper: uploadReaper,
+		Transcoder:   transcoder,
 	}
 }
 
@@ -176,42 +206,44 @@ func NewRedis(cfg *config.Config) (*redis.Redis, func(), error) {
 	return rdb, cleanup, nil
 }
 
-// NewPublicKey 加载 SSO 公钥。
-func NewPublicKey(cfg *config.Config) (*rsa.PublicKey, error) {
-	keyData, err := os.ReadFile(cfg.SSO.PublicKeyPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read public key: %w", err)
-	}
-
-	block, _ := pem.Decode(keyData)
-	if block == nil {
-		return nil, fmt.Errorf("failed to decode PEM block")
-	}
-
-	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse public key: %w", err)
+// NewSSOKeySet 创建 SSO 签名公钥缓存：按 kid 从 SSO 的 JWKS 端点获取，本地公钥文件作为兜底（可不配置）。
+func NewSSOKeySet(cfg *config.Config, ssoClient *webapi.SSOClient, l logger.Interface) (*middleware.SSOKeySet, error) {
+	var fallback *rsa.PublicKey
+	if cfg.SSO.PublicKeyPath != "" {
+		keyData, err := os.ReadFile(cfg.SSO.PublicKeyPath)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read public key: %w", err)
+		}
+		if err == nil {
+			block, _ := pem.Decode(keyData)
+			if block == nil {
+				return nil, fmt.Errorf("failed to decode PEM block")
+			}
+
+			pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse public key: %w", err)
+			}
+
+			rsaPub, ok := pub.(*rsa.PublicKey)
+			if !ok {
+				return nil, fmt.Errorf("not an RSA public key")
+			}
+			fallback = rsaPub
+		}
 	}
 
-	rsaPub, ok := pub.(*rsa.PublicKey)
-	if !ok {
-		return nil, fmt.Errorf("not an RSA public key")
-	}
+	return middleware.NewSSOKeySet(ssoClient, fallback, l), nil
+}
 
-	return rsaPub, nil
+// NewObjectStore 按 system.oss_type 创建对象存储，默认七牛云。
+func NewObjectStore(cfg *config.Config) (repo.ObjectStore, error) {
+	return storage.NewFromConfig(cfg, cfg.System.OssType)
 }
 
-// NewObjectStore 创建七牛云存储。
-func NewObjectStore(cfg *config.Config) repo.ObjectStore {
-	return storage.NewQiniuStore(
-		cfg.Qiniu.AccessKey,
-		cfg.Qiniu.SecretKey,
-		cfg.Qiniu.Bucket,
-		cfg.Qiniu.Domain,
-		cfg.Qiniu.Zone,
-		cfg.Qiniu.UseHTTPS,
-		"",
-	)
+// NewTranscoder 按 transcode.backend 创建视频转码器。
+func NewTranscoder(cfg *config.Config, objectStore repo.ObjectStore, resources repo.ResourceRepo, rdb *redis.Redis, l logger.Interface) (repo.Transcoder, error) {
+	return transcode.NewFromConfig(cfg, objectStore, resources, rdb, l)
 }
 
 // NewLLMWebAPI 创建 LLM API 客户端。
@@ -241,8 +273,9 @@ func NewContentUseCase(
 	articleLikes repo.ArticleLikeRepo,
 	articleViews repo.ArticleViewRepo,
 	users repo.UserRepo,
+	rdb *redis.Redis,
 ) usecase.Content {
-	return content.New(cfg, articles, tags, categories, articleLikes, articleViews, users)
+	return content.New(cfg, articles, tags, categories, articleLikes, articleViews, users, rdb)
 }
 
 // NewCommentUseCase 创建 Comment UseCase。
@@ -272,13 +305,35 @@ func NewLinkUseCase(cfg *config.Config, links repo.LinkRepo) usecase.Link {
 }
 
 // NewFileUseCase 创建 File UseCase。
-func NewFileUseCase(files repo.FileRepo, objectStore repo.ObjectStore) usecase.File {
-	return file.New(files, objectStore)
+func NewFileUseCase(cfg *config.Config, files repo.FileRepo, objectStore repo.ObjectStore) usecase.File {
+	var images *imageproc.Processor
+	if cfg.Image.Enabled {
+		images = imageproc.New(imageproc.Options{
+			Widths:    cfg.Image.Widths,
+			Quality:   cfg.Image.Quality,
+			WebP:      cfg.Image.WebP,
+			CWebPPath: cfg.Image.CWebPPath,
+
+			MaxPixels:   cfg.Image.MaxPixels,
+			Concurrency: cfg.Image.Concurrency,
+		})
+	}
+	return file.New(files, objectStore, images)
 }
 
 // NewResourceUseCase 创建 Resource UseCase。
-func NewResourceUseCase(resources repo.ResourceRepo, tasks repo.ResourceUploadTaskRepo, objectStore repo.ObjectStore, rdb *redis.Redis) usecase.Resource {
-	return resource.New(resources, tasks, objectStore, rdb)
+func NewResourceUseCase(
+	cfg *config.Config,
+	resources repo.ResourceRepo,
+	tasks repo.ResourceUploadTaskRepo,
+	objectStore repo.ObjectStore,
+	transcoder repo.Transcoder,
+	users repo.UserRepo,
+	policies repo.UploadPolicyRepo,
+	usage repo.UploadUsageRepo,
+	rdb *redis.Redis,
+) usecase.Resource {
+	return resource.New(resources, tasks, objectStore, transcoder, users, policies, usage, rdb, cfg.Upload.SignedURLTTL)
 }
 
 // NewAIModelUseCase 创建 AIModel UseCase。
@@ -311,15 +366,37 @@ func NewAdvertisementUseCase(cfg *config.Config, ads repo.AdvertisementRepo) use
 	return advertisement.New(cfg, ads)
 }
 
+// NewMediaGCUseCase 创建 MediaGC UseCase。
+func NewMediaGCUseCase(files repo.FileRepo, resources repo.ResourceRepo, references repo.MediaReferenceRepo, objectStore repo.ObjectStore, transcoder repo.Transcoder) usecase.MediaGC {
+	return mediagc.New(files, resources, references, objectStore, transcoder)
+}
+
+// NewMediaLibraryUseCase 创建 MediaLibrary UseCase。
+func NewMediaLibraryUseCase(
+	cfg *config.Config,
+	library repo.MediaLibraryRepo,
+	folders repo.MediaFolderRepo,
+	files repo.FileRepo,
+	resources repo.ResourceRepo,
+	objectStore repo.ObjectStore,
+) usecase.MediaLibrary {
+	return medialibrary.New(library, folders, files, resources, objectStore, cfg.Upload.SignedURLTTL)
+}
+
 // NewSessionManager 创建 Session 管理器。
 func NewSessionManager(redis2 *redis.Redis, l logger.Interface) *middleware.SessionManager {
 	return middleware.NewSessionManager(redis2, l)
 }
 
+// NewUploadReaper 创建过期上传任务清理器。
+func NewUploadReaper(cfg *config.Config, resourceUC usecase.Resource, rdb *redis.Redis, l logger.Interface) *job.UploadReaper {
+	return job.NewUploadReaper(cfg.Upload.ReaperEnabled, cfg.Upload.ReaperInterval, resourceUC, rdb, l)
+}
+
 func SetupHTTPServer(
 	cfg *config.Config,
 	l logger.Interface,
-	publicKey *rsa.PublicKey,
+	ssoKeys *middleware.SSOKeySet,
 	userRepo repo.UserRepo,
 	contentUC usecase.Content,
 	commentUC usecase.Comment,
@@ -334,11 +411,13 @@ func SetupHTTPServer(
 	websiteUC usecase.Website,
 	emojiUC usecase.Emoji,
 	advertisementUC usecase.Advertisement,
+	mediaGCUC usecase.MediaGC,
+	mediaLibraryUC usecase.MediaLibrary,
 	sessionManager *middleware.SessionManager,
 	ssoClient *webapi.SSOClient,
 ) *httpserver.Server {
 	srv := httpserver.New(l, httpserver.WithPort(strconv.Itoa(cfg.HTTP.Port)), httpserver.WithPrefork(cfg.HTTP.UsePreforkMode))
-	http.NewRouter(srv.App, cfg, l, publicKey, userRepo, contentUC, commentUC, aiChatUC, aiModelUC, feedbackUC, linkUC, fileUC, resourceUC, userUC, settingUC, websiteUC, emojiUC, advertisementUC, sessionManager, ssoClient)
+	http.NewRouter(srv.App, cfg, l, ssoKeys, userRepo, contentUC, commentUC, aiChatUC, aiModelUC, feedbackUC, linkUC, fileUC, resourceUC, userUC, settingUC, websiteUC, emojiUC, advertisementUC, mediaGCUC, mediaLibraryUC, sessionManager, ssoClient)
 	return srv
 }
 
@@ -351,7 +430,8 @@ var ProviderSet = wire.NewSet(
 	NewPostgres,
 	NewGormDB,
 	NewRedis,
-	NewPublicKey, persistence.NewArticleRepo, persistence.NewArticleLikeRepo, persistence.NewArticleViewRepo, persistence.NewCategoryRepo, persistence.NewTagRepo, persistence.NewCommentRepo, persistence.NewUserRepo, persistence.NewChatSessionRepo, persistence.NewChatMessageRepo, persistence.NewFeedbackRepo, persistence.NewLinkRepo, persistence.NewFileRepo, persistence.NewResourceRepo, persistence.NewResourceUploadTaskRepo, persistence.NewAIModelRepo, persistence.NewEmojiRepo, persistence.NewEmojiSpriteRepo, persistence.NewAdvertisementRepo, persistence.NewFooterLinkRepo, persistence.NewSiteSettingRepo, NewObjectStore,
+	NewSSOKeySet, persistence.NewArticleRepo, persistence.NewArticleLikeRepo, persistence.NewArticleViewRepo, persistence.NewCategoryRepo, persistence.NewTagRepo, persistence.NewCommentRepo, persistence.NewUserRepo, persistence.NewChatSessionRepo, persistence.NewChatMessageRepo, persistence.NewFeedbackRepo, persistence.NewLinkRepo, persistence.NewFileRepo, persistence.NewResourceRepo, persistence.NewResourceUploadTaskRepo, persistence.NewUploadPolicyRepo, persistence.NewUploadUsageRepo, persistence.NewAIModelRepo, persistence.NewEmojiRepo, persistence.NewEmojiSpriteRepo, persistence.NewAdvertisementRepo, persistence.NewFooterLinkRepo, persistence.NewSiteSettingRepo, persistence.NewMediaReferenceRepo, persistence.NewMediaLibraryRepo, persistence.NewMediaFolderRepo, NewObjectStore,
+	NewTranscoder,
 	NewLLMWebAPI,
 	NewSSOClient,
 
@@ -368,8 +448,12 @@ var ProviderSet = wire.NewSet(
 	NewWebsiteUseCase,
 	NewEmojiUseCase,
 	NewAdvertisementUseCase,
+	NewMediaGCUseCase,
+	NewMediaLibraryUseCase,
 
 	NewSessionManager,
 
+	NewUploadReaper,
+
 	SetupHTTPServer,
 )
@@ -1,15 +1,18 @@
 package admin
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
 
 	"server-blog-v2/internal/controller/http/admin/request"
 	"server-blog-v2/internal/controller/http/bizcode"
 	"server-blog-v2/internal/controller/http/middleware"
 	"server-blog-v2/internal/controller/http/shared"
+	"server-blog-v2/internal/usecase/content"
 	"server-blog-v2/internal/usecase/input"
 )
 
@@ -24,7 +27,7 @@ import (
 // @Success 200 {object} shared.Envelope
 // @Router /admin/article/list [get]
 func (a *Admin) listArticles(c fiber.Ctx) error {
-	pq := shared.ParsePageQueryWithOptions(c, shared.WithAllowedFilters("category_id", "tag_id", "status", "visibility"))
+	pq := shared.ParsePageQueryWithOptions(c, shared.WithAllowedFilters("category_id", "include_descendants", "tag_id", "status", "visibility", "author_uuid"))
 
 	pageParams := input.PageParams{
 		Page:     pq.Page,
@@ -48,6 +51,8 @@ func (a *Admin) listArticles(c fiber.Ctx) error {
 		}
 	}
 
+	includeDescendants := pq.Filters["include_descendants"] == "true"
+
 	var tagID input.IntFilterParam
 	if tid, ok := pq.Filters["tag_id"]; ok && tid != "" {
 		if id, err := strconv.Atoi(tid); err == nil {
@@ -65,14 +70,26 @@ func (a *Admin) listArticles(c fiber.Ctx) error {
 		visibility = &v
 	}
 
+	var authorUUID *string
+	if au, ok := pq.Filters["author_uuid"]; ok && au != "" {
+		parsed, err := uuid.Parse(au)
+		if err != nil {
+			return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParam, "invalid author_uuid")
+		}
+		au = parsed.String()
+		authorUUID = &au
+	}
+
 	result, err := a.content.ListArticles(c.Context(), input.ListArticles{
-		PageParams: pageParams,
-		Keyword:    keywordParams,
-		Sort:       sortParams,
-		CategoryID: categoryID,
-		TagID:      tagID,
-		Status:     status,
-		Visibility: visibility,
+		PageParams:         pageParams,
+		Keyword:            keywordParams,
+		Sort:               sortParams,
+		CategoryID:         categoryID,
+		IncludeDescendants: includeDescendants,
+		TagID:              tagID,
+		Status:             status,
+		Visibility:         visibility,
+		AuthorUUID:         authorUUID,
 	})
 
 	if err != nil {
@@ -161,12 +178,16 @@ func (a *Admin) createArticle(c fiber.Ctx) error {
 		AuthorUUID:    userUUID,
 		CategoryID:    categoryID,
 		TagIDs:        req.TagIDs,
+		CoAuthorUUIDs: req.CoAuthorUUIDs,
 		Status:        req.Status,
 		Visibility:    req.Visibility,
 		IsFeatured:    req.IsFeatured,
 	})
 
-	if err != nil {
+	switch {
+	case errors.Is(err, content.ErrInvalidParam):
+		return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParam, "invalid co-authors")
+	case err != nil:
 		a.logger.Error(err, "http - admin - article - createArticle")
 		return shared.WriteError(c, http.StatusInternalServerError, bizcode.ErrorDatabase, "failed to create article")
 	}
@@ -222,12 +243,16 @@ func (a *Admin) updateArticle(c fiber.Ctx) error {
 		FeaturedImage: featuredImage,
 		CategoryID:    categoryID,
 		TagIDs:        req.TagIDs,
+		CoAuthorUUIDs: req.CoAuthorUUIDs,
 		Status:        req.Status,
 		Visibility:    req.Visibility,
 		IsFeatured:    req.IsFeatured,
 	})
 
-	if err != nil {
+	switch {
+	case errors.Is(err, content.ErrInvalidParam):
+		return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParam, "invalid co-authors")
+	case err != nil:
 		a.logger.Error(err, "http - admin - article - updateArticle")
 		return shared.WriteError(c, http.StatusInternalServerError, bizcode.ErrorDatabase, "failed to update article")
 	}
@@ -1,6 +1,7 @@
 package admin
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -9,6 +10,7 @@ import (
 	"server-blog-v2/internal/controller/http/admin/request"
 	"server-blog-v2/internal/controller/http/bizcode"
 	"server-blog-v2/internal/controller/http/shared"
+	"server-blog-v2/internal/usecase/content"
 	"server-blog-v2/internal/usecase/input"
 )
 
@@ -39,6 +41,23 @@ func (a *Admin) listCategories(c fiber.Ctx) error {
 	return shared.WriteSuccess(c, shared.WithData(shared.NewPage(result.Items, result.Page, result.PageSize, result.Total)))
 }
 
+// getCategoryTree 分类树。
+// @Summary 分类树（管理端）
+// @Tags Admin.Category
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} shared.Envelope
+// @Router /admin/category/tree [get]
+func (a *Admin) getCategoryTree(c fiber.Ctx) error {
+	result, err := a.content.GetAllPublicCategories(c.Context())
+	if err != nil {
+		a.logger.Error(err, "http - admin - category - getCategoryTree")
+		return shared.WriteError(c, http.StatusInternalServerError, bizcode.ErrorDatabase, "failed to get category tree")
+	}
+
+	return shared.WriteSuccess(c, shared.WithData(result.Items))
+}
+
 // createCategory 创建分类。
 // @Summary 创建分类（管理端）
 // @Tags Admin.Category
@@ -59,11 +78,16 @@ func (a *Admin) createCategory(c fiber.Ctx) error {
 	}
 
 	id, err := a.content.CreateCategory(c.Context(), input.CreateCategory{
-		Name: req.Name,
-		Slug: req.Slug,
+		Name:      req.Name,
+		Slug:      req.Slug,
+		ParentID:  req.ParentID,
+		SortOrder: req.SortOrder,
 	})
 
-	if err != nil {
+	switch {
+	case errors.Is(err, content.ErrInvalidParent):
+		return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParam, "invalid parent category")
+	case err != nil:
 		a.logger.Error(err, "http - admin - category - createCategory")
 		return shared.WriteError(c, http.StatusInternalServerError, bizcode.ErrorDatabase, "failed to create category")
 	}
@@ -96,7 +120,10 @@ func (a *Admin) updateCategory(c fiber.Ctx) error {
 		Slug: req.Slug,
 	})
 
-	if err != nil {
+	switch {
+	case errors.Is(err, content.ErrNotFound):
+		return shared.WriteError(c, http.StatusNotFound, bizcode.ErrorNotFound, "category not found")
+	case err != nil:
 		a.logger.Error(err, "http - admin - category - updateCategory")
 		return shared.WriteError(c, http.StatusInternalServerError, bizcode.ErrorDatabase, "failed to update category")
 	}
@@ -104,7 +131,45 @@ func (a *Admin) updateCategory(c fiber.Ctx) error {
 	return shared.WriteSuccess(c)
 }
 
-// deleteCategory 删除分类。
+// moveCategory 移动分类（连同子树）。
+// @Summary 移动分类（管理端）
+// @Tags Admin.Category
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body request.MoveCategory true "目标位置"
+// @Success 200 {object} shared.Envelope
+// @Router /admin/category/move [put]
+func (a *Admin) moveCategory(c fiber.Ctx) error {
+	var req request.MoveCategory
+	if err := c.Bind().JSON(&req); err != nil {
+		return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParam, "invalid request body")
+	}
+
+	if err := a.validate.Struct(req); err != nil {
+		return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParamFormat, err.Error())
+	}
+
+	err := a.content.MoveCategory(c.Context(), input.MoveCategory{
+		ID:        req.ID,
+		ParentID:  req.ParentID,
+		SortOrder: req.SortOrder,
+	})
+
+	switch {
+	case errors.Is(err, content.ErrNotFound):
+		return shared.WriteError(c, http.StatusNotFound, bizcode.ErrorNotFound, "category not found")
+	case errors.Is(err, content.ErrInvalidParent):
+		return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParam, "invalid parent category")
+	case err != nil:
+		a.logger.Error(err, "http - admin - category - moveCategory")
+		return shared.WriteError(c, http.StatusInternalServerError, bizcode.ErrorDatabase, "failed to move category")
+	}
+
+	return shared.WriteSuccess(c)
+}
+
+// deleteCategory 删除分类（子分类上移到其父分类）。
 // @Summary 删除分类（管理端）
 // @Tags Admin.Category
 // @Security BearerAuth
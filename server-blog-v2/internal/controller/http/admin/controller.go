@@ -26,6 +26,8 @@ type Admin struct {
 	aiModel       usecase.AIModel
 	website       usecase.Website
 	advertisement usecase.Advertisement
+	mediaGC       usecase.MediaGC
+	mediaLibrary  usecase.MediaLibrary
 }
 
 // New 创建 Admin 控制器。
@@ -45,6 +47,8 @@ func New(
 	aiModel usecase.AIModel,
 	website usecase.Website,
 	advertisement usecase.Advertisement,
+	mediaGC usecase.MediaGC,
+	mediaLibrary usecase.MediaLibrary,
 ) *Admin {
 	return &Admin{
 		cfg:           cfg,
@@ -63,6 +67,8 @@ func New(
 		aiModel:       aiModel,
 		website:       website,
 		advertisement: advertisement,
+		mediaGC:       mediaGC,
+		mediaLibrary:  mediaLibrary,
 	}
 }
 
@@ -1,13 +1,18 @@
 package admin
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gofiber/fiber/v3"
 
+	"server-blog-v2/internal/controller/http/admin/request"
 	"server-blog-v2/internal/controller/http/bizcode"
 	"server-blog-v2/internal/controller/http/shared"
+	"server-blog-v2/internal/pkg/imageproc"
+	fileuc "server-blog-v2/internal/usecase/file"
 	"server-blog-v2/internal/usecase/input"
+	"server-blog-v2/internal/usecase/resource"
 )
 
 // uploadFile 上传文件。
@@ -17,6 +22,7 @@ import (
 // @Accept multipart/form-data
 // @Produce json
 // @Param file formance file true "文件"
+// @Param access formData string false "访问级别：public（默认）/private"
 // @Success 200 {object} shared.Envelope
 // @Router /admin/file/upload [post]
 func (a *Admin) uploadFile(c fiber.Ctx) error {
@@ -36,8 +42,15 @@ func (a *Admin) uploadFile(c fiber.Ctx) error {
 		File:     src,
 		Filename: file.Filename,
 		Size:     file.Size,
+		Access:   c.FormValue("access"),
 	})
 
+	if errors.Is(err, imageproc.ErrImageTooLarge) {
+		return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParam, "image dimensions too large")
+	}
+	if errors.Is(err, fileuc.ErrInvalidAccess) {
+		return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParam, "invalid access")
+	}
 	if err != nil {
 		a.logger.Error(err, "http - admin - file - uploadFile")
 		return shared.WriteError(c, http.StatusInternalServerError, bizcode.ErrorSystem, "failed to upload file")
@@ -124,6 +137,42 @@ func (a *Admin) deleteImages(c fiber.Ctx) error {
 	return shared.WriteSuccess(c)
 }
 
+// setImageAccess 批量修改图片访问级别。
+// @Summary 修改图片访问级别（管理端）
+// @Tags Admin.Image
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body request.SetFileAccess true "图片 ID 与访问级别"
+// @Success 200 {object} shared.Envelope
+// @Router /admin/image/access [put]
+func (a *Admin) setImageAccess(c fiber.Ctx) error {
+	var req request.SetFileAccess
+	if err := c.Bind().JSON(&req); err != nil {
+		return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParam, "invalid request body")
+	}
+
+	if err := a.validate.Struct(req); err != nil {
+		return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParamFormat, err.Error())
+	}
+
+	if err := a.file.SetAccess(c.Context(), req.IDs, req.Access); err != nil {
+		switch {
+		case errors.Is(err, fileuc.ErrInvalidAccess):
+			return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParam, "invalid access")
+		case errors.Is(err, fileuc.ErrNotFound):
+			return shared.WriteError(c, http.StatusNotFound, bizcode.ErrorNotFound, "image not found")
+		}
+		a.logger.Error(err, "http - admin - file - setImageAccess")
+		if errors.Is(err, fileuc.ErrStorage) {
+			return shared.WriteError(c, http.StatusBadGateway, bizcode.ErrorThirdParty, "failed to move image objects")
+		}
+		return shared.WriteError(c, http.StatusInternalServerError, bizcode.ErrorDatabase, "failed to set image access")
+	}
+
+	return shared.WriteSuccess(c)
+}
+
 // listResources 资源列表。
 // @Summary 资源列表（管理端）
 // @Tags Admin.Resource
@@ -179,3 +228,41 @@ func (a *Admin) deleteResources(c fiber.Ctx) error {
 
 	return shared.WriteSuccess(c)
 }
+
+// setResourceAccess 批量修改资源访问级别。
+// @Summary 修改资源访问级别（管理端）
+// @Tags Admin.Resource
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body request.SetResourceAccess true "资源 ID 与访问级别"
+// @Success 200 {object} shared.Envelope
+// @Router /admin/resources/access [put]
+func (a *Admin) setResourceAccess(c fiber.Ctx) error {
+	var req request.SetResourceAccess
+	if err := c.Bind().JSON(&req); err != nil {
+		return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParam, "invalid request body")
+	}
+
+	if err := a.validate.Struct(req); err != nil {
+		return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParamFormat, err.Error())
+	}
+
+	if err := a.resource.SetAccess(c.Context(), req.IDs, req.Access); err != nil {
+		switch {
+		case errors.Is(err, resource.ErrInvalidAccess):
+			return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParam, "invalid access")
+		case errors.Is(err, resource.ErrNotFound):
+			return shared.WriteError(c, http.StatusNotFound, bizcode.ErrorNotFound, "resource not found")
+		case errors.Is(err, resource.ErrTranscoding):
+			return shared.WriteError(c, http.StatusConflict, bizcode.ErrorParam, "resource is transcoding")
+		}
+		a.logger.Error(err, "http - admin - resource - setResourceAccess")
+		if errors.Is(err, resource.ErrStorage) {
+			return shared.WriteError(c, http.StatusBadGateway, bizcode.ErrorThirdParty, "failed to move resource objects")
+		}
+		return shared.WriteError(c, http.StatusInternalServerError, bizcode.ErrorDatabase, "failed to set resource access")
+	}
+
+	return shared.WriteSuccess(c)
+}
@@ -0,0 +1,351 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v3"
+
+	"server-blog-v2/internal/controller/http/admin/request"
+	"server-blog-v2/internal/controller/http/bizcode"
+	"server-blog-v2/internal/controller/http/shared"
+	"server-blog-v2/internal/usecase/input"
+	"server-blog-v2/internal/usecase/medialibrary"
+)
+
+// scanOrphans 扫描未被引用的文件与资源。
+// @Summary 扫描孤儿媒体（管理端）
+// @Tags Admin.Media
+// @Security BearerAuth
+// @Produce json
+// @Param grace_days query int false "宽限天数，默认 7 天"
+// @Success 200 {object} shared.Envelope
+// @Router /admin/media/orphans [get]
+func (a *Admin) scanOrphans(c fiber.Ctx) error {
+	var graceDays int
+	if v := c.Query("grace_days"); v != "" {
+		days, err := strconv.Atoi(v)
+		if err != nil || days < 0 {
+			return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParam, "invalid grace_days")
+		}
+		graceDays = days
+	}
+
+	report, err := a.mediaGC.ScanOrphans(c.Context(), input.ScanOrphans{GraceDays: graceDays})
+	if err != nil {
+		a.logger.Error(err, "http - admin - media - scanOrphans")
+		return shared.WriteError(c, http.StatusInternalServerError, bizcode.ErrorDatabase, "failed to scan orphans")
+	}
+
+	return shared.WriteSuccess(c, shared.WithData(report))
+}
+
+// purgeOrphans 删除选中的孤儿媒体，删除前会重新确认仍未被引用。
+// @Summary 删除孤儿媒体（管理端）
+// @Tags Admin.Media
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body request.PurgeOrphans true "待删除的文件与资源 ID"
+// @Success 200 {object} shared.Envelope
+// @Router /admin/media/orphans/purge [post]
+func (a *Admin) purgeOrphans(c fiber.Ctx) error {
+	var req request.PurgeOrphans
+	if err := c.Bind().JSON(&req); err != nil {
+		return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParam, "invalid request body")
+	}
+
+	if err := a.validate.Struct(req); err != nil {
+		return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParamFormat, err.Error())
+	}
+
+	if len(req.FileIDs) == 0 && len(req.ResourceIDs) == 0 {
+		return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParamMissing, "file_ids or resource_ids is required")
+	}
+
+	result, err := a.mediaGC.PurgeOrphans(c.Context(), input.PurgeOrphans{
+		GraceDays:   req.GraceDays,
+		FileIDs:     req.FileIDs,
+		ResourceIDs: req.ResourceIDs,
+	})
+	if err != nil {
+		a.logger.Error(err, "http - admin - media - purgeOrphans")
+		return shared.WriteError(c, http.StatusInternalServerError, bizcode.ErrorSystem, "failed to purge orphans")
+	}
+
+	return shared.WriteSuccess(c, shared.WithData(result))
+}
+
+// listMediaItems 媒体库列表（合并图片与资源）。
+// @Summary 媒体库列表（管理端）
+// @Tags Admin.Media
+// @Security BearerAuth
+// @Produce json
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Param keyword query string false "文件名检索"
+// @Param source query string false "来源：file | resource"
+// @Param folder_id query int false "文件夹 ID，0 表示根目录"
+// @Param tag query string false "标签"
+// @Param mime_type query string false "MIME 大类，如 image、video"
+// @Success 200 {object} shared.Envelope
+// @Router /admin/media/items [get]
+func (a *Admin) listMediaItems(c fiber.Ctx) error {
+	pq := shared.ParsePageQueryWithOptions(c, shared.WithAllowedFilters("source", "folder_id", "tag", "mime_type"))
+
+	var folderID *int64
+	if v := pq.Filters["folder_id"]; v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || id < 0 {
+			return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParam, "invalid folder_id")
+		}
+		folderID = &id
+	}
+
+	result, err := a.mediaLibrary.ListItems(c.Context(), input.ListMediaItems{
+		PageParams: input.PageParams{Page: pq.Page, PageSize: pq.PageSize},
+		Source:     pq.Filters["source"],
+		FolderID:   folderID,
+		Tag:        pq.Filters["tag"],
+		Keyword:    pq.Keyword,
+		MimeFamily: pq.Filters["mime_type"],
+	})
+	if err != nil {
+		return a.writeMediaLibraryError(c, err, "listMediaItems", "failed to list media items")
+	}
+
+	return shared.WriteSuccess(c, shared.WithData(shared.NewPage(result.Items, result.Page, result.PageSize, result.Total)))
+}
+
+// updateMediaItem 更新媒体库条目的替代文本、说明与标签。
+// @Summary 更新媒体信息（管理端）
+// @Tags Admin.Media
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param source path string true "来源：file | resource"
+// @Param id path int true "文件或资源 ID"
+// @Param body body request.UpdateMediaItem true "媒体信息"
+// @Success 200 {object} shared.Envelope
+// @Router /admin/media/items/{source}/{id} [put]
+func (a *Admin) updateMediaItem(c fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParam, "invalid id")
+	}
+
+	var req request.UpdateMediaItem
+	if err := c.Bind().JSON(&req); err != nil {
+		return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParam, "invalid request body")
+	}
+
+	if err := a.validate.Struct(req); err != nil {
+		return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParamFormat, err.Error())
+	}
+
+	if err := a.mediaLibrary.UpdateItem(c.Context(), input.UpdateMediaItem{
+		Source:  c.Params("source"),
+		ID:      id,
+		AltText: req.AltText,
+		Caption: req.Caption,
+		Tags:    req.Tags,
+	}); err != nil {
+		return a.writeMediaLibraryError(c, err, "updateMediaItem", "failed to update media item")
+	}
+
+	return shared.WriteSuccess(c)
+}
+
+// getMediaItemUsage 列出引用该媒体的文章。
+// @Summary 媒体引用情况（管理端）
+// @Tags Admin.Media
+// @Security BearerAuth
+// @Produce json
+// @Param source path string true "来源：file | resource"
+// @Param id path int true "文件或资源 ID"
+// @Success 200 {object} shared.Envelope
+// @Router /admin/media/items/{source}/{id}/usage [get]
+func (a *Admin) getMediaItemUsage(c fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParam, "invalid id")
+	}
+
+	result, err := a.mediaLibrary.GetItemUsage(c.Context(), c.Params("source"), id)
+	if err != nil {
+		return a.writeMediaLibraryError(c, err, "getMediaItemUsage", "failed to get media item usage")
+	}
+
+	return shared.WriteSuccess(c, shared.WithData(result.Items))
+}
+
+// moveMediaItems 移动媒体到文件夹，不改变对象 Key。
+// @Summary 移动媒体（管理端）
+// @Tags Admin.Media
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body request.MoveMediaItems true "条目与目标文件夹"
+// @Success 200 {object} shared.Envelope
+// @Router /admin/media/items/move [post]
+func (a *Admin) moveMediaItems(c fiber.Ctx) error {
+	var req request.MoveMediaItems
+	if err := c.Bind().JSON(&req); err != nil {
+		return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParam, "invalid request body")
+	}
+
+	if err := a.validate.Struct(req); err != nil {
+		return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParamFormat, err.Error())
+	}
+
+	items := make([]input.MediaItemRef, len(req.Items))
+	for i, item := range req.Items {
+		items[i] = input.MediaItemRef{Source: item.Source, ID: item.ID}
+	}
+
+	if err := a.mediaLibrary.MoveItems(c.Context(), input.MoveMediaItems{
+		Items:    items,
+		FolderID: req.FolderID,
+	}); err != nil {
+		return a.writeMediaLibraryError(c, err, "moveMediaItems", "failed to move media items")
+	}
+
+	return shared.WriteSuccess(c)
+}
+
+// listMediaTags 媒体标签列表。
+// @Summary 媒体标签列表（管理端）
+// @Tags Admin.Media
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} shared.Envelope
+// @Router /admin/media/tags [get]
+func (a *Admin) listMediaTags(c fiber.Ctx) error {
+	result, err := a.mediaLibrary.ListTags(c.Context())
+	if err != nil {
+		a.logger.Error(err, "http - admin - media - listMediaTags")
+		return shared.WriteError(c, http.StatusInternalServerError, bizcode.ErrorDatabase, "failed to list media tags")
+	}
+
+	return shared.WriteSuccess(c, shared.WithData(result.Items))
+}
+
+// listMediaFolders 媒体库文件夹列表。
+// @Summary 媒体文件夹列表（管理端）
+// @Tags Admin.Media
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} shared.Envelope
+// @Router /admin/media/folders [get]
+func (a *Admin) listMediaFolders(c fiber.Ctx) error {
+	result, err := a.mediaLibrary.ListFolders(c.Context())
+	if err != nil {
+		a.logger.Error(err, "http - admin - media - listMediaFolders")
+		return shared.WriteError(c, http.StatusInternalServerError, bizcode.ErrorDatabase, "failed to list media folders")
+	}
+
+	return shared.WriteSuccess(c, shared.WithData(result.Items))
+}
+
+// createMediaFolder 创建媒体库文件夹。
+// @Summary 创建媒体文件夹（管理端）
+// @Tags Admin.Media
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body request.SaveMediaFolder true "文件夹"
+// @Success 200 {object} shared.Envelope
+// @Router /admin/media/folders [post]
+func (a *Admin) createMediaFolder(c fiber.Ctx) error {
+	var req request.SaveMediaFolder
+	if err := c.Bind().JSON(&req); err != nil {
+		return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParam, "invalid request body")
+	}
+
+	if err := a.validate.Struct(req); err != nil {
+		return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParamFormat, err.Error())
+	}
+
+	id, err := a.mediaLibrary.CreateFolder(c.Context(), input.SaveMediaFolder{
+		Name:     req.Name,
+		ParentID: req.ParentID,
+	})
+	if err != nil {
+		return a.writeMediaLibraryError(c, err, "createMediaFolder", "failed to create media folder")
+	}
+
+	return shared.WriteSuccess(c, shared.WithData(map[string]interface{}{
+		"id": id,
+	}))
+}
+
+// updateMediaFolder 重命名或移动媒体库文件夹。
+// @Summary 修改媒体文件夹（管理端）
+// @Tags Admin.Media
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "文件夹 ID"
+// @Param body body request.SaveMediaFolder true "文件夹"
+// @Success 200 {object} shared.Envelope
+// @Router /admin/media/folders/{id} [put]
+func (a *Admin) updateMediaFolder(c fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParam, "invalid id")
+	}
+
+	var req request.SaveMediaFolder
+	if err := c.Bind().JSON(&req); err != nil {
+		return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParam, "invalid request body")
+	}
+
+	if err := a.validate.Struct(req); err != nil {
+		return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParamFormat, err.Error())
+	}
+
+	if err := a.mediaLibrary.UpdateFolder(c.Context(), id, input.SaveMediaFolder{
+		Name:     req.Name,
+		ParentID: req.ParentID,
+	}); err != nil {
+		return a.writeMediaLibraryError(c, err, "updateMediaFolder", "failed to update media folder")
+	}
+
+	return shared.WriteSuccess(c)
+}
+
+// deleteMediaFolder 删除媒体库文件夹，其中的子文件夹与媒体移到上级文件夹。
+// @Summary 删除媒体文件夹（管理端）
+// @Tags Admin.Media
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "文件夹 ID"
+// @Success 200 {object} shared.Envelope
+// @Router /admin/media/folders/{id} [delete]
+func (a *Admin) deleteMediaFolder(c fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParam, "invalid id")
+	}
+
+	if err := a.mediaLibrary.DeleteFolder(c.Context(), id); err != nil {
+		return a.writeMediaLibraryError(c, err, "deleteMediaFolder", "failed to delete media folder")
+	}
+
+	return shared.WriteSuccess(c)
+}
+
+// writeMediaLibraryError 将媒体库用例错误映射为 400/404/409，其余错误记录日志后返回 500。
+func (a *Admin) writeMediaLibraryError(c fiber.Ctx, err error, handler, message string) error {
+	switch {
+	case errors.Is(err, medialibrary.ErrInvalidParam):
+		return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParam, "invalid media library request")
+	case errors.Is(err, medialibrary.ErrNotFound):
+		return shared.WriteError(c, http.StatusNotFound, bizcode.ErrorNotFound, "media item or folder not found")
+	case errors.Is(err, medialibrary.ErrFolderConflict):
+		return shared.WriteError(c, http.StatusConflict, bizcode.ErrorParam, "folder name already exists")
+	}
+	a.logger.Error(err, "http - admin - media - "+handler)
+	return shared.WriteError(c, http.StatusInternalServerError, bizcode.ErrorDatabase, message)
+}
@@ -2,8 +2,10 @@ package request
 
 // CreateCategory 创建分类请求。
 type CreateCategory struct {
-	Name string `json:"name" validate:"required,max=50"`
-	Slug string `json:"slug" validate:"required,max=50"`
+	Name      string `json:"name" validate:"required,max=50"`
+	Slug      string `json:"slug" validate:"required,max=50"`
+	ParentID  *int64 `json:"parent_id"`
+	SortOrder int32  `json:"sort_order"`
 }
 
 // UpdateCategory 更新分类请求。
@@ -12,3 +14,10 @@ type UpdateCategory struct {
 	Name string `json:"name" validate:"required,max=50"`
 	Slug string `json:"slug" validate:"required,max=50"`
 }
+
+// MoveCategory 移动分类请求，parent_id 为空表示移到顶级。
+type MoveCategory struct {
+	ID        int64  `json:"id" validate:"required"`
+	ParentID  *int64 `json:"parent_id"`
+	SortOrder int32  `json:"sort_order"`
+}
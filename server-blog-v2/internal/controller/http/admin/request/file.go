@@ -0,0 +1,7 @@
+package request
+
+// SetFileAccess 批量修改文件访问级别请求。
+type SetFileAccess struct {
+	IDs    []int64 `json:"ids" validate:"required,min=1"`
+	Access string  `json:"access" validate:"required,oneof=public private"`
+}
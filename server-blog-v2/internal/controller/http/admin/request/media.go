@@ -0,0 +1,33 @@
+package request
+
+// PurgeOrphans 删除孤儿媒体请求。
+type PurgeOrphans struct {
+	FileIDs     []int64 `json:"file_ids"`
+	ResourceIDs []int64 `json:"resource_ids"`
+	GraceDays   int     `json:"grace_days" validate:"min=0"`
+}
+
+// UpdateMediaItem 更新媒体库条目请求。
+type UpdateMediaItem struct {
+	AltText string   `json:"alt_text" validate:"max=255"`
+	Caption string   `json:"caption" validate:"max=2000"`
+	Tags    []string `json:"tags" validate:"max=20,dive,max=50"`
+}
+
+// MediaItemRef 媒体库条目引用。
+type MediaItemRef struct {
+	Source string `json:"source" validate:"required,oneof=file resource"`
+	ID     int64  `json:"id" validate:"required,min=1"`
+}
+
+// MoveMediaItems 移动媒体库条目请求，folder_id 为 null 表示移回根目录。
+type MoveMediaItems struct {
+	Items    []MediaItemRef `json:"items" validate:"required,min=1,dive"`
+	FolderID *int64         `json:"folder_id" validate:"omitempty,min=1"`
+}
+
+// SaveMediaFolder 创建或修改文件夹请求，parent_id 为 null 表示根目录。
+type SaveMediaFolder struct {
+	Name     string `json:"name" validate:"required,max=100"`
+	ParentID *int64 `json:"parent_id" validate:"omitempty,min=1"`
+}
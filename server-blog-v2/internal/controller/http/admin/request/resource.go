@@ -0,0 +1,17 @@
+package request
+
+// SaveUploadPolicy 保存上传策略请求，role_id 与 user_uuid 二选一；字段为 null 表示不设置该项。
+type SaveUploadPolicy struct {
+	RoleID              *int     `json:"role_id" validate:"omitempty,min=1"`
+	UserUUID            *string  `json:"user_uuid" validate:"omitempty,uuid"`
+	QuotaBytes          *int64   `json:"quota_bytes" validate:"omitempty,min=0"`
+	MaxFileSize         *int64   `json:"max_file_size" validate:"omitempty,min=0"`
+	DailyUploadBytes    *int64   `json:"daily_upload_bytes" validate:"omitempty,min=0"`
+	AllowedMimeFamilies []string `json:"allowed_mime_families" validate:"omitempty,dive,oneof=image video audio application text"`
+}
+
+// SetResourceAccess 批量修改资源访问级别请求。
+type SetResourceAccess struct {
+	IDs    []int64 `json:"ids" validate:"required,min=1"`
+	Access string  `json:"access" validate:"required,oneof=public private"`
+}
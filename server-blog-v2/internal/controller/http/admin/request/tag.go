@@ -12,3 +12,21 @@ type UpdateTag struct {
 	Name string `json:"name" validate:"required,max=50"`
 	Slug string `json:"slug" validate:"required,max=50"`
 }
+
+// MergeTags 合并标签请求。
+type MergeTags struct {
+	SourceIDs []int64 `json:"source_ids" validate:"required,min=1"`
+	TargetID  int64   `json:"target_id" validate:"required"`
+}
+
+// BulkRetag 批量打标签请求，筛选条件至少指定一项。
+type BulkRetag struct {
+	TagID              int64   `json:"tag_id" validate:"required"`
+	Action             string  `json:"action" validate:"required,oneof=add remove"`
+	ArticleIDs         []int64 `json:"article_ids"`
+	CategoryID         *int    `json:"category_id"`
+	IncludeDescendants bool    `json:"include_descendants"`
+	FilterTagID        *int    `json:"filter_tag_id"`
+	Status             *string `json:"status" validate:"omitempty,oneof=draft published archived"`
+	Keyword            *string `json:"keyword"`
+}
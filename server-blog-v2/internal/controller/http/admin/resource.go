@@ -1,15 +1,19 @@
 package admin
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
 
+	"server-blog-v2/internal/controller/http/admin/request"
 	"server-blog-v2/internal/controller/http/bizcode"
 	"server-blog-v2/internal/controller/http/middleware"
 	"server-blog-v2/internal/controller/http/shared"
 	"server-blog-v2/internal/usecase/input"
+	"server-blog-v2/internal/usecase/resource"
 )
 
 // getMaxFileSize 获取最大文件大小。
@@ -26,6 +30,131 @@ func (a *Admin) getMaxFileSize(c fiber.Ctx) error {
 	}))
 }
 
+// getReapStats 获取过期上传任务清理统计。
+// @Summary 过期上传任务清理统计（管理端）
+// @Tags Admin.Resource
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} shared.Envelope
+// @Router /admin/resources/reaper-stats [get]
+func (a *Admin) getReapStats(c fiber.Ctx) error {
+	stats, err := a.resource.GetReapStats(c.Context())
+	if err != nil {
+		a.logger.Error(err, "http - admin - resource - getReapStats")
+		return shared.WriteError(c, http.StatusInternalServerError, bizcode.ErrorSystem, "failed to get reaper stats")
+	}
+	return shared.WriteSuccess(c, shared.WithData(stats))
+}
+
+// getStorageUsage 获取存储用量与生效的上传策略。
+// @Summary 存储用量（管理端）
+// @Tags Admin.Resource
+// @Security BearerAuth
+// @Produce json
+// @Param user_uuid query string false "用户 UUID，默认当前用户"
+// @Success 200 {object} shared.Envelope
+// @Router /admin/resources/usage [get]
+func (a *Admin) getStorageUsage(c fiber.Ctx) error {
+	userUUID := c.Query("user_uuid")
+	if userUUID != "" {
+		if _, err := uuid.Parse(userUUID); err != nil {
+			return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParam, "invalid user_uuid")
+		}
+	} else {
+		userUUID = middleware.GetUserUUID(c)
+	}
+	if userUUID == "" {
+		return shared.WriteError(c, http.StatusUnauthorized, bizcode.ErrorUnauthorized, "unauthorized")
+	}
+
+	usage, err := a.resource.GetStorageUsage(c.Context(), userUUID)
+	if errors.Is(err, resource.ErrNotFound) {
+		return shared.WriteError(c, http.StatusNotFound, bizcode.ErrorNotFound, "user not found")
+	}
+	if err != nil {
+		a.logger.Error(err, "http - admin - resource - getStorageUsage")
+		return shared.WriteError(c, http.StatusInternalServerError, bizcode.ErrorDatabase, "failed to get storage usage")
+	}
+	return shared.WriteSuccess(c, shared.WithData(usage))
+}
+
+// listUploadPolicies 上传策略列表。
+// @Summary 上传策略列表（管理端）
+// @Tags Admin.Resource
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} shared.Envelope
+// @Router /admin/resources/policies [get]
+func (a *Admin) listUploadPolicies(c fiber.Ctx) error {
+	result, err := a.resource.ListUploadPolicies(c.Context())
+	if err != nil {
+		a.logger.Error(err, "http - admin - resource - listUploadPolicies")
+		return shared.WriteError(c, http.StatusInternalServerError, bizcode.ErrorDatabase, "failed to list upload policies")
+	}
+	return shared.WriteSuccess(c, shared.WithData(result.Items))
+}
+
+// saveUploadPolicy 创建或覆盖角色/用户的上传策略。
+// @Summary 保存上传策略（管理端）
+// @Tags Admin.Resource
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body request.SaveUploadPolicy true "策略"
+// @Success 200 {object} shared.Envelope
+// @Router /admin/resources/policies [put]
+func (a *Admin) saveUploadPolicy(c fiber.Ctx) error {
+	var req request.SaveUploadPolicy
+	if err := c.Bind().JSON(&req); err != nil {
+		return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParam, "invalid request body")
+	}
+
+	if err := a.validate.Struct(req); err != nil {
+		return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParamFormat, err.Error())
+	}
+
+	if (req.RoleID == nil) == (req.UserUUID == nil) {
+		return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParam, "exactly one of role_id and user_uuid is required")
+	}
+
+	id, err := a.resource.SaveUploadPolicy(c.Context(), input.SaveUploadPolicy{
+		RoleID:              req.RoleID,
+		UserUUID:            req.UserUUID,
+		QuotaBytes:          req.QuotaBytes,
+		MaxFileSize:         req.MaxFileSize,
+		DailyUploadBytes:    req.DailyUploadBytes,
+		AllowedMimeFamilies: req.AllowedMimeFamilies,
+	})
+	if err != nil {
+		a.logger.Error(err, "http - admin - resource - saveUploadPolicy")
+		return shared.WriteError(c, http.StatusInternalServerError, bizcode.ErrorDatabase, "failed to save upload policy")
+	}
+	return shared.WriteSuccess(c, shared.WithData(map[string]interface{}{
+		"id": id,
+	}))
+}
+
+// deleteUploadPolicy 删除上传策略。
+// @Summary 删除上传策略（管理端）
+// @Tags Admin.Resource
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "策略 ID"
+// @Success 200 {object} shared.Envelope
+// @Router /admin/resources/policies/{id} [delete]
+func (a *Admin) deleteUploadPolicy(c fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParam, "invalid id")
+	}
+
+	if err := a.resource.DeleteUploadPolicy(c.Context(), id); err != nil {
+		a.logger.Error(err, "http - admin - resource - deleteUploadPolicy")
+		return shared.WriteError(c, http.StatusInternalServerError, bizcode.ErrorDatabase, "failed to delete upload policy")
+	}
+	return shared.WriteSuccess(c)
+}
+
 // checkResource 检查文件（秒传/续传检测）。
 // @Summary 检查文件（管理端）
 // @Tags Admin.Resource
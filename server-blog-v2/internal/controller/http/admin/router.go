@@ -1,8 +1,6 @@
 package admin
 
 import (
-	"crypto/rsa"
-
 	"github.com/gofiber/fiber/v3"
 
 	"server-blog-v2/config"
@@ -18,7 +16,7 @@ func NewRoutes(
 	router fiber.Router,
 	cfg *config.Config,
 	l logger.Interface,
-	publicKey *rsa.PublicKey,
+	ssoKeys *middleware.SSOKeySet,
 	userRepo repo.UserRepo,
 	sessionManager *middleware.SessionManager,
 	ssoClient *webapi.SSOClient,
@@ -35,12 +33,14 @@ func NewRoutes(
 	aiModel usecase.AIModel,
 	website usecase.Website,
 	advertisement usecase.Advertisement,
+	mediaGC usecase.MediaGC,
+	mediaLibrary usecase.MediaLibrary,
 ) {
-	admin := New(cfg, l, content, comment, feedback, link, file, resource, user, setting, emoji, aiChat, aiModel, website, advertisement)
+	admin := New(cfg, l, content, comment, feedback, link, file, resource, user, setting, emoji, aiChat, aiModel, website, advertisement, mediaGC, mediaLibrary)
 
 	// 管理员 JWT 中间件（SSO 模式，支持自动刷新 token）
 	ssoJWTConfig := middleware.SSOJWTConfig{
-		PublicKey:          publicKey,
+		Keys:               ssoKeys,
 		SSOClient:          ssoClient,
 		UserRoleGetter:     userRepo,
 		UserCreator:        userRepo,
@@ -63,8 +63,10 @@ func NewRoutes(
 	categoryGroup := router.Group("/category", adminRequired)
 	{
 		categoryGroup.Get("/list", admin.listCategories)
+		categoryGroup.Get("/tree", admin.getCategoryTree)
 		categoryGroup.Post("/create", admin.createCategory)
 		categoryGroup.Put("/update", admin.updateCategory)
+		categoryGroup.Put("/move", admin.moveCategory)
 		categoryGroup.Delete("/delete/:id", admin.deleteCategory)
 	}
 
@@ -75,6 +77,10 @@ func NewRoutes(
 		tagGroup.Post("/create", admin.createTag)
 		tagGroup.Put("/update", admin.updateTag)
 		tagGroup.Delete("/delete/:id", admin.deleteTag)
+		tagGroup.Get("/aliases/:id", admin.listTagAliases)
+		tagGroup.Delete("/alias/:id", admin.deleteTagAlias)
+		tagGroup.Post("/merge", admin.mergeTags)
+		tagGroup.Post("/bulk", admin.bulkRetag)
 	}
 
 	// ==================== 评论管理 /comment ====================
@@ -122,12 +128,18 @@ func NewRoutes(
 	{
 		imageGroup.Get("/list", admin.listImages)
 		imageGroup.Delete("/delete", admin.deleteImages)
+		imageGroup.Put("/access", admin.setImageAccess)
 	}
 
 	// ==================== 资源管理 /resources ====================
 	resourceGroup := router.Group("/resources", adminRequired)
 	{
 		resourceGroup.Get("/max-size", admin.getMaxFileSize)
+		resourceGroup.Get("/reaper-stats", admin.getReapStats)
+		resourceGroup.Get("/usage", admin.getStorageUsage)
+		resourceGroup.Get("/policies", admin.listUploadPolicies)
+		resourceGroup.Put("/policies", admin.saveUploadPolicy)
+		resourceGroup.Delete("/policies/:id", admin.deleteUploadPolicy)
 		resourceGroup.Post("/check", admin.checkResource)
 		resourceGroup.Post("/init", admin.initResource)
 		resourceGroup.Post("/upload-chunk", admin.uploadChunk)
@@ -136,6 +148,23 @@ func NewRoutes(
 		resourceGroup.Get("/progress", admin.progressResource)
 		resourceGroup.Get("/list", admin.listResources)
 		resourceGroup.Post("/delete", admin.deleteResources)
+		resourceGroup.Put("/access", admin.setResourceAccess)
+	}
+
+	// ==================== 媒体清理 /media ====================
+	mediaGroup := router.Group("/media", adminRequired)
+	{
+		mediaGroup.Get("/orphans", admin.scanOrphans)
+		mediaGroup.Post("/orphans/purge", admin.purgeOrphans)
+		mediaGroup.Get("/items", admin.listMediaItems)
+		mediaGroup.Post("/items/move", admin.moveMediaItems)
+		mediaGroup.Put("/items/:source/:id", admin.updateMediaItem)
+		mediaGroup.Get("/items/:source/:id/usage", admin.getMediaItemUsage)
+		mediaGroup.Get("/tags", admin.listMediaTags)
+		mediaGroup.Get("/folders", admin.listMediaFolders)
+		mediaGroup.Post("/folders", admin.createMediaFolder)
+		mediaGroup.Put("/folders/:id", admin.updateMediaFolder)
+		mediaGroup.Delete("/folders/:id", admin.deleteMediaFolder)
 	}
 
 	// ==================== 表情管理 /emoji ====================
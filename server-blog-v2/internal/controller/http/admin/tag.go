@@ -1,6 +1,7 @@
 package admin
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -9,6 +10,7 @@ import (
 	"server-blog-v2/internal/controller/http/admin/request"
 	"server-blog-v2/internal/controller/http/bizcode"
 	"server-blog-v2/internal/controller/http/shared"
+	"server-blog-v2/internal/usecase/content"
 	"server-blog-v2/internal/usecase/input"
 )
 
@@ -125,3 +127,128 @@ func (a *Admin) deleteTag(c fiber.Ctx) error {
 
 	return shared.WriteSuccess(c)
 }
+
+// listTagAliases 标签别名列表。
+// @Summary 标签别名列表（管理端）
+// @Tags Admin.Tag
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "标签 ID"
+// @Success 200 {object} shared.Envelope
+// @Router /admin/tag/aliases/{id} [get]
+func (a *Admin) listTagAliases(c fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParamFormat, "invalid tag id")
+	}
+
+	aliases, err := a.content.ListTagAliases(c.Context(), id)
+	if err != nil {
+		a.logger.Error(err, "http - admin - tag - listTagAliases")
+		return shared.WriteError(c, http.StatusInternalServerError, bizcode.ErrorDatabase, "failed to list tag aliases")
+	}
+
+	return shared.WriteSuccess(c, shared.WithData(aliases))
+}
+
+// deleteTagAlias 删除标签别名。
+// @Summary 删除标签别名（管理端）
+// @Tags Admin.Tag
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "别名 ID"
+// @Success 200 {object} shared.Envelope
+// @Router /admin/tag/alias/{id} [delete]
+func (a *Admin) deleteTagAlias(c fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParamFormat, "invalid alias id")
+	}
+
+	if err := a.content.DeleteTagAlias(c.Context(), id); err != nil {
+		a.logger.Error(err, "http - admin - tag - deleteTagAlias")
+		return shared.WriteError(c, http.StatusInternalServerError, bizcode.ErrorDatabase, "failed to delete tag alias")
+	}
+
+	return shared.WriteSuccess(c)
+}
+
+// mergeTags 合并标签。
+// @Summary 合并标签（管理端）
+// @Tags Admin.Tag
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body request.MergeTags true "源标签与目标标签"
+// @Success 200 {object} shared.Envelope
+// @Router /admin/tag/merge [post]
+func (a *Admin) mergeTags(c fiber.Ctx) error {
+	var req request.MergeTags
+	if err := c.Bind().JSON(&req); err != nil {
+		return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParam, "invalid request body")
+	}
+
+	if err := a.validate.Struct(req); err != nil {
+		return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParamFormat, err.Error())
+	}
+
+	affected, err := a.content.MergeTags(c.Context(), input.MergeTags{
+		SourceIDs: req.SourceIDs,
+		TargetID:  req.TargetID,
+	})
+
+	switch {
+	case errors.Is(err, content.ErrInvalidParam):
+		return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParam, "invalid merge tags")
+	case errors.Is(err, content.ErrNotFound):
+		return shared.WriteError(c, http.StatusNotFound, bizcode.ErrorNotFound, "tag not found")
+	case err != nil:
+		a.logger.Error(err, "http - admin - tag - mergeTags")
+		return shared.WriteError(c, http.StatusInternalServerError, bizcode.ErrorDatabase, "failed to merge tags")
+	}
+
+	return shared.WriteSuccess(c, shared.WithData(fiber.Map{"affected": affected}))
+}
+
+// bulkRetag 批量添加/移除标签。
+// @Summary 批量打标签（管理端）
+// @Tags Admin.Tag
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body request.BulkRetag true "标签、操作与文章筛选条件"
+// @Success 200 {object} shared.Envelope
+// @Router /admin/tag/bulk [post]
+func (a *Admin) bulkRetag(c fiber.Ctx) error {
+	var req request.BulkRetag
+	if err := c.Bind().JSON(&req); err != nil {
+		return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParam, "invalid request body")
+	}
+
+	if err := a.validate.Struct(req); err != nil {
+		return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParamFormat, err.Error())
+	}
+
+	affected, err := a.content.BulkRetag(c.Context(), input.BulkRetag{
+		TagID:              req.TagID,
+		Action:             req.Action,
+		ArticleIDs:         req.ArticleIDs,
+		CategoryID:         req.CategoryID,
+		IncludeDescendants: req.IncludeDescendants,
+		FilterTagID:        req.FilterTagID,
+		Status:             req.Status,
+		Keyword:            req.Keyword,
+	})
+
+	switch {
+	case errors.Is(err, content.ErrInvalidParam):
+		return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParam, "invalid retag filter")
+	case errors.Is(err, content.ErrNotFound):
+		return shared.WriteError(c, http.StatusNotFound, bizcode.ErrorNotFound, "tag not found")
+	case err != nil:
+		a.logger.Error(err, "http - admin - tag - bulkRetag")
+		return shared.WriteError(c, http.StatusInternalServerError, bizcode.ErrorDatabase, "failed to retag articles")
+	}
+
+	return shared.WriteSuccess(c, shared.WithData(fiber.Map{"affected": affected}))
+}
@@ -19,7 +19,7 @@ const (
 	SessionCookieMaxAge = 7 * 24 * time.Hour
 )
 
-// SessionManager Session 管理器，实现 RefreshTokenGetter 接口。
+// SessionManager Session 管理器，实现 RefreshTokenGetter 与 SessionAccessTokenStore 接口。
 type SessionManager struct {
 	redis  *redis.Redis
 	logger logger.Interface
@@ -80,6 +80,35 @@ func (s *SessionManager) SetRefreshToken(c fiber.Ctx, token string) {
 	}
 }
 
+// GetAccessToken 从 Session 获取最近一次自动刷新得到的 access_token。
+func (s *SessionManager) GetAccessToken(c fiber.Ctx) string {
+	sessionID := c.Cookies(SessionCookieName)
+	if sessionID == "" {
+		return ""
+	}
+
+	accessToken, err := s.redis.GetAccessToken(context.Background(), sessionID)
+	if err != nil {
+		return ""
+	}
+
+	return accessToken
+}
+
+// SetAccessToken 缓存 access_token 到 Session，后续仅携带 Cookie 的请求无需再次刷新。
+func (s *SessionManager) SetAccessToken(c fiber.Ctx, token string) {
+	sessionID := c.Cookies(SessionCookieName)
+	if sessionID == "" {
+		return
+	}
+
+	if err := s.redis.SetAccessToken(context.Background(), sessionID, token); err != nil {
+		if s.logger != nil {
+			s.logger.Error(err, "session - SetAccessToken - redis error")
+		}
+	}
+}
+
 // ClearRefreshToken 清除 Session 中的 refresh_token。
 func (s *SessionManager) ClearRefreshToken(c fiber.Ctx) {
 	sessionID := c.Cookies(SessionCookieName)
@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"crypto/rsa"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"server-blog-v2/pkg/logger"
+)
+
+const (
+	// ssoKeysCacheTTL 公钥缓存有效期，过期后在下次验证时重新拉取。
+	ssoKeysCacheTTL = 10 * time.Minute
+	// ssoKeysMinRefreshInterval 遇到未知 kid 时两次拉取的最小间隔，避免伪造 kid 打满 SSO。
+	ssoKeysMinRefreshInterval = 30 * time.Second
+)
+
+// SigningKeyFetcher 获取 SSO 签名公钥的接口。
+type SigningKeyFetcher interface {
+	GetSigningKeys() (map[string]*rsa.PublicKey, error)
+}
+
+// SSOKeySet 按 kid 缓存 SSO 签名公钥。
+// SSO 轮换密钥后，首次遇到新 kid 时从 JWKS 端点重新拉取；拉取失败时沿用本地公钥文件。
+type SSOKeySet struct {
+	fetcher  SigningKeyFetcher
+	fallback *rsa.PublicKey
+	l        logger.Interface
+
+	mu          sync.RWMutex
+	keys        map[string]*rsa.PublicKey
+	fetchedAt   time.Time
+	attemptedAt time.Time
+	refreshMu   sync.Mutex
+}
+
+// NewSSOKeySet 创建 SSO 公钥缓存，fallback 为本地配置的公钥（可为 nil）。
+func NewSSOKeySet(fetcher SigningKeyFetcher, fallback *rsa.PublicKey, l logger.Interface) *SSOKeySet {
+	ks := &SSOKeySet{
+		fetcher:  fetcher,
+		fallback: fallback,
+		l:        l,
+		keys:     make(map[string]*rsa.PublicKey),
+	}
+	ks.refresh(true)
+	return ks
+}
+
+// keyFunc 供 jwt.ParseWithClaims 使用，按头部 kid 选择公钥。
+func (ks *SSOKeySet) keyFunc(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+		return nil, errors.New("unexpected signing method")
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		// 轮换前签发的 token 不带 kid
+		if ks.fallback != nil {
+			return ks.fallback, nil
+		}
+		return nil, errors.New("missing kid")
+	}
+
+	// 带 kid 的 token 只接受 JWKS 中对应的公钥，不回退到本地公钥
+	if key := ks.lookup(kid); key != nil {
+		return key, nil
+	}
+	return nil, errors.New("unknown kid")
+}
+
+// lookup 查找 kid 对应的公钥，未命中或缓存过期时重新拉取。
+func (ks *SSOKeySet) lookup(kid string) *rsa.PublicKey {
+	ks.mu.RLock()
+	key, ok := ks.keys[kid]
+	fresh := time.Since(ks.fetchedAt) < ssoKeysCacheTTL
+	ks.mu.RUnlock()
+	if ok && fresh {
+		return key
+	}
+
+	ks.refresh(false)
+
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	return ks.keys[kid]
+}
+
+// refresh 从 SSO 拉取公钥；失败时保留已缓存的公钥。
+func (ks *SSOKeySet) refresh(force bool) {
+	if ks.fetcher == nil {
+		return
+	}
+
+	ks.refreshMu.Lock()
+	defer ks.refreshMu.Unlock()
+
+	// 并发请求只拉取一次
+	ks.mu.RLock()
+	recent := time.Since(ks.attemptedAt) < ssoKeysMinRefreshInterval
+	ks.mu.RUnlock()
+	if recent && !force {
+		return
+	}
+
+	keys, err := ks.fetcher.GetSigningKeys()
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	ks.attemptedAt = time.Now()
+	if err != nil {
+		if ks.l != nil {
+			ks.l.Error(err, "middleware - sso keys - fetch jwks failed")
+		}
+		return
+	}
+	ks.keys = keys
+	ks.fetchedAt = ks.attemptedAt
+}
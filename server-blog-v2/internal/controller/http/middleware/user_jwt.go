@@ -2,8 +2,8 @@ package middleware
 
 import (
 	"context"
-	"crypto/rsa"
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
@@ -46,7 +46,7 @@ func AccessClaimsFromContext(ctx context.Context) (*AccessClaims, bool) {
 
 // JWTConfig JWT 中间件配置。
 type JWTConfig struct {
-	PublicKey *rsa.PublicKey
+	Keys *SSOKeySet
 }
 
 // NewUserJWTMiddleware 创建基础 JWT 认证中间件（必须登录）。
@@ -55,9 +55,9 @@ type JWTConfig struct {
 // 该中间件暂保留仅用于兼容/回滚。
 //
 //nolint:unused // 保留用于兼容/回滚
-func NewUserJWTMiddleware(publicKey *rsa.PublicKey) fiber.Handler {
+func NewUserJWTMiddleware(keys *SSOKeySet) fiber.Handler {
 	return func(c fiber.Ctx) error {
-		claims, err := parseToken(c, publicKey)
+		claims, err := parseToken(c, keys)
 		if err != nil {
 			if errors.Is(err, jwt.ErrTokenExpired) {
 				return shared.WriteError(c, http.StatusUnauthorized, bizcode.ErrorTokenExpired, "token expired")
@@ -76,9 +76,9 @@ func NewUserJWTMiddleware(publicKey *rsa.PublicKey) fiber.Handler {
 // Deprecated: 请使用 `NewOptionalSSOUserJWTMiddleware`，以支持 refresh_token 自动刷新、查询角色和用户同步。
 //
 //nolint:unused // 保留用于兼容/回滚
-func NewOptionalUserJWTMiddleware(publicKey *rsa.PublicKey) fiber.Handler {
+func NewOptionalUserJWTMiddleware(keys *SSOKeySet) fiber.Handler {
 	return func(c fiber.Ctx) error {
-		claims, err := parseToken(c, publicKey)
+		claims, err := parseToken(c, keys)
 		if err == nil && claims != nil {
 			c.Locals("claims", claims)
 			c.SetContext(WithAccessClaims(c.Context(), claims))
@@ -93,9 +93,9 @@ func NewOptionalUserJWTMiddleware(publicKey *rsa.PublicKey) fiber.Handler {
 // 该中间件暂保留仅用于兼容/回滚。
 //
 //nolint:unused // 保留用于兼容/回滚
-func NewAdminJWTMiddleware(publicKey *rsa.PublicKey, userRoleGetter ...UserRoleGetter) fiber.Handler {
+func NewAdminJWTMiddleware(keys *SSOKeySet, userRoleGetter ...UserRoleGetter) fiber.Handler {
 	return func(c fiber.Ctx) error {
-		claims, err := parseToken(c, publicKey)
+		claims, err := parseToken(c, keys)
 		if err != nil {
 			return shared.WriteError(c, http.StatusUnauthorized, bizcode.ErrorTokenInvalid, "invalid token")
 		}
@@ -136,18 +136,25 @@ type RefreshTokenGetter interface {
 	ClearRefreshToken(c fiber.Ctx)
 }
 
+// SessionAccessTokenStore Session 中缓存的 access_token，供无法携带 Authorization 头的请求（<img>、<video>）校验登录。
+type SessionAccessTokenStore interface {
+	GetAccessToken(c fiber.Ctx) string
+	SetAccessToken(c fiber.Ctx, token string)
+}
+
 // SSOJWTConfig SSO JWT 中间件配置。
 type SSOJWTConfig struct {
-	PublicKey          *rsa.PublicKey
+	Keys               *SSOKeySet
 	SSOClient          *webapi.SSOClient
 	UserRoleGetter     UserRoleGetter
 	UserCreator        UserCreator
 	RefreshTokenGetter RefreshTokenGetter
+	SessionTokens      SessionAccessTokenStore // 非空时，未携带 Authorization 头的请求改用 Session 校验登录
 	Logger             logger.Interface
 }
 
 // parseToken 解析 JWT Token。
-func parseToken(c fiber.Ctx, publicKey *rsa.PublicKey) (*AccessClaims, error) {
+func parseToken(c fiber.Ctx, keys *SSOKeySet) (*AccessClaims, error) {
 	authorization := c.Get("Authorization")
 	if authorization == "" {
 		return nil, errors.New("missing authorization header")
@@ -159,12 +166,7 @@ func parseToken(c fiber.Ctx, publicKey *rsa.PublicKey) (*AccessClaims, error) {
 	}
 	tokenStr := parts[1]
 
-	token, err := jwt.ParseWithClaims(tokenStr, &AccessClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
-			return nil, errors.New("unexpected signing method")
-		}
-		return publicKey, nil
-	})
+	token, err := jwt.ParseWithClaims(tokenStr, &AccessClaims{}, keys.keyFunc)
 
 	if err != nil {
 		return nil, err
@@ -212,7 +214,7 @@ func IsAdmin(c fiber.Ctx) bool {
 // NewSSOJWTMiddleware 创建 SSO JWT 认证中间件（支持自动刷新 token 和用户同步）。
 func NewSSOUserJWTMiddleware(cfg SSOJWTConfig) fiber.Handler {
 	return func(c fiber.Ctx) error {
-		claims, err := parseToken(c, cfg.PublicKey)
+		claims, err := parseToken(c, cfg.Keys)
 		if err != nil {
 			if errors.Is(err, jwt.ErrTokenExpired) {
 				// Token 过期，尝试自动刷新
@@ -258,7 +260,7 @@ func NewSSOUserJWTMiddleware(cfg SSOJWTConfig) fiber.Handler {
 // 有 token 时走完整 SSO 流程（自动刷新、查角色、同步用户）；无 token 或解析失败时直接放行。
 func NewOptionalSSOUserJWTMiddleware(cfg SSOJWTConfig) fiber.Handler {
 	return func(c fiber.Ctx) error {
-		claims, err := parseToken(c, cfg.PublicKey)
+		claims, err := parseRequestToken(c, cfg)
 		if err != nil {
 			if errors.Is(err, jwt.ErrTokenExpired) {
 				// token 过期，尝试自动刷新；刷新失败则当作未登录放行
@@ -304,7 +306,7 @@ func NewOptionalSSOUserJWTMiddleware(cfg SSOJWTConfig) fiber.Handler {
 // NewSSOAdminJWTMiddleware 创建 SSO 管理员 JWT 认证中间件。
 func NewSSOAdminJWTMiddleware(cfg SSOJWTConfig) fiber.Handler {
 	return func(c fiber.Ctx) error {
-		claims, err := parseToken(c, cfg.PublicKey)
+		claims, err := parseToken(c, cfg.Keys)
 		if err != nil {
 			if errors.Is(err, jwt.ErrTokenExpired) {
 				newClaims, refreshErr := autoRefreshToken(c, cfg)
@@ -369,9 +371,12 @@ func autoRefreshToken(c fiber.Ctx, cfg SSOJWTConfig) (*AccessClaims, error) {
 	if tokenResp.RefreshToken != "" && tokenResp.RefreshToken != refreshToken {
 		cfg.RefreshTokenGetter.SetRefreshToken(c, tokenResp.RefreshToken)
 	}
+	if cfg.SessionTokens != nil {
+		cfg.SessionTokens.SetAccessToken(c, tokenResp.AccessToken)
+	}
 
 	// 解析新 token
-	newClaims, err := parseTokenString(tokenResp.AccessToken, cfg.PublicKey)
+	newClaims, err := parseTokenString(tokenResp.AccessToken, cfg.Keys)
 	if err != nil {
 		return nil, err
 	}
@@ -402,14 +407,30 @@ func createUserFromSSO(ctx context.Context, userUUID string, cfg SSOJWTConfig) e
 	)
 }
 
+// parseRequestToken 解析请求携带的 Token。配置了 SessionTokens 且请求没有 Authorization 头时，
+// 解析 Session 中缓存的 access_token；缓存缺失或失效时按过期处理，由自动刷新向 SSO 校验 refresh_token。
+func parseRequestToken(c fiber.Ctx, cfg SSOJWTConfig) (*AccessClaims, error) {
+	if cfg.SessionTokens == nil || c.Get("Authorization") != "" {
+		return parseToken(c, cfg.Keys)
+	}
+	if cfg.RefreshTokenGetter == nil || cfg.RefreshTokenGetter.GetRefreshToken(c) == "" {
+		return nil, errors.New("missing session")
+	}
+
+	tokenStr := cfg.SessionTokens.GetAccessToken(c)
+	if tokenStr == "" {
+		return nil, jwt.ErrTokenExpired
+	}
+	claims, err := parseTokenString(tokenStr, cfg.Keys)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", jwt.ErrTokenExpired, err)
+	}
+	return claims, nil
+}
+
 // parseTokenString 解析 JWT Token 字符串。
-func parseTokenString(tokenStr string, publicKey *rsa.PublicKey) (*AccessClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenStr, &AccessClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
-			return nil, errors.New("unexpected signing method")
-		}
-		return publicKey, nil
-	})
+func parseTokenString(tokenStr string, keys *SSOKeySet) (*AccessClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenStr, &AccessClaims{}, keys.keyFunc)
 
 	if err != nil {
 		return nil, err
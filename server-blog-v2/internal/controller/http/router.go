@@ -1,8 +1,6 @@
 package http
 
 import (
-	"crypto/rsa"
-
 	"github.com/gofiber/fiber/v3"
 	"github.com/gofiber/fiber/v3/middleware/cors"
 
@@ -10,6 +8,7 @@ import (
 	"server-blog-v2/internal/controller/http/admin"
 	"server-blog-v2/internal/controller/http/middleware"
 	"server-blog-v2/internal/controller/http/qiniu"
+	"server-blog-v2/internal/controller/http/static"
 	v1 "server-blog-v2/internal/controller/http/v1"
 	"server-blog-v2/internal/repo"
 	"server-blog-v2/internal/repo/webapi"
@@ -22,7 +21,7 @@ func NewRouter(
 	app *fiber.App,
 	cfg *config.Config,
 	l logger.Interface,
-	publicKey *rsa.PublicKey,
+	ssoKeys *middleware.SSOKeySet,
 	userRepo repo.UserRepo,
 	content usecase.Content,
 	comment usecase.Comment,
@@ -37,6 +36,8 @@ func NewRouter(
 	website usecase.Website,
 	emoji usecase.Emoji,
 	advertisement usecase.Advertisement,
+	mediaGC usecase.MediaGC,
+	mediaLibrary usecase.MediaLibrary,
 	sessionManager *middleware.SessionManager,
 	ssoClient *webapi.SSOClient,
 ) {
@@ -61,13 +62,18 @@ func NewRouter(
 
 	// V1 公开 API
 	v1Group := api.Group("/v1")
-	v1.NewRoutes(v1Group, cfg, l, publicKey, content, comment, aiChat, feedback, link, file, user, setting, website, emoji, advertisement, sessionManager, ssoClient, userRepo)
+	v1.NewRoutes(v1Group, cfg, l, ssoKeys, content, comment, aiChat, feedback, link, file, resource, user, setting, website, emoji, advertisement, sessionManager, ssoClient, userRepo)
 
 	// Admin API
 	adminGroup := api.Group("/admin")
-	admin.NewRoutes(adminGroup, cfg, l, publicKey, userRepo, sessionManager, ssoClient, content, comment, feedback, link, file, resource, user, setting, emoji, aiChat, aiModel, website, advertisement)
+	admin.NewRoutes(adminGroup, cfg, l, ssoKeys, userRepo, sessionManager, ssoClient, content, comment, feedback, link, file, resource, user, setting, emoji, aiChat, aiModel, website, advertisement, mediaGC, mediaLibrary)
 
 	// 第三方回调（无需认证）
 	callbackGroup := api.Group("/callback")
 	qiniu.NewRoutes(callbackGroup.Group("/qiniu"), l, resource)
+
+	// 本地存储文件访问（签名校验）
+	if cfg.System.OssType == config.OssTypeLocal {
+		static.NewRoutes(api.Group("/static"), cfg, l)
+	}
 }
@@ -16,6 +16,7 @@ var fieldNames = map[string]string{
 	"FeaturedImage": "封面图",
 	"CategoryID":    "分类",
 	"TagIDs":        "标签",
+	"CoAuthorUUIDs": "共同作者",
 	"Status":        "状态",
 	"Visibility":    "可见性",
 	"Name":          "名称",
@@ -32,6 +33,7 @@ var tagMessages = map[string]string{
 	"min":      "长度不足",
 	"email":    "格式不正确",
 	"url":      "格式不正确",
+	"uuid":     "格式不正确",
 	"oneof":    "值不在允许范围内",
 }
 
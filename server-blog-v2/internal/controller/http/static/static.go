@@ -0,0 +1,101 @@
+package static
+
+import (
+	"mime"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+
+	"server-blog-v2/config"
+	"server-blog-v2/internal/entity"
+	"server-blog-v2/pkg/logger"
+	"server-blog-v2/pkg/urlsign"
+)
+
+// Static 本地存储文件访问控制器。
+type Static struct {
+	logger     logger.Interface
+	root       string
+	signSecret string
+}
+
+// NewStatic 创建本地存储文件访问控制器。
+func NewStatic(cfg *config.Config, l logger.Interface) *Static {
+	root, err := filepath.Abs(cfg.Local.Root)
+	if err != nil {
+		root = cfg.Local.Root
+	}
+	return &Static{
+		logger:     l,
+		root:       root,
+		signSecret: cfg.Local.SignSecret,
+	}
+}
+
+// NewRoutes 注册本地存储文件访问路由（仅 oss_type 为 local 时启用）。
+func NewRoutes(router fiber.Router, cfg *config.Config, l logger.Interface) {
+	s := NewStatic(cfg, l)
+
+	router.Get("/*", s.serve)
+}
+
+// serve 校验签名后返回文件，私有对象（private/ 前缀）不接受永久签名。
+// GET /api/static/{key}?sign=xxx[&expires=unix]
+func (s *Static) serve(c fiber.Ctx) error {
+	key := c.Params("*")
+
+	// 拒绝越界路径
+	cleaned := path.Clean("/" + key)
+	if cleaned == "/" || cleaned != "/"+key {
+		return c.SendStatus(fiber.StatusNotFound)
+	}
+
+	var expires int64
+	if v := c.Query("expires"); v != "" {
+		var err error
+		if expires, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return c.SendStatus(fiber.StatusForbidden)
+		}
+	}
+	if !urlsign.Verify(s.signSecret, key, expires, c.Query("sign")) {
+		return c.SendStatus(fiber.StatusForbidden)
+	}
+	// 私有对象只接受带过期时间的签名
+	if expires == 0 && entity.IsPrivateKey(key) {
+		return c.SendStatus(fiber.StatusForbidden)
+	}
+
+	setSafetyHeaders(c, cleaned)
+
+	cfg := fiber.SendFile{ByteRange: true}
+	if expires == 0 {
+		// 永久链接对应的文件内容不会变化（资源 key 基于 hash），可长期缓存
+		cfg.MaxAge = 30 * 24 * 3600
+	}
+	return c.SendFile(filepath.Join(s.root, filepath.FromSlash(cleaned)), cfg)
+}
+
+// setSafetyHeaders 禁止浏览器嗅探类型；SVG 禁止执行脚本，图片、音视频以外的文件强制下载。
+func setSafetyHeaders(c fiber.Ctx, key string) {
+	c.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+
+	ext := strings.ToLower(path.Ext(key))
+	if ext == ".svg" {
+		c.Set(fiber.HeaderContentSecurityPolicy, "default-src 'none'; style-src 'unsafe-inline'; sandbox")
+		return
+	}
+	mimeType := mime.TypeByExtension(ext)
+	for _, family := range []string{"image/", "video/", "audio/"} {
+		if strings.HasPrefix(mimeType, family) {
+			return
+		}
+	}
+	// HLS 播放列表与分片需要由播放器直接读取
+	if ext == ".m3u8" || ext == ".ts" {
+		return
+	}
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="`+path.Base(key)+`"`)
+}
@@ -0,0 +1,87 @@
+package static
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+
+	"server-blog-v2/config"
+	"server-blog-v2/pkg/urlsign"
+)
+
+const testSignSecret = "0123456789abcdef0123456789abcdef"
+
+func newTestApp(t *testing.T) *fiber.App {
+	t.Helper()
+	root := t.TempDir()
+	for _, key := range []string{"image/a.png", "private/draft.png"} {
+		file := filepath.Join(root, filepath.FromSlash(key))
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte("png"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := &config.Config{}
+	cfg.Local.Root = root
+	cfg.Local.SignSecret = testSignSecret
+
+	app := fiber.New()
+	NewRoutes(app.Group("/api/static"), cfg, nil)
+	return app
+}
+
+func TestServeRejectsUnsignedAndForgedURLs(t *testing.T) {
+	app := newTestApp(t)
+	future := time.Now().Add(time.Hour).Unix()
+	past := time.Now().Add(-time.Hour).Unix()
+
+	signed := func(secret, key string, expires int64) string {
+		url := "/api/static/" + key + "?sign=" + urlsign.Sign(secret, key, expires)
+		if expires != 0 {
+			url += "&expires=" + strconv.FormatInt(expires, 10)
+		}
+		return url
+	}
+
+	tests := []struct {
+		name string
+		url  string
+		want int
+	}{
+		{name: "valid permanent", url: signed(testSignSecret, "image/a.png", 0), want: fiber.StatusOK},
+		{name: "valid expiring private", url: signed(testSignSecret, "private/draft.png", future), want: fiber.StatusOK},
+		{name: "unsigned", url: "/api/static/image/a.png", want: fiber.StatusForbidden},
+		{name: "empty secret forgery", url: signed("", "image/a.png", 0), want: fiber.StatusForbidden},
+		{name: "wrong secret", url: signed("another-secret-0123456789", "image/a.png", 0), want: fiber.StatusForbidden},
+		{name: "signature for another key", url: "/api/static/private/draft.png?sign=" + urlsign.Sign(testSignSecret, "image/a.png", 0), want: fiber.StatusForbidden},
+		{name: "permanent private", url: signed(testSignSecret, "private/draft.png", 0), want: fiber.StatusForbidden},
+		{name: "expired", url: signed(testSignSecret, "private/draft.png", past), want: fiber.StatusForbidden},
+		{name: "extended expiry", url: "/api/static/private/draft.png?sign=" + urlsign.Sign(testSignSecret, "private/draft.png", future) + "&expires=" + strconv.FormatInt(future+3600, 10), want: fiber.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, tt.url, nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				t.Fatalf("GET %s = %d, want %d", tt.url, resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyRejectsEmptySecret(t *testing.T) {
+	if urlsign.Verify("", "image/a.png", 0, urlsign.Sign("", "image/a.png", 0)) {
+		t.Fatal("signature with an empty secret must not verify")
+	}
+}
@@ -0,0 +1,89 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+
+	"server-blog-v2/internal/controller/http/middleware"
+	"server-blog-v2/internal/controller/http/shared"
+	"server-blog-v2/internal/controller/http/v1/response"
+	"server-blog-v2/internal/usecase/input"
+)
+
+// getAuthor 作者主页信息。
+// @Summary 作者主页
+// @Tags V1.Author
+// @Produce json
+// @Param uuid path string true "作者 UUID"
+// @Success 200 {object} shared.Envelope{data=response.AuthorProfile}
+// @Router /author/{uuid} [get]
+func (v *V1) getAuthor(c fiber.Ctx) error {
+	authorUUID := c.Params("uuid")
+	if authorUUID == "" {
+		return shared.WriteError(c, http.StatusBadRequest, response.ErrorParamMissing, "missing uuid")
+	}
+	parsed, err := uuid.Parse(authorUUID)
+	if err != nil {
+		return shared.WriteError(c, http.StatusBadRequest, response.ErrorParam, "invalid uuid")
+	}
+	authorUUID = parsed.String()
+
+	profile, err := v.content.GetAuthorProfile(c.Context(), authorUUID)
+	if err != nil {
+		v.logger.Error(err, "http - v1 - author - getAuthor")
+		return shared.WriteError(c, http.StatusNotFound, response.ErrorAuthorNotFound, "author not found")
+	}
+
+	return shared.WriteSuccess(c, shared.WithData(response.AuthorProfile{
+		AuthorInfo: response.AuthorInfo{
+			UUID:     profile.UUID,
+			Nickname: profile.Nickname,
+			Avatar:   profile.Avatar,
+		},
+		Bio:          profile.Bio,
+		ArticleCount: profile.ArticleCount,
+		JoinedAt:     profile.JoinedAt,
+	}))
+}
+
+// listAuthorArticles 作者的文章列表（含共同署名）。
+// @Summary 作者文章列表
+// @Tags V1.Author
+// @Produce json
+// @Param uuid path string true "作者 UUID"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "分页大小" default(10)
+// @Success 200 {object} shared.Envelope{data=response.ArticleSummaryPage}
+// @Router /author/{uuid}/articles [get]
+func (v *V1) listAuthorArticles(c fiber.Ctx) error {
+	authorUUID := c.Params("uuid")
+	if authorUUID == "" {
+		return shared.WriteError(c, http.StatusBadRequest, response.ErrorParamMissing, "missing uuid")
+	}
+	parsed, err := uuid.Parse(authorUUID)
+	if err != nil {
+		return shared.WriteError(c, http.StatusBadRequest, response.ErrorParam, "invalid uuid")
+	}
+	authorUUID = parsed.String()
+
+	pq := shared.ParsePageQuery(c)
+	userUUID := middleware.GetOptionalUserUUID(c)
+
+	result, err := v.content.ListPublicArticles(c.Context(), input.ListPublicArticles{
+		PageParams: input.PageParams{Page: pq.Page, PageSize: pq.PageSize},
+		AuthorUUID: &authorUUID,
+	}, userUUID)
+	if err != nil {
+		v.logger.Error(err, "http - v1 - author - listAuthorArticles")
+		return shared.WriteError(c, http.StatusInternalServerError, response.ErrorListAuthorPostsFailed, "failed to list author posts")
+	}
+
+	list := make([]response.ArticleSummary, 0, len(result.Items))
+	for _, p := range result.Items {
+		list = append(list, toArticleSummaryResponse(p))
+	}
+
+	return shared.WriteSuccess(c, shared.WithData(shared.NewPage(list, result.Page, result.PageSize, result.Total)))
+}
@@ -21,11 +21,12 @@ import (
 // @Param page_size query int false "分页大小" default(10)
 // @Param keyword query string false "关键字"
 // @Param filter.category_id query string false "分类 ID"
+// @Param filter.include_descendants query bool false "是否包含子分类"
 // @Param filter.tag_id query string false "标签 ID"
 // @Success 200 {object} shared.Envelope{data=response.ArticleSummaryPage}
 // @Router /v1/content/posts [get]
 func (v *V1) listArticles(c fiber.Ctx) error {
-	pq := shared.ParsePageQueryWithOptions(c, shared.WithAllowedFilters("category_id", "tag_id"))
+	pq := shared.ParsePageQueryWithOptions(c, shared.WithAllowedFilters("category_id", "include_descendants", "tag_id"))
 
 	pageParams := input.PageParams{
 		Page:     pq.Page,
@@ -49,6 +50,8 @@ func (v *V1) listArticles(c fiber.Ctx) error {
 		}
 	}
 
+	includeDescendants := pq.Filters["include_descendants"] == "true"
+
 	var tagID input.IntFilterParam
 	if tid, ok := pq.Filters["tag_id"]; ok && tid != "" {
 		if id, err := strconv.Atoi(tid); err == nil {
@@ -60,11 +63,12 @@ func (v *V1) listArticles(c fiber.Ctx) error {
 	userUUID := middleware.GetOptionalUserUUID(c)
 
 	result, err := v.content.ListPublicArticles(c.Context(), input.ListPublicArticles{
-		PageParams: pageParams,
-		Keyword:    keywordParams,
-		Sort:       sortParams,
-		CategoryID: categoryID,
-		TagID:      tagID,
+		PageParams:         pageParams,
+		Keyword:            keywordParams,
+		Sort:               sortParams,
+		CategoryID:         categoryID,
+		IncludeDescendants: includeDescendants,
+		TagID:              tagID,
 	}, userUUID)
 
 	if err != nil {
@@ -108,6 +112,53 @@ func (v *V1) getArticle(c fiber.Ctx) error {
 	return shared.WriteSuccess(c, shared.WithData(toArticleDetailResponse(post)))
 }
 
+// getArchive 文章归档。
+// @Summary 文章归档（按年月分组）
+// @Tags V1.Content
+// @Produce json
+// @Param category_id query int false "分类 ID"
+// @Param tag_id query int false "标签 ID"
+// @Success 200 {object} shared.Envelope{data=[]response.ArchiveYear}
+// @Router /article/archive [get]
+func (v *V1) getArchive(c fiber.Ctx) error {
+	var params input.GetArchive
+	if cid := c.Query("category_id"); cid != "" {
+		id, err := strconv.Atoi(cid)
+		if err != nil {
+			return shared.WriteError(c, http.StatusBadRequest, response.ErrorParamFormat, "invalid category_id")
+		}
+		params.CategoryID = &id
+	}
+	if tid := c.Query("tag_id"); tid != "" {
+		id, err := strconv.Atoi(tid)
+		if err != nil {
+			return shared.WriteError(c, http.StatusBadRequest, response.ErrorParamFormat, "invalid tag_id")
+		}
+		params.TagID = &id
+	}
+
+	years, err := v.content.GetArchive(c.Context(), params)
+	if err != nil {
+		v.logger.Error(err, "http - v1 - content - getArchive")
+		return shared.WriteError(c, http.StatusInternalServerError, response.ErrorGetArchiveFailed, "failed to get archive")
+	}
+
+	list := make([]response.ArchiveYear, len(years))
+	for i, y := range years {
+		months := make([]response.ArchiveMonth, len(y.Months))
+		for j, m := range y.Months {
+			articles := make([]response.ArchiveArticle, len(m.Articles))
+			for k, a := range m.Articles {
+				articles[k] = response.ArchiveArticle{ID: a.ID, Title: a.Title, Slug: a.Slug, PublishedAt: a.PublishedAt}
+			}
+			months[j] = response.ArchiveMonth{Month: m.Month, Count: m.Count, Articles: articles}
+		}
+		list[i] = response.ArchiveYear{Year: y.Year, Count: y.Count, Months: months}
+	}
+
+	return shared.WriteSuccess(c, shared.WithData(list))
+}
+
 // toggleArticleLike 点赞/取消点赞。
 // @Summary 点赞/取消点赞
 // @Tags V1.Content
@@ -162,7 +213,7 @@ func (v *V1) removeArticleLike(c fiber.Ctx) error {
 	return shared.WriteSuccess(c, shared.WithData(response.LikeInfo{Liked: nil, Likes: likes}))
 }
 
-// listCategories 分类列表。
+// listCategories 分类树。
 func (v *V1) listCategories(c fiber.Ctx) error {
 	result, err := v.content.GetAllPublicCategories(c.Context())
 	if err != nil {
@@ -170,19 +221,7 @@ func (v *V1) listCategories(c fiber.Ctx) error {
 		return shared.WriteError(c, http.StatusInternalServerError, response.ErrorListCategoriesFailed, "failed to list categories")
 	}
 
-	list := make([]response.CategoryDetail, 0, len(result.Items))
-	for _, cat := range result.Items {
-		list = append(list, response.CategoryDetail{
-			ID:        cat.ID,
-			Name:      cat.Name,
-			Slug:      cat.Slug,
-			ArticleCount: cat.ArticleCount,
-			CreatedAt: cat.CreatedAt,
-			UpdatedAt: cat.UpdatedAt,
-		})
-	}
-
-	return shared.WriteSuccess(c, shared.WithData(list))
+	return shared.WriteSuccess(c, shared.WithData(toCategoryTreeResponse(result.Items)))
 }
 
 // listTags 标签列表。
@@ -208,6 +247,38 @@ func (v *V1) listTags(c fiber.Ctx) error {
 	return shared.WriteSuccess(c, shared.WithData(list))
 }
 
+// getTag 按 slug 获取标签，旧 slug（别名）返回新标签并标记 redirected。
+// @Summary 标签详情
+// @Tags V1.Content
+// @Produce json
+// @Param slug path string true "标签 Slug"
+// @Success 200 {object} shared.Envelope{data=response.ResolvedTag}
+// @Router /article/tag/{slug} [get]
+func (v *V1) getTag(c fiber.Ctx) error {
+	slug := c.Params("slug")
+	if slug == "" {
+		return shared.WriteError(c, http.StatusBadRequest, response.ErrorParamMissing, "missing slug")
+	}
+
+	tag, err := v.content.ResolveTag(c.Context(), slug)
+	if err != nil {
+		v.logger.Error(err, "http - v1 - content - getTag")
+		return shared.WriteError(c, http.StatusNotFound, response.ErrorTagNotFound, "tag not found")
+	}
+
+	return shared.WriteSuccess(c, shared.WithData(response.ResolvedTag{
+		TagDetail: response.TagDetail{
+			ID:           tag.ID,
+			Name:         tag.Name,
+			Slug:         tag.Slug,
+			ArticleCount: tag.ArticleCount,
+			CreatedAt:    tag.CreatedAt,
+			UpdatedAt:    tag.UpdatedAt,
+		},
+		Redirected: tag.Redirected,
+	}))
+}
+
 // ==================== 辅助函数 ====================
 
 func toArticleSummaryResponse(p output.ArticleSummary) response.ArticleSummary {
@@ -232,7 +303,29 @@ func toArticleSummaryResponse(p output.ArticleSummary) response.ArticleSummary {
 		UpdatedAt:     p.UpdatedAt,
 		Category:      response.BaseCategory{ID: p.Category.ID, Name: p.Category.Name, Slug: p.Category.Slug},
 		Tags:          tags,
+		Highlight:     p.Highlight,
+	}
+}
+
+func toCategoryTreeResponse(nodes []output.CategoryTreeNode) []response.CategoryTreeNode {
+	list := make([]response.CategoryTreeNode, 0, len(nodes))
+	for _, n := range nodes {
+		list = append(list, response.CategoryTreeNode{
+			CategoryDetail: response.CategoryDetail{
+				ID:           n.ID,
+				Name:         n.Name,
+				Slug:         n.Slug,
+				ParentID:     n.ParentID,
+				SortOrder:    n.SortOrder,
+				ArticleCount: n.ArticleCount,
+				CreatedAt:    n.CreatedAt,
+				UpdatedAt:    n.UpdatedAt,
+			},
+			TotalArticleCount: n.TotalArticleCount,
+			Children:          toCategoryTreeResponse(n.Children),
+		})
 	}
+	return list
 }
 
 func toArticleDetailResponse(p *output.ArticleDetail) response.ArticleDetail {
@@ -240,6 +333,14 @@ func toArticleDetailResponse(p *output.ArticleDetail) response.ArticleDetail {
 	for i, t := range p.Tags {
 		tags[i] = response.BaseTag{ID: t.ID, Name: t.Name, Slug: t.Slug}
 	}
+	breadcrumb := make([]response.BaseCategory, len(p.Breadcrumb))
+	for i, b := range p.Breadcrumb {
+		breadcrumb[i] = response.BaseCategory{ID: b.ID, Name: b.Name, Slug: b.Slug}
+	}
+	byline := make([]response.AuthorInfo, len(p.Byline))
+	for i, a := range p.Byline {
+		byline[i] = response.AuthorInfo{UUID: a.UUID, Nickname: a.Nickname, Avatar: a.Avatar}
+	}
 	return response.ArticleDetail{
 		ID:              p.ID,
 		Title:           p.Title,
@@ -256,6 +357,8 @@ func toArticleDetailResponse(p *output.ArticleDetail) response.ArticleDetail {
 		CreatedAt:       p.CreatedAt,
 		UpdatedAt:       p.UpdatedAt,
 		Category:        response.BaseCategory{ID: p.Category.ID, Name: p.Category.Name, Slug: p.Category.Slug},
+		Breadcrumb:      breadcrumb,
+		Byline:          byline,
 		Tags:            tags,
 		Content:         p.Content,
 		MetaTitle:       p.MetaTitle,
@@ -20,6 +20,7 @@ type V1 struct {
 	feedback       usecase.Feedback
 	link           usecase.Link
 	file           usecase.File
+	resource       usecase.Resource
 	user           usecase.User
 	setting        usecase.Setting
 	website        usecase.Website
@@ -38,6 +39,7 @@ func New(
 	feedback usecase.Feedback,
 	link usecase.Link,
 	file usecase.File,
+	resource usecase.Resource,
 	user usecase.User,
 	setting usecase.Setting,
 	website usecase.Website,
@@ -55,6 +57,7 @@ func New(
 		feedback:       feedback,
 		link:           link,
 		file:           file,
+		resource:       resource,
 		user:           user,
 		setting:        setting,
 		website:        website,
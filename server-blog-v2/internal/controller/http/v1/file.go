@@ -1,6 +1,7 @@
 package v1
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gofiber/fiber/v3"
@@ -8,6 +9,8 @@ import (
 	"server-blog-v2/internal/controller/http/bizcode"
 	"server-blog-v2/internal/controller/http/middleware"
 	"server-blog-v2/internal/controller/http/shared"
+	"server-blog-v2/internal/pkg/imageproc"
+	fileuc "server-blog-v2/internal/usecase/file"
 	"server-blog-v2/internal/usecase/input"
 )
 
@@ -34,7 +37,14 @@ func (v *V1) uploadFile(c fiber.Ctx) error {
 		ContentType: file.Header.Get("Content-Type"),
 		Usage:       usage,
 		UserUUID:    userUUID,
+		Access:      c.FormValue("access"),
 	})
+	if errors.Is(err, imageproc.ErrImageTooLarge) {
+		return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParam, "image dimensions too large")
+	}
+	if errors.Is(err, fileuc.ErrInvalidAccess) {
+		return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParam, "invalid access")
+	}
 	if err != nil {
 		v.logger.Error(err, "http - v1 - file - uploadFile")
 		return shared.WriteError(c, http.StatusInternalServerError, bizcode.ErrorThirdParty, "failed to upload file")
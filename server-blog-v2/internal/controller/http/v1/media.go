@@ -0,0 +1,55 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+
+	"server-blog-v2/internal/controller/http/bizcode"
+	"server-blog-v2/internal/controller/http/middleware"
+	"server-blog-v2/internal/controller/http/shared"
+	"server-blog-v2/internal/usecase/resource"
+)
+
+// getPrivateMedia 私有对象的稳定地址：校验登录后跳转到新签发的签名地址，HLS 播放列表直接返回签名后的内容。
+// <img>、<video> 无法携带 Authorization 头，因此同时接受博客 Session Cookie，Session 由 SSO 校验（见 SessionAccessTokenStore）。
+// 私有表示"仅登录会员可见"：任何已登录用户都可以读取 private/ 下的对象（会员附件、草稿配图），匿名访问者和爬虫不能。
+// GET /api/v1/media/private/{key}
+func (v *V1) getPrivateMedia(c fiber.Ctx) error {
+	key := c.Params("*")
+	if cleaned := path.Clean("/" + key); cleaned != "/"+key {
+		return shared.WriteError(c, http.StatusNotFound, bizcode.ErrorNotFound, "media not found")
+	}
+
+	if middleware.GetOptionalUserUUID(c) == nil {
+		return shared.WriteError(c, http.StatusUnauthorized, bizcode.ErrorLoginRequired, "login required")
+	}
+
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+
+	if strings.HasSuffix(key, ".m3u8") {
+		playlist, err := v.resource.PrivatePlaylist(c.Context(), key)
+		if err != nil {
+			return v.writeMediaError(c, err)
+		}
+		c.Set(fiber.HeaderContentType, "application/vnd.apple.mpegurl")
+		return c.Send(playlist)
+	}
+
+	url, err := v.resource.PrivateURL(key)
+	if err != nil {
+		return v.writeMediaError(c, err)
+	}
+	return c.Redirect().Status(http.StatusFound).To(url)
+}
+
+func (v *V1) writeMediaError(c fiber.Ctx, err error) error {
+	if errors.Is(err, resource.ErrNotFound) {
+		return shared.WriteError(c, http.StatusNotFound, bizcode.ErrorNotFound, "media not found")
+	}
+	v.logger.Error(err, "http - v1 - media - getPrivateMedia")
+	return shared.WriteError(c, http.StatusBadGateway, bizcode.ErrorThirdParty, "failed to read media")
+}
@@ -11,12 +11,18 @@ const (
 	ErrorPostNotFound    = "0103"
 	ErrorLikePostFailed  = "0104"
 	ErrorUnlikePostFailed = "0105"
+	ErrorGetArchiveFailed = "0106"
 
 	// 分类
 	ErrorListCategoriesFailed = "0111"
 
 	// 标签
 	ErrorListTagsFailed = "0121"
+	ErrorTagNotFound    = "0122"
+
+	// 作者
+	ErrorAuthorNotFound        = "0131"
+	ErrorListAuthorPostsFailed = "0132"
 
 	// 通用
 	ErrorParamMissing    = "0001"
@@ -52,6 +58,7 @@ type ArticleSummary struct {
 	UpdatedAt     time.Time    `json:"updated_at"`
 	Category      BaseCategory `json:"category"`
 	Tags          []BaseTag    `json:"tags"`
+	Highlight     string       `json:"highlight,omitempty"` // 全文检索命中片段
 }
 
 // ArticleDetail 文章详情响应。
@@ -72,6 +79,8 @@ type ArticleDetail struct {
 	CreatedAt       time.Time    `json:"created_at"`
 	UpdatedAt       time.Time    `json:"updated_at"`
 	Category        BaseCategory `json:"category"`
+	Breadcrumb      []BaseCategory `json:"breadcrumb"`
+	Byline          []AuthorInfo   `json:"byline"`
 	Tags            []BaseTag    `json:"tags"`
 	Content         string       `json:"content"`
 	MetaTitle       string       `json:"meta_title"`
@@ -85,6 +94,14 @@ type AuthorInfo struct {
 	Avatar         string `json:"avatar"`
 }
 
+// AuthorProfile 作者主页响应。
+type AuthorProfile struct {
+	AuthorInfo
+	Bio          string    `json:"bio"`
+	ArticleCount int64     `json:"article_count"`
+	JoinedAt     time.Time `json:"joined_at"`
+}
+
 // LikeInfo 点赞信息。
 type LikeInfo struct {
 	Liked *bool `json:"liked,omitempty"`
@@ -110,11 +127,20 @@ type CategoryDetail struct {
 	ID        int64     `json:"id"`
 	Name      string    `json:"name"`
 	Slug      string    `json:"slug"`
+	ParentID  *int64    `json:"parent_id"`
+	SortOrder int32     `json:"sort_order"`
 	ArticleCount int32     `json:"article_count"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// CategoryTreeNode 分类树节点响应。
+type CategoryTreeNode struct {
+	CategoryDetail
+	TotalArticleCount int32              `json:"total_article_count"`
+	Children          []CategoryTreeNode `json:"children"`
+}
+
 // TagDetail 标签详情响应。
 type TagDetail struct {
 	ID        int64     `json:"id"`
@@ -125,6 +151,34 @@ type TagDetail struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// ResolvedTag 按 slug 解析的标签响应，redirected 为 true 时前端应跳转到新 slug。
+type ResolvedTag struct {
+	TagDetail
+	Redirected bool `json:"redirected"`
+}
+
+// ArchiveArticle 归档文章条目响应。
+type ArchiveArticle struct {
+	ID          int64     `json:"id"`
+	Title       string    `json:"title"`
+	Slug        string    `json:"slug"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+// ArchiveMonth 归档月份响应。
+type ArchiveMonth struct {
+	Month    int              `json:"month"`
+	Count    int64            `json:"count"`
+	Articles []ArchiveArticle `json:"articles"`
+}
+
+// ArchiveYear 归档年份响应。
+type ArchiveYear struct {
+	Year   int            `json:"year"`
+	Count  int64          `json:"count"`
+	Months []ArchiveMonth `json:"months"`
+}
+
 // ArticleSummaryPage 文章摘要分页响应。
 type ArticleSummaryPage struct {
 	List        []ArticleSummary `json:"list"`
@@ -1,8 +1,6 @@
 package v1
 
 import (
-	"crypto/rsa"
-
 	"github.com/gofiber/fiber/v3"
 
 	"server-blog-v2/config"
@@ -18,13 +16,14 @@ func NewRoutes(
 	router fiber.Router,
 	cfg *config.Config,
 	l logger.Interface,
-	publicKey *rsa.PublicKey,
+	ssoKeys *middleware.SSOKeySet,
 	content usecase.Content,
 	comment usecase.Comment,
 	aiChat usecase.AIChat,
 	feedback usecase.Feedback,
 	link usecase.Link,
 	file usecase.File,
+	resource usecase.Resource,
 	user usecase.User,
 	setting usecase.Setting,
 	website usecase.Website,
@@ -34,11 +33,11 @@ func NewRoutes(
 	ssoClient *webapi.SSOClient,
 	userRepo repo.UserRepo,
 ) {
-	v1 := New(cfg, l, content, comment, aiChat, feedback, link, file, user, setting, website, emoji, advertisement, sessionManager)
+	v1 := New(cfg, l, content, comment, aiChat, feedback, link, file, resource, user, setting, website, emoji, advertisement, sessionManager)
 
 	// SSO JWT 中间件配置（支持自动刷新 token）
 	ssoJWTConfig := middleware.SSOJWTConfig{
-		PublicKey:          publicKey,
+		Keys:               ssoKeys,
 		SSOClient:          ssoClient,
 		UserRoleGetter:     userRepo, // UserRepo 实现了 GetRoleByUUID
 		UserCreator:        userRepo, // UserRepo 实现了 CreateFromSSO
@@ -64,6 +63,8 @@ func NewRoutes(
 		articleGroup.Get("/search", v1.listArticles, jwtOptional)
 		articleGroup.Get("/category", v1.listCategories)
 		articleGroup.Get("/tags", v1.listTags)
+		articleGroup.Get("/tag/:slug", v1.getTag)
+		articleGroup.Get("/archive", v1.getArchive)
 		// 需要登录（放在 :slug 之前避免被匹配）
 		articleGroup.Get("/likes", v1.listUserLikedArticles, jwtRequired)
 		// 动态路由放最后
@@ -72,6 +73,13 @@ func NewRoutes(
 		articleGroup.Delete("/:slug/like", v1.removeArticleLike, jwtRequired)
 	}
 
+	// ==================== 作者 /author ====================
+	authorGroup := router.Group("/author")
+	{
+		authorGroup.Get("/:uuid", v1.getAuthor)
+		authorGroup.Get("/:uuid/articles", v1.listAuthorArticles, jwtOptional)
+	}
+
 	// ==================== 评论 /comment ====================
 	commentGroup := router.Group("/comment")
 	{
@@ -154,6 +162,12 @@ func NewRoutes(
 		imageGroup.Post("/upload", v1.uploadFile)
 	}
 
+	// ==================== 私有媒体 /media ====================
+	// 私有对象的稳定地址，登录后跳转到签名地址（Authorization 头或 Session Cookie）
+	mediaJWTConfig := ssoJWTConfig
+	mediaJWTConfig.SessionTokens = sessionManager
+	router.Get("/media/*", v1.getPrivateMedia, middleware.NewOptionalSSOUserJWTMiddleware(mediaJWTConfig))
+
 	// ==================== 表情 /emoji ====================
 	emojiGroup := router.Group("/emoji")
 	{
@@ -38,17 +38,23 @@ func (v *V1) updateProfile(c fiber.Ctx) error {
 	}
 
 	var req struct {
-		Nickname string `json:"nickname"`
-		Avatar   string `json:"avatar"`
+		Nickname string  `json:"nickname"`
+		Avatar   string  `json:"avatar"`
+		Bio      *string `json:"bio" validate:"omitempty,max=500"`
 	}
 
 	if err := c.Bind().JSON(&req); err != nil {
 		return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParam, "invalid request body")
 	}
 
+	if err := v.validate.Struct(req); err != nil {
+		return shared.WriteError(c, http.StatusBadRequest, bizcode.ErrorParamFormat, err.Error())
+	}
+
 	if err := v.user.UpdateProfile(c.Context(), userUUID, input.UpdateProfile{
 		Nickname: req.Nickname,
 		Avatar:   req.Avatar,
+		Bio:      req.Bio,
 	}); err != nil {
 		v.logger.Error(err, "http - v1 - user - updateProfile")
 		return shared.WriteError(c, http.StatusInternalServerError, bizcode.ErrorDatabase, "failed to update profile")
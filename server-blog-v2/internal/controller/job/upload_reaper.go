@@ -0,0 +1,108 @@
+// Package job 后台定时任务。
+package job
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"server-blog-v2/internal/usecase"
+	"server-blog-v2/pkg/logger"
+	"server-blog-v2/pkg/redis"
+)
+
+const (
+	_defaultReapInterval = 10 * time.Minute
+	_reapLockName        = "upload_reaper"
+)
+
+// UploadReaper 定期清理过期的分片上传任务。
+// 多实例部署时通过 Redis 分布式锁保证同一时刻只有一个实例执行。
+type UploadReaper struct {
+	enabled  bool
+	interval time.Duration
+	resource usecase.Resource
+	locker   redis.Locker
+	logger   logger.Interface
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewUploadReaper 创建过期上传任务清理器。
+func NewUploadReaper(enabled bool, interval time.Duration, resource usecase.Resource, locker redis.Locker, l logger.Interface) *UploadReaper {
+	if interval <= 0 {
+		interval = _defaultReapInterval
+	}
+	return &UploadReaper{
+		enabled:  enabled,
+		interval: interval,
+		resource: resource,
+		locker:   locker,
+		logger:   l,
+	}
+}
+
+// Start 启动定时清理，未启用时不做任何事。
+func (r *UploadReaper) Start() {
+	if !r.enabled {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			r.run(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop 停止定时清理并等待正在执行的清理结束。
+func (r *UploadReaper) Stop() {
+	if r.cancel == nil {
+		return
+	}
+	r.cancel()
+	r.wg.Wait()
+}
+
+func (r *UploadReaper) run(ctx context.Context) {
+	// 锁的有效期与间隔一致，实例崩溃后下一轮即可由其他实例接管
+	token, ok, err := r.locker.TryLock(ctx, _reapLockName, r.interval)
+	if err != nil {
+		r.logger.Error(err, "job - UploadReaper - TryLock")
+		return
+	}
+	if !ok {
+		return
+	}
+	defer func() {
+		if err := r.locker.Unlock(context.Background(), _reapLockName, token); err != nil {
+			r.logger.Error(err, "job - UploadReaper - Unlock")
+		}
+	}()
+
+	result, err := r.resource.ReapExpiredTasks(ctx)
+	if err != nil {
+		r.logger.Error(err, "job - UploadReaper - ReapExpiredTasks")
+		return
+	}
+	if result.Failed > 0 || result.Cancelled > 0 {
+		r.logger.Info("job - UploadReaper - expired tasks: %d failed, %d cancelled, %d blocks deleted",
+			result.Failed, result.Cancelled, result.DeletedBlocks)
+	}
+}
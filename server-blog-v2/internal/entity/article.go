@@ -15,6 +15,22 @@ const (
 	ArticleVisibilityPrivate = "private" // 私有，仅作者可见
 )
 
+// ArticleSearchHit 全文检索命中结果。
+type ArticleSearchHit struct {
+	Article  *Article
+	Rank     float64
+	Headline string // 命中片段，关键词以 <mark></mark> 包裹
+}
+
+// ArticleFilter 批量操作的文章筛选条件，字段为空表示不限制。
+type ArticleFilter struct {
+	IDs         []int64
+	CategoryIDs []int64
+	TagID       *int64
+	Status      *string
+	Keyword     *string
+}
+
 // Article 文章实体。
 type Article struct {
 	ID              int64
@@ -23,7 +39,8 @@ type Article struct {
 	Excerpt         *string
 	Content         string
 	FeaturedImage   *string
-	AuthorUUID      string // 作者 UUID
+	AuthorUUID      string   // 作者 UUID
+	CoAuthorUUIDs   []string // 共同作者 UUID（不含主作者）
 	CategoryID      int64
 	TagIDs          []int64 // 标签 ID 数组
 	Status          string  // 状态：draft, published, archived
@@ -46,3 +63,19 @@ type ArticleLike struct {
 	UserUUID    string
 	CreatedAt   time.Time
 }
+
+// ArchiveArticle 归档中的文章条目（轻量）。
+type ArchiveArticle struct {
+	ID          int64
+	Title       string
+	Slug        string
+	PublishedAt time.Time
+}
+
+// ArchiveBucket 按年月聚合的归档桶。
+type ArchiveBucket struct {
+	Year     int
+	Month    int
+	Count    int64
+	Articles []ArchiveArticle
+}
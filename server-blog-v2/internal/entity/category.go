@@ -7,6 +7,8 @@ type Category struct {
 	ID        int64
 	Name      string
 	Slug      string
+	ParentID  *int64 // 父分类 ID，nil 表示顶级分类
+	SortOrder int32  // 同级排序（升序）
 	ArticleCount int32
 	CreatedAt time.Time
 	UpdatedAt time.Time
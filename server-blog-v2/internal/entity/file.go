@@ -19,5 +19,18 @@ type File struct {
 	Usage      string
 	ResourceID *int64
 	UserUUID   string
+	Width      int // 图片宽高，非图片为 0
+	Height     int
+	Variants   []FileVariant // 图片缩放版本与 WebP 版本
+	Access     string        // public/private，取值同 ResourceAccess*
 	CreatedAt  time.Time
 }
+
+// FileVariant 图片处理生成的版本。
+type FileVariant struct {
+	Key      string
+	Width    int
+	Height   int
+	Size     int64
+	MimeType string
+}
@@ -0,0 +1,57 @@
+package entity
+
+import "time"
+
+// 媒体库条目来源。
+const (
+	MediaSourceFile     = "file"     // files 表（图片上传）
+	MediaSourceResource = "resource" // resources 表（分片上传）
+)
+
+// MediaFolder 媒体库虚拟文件夹。
+type MediaFolder struct {
+	ID        int64
+	Name      string
+	ParentID  *int64 // 为 nil 表示根目录
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// MediaItem 媒体库条目，合并 files/resources 与媒体库元数据。
+type MediaItem struct {
+	Source       string
+	SourceID     int64
+	Key          string
+	Name         string
+	Size         int64
+	MimeType     string
+	Access       string
+	ThumbnailKey string
+	FolderID     *int64
+	AltText      string
+	Caption      string
+	Tags         []string
+	CreatedAt    time.Time
+}
+
+// MediaRef 指向一个媒体库条目。
+type MediaRef struct {
+	Source   string
+	SourceID int64
+}
+
+// MediaUsage 引用媒体的文章。
+type MediaUsage struct {
+	ArticleID int64
+	Title     string
+	Slug      string
+	Status    string
+	InCover   bool // 作为封面
+	InContent bool // 出现在正文中
+}
+
+// MediaTagCount 媒体标签及使用次数。
+type MediaTagCount struct {
+	Tag   string
+	Count int64
+}
@@ -1,6 +1,9 @@
 package entity
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // TranscodeStatus 转码状态枚举。
 type TranscodeStatus int8
@@ -12,6 +15,32 @@ const (
 	TranscodeStatusFailed     TranscodeStatus = 3 // 转码失败
 )
 
+// 资源访问级别。
+const (
+	ResourceAccessPublic  = "public"  // 公开，返回永久地址
+	ResourceAccessPrivate = "private" // 私有（任何登录会员可见），返回带有效期的签名地址
+)
+
+// DefaultSignedURLTTL 私有对象签名地址默认有效期（资源与媒体库共用，可由 upload.signed_url_ttl 覆盖）。
+const DefaultSignedURLTTL = time.Hour
+
+// PrivateKeyPrefix 私有对象的 Key 前缀，存储后端据此路由到私有空间或拒绝永久地址。
+const PrivateKeyPrefix = "private/"
+
+// IsPrivateKey 判断对象 Key 是否属于私有对象。
+func IsPrivateKey(key string) bool {
+	return strings.HasPrefix(key, PrivateKeyPrefix)
+}
+
+// ObjectKeyForAccess 返回对象在指定访问级别下的 Key。
+func ObjectKeyForAccess(key, access string) string {
+	key = strings.TrimPrefix(key, PrivateKeyPrefix)
+	if access == ResourceAccessPrivate {
+		return PrivateKeyPrefix + key
+	}
+	return key
+}
+
 // Resource 资源实体。
 type Resource struct {
 	ID              int64
@@ -24,6 +53,7 @@ type Resource struct {
 	TranscodeStatus TranscodeStatus
 	TranscodeKey    string
 	ThumbnailKey    string
+	Access          string
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
 }
@@ -53,6 +83,7 @@ type ResourceUploadTask struct {
 	UserUUID      string
 	ExpiresAt     time.Time
 	QiniuContexts string
+	Access        string
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
 }
@@ -11,3 +11,11 @@ type Tag struct {
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
+
+// TagAlias 标签别名（旧 slug 指向现有标签）。
+type TagAlias struct {
+	ID        int64
+	Slug      string
+	TagID     int64
+	CreatedAt time.Time
+}
@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+// UploadPolicy 上传策略，作用于一个角色或一个用户。
+// 指针字段为 nil 表示未设置：用户策略继承角色策略，角色策略不限制。
+type UploadPolicy struct {
+	ID                  int64
+	RoleID              *int
+	UserUUID            *string
+	QuotaBytes          *int64   // 存储配额
+	MaxFileSize         *int64   // 单文件大小上限
+	DailyUploadBytes    *int64   // 每日上传量上限
+	AllowedMimeFamilies []string // 允许的 MIME 大类（image、video 等），nil 表示未设置
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
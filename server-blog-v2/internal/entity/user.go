@@ -13,6 +13,7 @@ type User struct {
 	UUID      string
 	Nickname  string
 	Avatar    string
+	Bio       string // 作者简介
 	Email     *string
 	RoleID    int
 	Status    string
@@ -22,6 +22,11 @@ func DetectMimeType(reader io.Reader) (mimeType string, err error) {
 		return "", errors.New("文件为空")
 	}
 	
+	// SVG 是 XML 文本，标准库只能识别为 text/xml 或 text/plain
+	if isSVG(buffer[:n]) {
+		return MimeTypeSVG, nil
+	}
+
 	// 使用标准库检测
 	detected := http.DetectContentType(buffer[:n])
 	
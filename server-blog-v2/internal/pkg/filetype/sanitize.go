@@ -0,0 +1,314 @@
+package filetype
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// MimeTypeSVG SVG 的 MIME 类型。
+const MimeTypeSVG = "image/svg+xml"
+
+var (
+	// ErrActiveContent 可在浏览器中执行脚本的文件类型（HTML、XHTML、JavaScript 等）。
+	ErrActiveContent = errors.New("不允许上传可执行脚本的文件类型")
+	// ErrPolyglot 文件头是二进制格式，但同时包含 HTML 片段。
+	ErrPolyglot = errors.New("文件内容疑似混合了 HTML（polyglot），已拒绝")
+	// ErrInvalidSVG SVG 无法解析或根元素不是 svg。
+	ErrInvalidSVG = errors.New("无效的 SVG 文件")
+)
+
+// activeContentTypes 浏览器会直接执行脚本的类型，一律拒绝上传。
+var activeContentTypes = map[string]bool{
+	"text/html":                     true,
+	"application/xhtml+xml":         true,
+	"text/javascript":               true,
+	"application/javascript":        true,
+	"application/x-javascript":      true,
+	"text/ecmascript":               true,
+	"application/ecmascript":        true,
+	"text/xml":                      true, // 可通过 xml-stylesheet 执行脚本
+	"application/xml":               true,
+	"text/xsl":                      true,
+	"application/xslt+xml":          true,
+	"application/x-shockwave-flash": true,
+}
+
+// polyglotMarkers 二进制文件头中出现即视为混入 HTML 的片段（小写匹配）。
+var polyglotMarkers = [][]byte{
+	[]byte("<script"),
+	[]byte("<html"),
+	[]byte("<head"),
+	[]byte("<body"),
+	[]byte("<iframe"),
+	[]byte("<object"),
+	[]byte("<embed"),
+	[]byte("<svg"),
+	[]byte("<!doctype html"),
+	[]byte("javascript:"),
+}
+
+// polyglotScanSize 检查 polyglot 时扫描的文件头长度。
+const polyglotScanSize = 1024
+
+// IsActiveContent 判断 MIME 类型是否会在浏览器中执行脚本（SVG 需要清理，不在此列）。
+func IsActiveContent(mimeType string) bool {
+	return activeContentTypes[normalizeMimeType(mimeType)]
+}
+
+// CheckContent 拒绝可执行脚本的类型，以及文件头中混入 HTML 的二进制文件。
+// head 为文件开头的内容，mimeType 为检测到的类型。
+func CheckContent(head []byte, mimeType string) error {
+	mimeType = normalizeMimeType(mimeType)
+	if IsActiveContent(mimeType) {
+		return ErrActiveContent
+	}
+	// 文本与 SVG 本身就是标记语言，由类型与清理保证安全
+	if strings.HasPrefix(mimeType, "text/") || mimeType == MimeTypeSVG {
+		return nil
+	}
+
+	if len(head) > polyglotScanSize {
+		head = head[:polyglotScanSize]
+	}
+	lower := bytes.ToLower(head)
+	for _, marker := range polyglotMarkers {
+		if bytes.Contains(lower, marker) {
+			return ErrPolyglot
+		}
+	}
+	return nil
+}
+
+// isSVG 判断内容是否以 svg 根元素开头（跳过 BOM、XML 声明、注释与 DOCTYPE）。
+func isSVG(head []byte) bool {
+	data := bytes.TrimPrefix(head, []byte("\xef\xbb\xbf"))
+	for {
+		data = bytes.TrimLeft(data, " \t\r\n")
+		switch {
+		case bytes.HasPrefix(data, []byte("<?")):
+			end := bytes.Index(data, []byte("?>"))
+			if end < 0 {
+				return false
+			}
+			data = data[end+2:]
+		case bytes.HasPrefix(data, []byte("<!--")):
+			end := bytes.Index(data, []byte("-->"))
+			if end < 0 {
+				return false
+			}
+			data = data[end+3:]
+		case bytes.HasPrefix(data, []byte("<!")):
+			end := bytes.IndexByte(data, '>')
+			if end < 0 {
+				return false
+			}
+			data = data[end+1:]
+		default:
+			lower := bytes.ToLower(data)
+			for _, prefix := range [][]byte{[]byte("<svg"), []byte("<svg:svg")} {
+				if bytes.HasPrefix(lower, prefix) && len(lower) > len(prefix) {
+					switch lower[len(prefix)] {
+					case ' ', '\t', '\r', '\n', '>', '/':
+						return true
+					}
+				}
+			}
+			return false
+		}
+	}
+}
+
+// svgDroppedElements 连同子节点一起删除的元素（小写本地名）。
+var svgDroppedElements = map[string]bool{
+	"script":        true,
+	"foreignobject": true,
+	"iframe":        true,
+	"object":        true,
+	"embed":         true,
+	"audio":         true,
+	"video":         true,
+	"handler":       true,
+	"listener":      true,
+}
+
+var (
+	// cssImportPattern 匹配样式中的 @import 规则
+	cssImportPattern = regexp.MustCompile(`(?i)@import[^;]*;?`)
+	// cssURLPattern 匹配样式中的 url(...) 引用
+	cssURLPattern = regexp.MustCompile(`(?i)url\(\s*['"]?([^'")]*)['"]?\s*\)`)
+	// safeDataImage 允许内嵌的位图 data URI
+	safeDataImage = regexp.MustCompile(`(?i)^data:image/(png|jpe?g|gif|webp);base64,`)
+)
+
+// SanitizeSVG 清理 SVG：删除脚本、foreignObject 等元素，事件属性，外部引用（href、url()、@import）
+// 以及 DOCTYPE、处理指令和注释。返回清理后的内容，以及是否删除了不安全的内容
+// （注释与不带内部子集的 DOCTYPE 同样被丢弃，但不计入）。
+func SanitizeSVG(data []byte) ([]byte, bool, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = true
+
+	var out bytes.Buffer
+	changed := false
+	depth := 0     // 当前元素深度
+	skipDepth := 0 // >0 时处于被删除元素内部
+	inStyle := false
+	sawRoot := false
+
+	for {
+		tok, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("%w: %v", ErrInvalidSVG, err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+			if skipDepth > 0 {
+				continue
+			}
+			local := strings.ToLower(t.Name.Local)
+			if !sawRoot {
+				if local != "svg" {
+					return nil, false, ErrInvalidSVG
+				}
+				sawRoot = true
+			}
+			if svgDroppedElements[local] || targetsHref(t.Attr) {
+				skipDepth = depth
+				changed = true
+				continue
+			}
+
+			out.WriteByte('<')
+			out.WriteString(rawName(t.Name))
+			for _, attr := range t.Attr {
+				if !safeAttr(attr) {
+					changed = true
+					continue
+				}
+				out.WriteByte(' ')
+				out.WriteString(rawName(attr.Name))
+				out.WriteString(`="`)
+				out.WriteString(attrEscaper.Replace(attr.Value))
+				out.WriteByte('"')
+			}
+			out.WriteByte('>')
+			inStyle = local == "style"
+
+		case xml.EndElement:
+			if skipDepth > 0 {
+				if depth == skipDepth {
+					skipDepth = 0
+				}
+				depth--
+				continue
+			}
+			depth--
+			inStyle = false
+			out.WriteString("</")
+			out.WriteString(rawName(t.Name))
+			out.WriteByte('>')
+
+		case xml.CharData:
+			if skipDepth > 0 || depth == 0 {
+				continue
+			}
+			text := string(t)
+			if inStyle {
+				cleaned := sanitizeCSS(text)
+				if cleaned != text {
+					changed = true
+				}
+				text = cleaned
+			}
+			out.WriteString(textEscaper.Replace(text))
+
+		case xml.Comment:
+			// 注释直接丢弃，不视为不安全内容
+
+		case xml.Directive:
+			// 带内部子集的 DOCTYPE 可声明实体
+			if bytes.IndexByte(t, '[') >= 0 {
+				changed = true
+			}
+
+		case xml.ProcInst:
+			if t.Target != "xml" {
+				changed = true
+			}
+		}
+	}
+
+	if !sawRoot {
+		return nil, false, ErrInvalidSVG
+	}
+	return out.Bytes(), changed, nil
+}
+
+// safeAttr 判断属性是否可以保留。
+func safeAttr(attr xml.Attr) bool {
+	local := strings.ToLower(attr.Name.Local)
+	value := strings.TrimSpace(attr.Value)
+
+	// 事件处理属性：onload、onclick 等
+	if strings.HasPrefix(local, "on") {
+		return false
+	}
+	// xml:base 会改变相对地址的解析基准
+	if strings.ToLower(attr.Name.Space) == "xml" && local == "base" {
+		return false
+	}
+	// href / xlink:href 只允许文档内引用与内嵌位图
+	if local == "href" {
+		return strings.HasPrefix(value, "#") || safeDataImage.MatchString(value)
+	}
+	// 属性与内联样式中的外部 url() 与 @import
+	if sanitizeCSS(value) != value {
+		return false
+	}
+	return true
+}
+
+// targetsHref 判断 animate/set 等元素是否在修改 href（可借此注入 javascript: 地址）。
+func targetsHref(attrs []xml.Attr) bool {
+	for _, attr := range attrs {
+		if strings.EqualFold(attr.Name.Local, "attributeName") &&
+			strings.HasSuffix(strings.ToLower(strings.TrimSpace(attr.Value)), "href") {
+			return true
+		}
+	}
+	return false
+}
+
+// sanitizeCSS 删除 @import 规则，以及指向文档外部的 url() 引用。
+func sanitizeCSS(css string) string {
+	css = cssImportPattern.ReplaceAllString(css, "")
+	return cssURLPattern.ReplaceAllStringFunc(css, func(m string) string {
+		target := strings.TrimSpace(cssURLPattern.FindStringSubmatch(m)[1])
+		if strings.HasPrefix(target, "#") || safeDataImage.MatchString(target) {
+			return m
+		}
+		return "none"
+	})
+}
+
+var (
+	textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	attrEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+)
+
+// rawName 还原带前缀的名称（RawToken 中 Space 为前缀）。
+func rawName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+	return name.Space + ":" + name.Local
+}
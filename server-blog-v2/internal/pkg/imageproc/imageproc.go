@@ -0,0 +1,265 @@
+package imageproc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+
+	xdraw "golang.org/x/image/draw"
+)
+
+const (
+	defaultQuality     = 85
+	defaultMaxPixels   = 40_000_000 // 4000 万像素，解码后约 160MB
+	defaultConcurrency = 2
+)
+
+// ErrImageTooLarge 图片像素数超过上限（在解码前按图片头部声明的尺寸判断）。
+var ErrImageTooLarge = errors.New("image dimensions too large")
+
+// Options 图片处理配置。
+type Options struct {
+	Widths    []int  // 生成的宽度档位，只生成小于原图宽度的档位
+	Quality   int    // JPEG/WebP 质量（1-100）
+	WebP      bool   // 是否生成 WebP 版本
+	CWebPPath string // cwebp 可执行文件路径，为空时从 PATH 查找
+
+	MaxPixels   int64 // 允许处理的最大像素数（宽×高），默认 4000 万
+	Concurrency int   // 同时处理的图片数，默认 2
+}
+
+// Rendition 处理后的一个版本。
+type Rendition struct {
+	Suffix   string // 追加在文件 key 上的后缀，原图为空
+	Ext      string // 扩展名（含点）
+	MimeType string
+	Width    int
+	Height   int
+	Data     []byte
+}
+
+// Result 处理结果。
+type Result struct {
+	Original Rendition   // 已纠正方向、去除元数据的原图
+	Variants []Rendition // 缩放版本与 WebP 版本
+}
+
+// Processor 图片处理器。
+type Processor struct {
+	widths    []int
+	quality   int
+	cwebp     string // 为空表示不生成 WebP
+	maxPixels int64
+	sem       chan struct{} // 限制并发，解码与 cwebp 编码都很占内存和 CPU
+}
+
+// New 创建图片处理器。开启 WebP 但找不到 cwebp 时仅跳过 WebP 版本。
+func New(opts Options) *Processor {
+	p := &Processor{
+		widths:    opts.Widths,
+		quality:   opts.Quality,
+		maxPixels: opts.MaxPixels,
+	}
+	if p.quality <= 0 || p.quality > 100 {
+		p.quality = defaultQuality
+	}
+	if p.maxPixels <= 0 {
+		p.maxPixels = defaultMaxPixels
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+	p.sem = make(chan struct{}, concurrency)
+	if opts.WebP {
+		bin := opts.CWebPPath
+		if bin == "" {
+			bin = "cwebp"
+		}
+		if path, err := exec.LookPath(bin); err == nil {
+			p.cwebp = path
+		}
+	}
+	return p
+}
+
+// Supported 是否支持处理该类型。GIF（可能是动图）、SVG、WebP 原样保存。
+func Supported(mimeType string) bool {
+	return mimeType == "image/jpeg" || mimeType == "image/png"
+}
+
+// WebPEnabled 是否会生成 WebP 版本。
+func (p *Processor) WebPEnabled() bool {
+	return p.cwebp != ""
+}
+
+// Process 解码图片，按 EXIF 纠正方向并重新编码（丢弃 EXIF/GPS 等元数据），
+// 然后生成各宽度档位及 WebP 版本。像素数超过上限时返回 ErrImageTooLarge；
+// 并发数已满时排队等待；ctx 取消时放弃排队，并终止正在运行的 cwebp。
+func (p *Processor) Process(ctx context.Context, data []byte, mimeType string) (*Result, error) {
+	if !Supported(mimeType) {
+		return nil, fmt.Errorf("unsupported image type: %s", mimeType)
+	}
+
+	// 先只读头部尺寸，避免恶意声明超大尺寸的小文件在解码时耗尽内存
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decode image config error: %w", err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || int64(cfg.Width)*int64(cfg.Height) > p.maxPixels {
+		return nil, fmt.Errorf("%w: %dx%d", ErrImageTooLarge, cfg.Width, cfg.Height)
+	}
+
+	select {
+	case p.sem <- struct{}{}:
+		defer func() { <-p.sem }()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decode image error: %w", err)
+	}
+	if mimeType == "image/jpeg" {
+		img = applyOrientation(img, jpegOrientation(data))
+	}
+
+	ext := ".jpg"
+	if mimeType == "image/png" {
+		ext = ".png"
+	}
+
+	original, err := p.encode(img, "", ext, mimeType)
+	if err != nil {
+		return nil, err
+	}
+	result := &Result{Original: *original}
+
+	if p.WebPEnabled() {
+		webp, err := p.encodeWebP(ctx, img, "")
+		if err != nil {
+			return nil, err
+		}
+		result.Variants = append(result.Variants, *webp)
+	}
+
+	width := img.Bounds().Dx()
+	for _, w := range p.widths {
+		if w <= 0 || w >= width {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		scaled := resize(img, w)
+		suffix := "_w" + strconv.Itoa(w)
+
+		variant, err := p.encode(scaled, suffix, ext, mimeType)
+		if err != nil {
+			return nil, err
+		}
+		result.Variants = append(result.Variants, *variant)
+
+		if p.WebPEnabled() {
+			webp, err := p.encodeWebP(ctx, scaled, suffix)
+			if err != nil {
+				return nil, err
+			}
+			result.Variants = append(result.Variants, *webp)
+		}
+	}
+
+	return result, nil
+}
+
+func (p *Processor) encode(img image.Image, suffix, ext, mimeType string) (*Rendition, error) {
+	var buf bytes.Buffer
+	var err error
+	if mimeType == "image/png" {
+		err = png.Encode(&buf, img)
+	} else {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: p.quality})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("encode image error: %w", err)
+	}
+
+	b := img.Bounds()
+	return &Rendition{
+		Suffix:   suffix,
+		Ext:      ext,
+		MimeType: mimeType,
+		Width:    b.Dx(),
+		Height:   b.Dy(),
+		Data:     buf.Bytes(),
+	}, nil
+}
+
+// encodeWebP 调用 cwebp 编码（标准库没有 WebP 编码器），以无损 PNG 作为中间格式。
+func (p *Processor) encodeWebP(ctx context.Context, img image.Image, suffix string) (*Rendition, error) {
+	dir, err := os.MkdirTemp("", "imageproc-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	in := filepath.Join(dir, "in.png")
+	out := filepath.Join(dir, "out.webp")
+
+	f, err := os.Create(in)
+	if err != nil {
+		return nil, err
+	}
+	encoder := png.Encoder{CompressionLevel: png.NoCompression}
+	if err := encoder.Encode(f, img); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("encode image error: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+
+	cmd := exec.CommandContext(ctx, p.cwebp, "-quiet", "-metadata", "none", "-q", strconv.Itoa(p.quality), in, "-o", out)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, fmt.Errorf("cwebp error: %w: %s", err, string(output))
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		return nil, err
+	}
+
+	b := img.Bounds()
+	return &Rendition{
+		Suffix:   suffix,
+		Ext:      ".webp",
+		MimeType: "image/webp",
+		Width:    b.Dx(),
+		Height:   b.Dy(),
+		Data:     data,
+	}, nil
+}
+
+// resize 按宽度等比缩放。
+func resize(img image.Image, width int) image.Image {
+	b := img.Bounds()
+	height := b.Dy() * width / b.Dx()
+	if height < 1 {
+		height = 1
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	xdraw.CatmullRom.Scale(dst, dst.Bounds(), img, b, xdraw.Src, nil)
+	return dst
+}
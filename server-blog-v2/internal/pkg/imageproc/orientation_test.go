@@ -0,0 +1,138 @@
+package imageproc
+
+import (
+	"encoding/binary"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// buildTIFF 构造只含 IFD0 的 TIFF 结构，entries 为 (tag, value) 对，值按 SHORT 类型存放。
+func buildTIFF(order binary.ByteOrder, entries [][2]uint16) []byte {
+	tiff := make([]byte, 8+2+len(entries)*12+4)
+	if order == binary.LittleEndian {
+		copy(tiff, "II")
+	} else {
+		copy(tiff, "MM")
+	}
+	order.PutUint16(tiff[2:], 42)
+	order.PutUint32(tiff[4:], 8)
+	order.PutUint16(tiff[8:], uint16(len(entries)))
+	for i, e := range entries {
+		entry := tiff[10+i*12:]
+		order.PutUint16(entry, e[0])
+		order.PutUint16(entry[2:], 3) // SHORT
+		order.PutUint32(entry[4:], 1)
+		order.PutUint16(entry[8:], e[1])
+	}
+	return tiff
+}
+
+// buildJPEG 构造带 APP1 EXIF 段的最小 JPEG 头部（SOI + APP1 + SOS）。
+func buildJPEG(tiff []byte) []byte {
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	data := []byte{0xFF, 0xD8, 0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(data[4:], uint16(len(payload)+2))
+	data = append(data, payload...)
+	return append(data, 0xFF, 0xDA, 0, 2)
+}
+
+func TestJPEGOrientationTags(t *testing.T) {
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		for want := 1; want <= 8; want++ {
+			tiff := buildTIFF(order, [][2]uint16{{0x010F, 7}, {0x0112, uint16(want)}})
+			if got := jpegOrientation(buildJPEG(tiff)); got != want {
+				t.Errorf("%v orientation %d: got %d", order, want, got)
+			}
+		}
+	}
+}
+
+func TestJPEGOrientationMalformed(t *testing.T) {
+	valid := buildTIFF(binary.BigEndian, [][2]uint16{{0x0112, 6}})
+
+	withOffset := func(offset uint32) []byte {
+		tiff := append([]byte(nil), valid...)
+		binary.BigEndian.PutUint32(tiff[4:], offset)
+		return buildJPEG(tiff)
+	}
+	withCount := func(count uint16) []byte {
+		tiff := buildTIFF(binary.BigEndian, [][2]uint16{{0x010F, 7}})
+		binary.BigEndian.PutUint16(tiff[8:], count)
+		return buildJPEG(tiff)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "empty", data: nil},
+		{name: "not a jpeg", data: []byte("\x89PNG\r\n\x1a\n")},
+		{name: "no exif", data: []byte{0xFF, 0xD8, 0xFF, 0xDA, 0, 2}},
+		{name: "segment size past end", data: []byte{0xFF, 0xD8, 0xFF, 0xE1, 0xFF, 0xFF, 'E', 'x'}},
+		{name: "segment size too small", data: []byte{0xFF, 0xD8, 0xFF, 0xE1, 0, 1}},
+		{name: "garbage between segments", data: []byte{0xFF, 0xD8, 0x00, 0xE1, 0, 2}},
+		{name: "truncated tiff header", data: buildJPEG([]byte("MM\x00"))},
+		{name: "unknown byte order", data: buildJPEG(append([]byte("XX"), valid[2:]...))},
+		{name: "ifd offset past end", data: withOffset(uint32(len(valid)))},
+		{name: "ifd offset overflows", data: withOffset(0xFFFFFFFF)},
+		{name: "ifd offset near int32 max", data: withOffset(0x7FFFFFFF)},
+		{name: "entry count past end", data: withCount(0xFFFF)},
+		{name: "orientation out of range", data: buildJPEG(buildTIFF(binary.BigEndian, [][2]uint16{{0x0112, 9}}))},
+		{name: "orientation zero", data: buildJPEG(buildTIFF(binary.BigEndian, [][2]uint16{{0x0112, 0}}))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := jpegOrientation(tt.data); got != 1 {
+				t.Fatalf("jpegOrientation = %d, want 1", got)
+			}
+		})
+	}
+}
+
+func TestApplyOrientation(t *testing.T) {
+	// 源图 3×2：
+	//   A B C
+	//   D E F
+	const (
+		A = iota + 1
+		B
+		C
+		D
+		E
+		F
+	)
+	src := image.NewNRGBA(image.Rect(0, 0, 3, 2))
+	for i, v := range []uint8{A, B, C, D, E, F} {
+		src.SetNRGBA(i%3, i/3, color.NRGBA{R: v, A: 0xFF})
+	}
+
+	tests := []struct {
+		orientation int
+		want        [][]uint8
+	}{
+		{1, [][]uint8{{A, B, C}, {D, E, F}}},
+		{2, [][]uint8{{C, B, A}, {F, E, D}}},
+		{3, [][]uint8{{F, E, D}, {C, B, A}}},
+		{4, [][]uint8{{D, E, F}, {A, B, C}}},
+		{5, [][]uint8{{A, D}, {B, E}, {C, F}}},
+		{6, [][]uint8{{D, A}, {E, B}, {F, C}}},
+		{7, [][]uint8{{F, C}, {E, B}, {D, A}}},
+		{8, [][]uint8{{C, F}, {B, E}, {A, D}}},
+	}
+	for _, tt := range tests {
+		got := applyOrientation(src, tt.orientation)
+		b := got.Bounds()
+		if b.Dx() != len(tt.want[0]) || b.Dy() != len(tt.want) {
+			t.Fatalf("orientation %d: size %dx%d, want %dx%d", tt.orientation, b.Dx(), b.Dy(), len(tt.want[0]), len(tt.want))
+		}
+		for y, row := range tt.want {
+			for x, want := range row {
+				r, _, _, _ := got.At(b.Min.X+x, b.Min.Y+y).RGBA()
+				if uint8(r>>8) != want {
+					t.Errorf("orientation %d: pixel (%d,%d) = %d, want %d", tt.orientation, x, y, r>>8, want)
+				}
+			}
+		}
+	}
+}
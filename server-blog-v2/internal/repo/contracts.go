@@ -2,23 +2,35 @@ package repo
 
 import (
 	"context"
+	"errors"
 	"io"
 	"time"
 
 	"server-blog-v2/internal/entity"
 )
 
+// ErrNotFound 记录不存在，用例层据此区分 404 与其他数据库错误。
+var ErrNotFound = errors.New("record not found")
+
 // ==================== 文章相关 ====================
 
 // ArticleRepo 文章数据仓库 (PostgreSQL)。
 type ArticleRepo interface {
-	List(ctx context.Context, offset, limit int, keyword *string, sortBy, order *string, categoryID, tagID *int, status, visibility *string) ([]*entity.Article, int64, error)
+	List(ctx context.Context, offset, limit int, keyword *string, sortBy, order *string, categoryIDs []int64, tagID *int, status, visibility, authorUUID *string) ([]*entity.Article, int64, error)
 	GetByID(ctx context.Context, id int64) (*entity.Article, error)
 	GetBySlug(ctx context.Context, slug string) (*entity.Article, error)
 	Create(ctx context.Context, article *entity.Article) (int64, error)
 	Update(ctx context.Context, article *entity.Article) error
 	UpdateBySlug(ctx context.Context, slug string, article *entity.Article, includeContent bool) error // 用 slug 更新文章
 	Delete(ctx context.Context, id int64) error
+	// CountPublishedByCategory 统计各分类下已发布的公开文章数
+	CountPublishedByCategory(ctx context.Context) (map[int64]int32, error)
+	// ListArchive 按发布时间年月聚合已发布的公开文章
+	ListArchive(ctx context.Context, categoryID, tagID *int) ([]*entity.ArchiveBucket, error)
+	// Search 基于 tsvector 的全文检索，按相关度排序
+	Search(ctx context.Context, offset, limit int, keyword string, categoryIDs []int64, tagID *int, status, visibility, authorUUID *string) ([]*entity.ArticleSearchHit, int64, error)
+	// BulkUpdateTag 为筛选出的文章批量添加或移除标签，返回受影响的文章数
+	BulkUpdateTag(ctx context.Context, tagID int64, remove bool, filter entity.ArticleFilter) (int64, error)
 }
 
 // ArticleSearchRepo 文章搜索仓库 (Elasticsearch)。
@@ -62,6 +74,7 @@ type CategoryRepo interface {
 	GetByID(ctx context.Context, id int64) (*entity.Category, error)
 	Create(ctx context.Context, category entity.Category) (int64, error)
 	Update(ctx context.Context, category entity.Category) error
+	Move(ctx context.Context, id int64, parentID *int64, sortOrder int32) error // 调整父分类与排序
 	Delete(ctx context.Context, id int64) error
 }
 
@@ -72,8 +85,14 @@ type TagRepo interface {
 	ListByIDs(ctx context.Context, ids []int64) ([]*entity.Tag, error) // 根据 ID 列表获取标签
 	GetByID(ctx context.Context, id int64) (*entity.Tag, error)
 	Create(ctx context.Context, tag entity.Tag) (int64, error)
-	Update(ctx context.Context, tag entity.Tag) error
+	Update(ctx context.Context, tag entity.Tag) error // slug 变更时旧 slug 记为别名
 	Delete(ctx context.Context, id int64) error
+	GetBySlug(ctx context.Context, slug string) (*entity.Tag, error)
+	GetByAlias(ctx context.Context, slug string) (*entity.Tag, error) // 按旧 slug 查找当前标签
+	ListAliases(ctx context.Context, tagID int64) ([]*entity.TagAlias, error)
+	DeleteAlias(ctx context.Context, id int64) error
+	// Merge 在单个事务中将源标签合并到目标标签，返回受影响的文章数
+	Merge(ctx context.Context, sourceIDs []int64, targetID int64) (int64, error)
 }
 
 // ==================== 评论相关 ====================
@@ -157,6 +176,10 @@ type FileRepo interface {
 	List(ctx context.Context, offset, limit int, filename, mimeType *string) ([]*entity.File, int64, error)
 	Delete(ctx context.Context, key string) error
 	DeleteByIDs(ctx context.Context, ids []int64) error
+	// UpdateAccess 更新文件访问级别及移动后的对象 Key（含图片版本）
+	UpdateAccess(ctx context.Context, file *entity.File) error
+	// ListCreatedBefore 列出指定时间之前上传的文件（孤儿扫描用）
+	ListCreatedBefore(ctx context.Context, before time.Time) ([]*entity.File, error)
 }
 
 // ResourceRepo 资源数据仓库。
@@ -171,6 +194,78 @@ type ResourceRepo interface {
 	DeleteByIDs(ctx context.Context, ids []int64) error
 	UpdateTranscodeStatus(ctx context.Context, id int64, status entity.TranscodeStatus, transcodeKey, thumbnailKey string) error
 	UpdateTranscodeStatusByFileKey(ctx context.Context, fileKey string, status entity.TranscodeStatus, transcodeKey, thumbnailKey string) error
+	// ListCreatedBefore 列出指定时间之前上传的资源（孤儿扫描用）
+	ListCreatedBefore(ctx context.Context, before time.Time) ([]*entity.Resource, error)
+	// ListByTranscodeStatus 按转码状态列出资源（转码 worker 重启后恢复任务用）
+	ListByTranscodeStatus(ctx context.Context, status entity.TranscodeStatus) ([]*entity.Resource, error)
+	// SumFileSize 统计用户资源总大小，since 不为空时只统计该时间之后上传的
+	SumFileSize(ctx context.Context, userUUID string, since *time.Time) (int64, error)
+	// UpdateAccess 修改资源访问级别及随之移动后的对象 Key
+	UpdateAccess(ctx context.Context, resource *entity.Resource) error
+	// CountByFileKey 统计引用该对象的资源数（秒传的资源共用对象）
+	CountByFileKey(ctx context.Context, fileKey string) (int64, error)
+}
+
+// UploadPolicyRepo 上传策略仓库。
+type UploadPolicyRepo interface {
+	List(ctx context.Context) ([]*entity.UploadPolicy, error)
+	// ListFor 返回作用于该角色与该用户的策略（0 到 2 条）
+	ListFor(ctx context.Context, roleID int, userUUID string) ([]*entity.UploadPolicy, error)
+	// Upsert 按 role_id 或 user_uuid 创建或覆盖策略
+	Upsert(ctx context.Context, policy *entity.UploadPolicy) (int64, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+// UploadUsageRepo 上传用量仓库：存储配额预留与每日上传量计数。
+type UploadUsageRepo interface {
+	// CreateTask 在存储配额内创建上传任务，已用空间按资源与未完成任务合计（quotaBytes <= 0 不限），超出时不创建并返回 false
+	CreateTask(ctx context.Context, task *entity.ResourceUploadTask, quotaBytes int64) (bool, error)
+	// CreateResource 在存储配额内创建资源记录（秒传），超出时不创建并返回 false
+	CreateResource(ctx context.Context, resource *entity.Resource, quotaBytes int64) (int64, bool, error)
+	// SumPending 统计未完成上传任务预留的空间
+	SumPending(ctx context.Context, userUUID string, now time.Time) (int64, error)
+	// ReserveDaily 累加用户当天上传量，累加后超过 limit（<= 0 不限）时不修改并返回 false
+	ReserveDaily(ctx context.Context, userUUID string, day time.Time, size, limit int64) (bool, error)
+	// ReleaseDaily 退回已计入当天的上传量（任务取消、失败或过期）
+	ReleaseDaily(ctx context.Context, userUUID string, day time.Time, size int64) error
+	GetDaily(ctx context.Context, userUUID string, day time.Time) (int64, error)
+}
+
+// MediaReferenceRepo 媒体引用来源仓库。
+type MediaReferenceRepo interface {
+	// ScanTexts 逐条返回可能引用媒体的文本：文章正文与封面、用户头像、站点配置（含轮播图）、广告图、友链 Logo
+	ScanTexts(ctx context.Context, fn func(text string)) error
+}
+
+// MediaListFilter 媒体库列表过滤条件，零值表示不过滤。
+type MediaListFilter struct {
+	Source     string // file | resource
+	FolderID   *int64 // 0 表示根目录（未放入任何文件夹）
+	Tag        string
+	Keyword    string // 文件名全文检索
+	MimeFamily string // 如 image、video
+}
+
+// MediaLibraryRepo 媒体库仓库，条目来自 files 与 resources，元数据保存在 media_items。
+type MediaLibraryRepo interface {
+	List(ctx context.Context, offset, limit int, filter MediaListFilter) ([]*entity.MediaItem, int64, error)
+	// UpdateMeta 保存替代文本、说明与标签，不存在元数据时创建
+	UpdateMeta(ctx context.Context, ref entity.MediaRef, altText, caption string, tags []string) error
+	// Move 将条目移动到文件夹，folderID 为 nil 表示移回根目录
+	Move(ctx context.Context, refs []entity.MediaRef, folderID *int64) error
+	ListTags(ctx context.Context) ([]*entity.MediaTagCount, error)
+	// ListUsage 列出正文或封面中包含任一 Key 的文章
+	ListUsage(ctx context.Context, keys []string) ([]*entity.MediaUsage, error)
+}
+
+// MediaFolderRepo 媒体库文件夹仓库。
+type MediaFolderRepo interface {
+	List(ctx context.Context) ([]*entity.MediaFolder, error)
+	GetByID(ctx context.Context, id int64) (*entity.MediaFolder, error)
+	Create(ctx context.Context, folder *entity.MediaFolder) (int64, error)
+	Update(ctx context.Context, folder *entity.MediaFolder) error
+	// Delete 删除文件夹，其子文件夹与条目移到上级文件夹
+	Delete(ctx context.Context, id int64) error
 }
 
 // ResourceUploadTaskRepo 资源上传任务数据仓库。
@@ -181,23 +276,50 @@ type ResourceUploadTaskRepo interface {
 	UpdateStatus(ctx context.Context, taskID string, status entity.TaskStatus) error
 	UpdateChunkContext(ctx context.Context, taskID string, chunkNumber int, context string, status entity.TaskStatus) error
 	UpdateMimeType(ctx context.Context, taskID string, mimeType string) error
+	// ListExpired 查询已过期但仍处于初始化/上传中的任务
+	ListExpired(ctx context.Context, now time.Time, limit int) ([]*entity.ResourceUploadTask, error)
+	// Expire 将过期任务标记为 status 并清空块 Context，任务已不在初始化/上传中时返回 false
+	Expire(ctx context.Context, taskID string, status entity.TaskStatus, now time.Time) (bool, error)
 }
 
 // ==================== 对象存储 ====================
 
-// ObjectStore 对象存储仓库 (七牛云)。
+// ObjectStore 对象存储仓库（七牛云、本地磁盘、S3 兼容存储）。
 type ObjectStore interface {
 	Upload(ctx context.Context, key string, data io.Reader, size int64, contentType string) (string, error)
 	Delete(ctx context.Context, key string) error
 	GetURL(key string) string
+	// SignedURL 返回带有效期的签名访问地址，用于私有资源
+	SignedURL(key string, ttl time.Duration) string
+	// Get 读取对象内容，返回内容与大小，调用方负责关闭
+	Get(ctx context.Context, key string) (io.ReadCloser, int64, error)
+	// Copy 复制对象，目标已存在时覆盖（修改访问级别时在公开与私有位置间移动对象）
+	Copy(ctx context.Context, srcKey, dstKey string) error
 	// 分片上传相关
 	UploadBlock(ctx context.Context, data io.Reader, size int64) (string, error)
 	MergeBlocks(ctx context.Context, fileSize int64, fileKey string, contexts []string) (string, error)
+	// DeleteBlocks 删除未合并的块（七牛云的块由服务端自动过期，无需删除）
+	DeleteBlocks(ctx context.Context, contexts []string) error
 	// VerifyHash 验证文件 hash（clientHash 是前端传的 qetag）
 	VerifyHash(ctx context.Context, clientHash, fileKey string) (bool, error)
 	GenerateFileKey(fileName, fileHash string) string
 }
 
+// ==================== 转码 ====================
+
+// Transcoder 视频转码（七牛云 pfop、本地 ffmpeg）。
+type Transcoder interface {
+	// Submit 提交转码任务，结果异步写回资源的 TranscodeStatus/TranscodeKey/ThumbnailKey
+	Submit(ctx context.Context, resource *entity.Resource) error
+	// DeleteOutputs 删除转码产物（转码文件、HLS 分片、封面）
+	DeleteOutputs(ctx context.Context, resource *entity.Resource) error
+	// CopyOutputs 将转码产物复制到 access 对应的位置，返回新的转码 Key 与封面 Key
+	CopyOutputs(ctx context.Context, resource *entity.Resource, access string) (string, string, error)
+	// Start/Stop 启动与停止后台 worker，七牛云为空操作
+	Start()
+	Stop()
+}
+
 // ==================== AI ====================
 
 // LLMWebAPI LLM API 调用。
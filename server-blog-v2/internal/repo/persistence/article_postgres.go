@@ -2,26 +2,33 @@ package persistence
 
 import (
 	"context"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"time"
 
 	"server-blog-v2/internal/entity"
 	"server-blog-v2/internal/repo"
 	"server-blog-v2/internal/repo/persistence/gen/model"
 	"server-blog-v2/internal/repo/persistence/gen/query"
 
+	"gorm.io/gen"
 	"gorm.io/gen/field"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type articleRepo struct {
+	db    *gorm.DB
 	query *query.Query
 }
 
 // NewArticleRepo 创建文章仓库。
 func NewArticleRepo(db *gorm.DB) repo.ArticleRepo {
-	return &articleRepo{query: query.Use(db)}
+	return &articleRepo{db: db, query: query.Use(db)}
 }
 
-func (r *articleRepo) List(ctx context.Context, offset, limit int, keyword *string, sortBy, order *string, categoryID, tagID *int, status, visibility *string) ([]*entity.Article, int64, error) {
+func (r *articleRepo) List(ctx context.Context, offset, limit int, keyword *string, sortBy, order *string, categoryIDs []int64, tagID *int, status, visibility, authorUUID *string) ([]*entity.Article, int64, error) {
 	a := r.query.Article
 	do := a.WithContext(ctx)
 
@@ -29,8 +36,8 @@ func (r *articleRepo) List(ctx context.Context, offset, limit int, keyword *stri
 		kw := "%" + *keyword + "%"
 		do = do.Where(a.Title.Like(kw))
 	}
-	if categoryID != nil {
-		do = do.Where(a.CategoryID.Eq(int64(*categoryID)))
+	if len(categoryIDs) > 0 {
+		do = do.Where(a.CategoryID.In(categoryIDs...))
 	}
 	if status != nil && *status != "" {
 		do = do.Where(a.Status.Eq(*status))
@@ -38,6 +45,13 @@ func (r *articleRepo) List(ctx context.Context, offset, limit int, keyword *stri
 	if visibility != nil && *visibility != "" {
 		do = do.Where(a.Visibility.Eq(*visibility))
 	}
+	// 主作者或共同作者
+	if authorUUID != nil && *authorUUID != "" {
+		do = do.Where(gen.Cond(clause.Or(
+			clause.Eq{Column: a.AuthorUUID.ColumnName().String(), Value: *authorUUID},
+			clause.Expr{SQL: "? = ANY(co_author_uuids)", Vars: []any{*authorUUID}},
+		))...)
+	}
 
 	total, err := do.Count()
 	if err != nil {
@@ -92,6 +106,9 @@ func (r *articleRepo) UpdateBySlug(ctx context.Context, slug string, article *en
 	if includeContent {
 		fields = append(fields, a.Content)
 	}
+	if article.CoAuthorUUIDs != nil {
+		fields = append(fields, a.CoAuthorUuids)
+	}
 	if article.Excerpt != nil {
 		fields = append(fields, a.Excerpt)
 	}
@@ -121,6 +138,96 @@ func (r *articleRepo) GetBySlug(ctx context.Context, slug string) (*entity.Artic
 	return toEntityArticle(ma), nil
 }
 
+func (r *articleRepo) CountPublishedByCategory(ctx context.Context) (map[int64]int32, error) {
+	var rows []struct {
+		CategoryID int64 `gorm:"column:category_id"`
+		Count      int32 `gorm:"column:count"`
+	}
+
+	err := r.db.WithContext(ctx).
+		Table("articles").
+		Select("category_id, COUNT(*) AS count").
+		Where("deleted_at IS NULL").
+		Where("status = ?", entity.ArticleStatusPublished).
+		Where("visibility = ?", entity.ArticleVisibilityPublic).
+		Group("category_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[int64]int32, len(rows))
+	for _, row := range rows {
+		counts[row.CategoryID] = row.Count
+	}
+	return counts, nil
+}
+
+// ListArchive 单条聚合查询：按 published_at 的年月分组，统计数量并聚合轻量文章条目。
+func (r *articleRepo) ListArchive(ctx context.Context, categoryID, tagID *int) ([]*entity.ArchiveBucket, error) {
+	var rows []struct {
+		Year     int    `gorm:"column:year"`
+		Month    int    `gorm:"column:month"`
+		Count    int64  `gorm:"column:count"`
+		Articles []byte `gorm:"column:articles"`
+	}
+
+	db := r.db.WithContext(ctx).
+		Table("articles").
+		Select(`EXTRACT(YEAR FROM published_at)::int AS year,
+			EXTRACT(MONTH FROM published_at)::int AS month,
+			COUNT(*) AS count,
+			json_agg(json_build_object('id', id, 'title', title, 'slug', slug, 'published_at', published_at) ORDER BY published_at DESC) AS articles`).
+		Where("deleted_at IS NULL").
+		Where("published_at IS NOT NULL").
+		Where("status = ?", entity.ArticleStatusPublished).
+		Where("visibility = ?", entity.ArticleVisibilityPublic)
+
+	if categoryID != nil {
+		db = db.Where("category_id = ?", *categoryID)
+	}
+	if tagID != nil {
+		db = db.Where("? = ANY(tag_ids)", *tagID)
+	}
+
+	err := db.Group("year, month").
+		Order("year DESC, month DESC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	buckets := make([]*entity.ArchiveBucket, len(rows))
+	for i, row := range rows {
+		var items []struct {
+			ID          int64     `json:"id"`
+			Title       string    `json:"title"`
+			Slug        string    `json:"slug"`
+			PublishedAt time.Time `json:"published_at"`
+		}
+		if err := json.Unmarshal(row.Articles, &items); err != nil {
+			return nil, err
+		}
+
+		articles := make([]entity.ArchiveArticle, len(items))
+		for j, item := range items {
+			articles[j] = entity.ArchiveArticle{
+				ID:          item.ID,
+				Title:       item.Title,
+				Slug:        item.Slug,
+				PublishedAt: item.PublishedAt,
+			}
+		}
+		buckets[i] = &entity.ArchiveBucket{
+			Year:     row.Year,
+			Month:    row.Month,
+			Count:    row.Count,
+			Articles: articles,
+		}
+	}
+	return buckets, nil
+}
+
 func toModelArticle(a *entity.Article) *model.Article {
 	ma := &model.Article{
 		ID:              a.ID,
@@ -130,6 +237,7 @@ func toModelArticle(a *entity.Article) *model.Article {
 		AuthorUUID:      &a.AuthorUUID,
 		CategoryID:      a.CategoryID,
 		TagIDs:          a.TagIDs, // 标签 ID 数组
+		CoAuthorUUIDs:   a.CoAuthorUUIDs,
 		Status:          &a.Status,
 		Visibility:      a.Visibility, // 非指针类型
 		Views:           &a.Views,
@@ -196,5 +304,127 @@ func toEntityArticle(ma *model.Article) *entity.Article {
 	if ma.TagIDs != nil {
 		a.TagIDs = ma.TagIDs
 	}
+	if ma.CoAuthorUUIDs != nil {
+		a.CoAuthorUUIDs = ma.CoAuthorUUIDs
+	}
 	return a
 }
+
+// BulkUpdateTag 为筛选出的文章添加或移除标签，并重算该标签的文章数。
+func (r *articleRepo) BulkUpdateTag(ctx context.Context, tagID int64, remove bool, filter entity.ArticleFilter) (int64, error) {
+	var affected int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		db := tx.Table("articles").Where("deleted_at IS NULL")
+		if len(filter.IDs) > 0 {
+			db = db.Where("id IN ?", filter.IDs)
+		}
+		if len(filter.CategoryIDs) > 0 {
+			db = db.Where("category_id IN ?", filter.CategoryIDs)
+		}
+		if filter.TagID != nil {
+			db = db.Where("? = ANY(tag_ids)", *filter.TagID)
+		}
+		if filter.Status != nil {
+			db = db.Where("status = ?", *filter.Status)
+		}
+		if filter.Keyword != nil && *filter.Keyword != "" {
+			db = db.Where("title ILIKE ?", "%"+*filter.Keyword+"%")
+		}
+
+		var expr clause.Expr
+		if remove {
+			db = db.Where("? = ANY(tag_ids)", tagID)
+			expr = gorm.Expr("array_remove(tag_ids, ?::bigint)", tagID)
+		} else {
+			db = db.Where("NOT (? = ANY(COALESCE(tag_ids, '{}')))", tagID)
+			expr = gorm.Expr("array_append(COALESCE(tag_ids, '{}'), ?::bigint)", tagID)
+		}
+
+		result := db.Updates(map[string]any{"tag_ids": expr, "updated_at": gorm.Expr("CURRENT_TIMESTAMP")})
+		if result.Error != nil {
+			return result.Error
+		}
+		affected = result.RowsAffected
+
+		return tx.Exec(`UPDATE article_tags SET article_count = (
+				SELECT COUNT(*) FROM articles WHERE deleted_at IS NULL AND ? = ANY(tag_ids)
+			) WHERE id = ?`, tagID, tagID).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return affected, nil
+}
+
+// Search 全文检索：search_vector 与 bigram 查询匹配，ts_rank_cd 排序，ts_headline 生成命中片段。
+func (r *articleRepo) Search(ctx context.Context, offset, limit int, keyword string, categoryIDs []int64, tagID *int, status, visibility, authorUUID *string) ([]*entity.ArticleSearchHit, int64, error) {
+	const tsQuery = "plainto_tsquery('simple', blog_bigram(?))"
+
+	db := r.db.WithContext(ctx).Model(&model.Article{}).
+		Where("search_vector @@ "+tsQuery, keyword)
+	if len(categoryIDs) > 0 {
+		db = db.Where("category_id IN ?", categoryIDs)
+	}
+	if tagID != nil {
+		db = db.Where("? = ANY(tag_ids)", *tagID)
+	}
+	if status != nil && *status != "" {
+		db = db.Where("status = ?", *status)
+	}
+	if visibility != nil && *visibility != "" {
+		db = db.Where("visibility = ?", *visibility)
+	}
+	if authorUUID != nil && *authorUUID != "" {
+		db = db.Where("(author_uuid = ? OR ? = ANY(co_author_uuids))", *authorUUID, *authorUUID)
+	}
+	// 条件共享给 Count 与分页查询
+	db = db.Session(&gorm.Session{})
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var rows []struct {
+		model.Article
+		Rank     float64 `gorm:"column:rank"`
+		Headline string  `gorm:"column:headline"`
+	}
+	err := db.Select(`articles.*,
+			ts_rank_cd(search_vector, `+tsQuery+`) AS rank,
+			ts_headline('simple', blog_cjk_split(COALESCE(excerpt, '') || ' ' || content),
+				phraseto_tsquery('simple', blog_cjk_split(?)),
+				'MaxFragments=2, MinWords=5, MaxWords=24, StartSel=<mark>, StopSel=</mark>') AS headline`,
+		keyword, keyword).
+		Order("rank DESC, published_at DESC NULLS LAST").
+		Offset(offset).Limit(limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	hits := make([]*entity.ArticleSearchHit, len(rows))
+	for i := range rows {
+		hits[i] = &entity.ArticleSearchHit{
+			Article:  toEntityArticle(&rows[i].Article),
+			Rank:     rows[i].Rank,
+			Headline: joinCJKSpaces(rows[i].Headline),
+		}
+	}
+	return hits, total, nil
+}
+
+// cjkGap 匹配 blog_cjk_split 在中文字符（及高亮标签）之间插入的空白。
+var cjkGap = regexp.MustCompile(`(\p{Han}|</?mark>)\s+(\p{Han}|</?mark>)`)
+
+// joinCJKSpaces 去掉 blog_cjk_split 插入的空格，并合并相邻的高亮标签。
+func joinCJKSpaces(s string) string {
+	for {
+		next := cjkGap.ReplaceAllString(s, "$1$2")
+		if next == s {
+			break
+		}
+		s = next
+	}
+	return strings.ReplaceAll(s, "</mark><mark>", "")
+}
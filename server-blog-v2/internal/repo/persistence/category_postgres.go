@@ -49,7 +49,7 @@ func (r *categoryRepo) List(ctx context.Context, offset, limit int, keyword *str
 
 func (r *categoryRepo) ListAll(ctx context.Context) ([]*entity.Category, error) {
 	c := r.query.ArticleCategory
-	rows, err := c.WithContext(ctx).Order(c.ID.Asc()).Find()
+	rows, err := c.WithContext(ctx).Order(c.SortOrder.Asc(), c.ID.Asc()).Find()
 	if err != nil {
 		return nil, err
 	}
@@ -65,7 +65,7 @@ func (r *categoryRepo) GetByID(ctx context.Context, id int64) (*entity.Category,
 	c := r.query.ArticleCategory
 	row, err := c.WithContext(ctx).Where(c.ID.Eq(id)).First()
 	if err != nil {
-		return nil, err
+		return nil, wrapNotFound(err)
 	}
 	return toEntityCategory(row), nil
 }
@@ -78,36 +78,76 @@ func (r *categoryRepo) Create(ctx context.Context, category entity.Category) (in
 	return mc.ID, nil
 }
 
+// Update 只更新名称和 slug，层级与排序通过 Move 调整。
 func (r *categoryRepo) Update(ctx context.Context, category entity.Category) error {
 	c := r.query.ArticleCategory
 	mc := toModelCategory(&category)
-	_, err := c.WithContext(ctx).Where(c.ID.Eq(category.ID)).Updates(mc)
-	return err
+	info, err := c.WithContext(ctx).Where(c.ID.Eq(category.ID)).Select(c.Name, c.Slug).Updates(mc)
+	if err != nil {
+		return err
+	}
+	if info.RowsAffected == 0 {
+		return repo.ErrNotFound
+	}
+	return nil
 }
 
-func (r *categoryRepo) Delete(ctx context.Context, id int64) error {
+// Move 调整父分类与排序，子树随节点一起移动。
+func (r *categoryRepo) Move(ctx context.Context, id int64, parentID *int64, sortOrder int32) error {
 	c := r.query.ArticleCategory
-	_, err := c.WithContext(ctx).Where(c.ID.Eq(id)).Delete()
+	parent := c.ParentID.Null()
+	if parentID != nil {
+		parent = c.ParentID.Value(*parentID)
+	}
+	_, err := c.WithContext(ctx).Where(c.ID.Eq(id)).UpdateSimple(parent, c.SortOrder.Value(sortOrder))
 	return err
 }
 
+// Delete 删除分类，其子分类上移到被删除分类的父级。
+func (r *categoryRepo) Delete(ctx context.Context, id int64) error {
+	return r.query.Transaction(func(tx *query.Query) error {
+		c := tx.ArticleCategory
+		row, err := c.WithContext(ctx).Where(c.ID.Eq(id)).First()
+		if err != nil {
+			return err
+		}
+
+		parent := c.ParentID.Null()
+		if row.ParentID != nil {
+			parent = c.ParentID.Value(*row.ParentID)
+		}
+		if _, err := c.WithContext(ctx).Where(c.ParentID.Eq(id)).UpdateSimple(parent); err != nil {
+			return err
+		}
+
+		_, err = c.WithContext(ctx).Where(c.ID.Eq(id)).Delete()
+		return err
+	})
+}
+
 func toModelCategory(c *entity.Category) *model.ArticleCategory {
 	return &model.ArticleCategory{
 		ID:           c.ID,
 		Name:         c.Name,
 		Slug:         &c.Slug,
+		ParentID:     c.ParentID,
+		SortOrder:    &c.SortOrder,
 		ArticleCount: &c.ArticleCount,
 	}
 }
 
 func toEntityCategory(mc *model.ArticleCategory) *entity.Category {
 	cat := &entity.Category{
-		ID:   mc.ID,
-		Name: mc.Name,
+		ID:       mc.ID,
+		Name:     mc.Name,
+		ParentID: mc.ParentID,
 	}
 	if mc.Slug != nil {
 		cat.Slug = *mc.Slug
 	}
+	if mc.SortOrder != nil {
+		cat.SortOrder = *mc.SortOrder
+	}
 	if mc.ArticleCount != nil {
 		cat.ArticleCount = *mc.ArticleCount
 	}
@@ -0,0 +1,17 @@
+package persistence
+
+import (
+	"errors"
+
+	"server-blog-v2/internal/repo"
+
+	"gorm.io/gorm"
+)
+
+// wrapNotFound 将 gorm 的记录不存在错误转换为 repo.ErrNotFound，其他错误原样返回。
+func wrapNotFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return repo.ErrNotFound
+	}
+	return err
+}
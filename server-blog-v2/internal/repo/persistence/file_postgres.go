@@ -2,6 +2,8 @@ package persistence
 
 import (
 	"context"
+	"encoding/json"
+	"time"
 
 	"server-blog-v2/internal/entity"
 	"server-blog-v2/internal/repo"
@@ -11,6 +13,15 @@ import (
 	"gorm.io/gorm"
 )
 
+// fileVariantJSON files.variants 列的存储结构。
+type fileVariantJSON struct {
+	Key      string `json:"key"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+	Size     int64  `json:"size"`
+	MimeType string `json:"mime_type"`
+}
+
 type fileRepo struct {
 	query *query.Query
 }
@@ -80,6 +91,20 @@ func (r *fileRepo) GetByIDs(ctx context.Context, ids []int64) ([]*entity.File, e
 	return files, nil
 }
 
+func (r *fileRepo) ListCreatedBefore(ctx context.Context, before time.Time) ([]*entity.File, error) {
+	f := r.query.File
+	rows, err := f.WithContext(ctx).Where(f.CreatedAt.Lt(before)).Order(f.ID).Find()
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]*entity.File, len(rows))
+	for i, row := range rows {
+		files[i] = toEntityFile(row)
+	}
+	return files, nil
+}
+
 func (r *fileRepo) Delete(ctx context.Context, key string) error {
 	f := r.query.File
 	_, err := f.WithContext(ctx).Where(f.Key.Eq(key)).Delete()
@@ -92,6 +117,17 @@ func (r *fileRepo) DeleteByIDs(ctx context.Context, ids []int64) error {
 	return err
 }
 
+func (r *fileRepo) UpdateAccess(ctx context.Context, file *entity.File) error {
+	mf := toModelFile(file)
+	f := r.query.File
+	_, err := f.WithContext(ctx).Where(f.ID.Eq(file.ID)).UpdateSimple(
+		f.Access.Value(mf.Access),
+		f.Key.Value(mf.Key),
+		f.Variants.Value(*mf.Variants),
+	)
+	return err
+}
+
 func toModelFile(f *entity.File) *model.File {
 	mf := &model.File{
 		ID:       f.ID,
@@ -100,6 +136,10 @@ func toModelFile(f *entity.File) *model.File {
 		Size:     &f.Size,
 		MimeType: &f.MimeType,
 		Usage:    &f.Usage,
+		Access:   f.Access,
+	}
+	if mf.Access == "" {
+		mf.Access = entity.ResourceAccessPublic
 	}
 	if f.FileHash != "" {
 		mf.FileHash = &f.FileHash
@@ -110,6 +150,18 @@ func toModelFile(f *entity.File) *model.File {
 	if f.UserUUID != "" {
 		mf.UserUUID = &f.UserUUID
 	}
+	if f.Width > 0 {
+		width, height := int32(f.Width), int32(f.Height)
+		mf.Width, mf.Height = &width, &height
+	}
+	variants := make([]fileVariantJSON, len(f.Variants))
+	for i, v := range f.Variants {
+		variants[i] = fileVariantJSON(v)
+	}
+	if data, err := json.Marshal(variants); err == nil {
+		variants := string(data)
+		mf.Variants = &variants
+	}
 	return mf
 }
 
@@ -118,6 +170,7 @@ func toEntityFile(mf *model.File) *entity.File {
 		ID:         mf.ID,
 		Key:        mf.Key,
 		ResourceID: mf.ResourceID,
+		Access:     mf.Access,
 	}
 	if mf.Filename != nil {
 		file.Filename = *mf.Filename
@@ -137,6 +190,21 @@ func toEntityFile(mf *model.File) *entity.File {
 	if mf.UserUUID != nil {
 		file.UserUUID = *mf.UserUUID
 	}
+	if mf.Width != nil {
+		file.Width = int(*mf.Width)
+	}
+	if mf.Height != nil {
+		file.Height = int(*mf.Height)
+	}
+	if mf.Variants != nil && *mf.Variants != "" {
+		var variants []fileVariantJSON
+		if err := json.Unmarshal([]byte(*mf.Variants), &variants); err == nil {
+			file.Variants = make([]entity.FileVariant, len(variants))
+			for i, v := range variants {
+				file.Variants[i] = entity.FileVariant(v)
+			}
+		}
+	}
 	if mf.CreatedAt != nil {
 		file.CreatedAt = *mf.CreatedAt
 	}
@@ -21,6 +21,8 @@ type ArticleCategory struct {
 	CreatedAt    *time.Time     `gorm:"column:created_at;type:timestamp with time zone;default:CURRENT_TIMESTAMP" json:"created_at"`
 	UpdatedAt    *time.Time     `gorm:"column:updated_at;type:timestamp with time zone;default:CURRENT_TIMESTAMP" json:"updated_at"`
 	DeletedAt    gorm.DeletedAt `gorm:"column:deleted_at;type:timestamp with time zone" json:"deleted_at"`
+	ParentID     *int64         `gorm:"column:parent_id;type:bigint" json:"parent_id"`
+	SortOrder    *int32         `gorm:"column:sort_order;type:integer" json:"sort_order"`
 }
 
 // TableName ArticleCategory's table name
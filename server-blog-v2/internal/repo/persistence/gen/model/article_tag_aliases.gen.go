@@ -0,0 +1,24 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package model
+
+import (
+	"time"
+)
+
+const TableNameArticleTagAlias = "article_tag_aliases"
+
+// ArticleTagAlias mapped from table <article_tag_aliases>
+type ArticleTagAlias struct {
+	ID        int64      `gorm:"column:id;type:bigint;primaryKey;autoIncrement:true" json:"id"`
+	Slug      string     `gorm:"column:slug;type:character varying(50);not null" json:"slug"`
+	TagID     int64      `gorm:"column:tag_id;type:bigint;not null" json:"tag_id"`
+	CreatedAt *time.Time `gorm:"column:created_at;type:timestamp with time zone;default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+// TableName ArticleTagAlias's table name
+func (*ArticleTagAlias) TableName() string {
+	return TableNameArticleTagAlias
+}
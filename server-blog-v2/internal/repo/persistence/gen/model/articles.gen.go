@@ -36,6 +36,7 @@ type Article struct {
 	AuthorUUID      *string        `gorm:"column:author_uuid;type:uuid" json:"author_uuid"`
 	Visibility      string         `gorm:"column:visibility;type:character varying(20);not null;default:public;comment:文章可见性: public(公开) | private(私有)" json:"visibility"` // 文章可见性: public(公开) | private(私有)
 	TagIDs          pq.Int64Array  `gorm:"column:tag_ids;type:bigint[];default:{}" json:"tag_ids"`
+	CoAuthorUUIDs   pq.StringArray `gorm:"column:co_author_uuids;type:uuid[];default:{}" json:"co_author_uuids"`
 }
 
 // TableName Article's table name
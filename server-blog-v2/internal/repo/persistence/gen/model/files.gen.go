@@ -25,6 +25,10 @@ type File struct {
 	CreatedAt  *time.Time     `gorm:"column:created_at;type:timestamp with time zone;default:CURRENT_TIMESTAMP" json:"created_at"`
 	UserUUID   *string        `gorm:"column:user_uuid;type:uuid" json:"user_uuid"`
 	DeletedAt  gorm.DeletedAt `gorm:"column:deleted_at;type:timestamp with time zone" json:"deleted_at"`
+	Width      *int32         `gorm:"column:width;type:integer" json:"width"`
+	Height     *int32         `gorm:"column:height;type:integer" json:"height"`
+	Variants   *string        `gorm:"column:variants;type:jsonb;default:'[]'" json:"variants"`
+	Access     string         `gorm:"column:access;type:character varying(10);not null;default:public" json:"access"`
 }
 
 // TableName File's table name
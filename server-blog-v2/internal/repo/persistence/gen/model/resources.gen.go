@@ -27,6 +27,7 @@ type Resource struct {
 	CreatedAt       *time.Time     `gorm:"column:created_at;type:timestamp with time zone;default:CURRENT_TIMESTAMP" json:"created_at"`
 	UpdatedAt       *time.Time     `gorm:"column:updated_at;type:timestamp with time zone;default:CURRENT_TIMESTAMP" json:"updated_at"`
 	DeletedAt       gorm.DeletedAt `gorm:"column:deleted_at;type:timestamp with time zone" json:"deleted_at"`
+	Access          string         `gorm:"column:access;type:character varying(10);not null;default:public" json:"access"`
 }
 
 // TableName Resource's table name
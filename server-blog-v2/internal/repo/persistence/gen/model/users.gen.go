@@ -24,6 +24,7 @@ type User struct {
 	CreatedAt *time.Time     `gorm:"column:created_at;type:timestamp with time zone;default:CURRENT_TIMESTAMP" json:"created_at"`
 	UpdatedAt *time.Time     `gorm:"column:updated_at;type:timestamp with time zone;default:CURRENT_TIMESTAMP" json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at;type:timestamp with time zone" json:"deleted_at"`
+	Bio       *string        `gorm:"column:bio;type:character varying(500)" json:"bio"`
 }
 
 // TableName User's table name
@@ -34,6 +34,8 @@ func newArticleCategory(db *gorm.DB, opts ...gen.DOOption) articleCategory {
 	_articleCategory.CreatedAt = field.NewTime(tableName, "created_at")
 	_articleCategory.UpdatedAt = field.NewTime(tableName, "updated_at")
 	_articleCategory.DeletedAt = field.NewField(tableName, "deleted_at")
+	_articleCategory.ParentID = field.NewInt64(tableName, "parent_id")
+	_articleCategory.SortOrder = field.NewInt32(tableName, "sort_order")
 
 	_articleCategory.fillFieldMap()
 
@@ -51,6 +53,8 @@ type articleCategory struct {
 	CreatedAt    field.Time
 	UpdatedAt    field.Time
 	DeletedAt    field.Field
+	ParentID     field.Int64
+	SortOrder    field.Int32
 
 	fieldMap map[string]field.Expr
 }
@@ -74,6 +78,8 @@ func (a *articleCategory) updateTableName(table string) *articleCategory {
 	a.CreatedAt = field.NewTime(table, "created_at")
 	a.UpdatedAt = field.NewTime(table, "updated_at")
 	a.DeletedAt = field.NewField(table, "deleted_at")
+	a.ParentID = field.NewInt64(table, "parent_id")
+	a.SortOrder = field.NewInt32(table, "sort_order")
 
 	a.fillFieldMap()
 
@@ -102,7 +108,7 @@ func (a *articleCategory) GetFieldByName(fieldName string) (field.OrderExpr, boo
 }
 
 func (a *articleCategory) fillFieldMap() {
-	a.fieldMap = make(map[string]field.Expr, 7)
+	a.fieldMap = make(map[string]field.Expr, 9)
 	a.fieldMap["id"] = a.ID
 	a.fieldMap["name"] = a.Name
 	a.fieldMap["slug"] = a.Slug
@@ -110,6 +116,8 @@ func (a *articleCategory) fillFieldMap() {
 	a.fieldMap["created_at"] = a.CreatedAt
 	a.fieldMap["updated_at"] = a.UpdatedAt
 	a.fieldMap["deleted_at"] = a.DeletedAt
+	a.fieldMap["parent_id"] = a.ParentID
+	a.fieldMap["sort_order"] = a.SortOrder
 }
 
 func (a articleCategory) clone(db *gorm.DB) articleCategory {
@@ -48,6 +48,7 @@ func newArticle(db *gorm.DB, opts ...gen.DOOption) article {
 	_article.AuthorUUID = field.NewString(tableName, "author_uuid")
 	_article.Visibility = field.NewString(tableName, "visibility")
 	_article.TagIds = field.NewString(tableName, "tag_ids")
+	_article.CoAuthorUuids = field.NewString(tableName, "co_author_uuids")
 
 	_article.fillFieldMap()
 
@@ -79,6 +80,7 @@ type article struct {
 	AuthorUUID      field.String
 	Visibility      field.String // 文章可见性: public(公开) | private(私有)
 	TagIds          field.String
+	CoAuthorUuids   field.String
 
 	fieldMap map[string]field.Expr
 }
@@ -116,6 +118,7 @@ func (a *article) updateTableName(table string) *article {
 	a.AuthorUUID = field.NewString(table, "author_uuid")
 	a.Visibility = field.NewString(table, "visibility")
 	a.TagIds = field.NewString(table, "tag_ids")
+	a.CoAuthorUuids = field.NewString(table, "co_author_uuids")
 
 	a.fillFieldMap()
 
@@ -140,7 +143,7 @@ func (a *article) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
 }
 
 func (a *article) fillFieldMap() {
-	a.fieldMap = make(map[string]field.Expr, 21)
+	a.fieldMap = make(map[string]field.Expr, 22)
 	a.fieldMap["id"] = a.ID
 	a.fieldMap["title"] = a.Title
 	a.fieldMap["slug"] = a.Slug
@@ -162,6 +165,7 @@ func (a *article) fillFieldMap() {
 	a.fieldMap["author_uuid"] = a.AuthorUUID
 	a.fieldMap["visibility"] = a.Visibility
 	a.fieldMap["tag_ids"] = a.TagIds
+	a.fieldMap["co_author_uuids"] = a.CoAuthorUuids
 }
 
 func (a article) clone(db *gorm.DB) article {
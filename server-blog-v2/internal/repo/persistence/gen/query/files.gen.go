@@ -38,6 +38,10 @@ func newFile(db *gorm.DB, opts ...gen.DOOption) file {
 	_file.CreatedAt = field.NewTime(tableName, "created_at")
 	_file.UserUUID = field.NewString(tableName, "user_uuid")
 	_file.DeletedAt = field.NewField(tableName, "deleted_at")
+	_file.Width = field.NewInt32(tableName, "width")
+	_file.Height = field.NewInt32(tableName, "height")
+	_file.Variants = field.NewString(tableName, "variants")
+	_file.Access = field.NewString(tableName, "access")
 
 	_file.fillFieldMap()
 
@@ -59,6 +63,10 @@ type file struct {
 	CreatedAt  field.Time
 	UserUUID   field.String
 	DeletedAt  field.Field
+	Width      field.Int32
+	Height     field.Int32
+	Variants   field.String
+	Access     field.String
 
 	fieldMap map[string]field.Expr
 }
@@ -86,6 +94,10 @@ func (f *file) updateTableName(table string) *file {
 	f.CreatedAt = field.NewTime(table, "created_at")
 	f.UserUUID = field.NewString(table, "user_uuid")
 	f.DeletedAt = field.NewField(table, "deleted_at")
+	f.Width = field.NewInt32(table, "width")
+	f.Height = field.NewInt32(table, "height")
+	f.Variants = field.NewString(table, "variants")
+	f.Access = field.NewString(table, "access")
 
 	f.fillFieldMap()
 
@@ -110,7 +122,7 @@ func (f *file) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
 }
 
 func (f *file) fillFieldMap() {
-	f.fieldMap = make(map[string]field.Expr, 11)
+	f.fieldMap = make(map[string]field.Expr, 15)
 	f.fieldMap["id"] = f.ID
 	f.fieldMap["key"] = f.Key
 	f.fieldMap["filename"] = f.Filename
@@ -122,6 +134,10 @@ func (f *file) fillFieldMap() {
 	f.fieldMap["created_at"] = f.CreatedAt
 	f.fieldMap["user_uuid"] = f.UserUUID
 	f.fieldMap["deleted_at"] = f.DeletedAt
+	f.fieldMap["width"] = f.Width
+	f.fieldMap["height"] = f.Height
+	f.fieldMap["variants"] = f.Variants
+	f.fieldMap["access"] = f.Access
 }
 
 func (f file) clone(db *gorm.DB) file {
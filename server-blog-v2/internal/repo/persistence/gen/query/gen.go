@@ -25,6 +25,7 @@ var (
 	ArticleCategory    *articleCategory
 	ArticleLike        *articleLike
 	ArticleTag         *articleTag
+	ArticleTagAlias    *articleTagAlias
 	ArticleView        *articleView
 	Comment            *comment
 	CommentLike        *commentLike
@@ -50,6 +51,7 @@ func SetDefault(db *gorm.DB, opts ...gen.DOOption) {
 	ArticleCategory = &Q.ArticleCategory
 	ArticleLike = &Q.ArticleLike
 	ArticleTag = &Q.ArticleTag
+	ArticleTagAlias = &Q.ArticleTagAlias
 	ArticleView = &Q.ArticleView
 	Comment = &Q.Comment
 	CommentLike = &Q.CommentLike
@@ -76,6 +78,7 @@ func Use(db *gorm.DB, opts ...gen.DOOption) *Query {
 		ArticleCategory:    newArticleCategory(db, opts...),
 		ArticleLike:        newArticleLike(db, opts...),
 		ArticleTag:         newArticleTag(db, opts...),
+		ArticleTagAlias:    newArticleTagAlias(db, opts...),
 		ArticleView:        newArticleView(db, opts...),
 		Comment:            newComment(db, opts...),
 		CommentLike:        newCommentLike(db, opts...),
@@ -103,6 +106,7 @@ type Query struct {
 	ArticleCategory    articleCategory
 	ArticleLike        articleLike
 	ArticleTag         articleTag
+	ArticleTagAlias    articleTagAlias
 	ArticleView        articleView
 	Comment            comment
 	CommentLike        commentLike
@@ -131,6 +135,7 @@ func (q *Query) clone(db *gorm.DB) *Query {
 		ArticleCategory:    q.ArticleCategory.clone(db),
 		ArticleLike:        q.ArticleLike.clone(db),
 		ArticleTag:         q.ArticleTag.clone(db),
+		ArticleTagAlias:    q.ArticleTagAlias.clone(db),
 		ArticleView:        q.ArticleView.clone(db),
 		Comment:            q.Comment.clone(db),
 		CommentLike:        q.CommentLike.clone(db),
@@ -166,6 +171,7 @@ func (q *Query) ReplaceDB(db *gorm.DB) *Query {
 		ArticleCategory:    q.ArticleCategory.replaceDB(db),
 		ArticleLike:        q.ArticleLike.replaceDB(db),
 		ArticleTag:         q.ArticleTag.replaceDB(db),
+		ArticleTagAlias:    q.ArticleTagAlias.replaceDB(db),
 		ArticleView:        q.ArticleView.replaceDB(db),
 		Comment:            q.Comment.replaceDB(db),
 		CommentLike:        q.CommentLike.replaceDB(db),
@@ -191,6 +197,7 @@ type queryCtx struct {
 	ArticleCategory    IArticleCategoryDo
 	ArticleLike        IArticleLikeDo
 	ArticleTag         IArticleTagDo
+	ArticleTagAlias    IArticleTagAliasDo
 	ArticleView        IArticleViewDo
 	Comment            ICommentDo
 	CommentLike        ICommentLikeDo
@@ -216,6 +223,7 @@ func (q *Query) WithContext(ctx context.Context) *queryCtx {
 		ArticleCategory:    q.ArticleCategory.WithContext(ctx),
 		ArticleLike:        q.ArticleLike.WithContext(ctx),
 		ArticleTag:         q.ArticleTag.WithContext(ctx),
+		ArticleTagAlias:    q.ArticleTagAlias.WithContext(ctx),
 		ArticleView:        q.ArticleView.WithContext(ctx),
 		Comment:            q.Comment.WithContext(ctx),
 		CommentLike:        q.CommentLike.WithContext(ctx),
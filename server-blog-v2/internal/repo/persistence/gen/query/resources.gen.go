@@ -40,6 +40,7 @@ func newResource(db *gorm.DB, opts ...gen.DOOption) resource {
 	_resource.CreatedAt = field.NewTime(tableName, "created_at")
 	_resource.UpdatedAt = field.NewTime(tableName, "updated_at")
 	_resource.DeletedAt = field.NewField(tableName, "deleted_at")
+	_resource.Access = field.NewString(tableName, "access")
 
 	_resource.fillFieldMap()
 
@@ -63,6 +64,7 @@ type resource struct {
 	CreatedAt       field.Time
 	UpdatedAt       field.Time
 	DeletedAt       field.Field
+	Access          field.String
 
 	fieldMap map[string]field.Expr
 }
@@ -92,6 +94,7 @@ func (r *resource) updateTableName(table string) *resource {
 	r.CreatedAt = field.NewTime(table, "created_at")
 	r.UpdatedAt = field.NewTime(table, "updated_at")
 	r.DeletedAt = field.NewField(table, "deleted_at")
+	r.Access = field.NewString(table, "access")
 
 	r.fillFieldMap()
 
@@ -116,7 +119,7 @@ func (r *resource) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
 }
 
 func (r *resource) fillFieldMap() {
-	r.fieldMap = make(map[string]field.Expr, 13)
+	r.fieldMap = make(map[string]field.Expr, 14)
 	r.fieldMap["id"] = r.ID
 	r.fieldMap["file_key"] = r.FileKey
 	r.fieldMap["file_name"] = r.FileName
@@ -130,6 +133,7 @@ func (r *resource) fillFieldMap() {
 	r.fieldMap["created_at"] = r.CreatedAt
 	r.fieldMap["updated_at"] = r.UpdatedAt
 	r.fieldMap["deleted_at"] = r.DeletedAt
+	r.fieldMap["access"] = r.Access
 }
 
 func (r resource) clone(db *gorm.DB) resource {
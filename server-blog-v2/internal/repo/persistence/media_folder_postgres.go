@@ -0,0 +1,100 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"server-blog-v2/internal/entity"
+	"server-blog-v2/internal/repo"
+)
+
+// MediaFolder 媒体库文件夹数据库模型。
+type MediaFolder struct {
+	ID        int64     `gorm:"column:id;primaryKey;autoIncrement"`
+	Name      string    `gorm:"column:name;type:varchar(100)"`
+	ParentID  *int64    `gorm:"column:parent_id"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (MediaFolder) TableName() string {
+	return "media_folders"
+}
+
+type mediaFolderRepo struct {
+	db *gorm.DB
+}
+
+// NewMediaFolderRepo 创建媒体库文件夹仓库。
+func NewMediaFolderRepo(db *gorm.DB) repo.MediaFolderRepo {
+	return &mediaFolderRepo{db: db}
+}
+
+func (r *mediaFolderRepo) List(ctx context.Context) ([]*entity.MediaFolder, error) {
+	var mfs []MediaFolder
+	if err := r.db.WithContext(ctx).Order("parent_id NULLS FIRST, name").Find(&mfs).Error; err != nil {
+		return nil, err
+	}
+	folders := make([]*entity.MediaFolder, len(mfs))
+	for i := range mfs {
+		folders[i] = toEntityMediaFolder(&mfs[i])
+	}
+	return folders, nil
+}
+
+func (r *mediaFolderRepo) GetByID(ctx context.Context, id int64) (*entity.MediaFolder, error) {
+	var mf MediaFolder
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&mf).Error; err != nil {
+		return nil, wrapNotFound(err)
+	}
+	return toEntityMediaFolder(&mf), nil
+}
+
+func (r *mediaFolderRepo) Create(ctx context.Context, folder *entity.MediaFolder) (int64, error) {
+	mf := MediaFolder{
+		Name:     folder.Name,
+		ParentID: folder.ParentID,
+	}
+	if err := r.db.WithContext(ctx).Create(&mf).Error; err != nil {
+		return 0, err
+	}
+	return mf.ID, nil
+}
+
+func (r *mediaFolderRepo) Update(ctx context.Context, folder *entity.MediaFolder) error {
+	return r.db.WithContext(ctx).Model(&MediaFolder{}).Where("id = ?", folder.ID).Updates(map[string]interface{}{
+		"name":       folder.Name,
+		"parent_id":  folder.ParentID,
+		"updated_at": time.Now(),
+	}).Error
+}
+
+func (r *mediaFolderRepo) Delete(ctx context.Context, id int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var mf MediaFolder
+		if err := tx.Where("id = ?", id).First(&mf).Error; err != nil {
+			return wrapNotFound(err)
+		}
+		if err := tx.Model(&MediaFolder{}).Where("parent_id = ?", id).
+			Update("parent_id", mf.ParentID).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&MediaItem{}).Where("folder_id = ?", id).
+			Update("folder_id", mf.ParentID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&MediaFolder{}, id).Error
+	})
+}
+
+func toEntityMediaFolder(mf *MediaFolder) *entity.MediaFolder {
+	return &entity.MediaFolder{
+		ID:        mf.ID,
+		Name:      mf.Name,
+		ParentID:  mf.ParentID,
+		CreatedAt: mf.CreatedAt,
+		UpdatedAt: mf.UpdatedAt,
+	}
+}
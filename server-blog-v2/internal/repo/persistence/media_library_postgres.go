@@ -0,0 +1,274 @@
+package persistence
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"server-blog-v2/internal/entity"
+	"server-blog-v2/internal/repo"
+)
+
+// MediaItem 媒体库元数据数据库模型。
+type MediaItem struct {
+	ID        int64          `gorm:"column:id;primaryKey;autoIncrement"`
+	Source    string         `gorm:"column:source;type:varchar(10)"`
+	SourceID  int64          `gorm:"column:source_id"`
+	FolderID  *int64         `gorm:"column:folder_id"`
+	AltText   string         `gorm:"column:alt_text;type:varchar(255)"`
+	Caption   string         `gorm:"column:caption;type:text"`
+	Tags      pq.StringArray `gorm:"column:tags;type:text[]"`
+	CreatedAt time.Time      `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt time.Time      `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (MediaItem) TableName() string {
+	return "media_items"
+}
+
+// mediaItemRow 媒体库列表查询结果。
+type mediaItemRow struct {
+	Source       string
+	SourceID     int64
+	Key          string
+	Name         string
+	Size         int64
+	MimeType     string
+	Access       string
+	ThumbnailKey string
+	CreatedAt    time.Time
+	FolderID     *int64
+	AltText      string
+	Caption      string
+	Tags         pq.StringArray
+}
+
+// mediaFileBranch / mediaResourceBranch 统一 files 与 resources 的列，%s 处追加过滤条件。
+// 文件名检索写在各分支内，才能命中 to_tsvector(blog_bigram(...)) 表达式索引。
+const (
+	mediaFileBranch = `SELECT 'file' AS source, f.id AS source_id, f.key, COALESCE(f.filename, '') AS name,
+	COALESCE(f.size, 0) AS size, COALESCE(f.mime_type, '') AS mime_type, f.access,
+	'' AS thumbnail_key, f.created_at, m.folder_id,
+	COALESCE(m.alt_text, '') AS alt_text, COALESCE(m.caption, '') AS caption, COALESCE(m.tags, '{}') AS tags
+FROM files f LEFT JOIN media_items m ON m.source = 'file' AND m.source_id = f.id
+WHERE f.deleted_at IS NULL%s`
+	mediaResourceBranch = `SELECT 'resource' AS source, r.id AS source_id, r.file_key AS key, r.file_name AS name,
+	r.file_size AS size, r.mime_type, r.access,
+	COALESCE(r.thumbnail_key, '') AS thumbnail_key, r.created_at, m.folder_id,
+	COALESCE(m.alt_text, '') AS alt_text, COALESCE(m.caption, '') AS caption, COALESCE(m.tags, '{}') AS tags
+FROM resources r LEFT JOIN media_items m ON m.source = 'resource' AND m.source_id = r.id
+WHERE r.deleted_at IS NULL%s`
+)
+
+type mediaLibraryRepo struct {
+	db *gorm.DB
+}
+
+// NewMediaLibraryRepo 创建媒体库仓库。
+func NewMediaLibraryRepo(db *gorm.DB) repo.MediaLibraryRepo {
+	return &mediaLibraryRepo{db: db}
+}
+
+func (r *mediaLibraryRepo) List(ctx context.Context, offset, limit int, filter repo.MediaListFilter) ([]*entity.MediaItem, int64, error) {
+	union, args := buildMediaUnion(filter)
+
+	var total int64
+	if err := r.db.WithContext(ctx).Raw("SELECT COUNT(*) FROM ("+union+") AS media", args...).Scan(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var rows []mediaItemRow
+	err := r.db.WithContext(ctx).
+		Raw("SELECT * FROM ("+union+") AS media ORDER BY created_at DESC, source, source_id DESC LIMIT ? OFFSET ?",
+			append(args, limit, offset)...).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	items := make([]*entity.MediaItem, len(rows))
+	for i, row := range rows {
+		items[i] = &entity.MediaItem{
+			Source:       row.Source,
+			SourceID:     row.SourceID,
+			Key:          row.Key,
+			Name:         row.Name,
+			Size:         row.Size,
+			MimeType:     row.MimeType,
+			Access:       row.Access,
+			ThumbnailKey: row.ThumbnailKey,
+			FolderID:     row.FolderID,
+			AltText:      row.AltText,
+			Caption:      row.Caption,
+			Tags:         []string(row.Tags),
+			CreatedAt:    row.CreatedAt,
+		}
+		if items[i].Tags == nil {
+			items[i].Tags = []string{}
+		}
+	}
+	return items, total, nil
+}
+
+// buildMediaUnion 按过滤条件拼接 files 与 resources 两个分支。
+func buildMediaUnion(filter repo.MediaListFilter) (string, []interface{}) {
+	branch := func(tpl, nameCol, mimeCol string) (string, []interface{}) {
+		var conds []string
+		var args []interface{}
+		if filter.Keyword != "" {
+			conds = append(conds, "to_tsvector('simple', blog_bigram("+nameCol+")) @@ plainto_tsquery('simple', blog_bigram(?))")
+			args = append(args, filter.Keyword)
+		}
+		if filter.MimeFamily != "" {
+			conds = append(conds, mimeCol+" LIKE ?")
+			args = append(args, escapeLike(filter.MimeFamily)+"/%")
+		}
+		if filter.FolderID != nil {
+			if *filter.FolderID == 0 {
+				conds = append(conds, "m.folder_id IS NULL")
+			} else {
+				conds = append(conds, "m.folder_id = ?")
+				args = append(args, *filter.FolderID)
+			}
+		}
+		if filter.Tag != "" {
+			conds = append(conds, "m.tags @> ARRAY[?]::text[]")
+			args = append(args, filter.Tag)
+		}
+
+		where := ""
+		if len(conds) > 0 {
+			where = " AND " + strings.Join(conds, " AND ")
+		}
+		return strings.Replace(tpl, "%s", where, 1), args
+	}
+
+	var parts []string
+	var args []interface{}
+	if filter.Source == "" || filter.Source == entity.MediaSourceFile {
+		sql, a := branch(mediaFileBranch, "f.filename", "f.mime_type")
+		parts = append(parts, sql)
+		args = append(args, a...)
+	}
+	if filter.Source == "" || filter.Source == entity.MediaSourceResource {
+		sql, a := branch(mediaResourceBranch, "r.file_name", "r.mime_type")
+		parts = append(parts, sql)
+		args = append(args, a...)
+	}
+	return strings.Join(parts, "\nUNION ALL\n"), args
+}
+
+func (r *mediaLibraryRepo) UpdateMeta(ctx context.Context, ref entity.MediaRef, altText, caption string, tags []string) error {
+	item := MediaItem{
+		Source:   ref.Source,
+		SourceID: ref.SourceID,
+		AltText:  altText,
+		Caption:  caption,
+		Tags:     pq.StringArray(tags),
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "source"}, {Name: "source_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"alt_text", "caption", "tags", "updated_at"}),
+	}).Create(&item).Error
+}
+
+func (r *mediaLibraryRepo) Move(ctx context.Context, refs []entity.MediaRef, folderID *int64) error {
+	if len(refs) == 0 {
+		return nil
+	}
+	items := make([]MediaItem, len(refs))
+	for i, ref := range refs {
+		items[i] = MediaItem{
+			Source:   ref.Source,
+			SourceID: ref.SourceID,
+			FolderID: folderID,
+			Tags:     pq.StringArray{},
+		}
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "source"}, {Name: "source_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"folder_id", "updated_at"}),
+	}).Create(&items).Error
+}
+
+func (r *mediaLibraryRepo) ListTags(ctx context.Context) ([]*entity.MediaTagCount, error) {
+	var rows []struct {
+		Tag   string
+		Count int64
+	}
+	err := r.db.WithContext(ctx).
+		Raw(`SELECT tag, COUNT(*) AS count FROM media_items m, unnest(m.tags) AS tag
+			WHERE (m.source = 'file' AND EXISTS (SELECT 1 FROM files f WHERE f.id = m.source_id AND f.deleted_at IS NULL))
+				OR (m.source = 'resource' AND EXISTS (SELECT 1 FROM resources r WHERE r.id = m.source_id AND r.deleted_at IS NULL))
+			GROUP BY tag ORDER BY count DESC, tag`).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]*entity.MediaTagCount, len(rows))
+	for i, row := range rows {
+		tags[i] = &entity.MediaTagCount{Tag: row.Tag, Count: row.Count}
+	}
+	return tags, nil
+}
+
+func (r *mediaLibraryRepo) ListUsage(ctx context.Context, keys []string) ([]*entity.MediaUsage, error) {
+	// 正文中的地址可能是转义后的 Key（本地存储逐段转义）
+	var patterns []string
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		patterns = append(patterns, "%"+escapeLike(key)+"%")
+		if escaped := (&url.URL{Path: key}).EscapedPath(); escaped != key {
+			patterns = append(patterns, "%"+escapeLike(escaped)+"%")
+		}
+	}
+	if len(patterns) == 0 {
+		return []*entity.MediaUsage{}, nil
+	}
+
+	var rows []struct {
+		ID        int64
+		Title     string
+		Slug      string
+		Status    string
+		InCover   bool
+		InContent bool
+	}
+	err := r.db.WithContext(ctx).Raw(`SELECT id, title, slug, COALESCE(status, '') AS status,
+			COALESCE(featured_image LIKE ANY(?::text[]), false) AS in_cover,
+			content LIKE ANY(?::text[]) AS in_content
+		FROM articles
+		WHERE deleted_at IS NULL AND (featured_image LIKE ANY(?::text[]) OR content LIKE ANY(?::text[]))
+		ORDER BY updated_at DESC`,
+		pq.StringArray(patterns), pq.StringArray(patterns), pq.StringArray(patterns), pq.StringArray(patterns)).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	usages := make([]*entity.MediaUsage, len(rows))
+	for i, row := range rows {
+		usages[i] = &entity.MediaUsage{
+			ArticleID: row.ID,
+			Title:     row.Title,
+			Slug:      row.Slug,
+			Status:    row.Status,
+			InCover:   row.InCover,
+			InContent: row.InContent,
+		}
+	}
+	return usages, nil
+}
+
+// escapeLike 转义 LIKE 通配符。
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,47 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+
+	"server-blog-v2/internal/repo"
+
+	"gorm.io/gorm"
+)
+
+// mediaReferenceSQL 汇总所有可能引用媒体的文本字段。
+const mediaReferenceSQL = `
+SELECT content FROM articles WHERE deleted_at IS NULL
+UNION ALL SELECT featured_image FROM articles WHERE deleted_at IS NULL AND featured_image <> ''
+UNION ALL SELECT avatar FROM users WHERE deleted_at IS NULL AND avatar <> ''
+UNION ALL SELECT setting_value FROM site_settings
+UNION ALL SELECT ad_image FROM advertisements WHERE ad_image <> ''
+UNION ALL SELECT logo FROM links WHERE deleted_at IS NULL AND logo <> ''`
+
+type mediaReferenceRepo struct {
+	db *gorm.DB
+}
+
+// NewMediaReferenceRepo 创建媒体引用来源仓库。
+func NewMediaReferenceRepo(db *gorm.DB) repo.MediaReferenceRepo {
+	return &mediaReferenceRepo{db: db}
+}
+
+func (r *mediaReferenceRepo) ScanTexts(ctx context.Context, fn func(text string)) error {
+	rows, err := r.db.WithContext(ctx).Raw(mediaReferenceSQL).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var text sql.NullString
+		if err := rows.Scan(&text); err != nil {
+			return err
+		}
+		if text.Valid && text.String != "" {
+			fn(text.String)
+		}
+	}
+	return rows.Err()
+}
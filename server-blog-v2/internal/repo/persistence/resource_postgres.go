@@ -31,7 +31,7 @@ func (r *resourceRepo) Create(ctx context.Context, resource *entity.Resource) (i
 func (r *resourceRepo) GetByID(ctx context.Context, id int64) (*entity.Resource, error) {
 	var mr model.Resource
 	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&mr).Error; err != nil {
-		return nil, err
+		return nil, wrapNotFound(err)
 	}
 	return toEntityResource(&mr), nil
 }
@@ -49,6 +49,45 @@ func (r *resourceRepo) GetByIDs(ctx context.Context, ids []int64) ([]*entity.Res
 	return resources, nil
 }
 
+func (r *resourceRepo) ListCreatedBefore(ctx context.Context, before time.Time) ([]*entity.Resource, error) {
+	var mrs []model.Resource
+	if err := r.db.WithContext(ctx).Where("created_at < ?", before).Order("id").Find(&mrs).Error; err != nil {
+		return nil, err
+	}
+
+	resources := make([]*entity.Resource, len(mrs))
+	for i, mr := range mrs {
+		resources[i] = toEntityResource(&mr)
+	}
+	return resources, nil
+}
+
+func (r *resourceRepo) ListByTranscodeStatus(ctx context.Context, status entity.TranscodeStatus) ([]*entity.Resource, error) {
+	var mrs []model.Resource
+	if err := r.db.WithContext(ctx).Where("transcode_status = ?", int16(status)).Order("id").Find(&mrs).Error; err != nil {
+		return nil, err
+	}
+
+	resources := make([]*entity.Resource, len(mrs))
+	for i, mr := range mrs {
+		resources[i] = toEntityResource(&mr)
+	}
+	return resources, nil
+}
+
+func (r *resourceRepo) SumFileSize(ctx context.Context, userUUID string, since *time.Time) (int64, error) {
+	db := r.db.WithContext(ctx).Model(&model.Resource{}).Where("user_uuid = ?", userUUID)
+	if since != nil {
+		db = db.Where("created_at >= ?", *since)
+	}
+
+	var total int64
+	if err := db.Select("COALESCE(SUM(file_size), 0)").Scan(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
 func (r *resourceRepo) GetByFileHash(ctx context.Context, fileHash, userUUID string) (*entity.Resource, error) {
 	var mr model.Resource
 	if err := r.db.WithContext(ctx).Where("file_hash = ? AND user_uuid = ?", fileHash, userUUID).First(&mr).Error; err != nil {
@@ -103,6 +142,23 @@ func (r *resourceRepo) DeleteByIDs(ctx context.Context, ids []int64) error {
 	return r.db.WithContext(ctx).Where("id IN ?", ids).Delete(&model.Resource{}).Error
 }
 
+func (r *resourceRepo) UpdateAccess(ctx context.Context, resource *entity.Resource) error {
+	mr := toModelResource(resource)
+	return r.db.WithContext(ctx).Model(&model.Resource{}).Where("id = ?", resource.ID).Updates(map[string]interface{}{
+		"access":        mr.Access,
+		"file_key":      mr.FileKey,
+		"transcode_key": mr.TranscodeKey,
+		"thumbnail_key": mr.ThumbnailKey,
+		"updated_at":    time.Now(),
+	}).Error
+}
+
+func (r *resourceRepo) CountByFileKey(ctx context.Context, fileKey string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.Resource{}).Where("file_key = ?", fileKey).Count(&count).Error
+	return count, err
+}
+
 func (r *resourceRepo) UpdateTranscodeStatus(ctx context.Context, id int64, status entity.TranscodeStatus, transcodeKey, thumbnailKey string) error {
 	updates := map[string]interface{}{
 		"transcode_status": int16(status),
@@ -138,6 +194,10 @@ func toModelResource(r *entity.Resource) *model.Resource {
 		FileName: r.FileName,
 		FileSize: r.FileSize,
 		MimeType: r.MimeType,
+		Access:   r.Access,
+	}
+	if mr.Access == "" {
+		mr.Access = entity.ResourceAccessPublic
 	}
 	if r.FileHash != "" {
 		mr.FileHash = &r.FileHash
@@ -163,6 +223,7 @@ func toEntityResource(mr *model.Resource) *entity.Resource {
 		FileName: mr.FileName,
 		FileSize: mr.FileSize,
 		MimeType: mr.MimeType,
+		Access:   mr.Access,
 	}
 	if mr.FileHash != nil {
 		r.FileHash = *mr.FileHash
@@ -26,6 +26,7 @@ type ResourceUploadTask struct {
 	UserUUID      string `gorm:"column:user_uuid;type:uuid;index"`
 	ExpiresAt     time.Time `gorm:"column:expires_at"`
 	QiniuContexts string `gorm:"column:qiniu_contexts;type:jsonb"`
+	Access        string `gorm:"column:access;type:varchar(10);default:public"`
 	CreatedAt     time.Time `gorm:"column:created_at;autoCreateTime"`
 	UpdatedAt     time.Time `gorm:"column:updated_at;autoUpdateTime"`
 }
@@ -121,6 +122,38 @@ func (r *resourceUploadTaskRepo) UpdateChunkContext(ctx context.Context, taskID
 	return nil
 }
 
+func (r *resourceUploadTaskRepo) ListExpired(ctx context.Context, now time.Time, limit int) ([]*entity.ResourceUploadTask, error) {
+	var mts []ResourceUploadTask
+	if err := r.db.WithContext(ctx).
+		Where("status IN ? AND expires_at < ?", []int8{int8(entity.TaskStatusInit), int8(entity.TaskStatusUploading)}, now).
+		Order("expires_at ASC").
+		Limit(limit).
+		Find(&mts).Error; err != nil {
+		return nil, err
+	}
+
+	tasks := make([]*entity.ResourceUploadTask, len(mts))
+	for i := range mts {
+		tasks[i] = toEntityTask(&mts[i])
+	}
+	return tasks, nil
+}
+
+func (r *resourceUploadTaskRepo) Expire(ctx context.Context, taskID string, status entity.TaskStatus, now time.Time) (bool, error) {
+	// 仅更新仍处于初始化/上传中的过期任务，避免与并发的 Complete/Cancel 冲突
+	result := r.db.WithContext(ctx).Model(&ResourceUploadTask{}).
+		Where("task_id = ? AND status IN ? AND expires_at < ?", taskID, []int8{int8(entity.TaskStatusInit), int8(entity.TaskStatusUploading)}, now).
+		Updates(map[string]interface{}{
+			"status":         int8(status),
+			"qiniu_contexts": "[]",
+			"updated_at":     now,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
 func toModelTask(t *entity.ResourceUploadTask) *ResourceUploadTask {
 	return &ResourceUploadTask{
 		ID:            t.ID,
@@ -137,6 +170,7 @@ func toModelTask(t *entity.ResourceUploadTask) *ResourceUploadTask {
 		CreatedAt:     t.CreatedAt,
 		UpdatedAt:     t.UpdatedAt,
 		QiniuContexts: t.QiniuContexts,
+		Access:        t.Access,
 	}
 }
 
@@ -154,6 +188,7 @@ func toEntityTask(mt *ResourceUploadTask) *entity.ResourceUploadTask {
 		UserUUID:      mt.UserUUID,
 		ExpiresAt:     mt.ExpiresAt,
 		QiniuContexts: mt.QiniuContexts,
+		Access:        mt.Access,
 		CreatedAt:     mt.CreatedAt,
 		UpdatedAt:     mt.UpdatedAt,
 	}
@@ -2,22 +2,26 @@ package persistence
 
 import (
 	"context"
+	"errors"
 
 	"server-blog-v2/internal/entity"
 	"server-blog-v2/internal/repo"
 	"server-blog-v2/internal/repo/persistence/gen/model"
 	"server-blog-v2/internal/repo/persistence/gen/query"
 
+	"github.com/lib/pq"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type tagRepo struct {
+	db    *gorm.DB
 	query *query.Query
 }
 
 // NewTagRepo 创建标签仓库。
 func NewTagRepo(db *gorm.DB) repo.TagRepo {
-	return &tagRepo{query: query.Use(db)}
+	return &tagRepo{db: db, query: query.Use(db)}
 }
 
 func (r *tagRepo) List(ctx context.Context, offset, limit int, keyword *string, sortBy, order *string) ([]*entity.Tag, int64, error) {
@@ -82,7 +86,7 @@ func (r *tagRepo) GetByID(ctx context.Context, id int64) (*entity.Tag, error) {
 	t := r.query.ArticleTag
 	row, err := t.WithContext(ctx).Where(t.ID.Eq(id)).First()
 	if err != nil {
-		return nil, err
+		return nil, wrapNotFound(err)
 	}
 	return toEntityTag(row), nil
 }
@@ -95,11 +99,33 @@ func (r *tagRepo) Create(ctx context.Context, tag entity.Tag) (int64, error) {
 	return mt.ID, nil
 }
 
+// Update 更新标签名称和 slug，slug 变更时旧 slug 记为别名。
 func (r *tagRepo) Update(ctx context.Context, tag entity.Tag) error {
-	t := r.query.ArticleTag
-	mt := toModelTag(&tag)
-	_, err := t.WithContext(ctx).Where(t.ID.Eq(tag.ID)).Updates(mt)
-	return err
+	return r.query.Transaction(func(tx *query.Query) error {
+		t := tx.ArticleTag
+		old, err := t.WithContext(ctx).Where(t.ID.Eq(tag.ID)).First()
+		if err != nil {
+			return err
+		}
+
+		mt := toModelTag(&tag)
+		if _, err := t.WithContext(ctx).Where(t.ID.Eq(tag.ID)).Select(t.Name, t.Slug).Updates(mt); err != nil {
+			return err
+		}
+
+		if old.Slug == nil || *old.Slug == tag.Slug {
+			return nil
+		}
+		a := tx.ArticleTagAlias
+		// 新 slug 若曾是别名则释放
+		if _, err := a.WithContext(ctx).Where(a.Slug.Eq(tag.Slug)).Delete(); err != nil {
+			return err
+		}
+		return a.WithContext(ctx).Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "slug"}},
+			DoUpdates: clause.AssignmentColumns([]string{"tag_id"}),
+		}).Create(&model.ArticleTagAlias{Slug: *old.Slug, TagID: tag.ID})
+	})
 }
 
 func (r *tagRepo) Delete(ctx context.Context, id int64) error {
@@ -108,6 +134,117 @@ func (r *tagRepo) Delete(ctx context.Context, id int64) error {
 	return err
 }
 
+func (r *tagRepo) GetBySlug(ctx context.Context, slug string) (*entity.Tag, error) {
+	t := r.query.ArticleTag
+	row, err := t.WithContext(ctx).Where(t.Slug.Eq(slug)).First()
+	if err != nil {
+		return nil, err
+	}
+	return toEntityTag(row), nil
+}
+
+// GetByAlias 通过别名查找当前标签。
+func (r *tagRepo) GetByAlias(ctx context.Context, slug string) (*entity.Tag, error) {
+	a := r.query.ArticleTagAlias
+	alias, err := a.WithContext(ctx).Where(a.Slug.Eq(slug)).First()
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByID(ctx, alias.TagID)
+}
+
+func (r *tagRepo) ListAliases(ctx context.Context, tagID int64) ([]*entity.TagAlias, error) {
+	a := r.query.ArticleTagAlias
+	rows, err := a.WithContext(ctx).Where(a.TagID.Eq(tagID)).Order(a.ID.Asc()).Find()
+	if err != nil {
+		return nil, err
+	}
+
+	aliases := make([]*entity.TagAlias, len(rows))
+	for i, row := range rows {
+		aliases[i] = &entity.TagAlias{ID: row.ID, Slug: row.Slug, TagID: row.TagID}
+		if row.CreatedAt != nil {
+			aliases[i].CreatedAt = *row.CreatedAt
+		}
+	}
+	return aliases, nil
+}
+
+func (r *tagRepo) DeleteAlias(ctx context.Context, id int64) error {
+	a := r.query.ArticleTagAlias
+	_, err := a.WithContext(ctx).Where(a.ID.Eq(id)).Delete()
+	return err
+}
+
+// Merge 合并标签：
+// 1. 改写文章 tag_ids（源标签替换为目标标签，去重并保持原顺序）
+// 2. 源标签的别名及 slug 转为目标标签的别名
+// 3. 释放源标签的名称与 slug（唯一约束包含软删除的行），软删除源标签并重算目标标签的文章数
+func (r *tagRepo) Merge(ctx context.Context, sourceIDs []int64, targetID int64) (int64, error) {
+	if len(sourceIDs) == 0 {
+		return 0, nil
+	}
+	for _, id := range sourceIDs {
+		if id == targetID {
+			return 0, errors.New("target tag cannot be a source tag")
+		}
+	}
+
+	var affected int64
+	sources := pq.Int64Array(sourceIDs)
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ?", targetID).First(&model.ArticleTag{}).Error; err != nil {
+			return wrapNotFound(err)
+		}
+		var count int64
+		if err := tx.Model(&model.ArticleTag{}).Where("id = ANY(?::bigint[])", sources).Count(&count).Error; err != nil {
+			return err
+		}
+		if count != int64(len(sourceIDs)) {
+			return repo.ErrNotFound
+		}
+
+		result := tx.Exec(`UPDATE articles SET tag_ids = ARRAY(
+				SELECT s.tag_id FROM (
+					SELECT CASE WHEN u.tag_id = ANY(?::bigint[]) THEN ? ELSE u.tag_id END AS tag_id, u.ord
+					FROM unnest(tag_ids) WITH ORDINALITY AS u(tag_id, ord)
+				) s
+				GROUP BY s.tag_id
+				ORDER BY MIN(s.ord)
+			), updated_at = CURRENT_TIMESTAMP
+			WHERE tag_ids && ?::bigint[]`, sources, targetID, sources)
+		if result.Error != nil {
+			return result.Error
+		}
+		affected = result.RowsAffected
+
+		if err := tx.Exec(`UPDATE article_tag_aliases SET tag_id = ? WHERE tag_id = ANY(?::bigint[])`,
+			targetID, sources).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(`INSERT INTO article_tag_aliases (slug, tag_id)
+			SELECT slug, ? FROM article_tags WHERE id = ANY(?::bigint[]) AND slug IS NOT NULL AND deleted_at IS NULL
+			ON CONFLICT (slug) DO UPDATE SET tag_id = EXCLUDED.tag_id`, targetID, sources).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(`UPDATE article_tags SET name = LEFT(name, 20) || '#merged-' || id, slug = NULL
+			WHERE id = ANY(?::bigint[])`, sources).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("id = ANY(?::bigint[])", sources).Delete(&model.ArticleTag{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Exec(`UPDATE article_tags SET article_count = (
+				SELECT COUNT(*) FROM articles WHERE deleted_at IS NULL AND ? = ANY(tag_ids)
+			), updated_at = CURRENT_TIMESTAMP WHERE id = ?`, targetID, targetID).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return affected, nil
+}
+
 func toModelTag(t *entity.Tag) *model.ArticleTag {
 	return &model.ArticleTag{
 		ID:           t.ID,
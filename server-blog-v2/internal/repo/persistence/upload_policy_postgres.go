@@ -0,0 +1,138 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"server-blog-v2/internal/entity"
+	"server-blog-v2/internal/repo"
+)
+
+// UploadPolicy 上传策略数据库模型。
+type UploadPolicy struct {
+	ID                  int64     `gorm:"column:id;primaryKey;autoIncrement"`
+	RoleID              *int32    `gorm:"column:role_id"`
+	UserUUID            *string   `gorm:"column:user_uuid;type:uuid"`
+	QuotaBytes          *int64    `gorm:"column:quota_bytes"`
+	MaxFileSize         *int64    `gorm:"column:max_file_size"`
+	DailyUploadBytes    *int64    `gorm:"column:daily_upload_bytes"`
+	AllowedMimeFamilies *string   `gorm:"column:allowed_mime_families;type:varchar(255)"`
+	CreatedAt           time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt           time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (UploadPolicy) TableName() string {
+	return "upload_policies"
+}
+
+type uploadPolicyRepo struct {
+	db *gorm.DB
+}
+
+// NewUploadPolicyRepo 创建上传策略仓库。
+func NewUploadPolicyRepo(db *gorm.DB) repo.UploadPolicyRepo {
+	return &uploadPolicyRepo{db: db}
+}
+
+func (r *uploadPolicyRepo) List(ctx context.Context) ([]*entity.UploadPolicy, error) {
+	var mps []UploadPolicy
+	if err := r.db.WithContext(ctx).Order("role_id NULLS LAST, id").Find(&mps).Error; err != nil {
+		return nil, err
+	}
+	return toEntityUploadPolicies(mps), nil
+}
+
+func (r *uploadPolicyRepo) ListFor(ctx context.Context, roleID int, userUUID string) ([]*entity.UploadPolicy, error) {
+	var mps []UploadPolicy
+	if err := r.db.WithContext(ctx).Where("role_id = ? OR user_uuid = ?", roleID, userUUID).Find(&mps).Error; err != nil {
+		return nil, err
+	}
+	return toEntityUploadPolicies(mps), nil
+}
+
+func (r *uploadPolicyRepo) Upsert(ctx context.Context, policy *entity.UploadPolicy) (int64, error) {
+	mp := toModelUploadPolicy(policy)
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing UploadPolicy
+		var query *gorm.DB
+		switch {
+		case mp.UserUUID != nil:
+			query = tx.Where("user_uuid = ?", *mp.UserUUID)
+		case mp.RoleID != nil:
+			query = tx.Where("role_id = ?", *mp.RoleID)
+		default:
+			return errors.New("role_id or user_uuid is required")
+		}
+		err := query.Take(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return tx.Create(mp).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		// Select("*") 使 nil 字段也写入（清除该项限制）
+		mp.ID = existing.ID
+		mp.CreatedAt = existing.CreatedAt
+		return tx.Select("*").Updates(mp).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return mp.ID, nil
+}
+
+func (r *uploadPolicyRepo) Delete(ctx context.Context, id int64) error {
+	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&UploadPolicy{}).Error
+}
+
+func toModelUploadPolicy(p *entity.UploadPolicy) *UploadPolicy {
+	mp := &UploadPolicy{
+		ID:               p.ID,
+		UserUUID:         p.UserUUID,
+		QuotaBytes:       p.QuotaBytes,
+		MaxFileSize:      p.MaxFileSize,
+		DailyUploadBytes: p.DailyUploadBytes,
+	}
+	if p.RoleID != nil {
+		roleID := int32(*p.RoleID)
+		mp.RoleID = &roleID
+	}
+	if p.AllowedMimeFamilies != nil {
+		families := strings.Join(p.AllowedMimeFamilies, ",")
+		mp.AllowedMimeFamilies = &families
+	}
+	return mp
+}
+
+func toEntityUploadPolicies(mps []UploadPolicy) []*entity.UploadPolicy {
+	policies := make([]*entity.UploadPolicy, len(mps))
+	for i, mp := range mps {
+		p := &entity.UploadPolicy{
+			ID:               mp.ID,
+			UserUUID:         mp.UserUUID,
+			QuotaBytes:       mp.QuotaBytes,
+			MaxFileSize:      mp.MaxFileSize,
+			DailyUploadBytes: mp.DailyUploadBytes,
+			CreatedAt:        mp.CreatedAt,
+			UpdatedAt:        mp.UpdatedAt,
+		}
+		if mp.RoleID != nil {
+			roleID := int(*mp.RoleID)
+			p.RoleID = &roleID
+		}
+		if mp.AllowedMimeFamilies != nil {
+			p.AllowedMimeFamilies = []string{}
+			if *mp.AllowedMimeFamilies != "" {
+				p.AllowedMimeFamilies = strings.Split(*mp.AllowedMimeFamilies, ",")
+			}
+		}
+		policies[i] = p
+	}
+	return policies
+}
@@ -0,0 +1,122 @@
+package persistence
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+
+	"server-blog-v2/internal/entity"
+	"server-blog-v2/internal/repo"
+)
+
+type uploadUsageRepo struct {
+	db *gorm.DB
+}
+
+// NewUploadUsageRepo 创建上传用量仓库。
+func NewUploadUsageRepo(db *gorm.DB) repo.UploadUsageRepo {
+	return &uploadUsageRepo{db: db}
+}
+
+func (r *uploadUsageRepo) CreateTask(ctx context.Context, task *entity.ResourceUploadTask, quotaBytes int64) (bool, error) {
+	created := false
+	err := r.withinQuota(ctx, task.UserUUID, task.FileSize, quotaBytes, func(tx *gorm.DB) error {
+		if err := tx.Create(toModelTask(task)).Error; err != nil {
+			return err
+		}
+		created = true
+		return nil
+	})
+	return created, err
+}
+
+func (r *uploadUsageRepo) CreateResource(ctx context.Context, resource *entity.Resource, quotaBytes int64) (int64, bool, error) {
+	var id int64
+	err := r.withinQuota(ctx, resource.UserUUID, resource.FileSize, quotaBytes, func(tx *gorm.DB) error {
+		mr := toModelResource(resource)
+		if err := tx.Create(mr).Error; err != nil {
+			return err
+		}
+		id = mr.ID
+		return nil
+	})
+	return id, id > 0, err
+}
+
+// withinQuota 在用户级事务锁内计算已用空间（资源与未完成任务），未超出配额时执行 create。
+// 同一用户的并发上传依次通过检查，不会同时占用同一份剩余配额。
+func (r *uploadUsageRepo) withinQuota(ctx context.Context, userUUID string, size, quotaBytes int64, create func(tx *gorm.DB) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if quotaBytes > 0 {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "upload_quota:"+userUUID).Error; err != nil {
+				return err
+			}
+
+			var used int64
+			if err := tx.Raw(`SELECT
+				(SELECT COALESCE(SUM(file_size), 0) FROM resources WHERE user_uuid = ? AND deleted_at IS NULL) +
+				(SELECT COALESCE(SUM(file_size), 0) FROM resource_upload_tasks WHERE user_uuid = ? AND status IN ? AND expires_at > ?)`,
+				userUUID, userUUID, pendingTaskStatuses, time.Now()).Scan(&used).Error; err != nil {
+				return err
+			}
+			if used+size > quotaBytes {
+				return nil
+			}
+		}
+		return create(tx)
+	})
+}
+
+// pendingTaskStatuses 仍占用配额的任务状态（初始化、上传中）。
+var pendingTaskStatuses = []int8{int8(entity.TaskStatusInit), int8(entity.TaskStatusUploading)}
+
+func (r *uploadUsageRepo) SumPending(ctx context.Context, userUUID string, now time.Time) (int64, error) {
+	var total int64
+	if err := r.db.WithContext(ctx).Model(&ResourceUploadTask{}).
+		Where("user_uuid = ? AND status IN ? AND expires_at > ?", userUUID, pendingTaskStatuses, now).
+		Select("COALESCE(SUM(file_size), 0)").Scan(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+func (r *uploadUsageRepo) ReserveDaily(ctx context.Context, userUUID string, day time.Time, size, limit int64) (bool, error) {
+	if limit <= 0 {
+		limit = math.MaxInt64
+	}
+	if size > limit {
+		return false, nil
+	}
+
+	// 冲突时仅在累加后不超过上限才更新，未更新的行不会出现在 RETURNING 中
+	var rows []int64
+	err := r.db.WithContext(ctx).Raw(`INSERT INTO upload_daily_usage (user_uuid, day, bytes, updated_at)
+		VALUES (?, ?, ?, NOW())
+		ON CONFLICT (user_uuid, day) DO UPDATE
+		SET bytes = upload_daily_usage.bytes + EXCLUDED.bytes, updated_at = NOW()
+		WHERE upload_daily_usage.bytes <= ? - EXCLUDED.bytes
+		RETURNING bytes`,
+		userUUID, day.Format(time.DateOnly), size, limit).Scan(&rows).Error
+	if err != nil {
+		return false, err
+	}
+	return len(rows) > 0, nil
+}
+
+func (r *uploadUsageRepo) ReleaseDaily(ctx context.Context, userUUID string, day time.Time, size int64) error {
+	return r.db.WithContext(ctx).Exec(`UPDATE upload_daily_usage
+		SET bytes = GREATEST(bytes - ?, 0), updated_at = NOW()
+		WHERE user_uuid = ? AND day = ?`,
+		size, userUUID, day.Format(time.DateOnly)).Error
+}
+
+func (r *uploadUsageRepo) GetDaily(ctx context.Context, userUUID string, day time.Time) (int64, error) {
+	var total int64
+	if err := r.db.WithContext(ctx).Raw("SELECT COALESCE(SUM(bytes), 0) FROM upload_daily_usage WHERE user_uuid = ? AND day = ?",
+		userUUID, day.Format(time.DateOnly)).Scan(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
@@ -25,7 +25,7 @@ func (r *userRepo) GetByUUID(ctx context.Context, uuid string) (*entity.User, er
 	u := r.query.User
 	row, err := u.WithContext(ctx).Where(u.UUID.Eq(uuid)).First()
 	if err != nil {
-		return nil, err
+		return nil, wrapNotFound(err)
 	}
 	return toEntityUser(row), nil
 }
@@ -113,7 +113,7 @@ func (r *userRepo) GetRoleByUUID(ctx context.Context, uuid string) (int, error)
 	u := r.query.User
 	row, err := u.WithContext(ctx).Where(u.UUID.Eq(uuid)).First()
 	if err != nil {
-		return 0, err
+		return 0, wrapNotFound(err)
 	}
 	if row.RoleID != nil {
 		return int(*row.RoleID), nil
@@ -128,6 +128,7 @@ func toModelUser(u *entity.User) *model.User {
 		UUID:     u.UUID,
 		Nickname: &u.Nickname,
 		Avatar:   &u.Avatar,
+		Bio:      &u.Bio,
 		RoleID:   &roleID,
 		Status:   &u.Status,
 	}
@@ -149,6 +150,9 @@ func toEntityUser(mu *model.User) *entity.User {
 	if mu.Avatar != nil {
 		user.Avatar = *mu.Avatar
 	}
+	if mu.Bio != nil {
+		user.Bio = *mu.Bio
+	}
 	if mu.RoleID != nil {
 		user.RoleID = int(*mu.RoleID)
 	}
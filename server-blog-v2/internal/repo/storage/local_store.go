@@ -0,0 +1,273 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"server-blog-v2/internal/repo"
+	"server-blog-v2/pkg/urlsign"
+)
+
+var errInvalidKey = errors.New("invalid object key")
+
+type localStore struct {
+	root       string
+	baseURL    string
+	signSecret string
+}
+
+// NewLocalStore 创建本地磁盘存储，文件通过博客自身的静态路由（带签名）访问。
+func NewLocalStore(root, baseURL, signSecret string) repo.ObjectStore {
+	return &localStore{
+		root:       root,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		signSecret: signSecret,
+	}
+}
+
+func (s *localStore) Upload(ctx context.Context, key string, data io.Reader, size int64, contentType string) (string, error) {
+	dst, err := s.path(key)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.writeFile(dst, func(w io.Writer) error {
+		n, err := io.Copy(w, data)
+		if err != nil {
+			return err
+		}
+		if size > 0 && n != size {
+			return fmt.Errorf("size mismatch, expected %d, got %d", size, n)
+		}
+		return nil
+	}); err != nil {
+		return "", fmt.Errorf("local upload error: %w", err)
+	}
+
+	return s.GetURL(key), nil
+}
+
+func (s *localStore) Delete(ctx context.Context, key string) error {
+	dst, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(dst); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// GetURL 返回带签名的永久访问地址。私有对象的永久地址会被静态路由拒绝，需使用 SignedURL。
+func (s *localStore) GetURL(key string) string {
+	return fmt.Sprintf("%s/%s?sign=%s", s.baseURL, escapeKey(key), urlsign.Sign(s.signSecret, key, 0))
+}
+
+// SignedURL 返回带过期时间的签名地址，由静态路由校验。
+func (s *localStore) SignedURL(key string, ttl time.Duration) string {
+	expires := time.Now().Add(ttl).Unix()
+	return fmt.Sprintf("%s/%s?sign=%s&expires=%d", s.baseURL, escapeKey(key), urlsign.Sign(s.signSecret, key, expires), expires)
+}
+
+func (s *localStore) Get(ctx context.Context, key string) (io.ReadCloser, int64, error) {
+	dst, err := s.path(key)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	f, err := os.Open(dst)
+	if err != nil {
+		return nil, 0, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, 0, err
+	}
+	return f, info.Size(), nil
+}
+
+// Copy 复制文件，先写临时文件再重命名。
+func (s *localStore) Copy(ctx context.Context, srcKey, dstKey string) error {
+	src, err := s.path(srcKey)
+	if err != nil {
+		return err
+	}
+	dst, err := s.path(dstKey)
+	if err != nil {
+		return err
+	}
+	if err := s.writeFile(dst, func(w io.Writer) error {
+		_, err := copyFile(w, src)
+		return err
+	}); err != nil {
+		return fmt.Errorf("local copy error: %w", err)
+	}
+	return nil
+}
+
+// UploadBlock 将块写入临时目录，返回块 Key 作为 Context。
+func (s *localStore) UploadBlock(ctx context.Context, data io.Reader, size int64) (string, error) {
+	blockKey := path.Join(BlockPathPrefix, uuid.New().String())
+	dst, err := s.path(blockKey)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.writeFile(dst, func(w io.Writer) error {
+		n, err := io.Copy(w, data)
+		if err != nil {
+			return err
+		}
+		if n != size {
+			return fmt.Errorf("block size mismatch, expected %d, got %d", size, n)
+		}
+		return nil
+	}); err != nil {
+		return "", fmt.Errorf("upload block error: %w", err)
+	}
+
+	return blockKey, nil
+}
+
+// MergeBlocks 按顺序拼接所有块为最终文件，返回 qetag。
+func (s *localStore) MergeBlocks(ctx context.Context, fileSize int64, fileKey string, contexts []string) (string, error) {
+	dst, err := s.path(fileKey)
+	if err != nil {
+		return "", err
+	}
+
+	blocks := make([]string, len(contexts))
+	for i, blockKey := range contexts {
+		if !strings.HasPrefix(blockKey, BlockPathPrefix+"/") {
+			return "", fmt.Errorf("invalid block context: %s", blockKey)
+		}
+		if blocks[i], err = s.path(blockKey); err != nil {
+			return "", err
+		}
+	}
+
+	hasher := newQetagHasher()
+	if err := s.writeFile(dst, func(w io.Writer) error {
+		var total int64
+		for _, block := range blocks {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			n, err := copyFile(io.MultiWriter(w, hasher), block)
+			if err != nil {
+				return err
+			}
+			total += n
+		}
+		if total != fileSize {
+			return fmt.Errorf("file size mismatch, expected %d, got %d", fileSize, total)
+		}
+		return nil
+	}); err != nil {
+		return "", fmt.Errorf("merge blocks error: %w", err)
+	}
+
+	for _, block := range blocks {
+		_ = os.Remove(block)
+	}
+
+	return hasher.Sum(), nil
+}
+
+// DeleteBlocks 删除未合并的临时块。
+func (s *localStore) DeleteBlocks(ctx context.Context, contexts []string) error {
+	for _, blockKey := range contexts {
+		if !strings.HasPrefix(blockKey, BlockPathPrefix+"/") {
+			continue
+		}
+		if err := s.Delete(ctx, blockKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// VerifyHash 重新计算文件 qetag 并与前端传的值比较。
+func (s *localStore) VerifyHash(ctx context.Context, clientHash, fileKey string) (bool, error) {
+	dst, err := s.path(fileKey)
+	if err != nil {
+		return false, err
+	}
+
+	f, err := os.Open(dst)
+	if err != nil {
+		return false, fmt.Errorf("open file error: %w", err)
+	}
+	defer f.Close()
+
+	hash, err := computeQetag(f)
+	if err != nil {
+		return false, fmt.Errorf("compute hash error: %w", err)
+	}
+	return hash == clientHash, nil
+}
+
+// GenerateFileKey 生成文件存储 Key。
+func (s *localStore) GenerateFileKey(fileName, fileHash string) string {
+	return generateFileKey(fileName, fileHash)
+}
+
+// path 将对象 Key 转换为根目录下的文件路径，拒绝越界访问。
+func (s *localStore) path(key string) (string, error) {
+	cleaned := path.Clean("/" + key)
+	if cleaned == "/" || cleaned != "/"+key {
+		return "", fmt.Errorf("%w: %s", errInvalidKey, key)
+	}
+	return filepath.Join(s.root, filepath.FromSlash(cleaned)), nil
+}
+
+// writeFile 先写入临时文件再重命名，避免读到写了一半的文件。
+func (s *localStore) writeFile(dst string, write func(w io.Writer) error) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), dst)
+}
+
+func copyFile(w io.Writer, name string) (int64, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	return io.Copy(w, f)
+}
+
+// escapeKey 逐段转义对象 Key，保留路径分隔符。
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, seg := range segments {
+		segments[i] = url.PathEscape(seg)
+	}
+	return strings.Join(segments, "/")
+}
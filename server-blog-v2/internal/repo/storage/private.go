@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"server-blog-v2/internal/entity"
+	"server-blog-v2/internal/repo"
+)
+
+// DefaultPrivateBaseURL 私有对象稳定地址的默认前缀（博客 /api/v1/media 路由）。
+const DefaultPrivateBaseURL = "/api/v1/media"
+
+// privateLinkStore 私有对象（private/ 前缀）的 GetURL 返回博客自身的稳定地址，
+// 访问时校验登录后跳转到新签发的签名地址，可长期嵌入文章；签名地址仍由 SignedURL 直接生成。
+type privateLinkStore struct {
+	repo.ObjectStore
+	baseURL string
+}
+
+// WithPrivateLinks 包装对象存储，baseURL 为空时使用 DefaultPrivateBaseURL。
+func WithPrivateLinks(store repo.ObjectStore, baseURL string) repo.ObjectStore {
+	if baseURL == "" {
+		baseURL = DefaultPrivateBaseURL
+	}
+	return &privateLinkStore{ObjectStore: store, baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+func (s *privateLinkStore) Upload(ctx context.Context, key string, data io.Reader, size int64, contentType string) (string, error) {
+	if _, err := s.ObjectStore.Upload(ctx, key, data, size, contentType); err != nil {
+		return "", err
+	}
+	return s.GetURL(key), nil
+}
+
+func (s *privateLinkStore) GetURL(key string) string {
+	if entity.IsPrivateKey(key) {
+		return s.baseURL + "/" + escapeKey(key)
+	}
+	return s.ObjectStore.GetURL(key)
+}
@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"io"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// BlockPathPrefix 分片临时存储路径前缀（本地与 S3 后端使用）
+	BlockPathPrefix = "tmp/blocks"
+
+	// qetagBlockSize qetag 分块大小（4MB），与前端计算方式一致
+	qetagBlockSize = 4 << 20
+)
+
+// qetagHasher 按七牛 qetag 算法计算文件 hash，便于非七牛后端复用前端传来的校验值。
+type qetagHasher struct {
+	block     hash.Hash
+	blockSize int
+	sums      []byte
+}
+
+func newQetagHasher() *qetagHasher {
+	return &qetagHasher{block: sha1.New()}
+}
+
+func (h *qetagHasher) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		remain := qetagBlockSize - h.blockSize
+		if remain > len(p) {
+			remain = len(p)
+		}
+		h.block.Write(p[:remain])
+		h.blockSize += remain
+		p = p[remain:]
+
+		if h.blockSize == qetagBlockSize {
+			h.sums = h.block.Sum(h.sums)
+			h.block.Reset()
+			h.blockSize = 0
+		}
+	}
+	return n, nil
+}
+
+// Sum 返回 qetag：单块为 0x16 + sha1，多块为 0x96 + sha1(各块 sha1 拼接)。
+func (h *qetagHasher) Sum() string {
+	sums := h.sums
+	if h.blockSize > 0 || len(sums) == 0 {
+		sums = h.block.Sum(sums)
+	}
+
+	var buf []byte
+	if len(sums) == sha1.Size {
+		buf = append([]byte{0x16}, sums...)
+	} else {
+		all := sha1.Sum(sums)
+		buf = append([]byte{0x96}, all[:]...)
+	}
+	return base64.URLEncoding.EncodeToString(buf)
+}
+
+// computeQetag 读取全部数据并计算 qetag。
+func computeQetag(r io.Reader) (string, error) {
+	h := newQetagHasher()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return h.Sum(), nil
+}
+
+// generateFileKey 生成资源文件 Key，各存储后端共用。
+func generateFileKey(fileName, fileHash string) string {
+	ext := filepath.Ext(fileName)
+	if fileHash != "" {
+		return fmt.Sprintf("%s/%s%s", ResourcePathPrefix, fileHash, ext)
+	}
+	timestamp := time.Now().Format("20060102150405")
+	return fmt.Sprintf("%s/%s%s", ResourcePathPrefix, timestamp, ext)
+}
@@ -4,50 +4,65 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
-	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/qiniu/go-sdk/v7/auth/qbox"
 	"github.com/qiniu/go-sdk/v7/storage"
 
+	"server-blog-v2/internal/entity"
 	"server-blog-v2/internal/repo"
 )
 
 const (
 	// ResourcePathPrefix 资源存储路径前缀
 	ResourcePathPrefix = "resource"
+
+	// qiniuGetURLTTL 服务端读取对象时签名地址的有效期
+	qiniuGetURLTTL = 10 * time.Minute
+
+	// qiniuNoSuchFile 七牛云对象不存在的状态码
+	qiniuNoSuchFile = 612
 )
 
 type qiniuStore struct {
-	mac        *qbox.Mac
-	bucket     string
-	domain     string
-	useHTTPS   bool
-	pathPrefix string
-	zone       string
+	mac           *qbox.Mac
+	bucket        string
+	domain        string
+	privateBucket string
+	privateDomain string
+	useHTTPS      bool
+	pathPrefix    string
+	zone          string
 }
 
-// NewQiniuStore 创建七牛云存储。
-func NewQiniuStore(accessKey, secretKey, bucket, domain, zone string, useHTTPS bool, pathPrefix string) repo.ObjectStore {
+// NewQiniuStore 创建七牛云存储。privateBucket/privateDomain 为存放私有对象（private/ 前缀）的私有空间，
+// 为空时私有对象与公开对象在同一空间。
+func NewQiniuStore(accessKey, secretKey, bucket, domain, privateBucket, privateDomain, zone string, useHTTPS bool, pathPrefix string) repo.ObjectStore {
 	mac := qbox.NewMac(accessKey, secretKey)
+	if privateBucket == "" || privateDomain == "" {
+		privateBucket, privateDomain = bucket, domain
+	}
 	return &qiniuStore{
-		mac:        mac,
-		bucket:     bucket,
-		domain:     domain,
-		useHTTPS:   useHTTPS,
-		pathPrefix: pathPrefix,
-		zone:       zone,
+		mac:           mac,
+		bucket:        bucket,
+		domain:        domain,
+		privateBucket: privateBucket,
+		privateDomain: privateDomain,
+		useHTTPS:      useHTTPS,
+		pathPrefix:    pathPrefix,
+		zone:          zone,
 	}
 }
 
 func (s *qiniuStore) Upload(ctx context.Context, key string, data io.Reader, size int64, contentType string) (string, error) {
 	// 生成上传凭证
 	putPolicy := storage.PutPolicy{
-		Scope: fmt.Sprintf("%s:%s", s.bucket, key),
+		Scope: fmt.Sprintf("%s:%s", s.bucketFor(key), key),
 	}
 	upToken := putPolicy.UploadToken(s.mac)
 
@@ -78,15 +93,70 @@ func (s *qiniuStore) Delete(ctx context.Context, key string) error {
 		UseHTTPS: s.useHTTPS,
 	}
 	bucketManager := storage.NewBucketManager(s.mac, &cfg)
-	return bucketManager.Delete(s.bucket, key)
+	err := bucketManager.Delete(s.bucketFor(key), key)
+	// 与本地存储、S3 一致，对象不存在视为已删除
+	var info *storage.ErrorInfo
+	if errors.As(err, &info) && info.Code == qiniuNoSuchFile {
+		return nil
+	}
+	return err
 }
 
 func (s *qiniuStore) GetURL(key string) string {
-	scheme := "http"
+	return fmt.Sprintf("%s/%s", s.baseURL(key), key)
+}
+
+// baseURL 返回对象所在空间的访问域名。
+func (s *qiniuStore) baseURL(key string) string {
+	domain := s.domain
+	if entity.IsPrivateKey(key) {
+		domain = s.privateDomain
+	}
 	if s.useHTTPS {
-		scheme = "https"
+		return "https://" + domain
+	}
+	return "http://" + domain
+}
+
+// bucketFor 返回对象所在的空间，私有对象使用私有空间。
+func (s *qiniuStore) bucketFor(key string) string {
+	if entity.IsPrivateKey(key) {
+		return s.privateBucket
+	}
+	return s.bucket
+}
+
+// SignedURL 返回七牛云私有空间下载地址，需要空间本身设为私有才能阻止直接访问。
+func (s *qiniuStore) SignedURL(key string, ttl time.Duration) string {
+	return storage.MakePrivateURL(s.mac, s.baseURL(key), key, time.Now().Add(ttl).Unix())
+}
+
+// Copy 在空间内或公开、私有空间之间复制对象。
+func (s *qiniuStore) Copy(ctx context.Context, srcKey, dstKey string) error {
+	cfg := storage.Config{UseHTTPS: s.useHTTPS}
+	bucketManager := storage.NewBucketManager(s.mac, &cfg)
+	if err := bucketManager.Copy(s.bucketFor(srcKey), srcKey, s.bucketFor(dstKey), dstKey, true); err != nil {
+		return fmt.Errorf("qiniu copy error: %w", err)
+	}
+	return nil
+}
+
+// Get 通过访问域名下载对象。使用短期签名地址，私有空间同样可读（公开空间会忽略签名参数）。
+func (s *qiniuStore) Get(ctx context.Context, key string) (io.ReadCloser, int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.SignedURL(key, qiniuGetURLTTL), nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("create request error: %w", err)
 	}
-	return fmt.Sprintf("%s://%s/%s", scheme, s.domain, key)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("qiniu get error: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, 0, fmt.Errorf("qiniu get failed, status: %d", resp.StatusCode)
+	}
+	return resp.Body, resp.ContentLength, nil
 }
 
 // UploadBlock 流式上传块到七牛云，返回 Context。
@@ -131,8 +201,24 @@ func (s *qiniuStore) UploadBlock(ctx context.Context, data io.Reader, size int64
 	return ctxStr, nil
 }
 
-// MergeBlocks 合并所有块为最终文件。
+// MergeBlocks 合并所有块为最终文件。块随公开空间的凭证上传，私有对象先合并到公开空间再移动到私有空间。
 func (s *qiniuStore) MergeBlocks(ctx context.Context, fileSize int64, fileKey string, contexts []string) (string, error) {
+	hash, err := s.mkfile(ctx, fileSize, fileKey, contexts)
+	if err != nil || s.bucketFor(fileKey) == s.bucket {
+		return hash, err
+	}
+
+	cfg := storage.Config{UseHTTPS: s.useHTTPS}
+	bucketManager := storage.NewBucketManager(s.mac, &cfg)
+	if err := bucketManager.Move(s.bucket, fileKey, s.privateBucket, fileKey, true); err != nil {
+		_ = bucketManager.Delete(s.bucket, fileKey)
+		return "", fmt.Errorf("move to private bucket error: %w", err)
+	}
+	return hash, nil
+}
+
+// mkfile 在公开空间中合并块。
+func (s *qiniuStore) mkfile(ctx context.Context, fileSize int64, fileKey string, contexts []string) (string, error) {
 	putPolicy := storage.PutPolicy{Scope: s.bucket}
 	upToken := putPolicy.UploadToken(s.mac)
 
@@ -170,13 +256,18 @@ func (s *qiniuStore) MergeBlocks(ctx context.Context, fileSize int64, fileKey st
 	return ret.Hash, nil
 }
 
+// DeleteBlocks 七牛云的块 Context 在服务端 7 天后自动失效，无需删除。
+func (s *qiniuStore) DeleteBlocks(ctx context.Context, contexts []string) error {
+	return nil
+}
+
 // VerifyHash 验证文件 hash（clientHash 是前端传的 qetag）。
 func (s *qiniuStore) VerifyHash(ctx context.Context, clientHash, fileKey string) (bool, error) {
 	// 获取文件元数据
 	cfg := storage.Config{UseHTTPS: s.useHTTPS}
 	bucketManager := storage.NewBucketManager(s.mac, &cfg)
 
-	fileInfo, err := bucketManager.Stat(s.bucket, fileKey)
+	fileInfo, err := bucketManager.Stat(s.bucketFor(fileKey), fileKey)
 	if err != nil {
 		return false, fmt.Errorf("get file info error: %w", err)
 	}
@@ -187,12 +278,7 @@ func (s *qiniuStore) VerifyHash(ctx context.Context, clientHash, fileKey string)
 
 // GenerateFileKey 生成文件存储 Key。
 func (s *qiniuStore) GenerateFileKey(fileName, fileHash string) string {
-	ext := filepath.Ext(fileName)
-	if fileHash != "" {
-		return fmt.Sprintf("%s/%s%s", ResourcePathPrefix, fileHash, ext)
-	}
-	timestamp := time.Now().Format("20060102150405")
-	return fmt.Sprintf("%s/%s%s", ResourcePathPrefix, timestamp, ext)
+	return generateFileKey(fileName, fileHash)
 }
 
 // getUpHost 获取上传域名。
@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"fmt"
+
+	"server-blog-v2/config"
+	"server-blog-v2/internal/repo"
+)
+
+// NewFromConfig 按存储类型创建对象存储，ossType 为空时使用七牛云。私有对象的 GetURL 返回博客的稳定地址。
+func NewFromConfig(cfg *config.Config, ossType string) (repo.ObjectStore, error) {
+	store, err := newStore(cfg, ossType)
+	if err != nil {
+		return nil, err
+	}
+	return WithPrivateLinks(store, cfg.Upload.PrivateBaseURL), nil
+}
+
+func newStore(cfg *config.Config, ossType string) (repo.ObjectStore, error) {
+	switch ossType {
+	case config.OssTypeLocal:
+		return NewLocalStore(cfg.Local.Root, cfg.Local.BaseURL, cfg.Local.SignSecret), nil
+	case config.OssTypeS3:
+		return NewS3Store(
+			cfg.S3.Endpoint,
+			cfg.S3.Region,
+			cfg.S3.AccessKey,
+			cfg.S3.SecretKey,
+			cfg.S3.Bucket,
+			cfg.S3.PrivateBucket,
+			cfg.S3.PathStyle,
+			cfg.S3.PublicURL,
+		)
+	case "", config.OssTypeQiniu:
+		return NewQiniuStore(
+			cfg.Qiniu.AccessKey,
+			cfg.Qiniu.SecretKey,
+			cfg.Qiniu.Bucket,
+			cfg.Qiniu.Domain,
+			cfg.Qiniu.PrivateBucket,
+			cfg.Qiniu.PrivateDomain,
+			cfg.Qiniu.Zone,
+			cfg.Qiniu.UseHTTPS,
+			"",
+		), nil
+	default:
+		return nil, fmt.Errorf("unsupported oss_type: %s", ossType)
+	}
+}
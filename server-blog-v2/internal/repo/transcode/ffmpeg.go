@@ -0,0 +1,438 @@
+package transcode
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"server-blog-v2/internal/entity"
+	"server-blog-v2/internal/repo"
+	"server-blog-v2/pkg/hls"
+	"server-blog-v2/pkg/logger"
+	"server-blog-v2/pkg/redis"
+)
+
+const (
+	_defaultWorkers    = 1
+	_defaultHLSTime    = 6
+	_defaultMaxRetries = 3
+	_defaultRetryDelay = 30 * time.Second
+	_queueSize         = 1024
+	// _rescanInterval 定期重新排队仍在转码中的资源的间隔
+	_rescanInterval = 10 * time.Minute
+	// _lockTTL 单个转码任务的锁有效期，防止多实例重复转码
+	_lockTTL = 2 * time.Hour
+
+	// HLSPlaylistName HLS 播放列表文件名
+	HLSPlaylistName = "index.m3u8"
+	// HLSDirSuffix HLS 产物目录后缀
+	HLSDirSuffix = "_hls"
+)
+
+// FFmpegOptions 本地 ffmpeg 转码配置。
+type FFmpegOptions struct {
+	FFmpegPath string        // 为空时从 PATH 查找
+	WorkDir    string        // 临时目录，为空时使用系统临时目录
+	Workers    int           // 并发转码数
+	HLSTime    int           // HLS 分片时长（秒）
+	MaxRetries int           // 失败重试次数
+	RetryDelay time.Duration // 首次重试间隔，之后翻倍
+}
+
+type transcodeJob struct {
+	resourceID int64
+	attempt    int
+}
+
+type ffmpegTranscoder struct {
+	bin         string
+	workDir     string
+	workers     int
+	hlsTime     int
+	maxRetries  int
+	retryDelay  time.Duration
+	resources   repo.ResourceRepo
+	objectStore repo.ObjectStore
+	locker      redis.Locker
+	logger      logger.Interface
+
+	queue  chan transcodeJob
+	mu     sync.Mutex
+	queued map[int64]struct{} // 在队列中、转码中或等待重试的资源，避免重复排队
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewFFmpegTranscoder 创建本地 ffmpeg 转码器：生成 HLS（H.264/AAC）与封面图，
+// 上传到对象存储后通过 UpdateTranscodeStatus 写回，失败按指数退避重试。
+func NewFFmpegTranscoder(opts FFmpegOptions, resources repo.ResourceRepo, objectStore repo.ObjectStore, locker redis.Locker, l logger.Interface) (repo.Transcoder, error) {
+	bin := opts.FFmpegPath
+	if bin == "" {
+		bin = "ffmpeg"
+	}
+	bin, err := exec.LookPath(bin)
+	if err != nil {
+		return nil, fmt.Errorf("ffmpeg not found: %w", err)
+	}
+
+	t := &ffmpegTranscoder{
+		bin:         bin,
+		workDir:     opts.WorkDir,
+		workers:     opts.Workers,
+		hlsTime:     opts.HLSTime,
+		maxRetries:  opts.MaxRetries,
+		retryDelay:  opts.RetryDelay,
+		resources:   resources,
+		objectStore: objectStore,
+		locker:      locker,
+		logger:      l,
+		queue:       make(chan transcodeJob, _queueSize),
+		queued:      make(map[int64]struct{}),
+	}
+	if t.workers <= 0 {
+		t.workers = _defaultWorkers
+	}
+	if t.hlsTime <= 0 {
+		t.hlsTime = _defaultHLSTime
+	}
+	if t.maxRetries < 0 {
+		t.maxRetries = 0
+	} else if t.maxRetries == 0 {
+		t.maxRetries = _defaultMaxRetries
+	}
+	if t.retryDelay <= 0 {
+		t.retryDelay = _defaultRetryDelay
+	}
+	t.ctx, t.cancel = context.WithCancel(context.Background())
+
+	return t, nil
+}
+
+// Submit 将任务放入队列。队列已满时资源保持转码中，由定期扫描重新排队，不视为失败。
+func (t *ffmpegTranscoder) Submit(ctx context.Context, resource *entity.Resource) error {
+	if !t.offer(resource.ID) {
+		t.logger.Warn("transcode - ffmpeg - queue is full, resource %d left for the next rescan", resource.ID)
+	}
+	return nil
+}
+
+func (t *ffmpegTranscoder) DeleteOutputs(ctx context.Context, resource *entity.Resource) error {
+	return deleteOutputs(ctx, t.objectStore, resource)
+}
+
+func (t *ffmpegTranscoder) CopyOutputs(ctx context.Context, resource *entity.Resource, access string) (string, string, error) {
+	return copyOutputs(ctx, t.objectStore, resource, access)
+}
+
+// Start 启动 worker，并立即及定期重新排队仍在转码中的资源。
+func (t *ffmpegTranscoder) Start() {
+	for i := 0; i < t.workers; i++ {
+		t.wg.Add(1)
+		go t.work()
+	}
+
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+
+		ticker := time.NewTicker(_rescanInterval)
+		defer ticker.Stop()
+		for {
+			t.rescan()
+			select {
+			case <-ticker.C:
+			case <-t.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// rescan 重新排队仍在转码中的资源：上次退出时未完成、提交时队列已满，
+// 或其他实例持锁后中断的任务。正在本实例处理的资源不会重复排队。
+func (t *ffmpegTranscoder) rescan() {
+	processing, err := t.resources.ListByTranscodeStatus(t.ctx, entity.TranscodeStatusProcessing)
+	if err != nil {
+		t.logger.Error(err, "transcode - ffmpeg - ListByTranscodeStatus")
+		return
+	}
+	for i, r := range processing {
+		if !t.offer(r.ID) {
+			t.logger.Warn("transcode - ffmpeg - queue is full, %d resources left for the next rescan", len(processing)-i)
+			return
+		}
+	}
+}
+
+// Stop 停止 worker，正在执行的 ffmpeg 会被终止，资源保持转码中，下次启动时重新处理。
+func (t *ffmpegTranscoder) Stop() {
+	t.cancel()
+	t.wg.Wait()
+}
+
+// offer 非阻塞地排队，已在处理中的资源直接返回 true；队列已满时返回 false。
+func (t *ffmpegTranscoder) offer(resourceID int64) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.queued[resourceID]; ok {
+		return true
+	}
+	select {
+	case t.queue <- transcodeJob{resourceID: resourceID}:
+		t.queued[resourceID] = struct{}{}
+		return true
+	default:
+		return false
+	}
+}
+
+// done 资源处理结束（成功、放弃或交给其他实例），之后可再次排队。
+func (t *ffmpegTranscoder) done(resourceID int64) {
+	t.mu.Lock()
+	delete(t.queued, resourceID)
+	t.mu.Unlock()
+}
+
+func (t *ffmpegTranscoder) enqueue(job transcodeJob) {
+	select {
+	case t.queue <- job:
+	case <-t.ctx.Done():
+	}
+}
+
+func (t *ffmpegTranscoder) work() {
+	defer t.wg.Done()
+
+	for {
+		select {
+		case <-t.ctx.Done():
+			return
+		case job := <-t.queue:
+			t.handle(job)
+		}
+	}
+}
+
+func (t *ffmpegTranscoder) handle(job transcodeJob) {
+	lockName := "transcode:" + strconv.FormatInt(job.resourceID, 10)
+	token, ok, err := t.locker.TryLock(t.ctx, lockName, _lockTTL)
+	if err != nil {
+		t.logger.Error(err, "transcode - ffmpeg - TryLock")
+	}
+	if !ok {
+		// Redis 不可用时稍后重试；其他实例正在处理时交给它，资源保持转码中，
+		// 该实例中断的话由定期扫描重新排队
+		if err != nil {
+			t.retry(job, err)
+			return
+		}
+		t.done(job.resourceID)
+		return
+	}
+
+	err = t.transcode(t.ctx, job.resourceID)
+	_ = t.locker.Unlock(context.Background(), lockName, token)
+
+	if err == nil || t.ctx.Err() != nil {
+		t.done(job.resourceID)
+		return
+	}
+	t.retry(job, err)
+}
+
+// retry 按指数退避重新排队，超过重试次数后标记为转码失败。
+func (t *ffmpegTranscoder) retry(job transcodeJob, cause error) {
+	if job.attempt >= t.maxRetries {
+		t.logger.Error(cause, "transcode - ffmpeg - giving up", "resource_id", job.resourceID, "attempts", job.attempt+1)
+		if err := t.resources.UpdateTranscodeStatus(context.Background(), job.resourceID, entity.TranscodeStatusFailed, "", ""); err != nil {
+			t.logger.Error(err, "transcode - ffmpeg - UpdateTranscodeStatus")
+		}
+		t.done(job.resourceID)
+		return
+	}
+
+	delay := t.retryDelay << job.attempt
+	t.logger.Warn("transcode - ffmpeg - resource %d failed (attempt %d), retry in %s: %v", job.resourceID, job.attempt+1, delay, cause)
+
+	job.attempt++
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+			t.enqueue(job)
+		case <-t.ctx.Done():
+		}
+	}()
+}
+
+func (t *ffmpegTranscoder) transcode(ctx context.Context, resourceID int64) error {
+	resource, err := t.resources.GetByID(ctx, resourceID)
+	if err != nil {
+		return fmt.Errorf("get resource error: %w", err)
+	}
+	if resource.TranscodeStatus != entity.TranscodeStatusProcessing {
+		// 已删除重传或已被其他实例完成
+		return nil
+	}
+
+	dir, err := os.MkdirTemp(t.workDir, "transcode-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	input := filepath.Join(dir, "input"+path.Ext(resource.FileKey))
+	if err := t.download(ctx, resource.FileKey, input); err != nil {
+		return err
+	}
+
+	hlsDir := filepath.Join(dir, "hls")
+	if err := os.Mkdir(hlsDir, 0o755); err != nil {
+		return err
+	}
+	if err := t.run(ctx,
+		"-i", input,
+		"-map", "0:v:0", "-map", "0:a:0?",
+		"-c:v", "libx264", "-preset", "veryfast", "-crf", "23", "-pix_fmt", "yuv420p",
+		"-c:a", "aac", "-b:a", "128k",
+		"-f", "hls",
+		"-hls_time", strconv.Itoa(t.hlsTime),
+		"-hls_playlist_type", "vod",
+		"-hls_segment_filename", filepath.Join(hlsDir, "seg_%04d.ts"),
+		filepath.Join(hlsDir, HLSPlaylistName),
+	); err != nil {
+		return err
+	}
+
+	thumb := filepath.Join(dir, "thumb.jpg")
+	if err := t.run(ctx, "-ss", "1", "-i", input, "-frames:v", "1", "-q:v", "2", thumb); err != nil || !fileExists(thumb) {
+		// 视频不足 1 秒时取第一帧
+		if err := t.run(ctx, "-i", input, "-frames:v", "1", "-q:v", "2", thumb); err != nil {
+			return err
+		}
+	}
+
+	base := baseKey(resource.FileKey)
+	prefix := base + HLSDirSuffix + "/"
+	playlistKey := prefix + HLSPlaylistName
+	thumbnailKey := base + ThumbnailSuffix
+
+	uploaded, err := t.uploadHLS(ctx, hlsDir, prefix)
+	if err == nil {
+		err = t.uploadFile(ctx, thumb, thumbnailKey, "image/jpeg")
+		uploaded = append(uploaded, thumbnailKey)
+	}
+	if err != nil {
+		for _, key := range uploaded {
+			_ = t.objectStore.Delete(context.Background(), key)
+		}
+		return err
+	}
+
+	if err := t.resources.UpdateTranscodeStatus(ctx, resource.ID, entity.TranscodeStatusSuccess, playlistKey, thumbnailKey); err != nil {
+		return fmt.Errorf("update transcode status error: %w", err)
+	}
+	return nil
+}
+
+// uploadHLS 上传分片，再上传改写为完整地址的播放列表（本地存储的地址带签名，不能使用相对路径）。
+func (t *ffmpegTranscoder) uploadHLS(ctx context.Context, hlsDir, prefix string) ([]string, error) {
+	entries, err := os.ReadDir(hlsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var segments []string
+	for _, e := range entries {
+		if strings.HasSuffix(e.Name(), ".ts") {
+			segments = append(segments, e.Name())
+		}
+	}
+	sort.Strings(segments)
+
+	var uploaded []string
+	for _, name := range segments {
+		key := prefix + name
+		if err := t.uploadFile(ctx, filepath.Join(hlsDir, name), key, "video/mp2t"); err != nil {
+			return uploaded, err
+		}
+		uploaded = append(uploaded, key)
+	}
+
+	playlist, err := os.ReadFile(filepath.Join(hlsDir, HLSPlaylistName))
+	if err != nil {
+		return uploaded, err
+	}
+
+	key := prefix + HLSPlaylistName
+	playlist = hls.Rewrite(playlist, key, t.objectStore.GetURL)
+	if _, err := t.objectStore.Upload(ctx, key, bytes.NewReader(playlist), int64(len(playlist)), "application/vnd.apple.mpegurl"); err != nil {
+		return uploaded, fmt.Errorf("upload playlist error: %w", err)
+	}
+	return append(uploaded, key), nil
+}
+
+func (t *ffmpegTranscoder) download(ctx context.Context, key, dst string) error {
+	body, _, err := t.objectStore.Get(ctx, key)
+	if err != nil {
+		return fmt.Errorf("download source error: %w", err)
+	}
+	defer body.Close()
+
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, body); err != nil {
+		f.Close()
+		return fmt.Errorf("download source error: %w", err)
+	}
+	return f.Close()
+}
+
+func (t *ffmpegTranscoder) uploadFile(ctx context.Context, name, key, contentType string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if _, err := t.objectStore.Upload(ctx, key, f, info.Size(), contentType); err != nil {
+		return fmt.Errorf("upload %s error: %w", key, err)
+	}
+	return nil
+}
+
+func (t *ffmpegTranscoder) run(ctx context.Context, args ...string) error {
+	args = append([]string{"-hide_banner", "-loglevel", "error", "-y"}, args...)
+	cmd := exec.CommandContext(ctx, t.bin, args...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("ffmpeg error: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+func fileExists(name string) bool {
+	info, err := os.Stat(name)
+	return err == nil && info.Size() > 0
+}
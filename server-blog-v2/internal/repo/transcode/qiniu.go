@@ -0,0 +1,82 @@
+package transcode
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/qiniu/go-sdk/v7/auth/qbox"
+	"github.com/qiniu/go-sdk/v7/storage"
+
+	"server-blog-v2/internal/entity"
+	"server-blog-v2/internal/repo"
+)
+
+const (
+	// QiniuTranscodeSuffix 七牛云转码产物后缀，HandleQiniuCallback 据此识别
+	QiniuTranscodeSuffix = "_h264.mp4"
+	// ThumbnailSuffix 封面后缀
+	ThumbnailSuffix = "_thumb.jpg"
+)
+
+type qiniuTranscoder struct {
+	mac           *qbox.Mac
+	bucket        string
+	privateBucket string
+	useHTTPS      bool
+	notifyURL     string
+	pipeline      string
+	objectStore   repo.ObjectStore
+}
+
+// NewQiniuTranscoder 创建七牛云转码器，结果由 /api/callback/qiniu/callback 回调写回。
+// notifyURL 为空时不主动提交 pfop，沿用空间工作流自动转码。私有资源在 privateBucket 中转码，为空时与 bucket 相同。
+func NewQiniuTranscoder(accessKey, secretKey, bucket, privateBucket string, useHTTPS bool, notifyURL, pipeline string, objectStore repo.ObjectStore) repo.Transcoder {
+	if privateBucket == "" {
+		privateBucket = bucket
+	}
+	return &qiniuTranscoder{
+		mac:           qbox.NewMac(accessKey, secretKey),
+		bucket:        bucket,
+		privateBucket: privateBucket,
+		useHTTPS:      useHTTPS,
+		notifyURL:     notifyURL,
+		pipeline:      pipeline,
+		objectStore:   objectStore,
+	}
+}
+
+func (t *qiniuTranscoder) Submit(ctx context.Context, resource *entity.Resource) error {
+	if t.notifyURL == "" {
+		return nil
+	}
+
+	bucket := t.bucket
+	if entity.IsPrivateKey(resource.FileKey) {
+		bucket = t.privateBucket
+	}
+	base := baseKey(resource.FileKey)
+	fops := strings.Join([]string{
+		"avthumb/mp4/vcodec/libx264|saveas/" + storage.EncodedEntry(bucket, base+QiniuTranscodeSuffix),
+		"vframe/jpg/offset/1|saveas/" + storage.EncodedEntry(bucket, base+ThumbnailSuffix),
+	}, ";")
+
+	cfg := storage.Config{UseHTTPS: t.useHTTPS}
+	manager := storage.NewOperationManager(t.mac, &cfg)
+	if _, err := manager.Pfop(bucket, resource.FileKey, fops, t.pipeline, t.notifyURL, false); err != nil {
+		return fmt.Errorf("qiniu pfop error: %w", err)
+	}
+	return nil
+}
+
+func (t *qiniuTranscoder) DeleteOutputs(ctx context.Context, resource *entity.Resource) error {
+	return deleteOutputs(ctx, t.objectStore, resource)
+}
+
+func (t *qiniuTranscoder) CopyOutputs(ctx context.Context, resource *entity.Resource, access string) (string, string, error) {
+	return copyOutputs(ctx, t.objectStore, resource, access)
+}
+
+func (t *qiniuTranscoder) Start() {}
+
+func (t *qiniuTranscoder) Stop() {}
@@ -0,0 +1,157 @@
+// Package transcode 视频转码后端。
+package transcode
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"server-blog-v2/config"
+	"server-blog-v2/internal/entity"
+	"server-blog-v2/internal/repo"
+	"server-blog-v2/pkg/hls"
+	"server-blog-v2/pkg/logger"
+	"server-blog-v2/pkg/redis"
+)
+
+// 转码后端（transcode.backend）
+const (
+	BackendQiniu  = "qiniu"
+	BackendFFmpeg = "ffmpeg"
+	BackendNone   = "none"
+)
+
+// NewFromConfig 按配置创建转码器，backend 为 none 时返回 nil（视频不转码）。
+func NewFromConfig(cfg *config.Config, objectStore repo.ObjectStore, resources repo.ResourceRepo, locker redis.Locker, l logger.Interface) (repo.Transcoder, error) {
+	backend := cfg.Transcode.Backend
+	if backend == "" {
+		backend = BackendNone
+		if cfg.System.OssType == "" || cfg.System.OssType == config.OssTypeQiniu {
+			backend = BackendQiniu
+		}
+	}
+
+	switch backend {
+	case BackendQiniu:
+		return NewQiniuTranscoder(
+			cfg.Qiniu.AccessKey,
+			cfg.Qiniu.SecretKey,
+			cfg.Qiniu.Bucket,
+			cfg.Qiniu.PrivateBucket,
+			cfg.Qiniu.UseHTTPS,
+			cfg.Transcode.NotifyURL,
+			cfg.Transcode.Pipeline,
+			objectStore,
+		), nil
+	case BackendFFmpeg:
+		return NewFFmpegTranscoder(FFmpegOptions{
+			FFmpegPath: cfg.Transcode.FFmpegPath,
+			WorkDir:    cfg.Transcode.WorkDir,
+			Workers:    cfg.Transcode.Workers,
+			HLSTime:    cfg.Transcode.HLSTime,
+			MaxRetries: cfg.Transcode.MaxRetries,
+			RetryDelay: cfg.Transcode.RetryDelay,
+		}, resources, objectStore, locker, l)
+	case BackendNone:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unsupported transcode backend: %s", backend)
+	}
+}
+
+// baseKey 去掉扩展名的文件 Key，用于生成转码产物 Key。
+func baseKey(fileKey string) string {
+	return strings.TrimSuffix(fileKey, path.Ext(fileKey))
+}
+
+// deleteOutputs 删除转码产物。HLS 播放列表中的分片与播放列表位于同一前缀下，逐个删除。
+// 单个对象删除失败时继续删除其余对象，返回合并后的错误。
+func deleteOutputs(ctx context.Context, objectStore repo.ObjectStore, resource *entity.Resource) error {
+	var errs []error
+	del := func(key string) {
+		if err := objectStore.Delete(ctx, key); err != nil {
+			errs = append(errs, fmt.Errorf("delete %s: %w", key, err))
+		}
+	}
+	if key := resource.TranscodeKey; key != "" {
+		if strings.HasSuffix(key, ".m3u8") {
+			for _, segment := range PlaylistSegments(ctx, objectStore, key) {
+				del(segment)
+			}
+		}
+		del(key)
+	}
+	if resource.ThumbnailKey != "" {
+		del(resource.ThumbnailKey)
+	}
+	return errors.Join(errs...)
+}
+
+// PlaylistSegments 读取 HLS 播放列表，返回分片的对象 Key。
+func PlaylistSegments(ctx context.Context, objectStore repo.ObjectStore, playlistKey string) []string {
+	body, _, err := objectStore.Get(ctx, playlistKey)
+	if err != nil {
+		return nil
+	}
+	defer body.Close()
+	return hls.SegmentKeys(body, playlistKey)
+}
+
+// copyOutputs 将转码产物复制到指定访问级别对应的 Key，返回新的转码 Key 与封面 Key。
+// HLS 分片一并复制，播放列表中的分片地址改写为新位置。
+func copyOutputs(ctx context.Context, objectStore repo.ObjectStore, resource *entity.Resource, access string) (string, string, error) {
+	var transcodeKey, thumbnailKey string
+	if key := resource.TranscodeKey; key != "" {
+		transcodeKey = entity.ObjectKeyForAccess(key, access)
+		var err error
+		if strings.HasSuffix(key, ".m3u8") {
+			err = copyPlaylist(ctx, objectStore, key, transcodeKey)
+		} else {
+			err = objectStore.Copy(ctx, key, transcodeKey)
+		}
+		if err != nil {
+			return "", "", err
+		}
+	}
+	if key := resource.ThumbnailKey; key != "" {
+		thumbnailKey = entity.ObjectKeyForAccess(key, access)
+		if err := objectStore.Copy(ctx, key, thumbnailKey); err != nil {
+			return "", "", err
+		}
+	}
+	return transcodeKey, thumbnailKey, nil
+}
+
+// copyPlaylist 复制 HLS 分片，并上传指向新分片地址的播放列表。
+func copyPlaylist(ctx context.Context, objectStore repo.ObjectStore, srcKey, dstKey string) error {
+	body, _, err := objectStore.Get(ctx, srcKey)
+	if err != nil {
+		return fmt.Errorf("read playlist error: %w", err)
+	}
+	playlist, err := io.ReadAll(body)
+	body.Close()
+	if err != nil {
+		return fmt.Errorf("read playlist error: %w", err)
+	}
+
+	srcPrefix, dstPrefix := path.Dir(srcKey)+"/", path.Dir(dstKey)+"/"
+	var copyErr error
+	rewritten := hls.Rewrite(playlist, dstKey, func(key string) string {
+		if copyErr == nil {
+			copyErr = objectStore.Copy(ctx, srcPrefix+strings.TrimPrefix(key, dstPrefix), key)
+		}
+		return objectStore.GetURL(key)
+	})
+	if copyErr != nil {
+		return fmt.Errorf("copy segment error: %w", copyErr)
+	}
+
+	if _, err := objectStore.Upload(ctx, dstKey, bytes.NewReader(rewritten), int64(len(rewritten)), "application/vnd.apple.mpegurl"); err != nil {
+		return fmt.Errorf("upload playlist error: %w", err)
+	}
+	return nil
+}
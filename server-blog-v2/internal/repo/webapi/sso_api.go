@@ -2,10 +2,14 @@ package webapi
 
 import (
 	"bytes"
+	"crypto/rsa"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
+	"math/big"
 	"net/http"
+	"time"
 )
 
 // SSOClient SSO 服务客户端。
@@ -135,3 +139,60 @@ func (c *SSOClient) GetUserInfo(userUUID string) (*SSOUserInfo, error) {
 
 	return apiResp.Data, nil
 }
+
+// jsonWebKey JWKS 中的单个 RSA 公钥。
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// GetSigningKeys 从 SSO 的 JWKS 端点获取签名公钥，按 kid 索引。
+func (c *SSOClient) GetSigningKeys() (map[string]*rsa.PublicKey, error) {
+	url := fmt.Sprintf("%s/.well-known/jwks.json", c.serviceURL)
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("SSO返回错误状态: %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(body, &jwks); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(jwks.Keys))
+	for _, k := range jwks.Keys {
+		if k.Kty != "RSA" || k.Kid == "" {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("解析公钥 %s 失败: %w", k.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("解析公钥 %s 失败: %w", k.Kid, err)
+		}
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+
+	return keys, nil
+}
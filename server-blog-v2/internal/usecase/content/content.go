@@ -4,8 +4,11 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
+	"strconv"
 	"time"
 
 	"server-blog-v2/config"
@@ -15,11 +18,23 @@ import (
 	"server-blog-v2/internal/usecase/input"
 	"server-blog-v2/internal/usecase/output"
 	"server-blog-v2/internal/usecase/urlutil"
+	"server-blog-v2/pkg/redis"
 )
 
 var (
-	ErrRepo     = errors.New("repo")
-	ErrNotFound = errors.New("not found")
+	ErrRepo          = errors.New("repo")
+	ErrNotFound      = errors.New("not found")
+	ErrInvalidParent = errors.New("invalid parent category")
+	ErrInvalidParam  = errors.New("invalid param")
+)
+
+const (
+	// RedisKeyArchiveVersion 归档缓存版本号，每次发布变更时刷新
+	RedisKeyArchiveVersion = "article:archive:version"
+	// RedisKeyArchivePrefix 归档缓存 key 前缀
+	RedisKeyArchivePrefix = "article:archive:"
+	// ArchiveCacheTTL 归档缓存兜底过期时间（版本号变更后旧 key 自然过期）
+	ArchiveCacheTTL = 7 * 24 * time.Hour
 )
 
 // generateSlug 生成随机 slug（15字节 = 20字符，与 ES _id 格式一致）
@@ -37,6 +52,7 @@ type useCase struct {
 	articleLikes repo.ArticleLikeRepo
 	articleViews repo.ArticleViewRepo
 	users        repo.UserRepo
+	redis        redis.Client
 }
 
 // New 创建 Content UseCase。
@@ -48,6 +64,7 @@ func New(
 	articleLikes repo.ArticleLikeRepo,
 	articleViews repo.ArticleViewRepo,
 	users repo.UserRepo,
+	rdb redis.Client,
 ) usecase.Content {
 	return &useCase{
 		cfg:          cfg,
@@ -57,6 +74,7 @@ func New(
 		articleLikes: articleLikes,
 		articleViews: articleViews,
 		users:        users,
+		redis:        rdb,
 	}
 }
 
@@ -74,13 +92,14 @@ func (u *useCase) ListArticles(ctx context.Context, params input.ListArticles) (
 		sortBy = &params.Sort.SortBy
 		order = &params.Sort.Order
 	}
-	var categoryID, tagID *int
-	if params.CategoryID != nil {
-		categoryID = (*int)(params.CategoryID)
-	}
+	var tagID *int
 	if params.TagID != nil {
 		tagID = (*int)(params.TagID)
 	}
+	categoryIDs, err := u.resolveCategoryFilter(ctx, params.CategoryID, params.IncludeDescendants)
+	if err != nil {
+		return nil, err
+	}
 	var status *string
 	if params.Status != nil {
 		status = (*string)(params.Status)
@@ -90,7 +109,7 @@ func (u *useCase) ListArticles(ctx context.Context, params input.ListArticles) (
 		visibility = params.Visibility
 	}
 
-	articles, total, err := u.articles.List(ctx, offset, params.PageSize, keyword, sortBy, order, categoryID, tagID, status, visibility)
+	articles, total, err := u.articles.List(ctx, offset, params.PageSize, keyword, sortBy, order, categoryIDs, tagID, status, visibility, params.AuthorUUID)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrRepo, err)
 	}
@@ -135,17 +154,23 @@ func (u *useCase) CreateArticle(ctx context.Context, params input.CreateArticle)
 		categoryID = 0 // 保持为 0，数据库会存储为 NULL
 	}
 
+	coAuthors, err := u.normalizeCoAuthors(ctx, params.AuthorUUID, params.CoAuthorUUIDs)
+	if err != nil {
+		return "", err
+	}
+
 	article := &entity.Article{
-		Title:      params.Title,
-		Slug:       slug,
-		Excerpt:    params.Excerpt,
-		Content:    params.Content,
-		AuthorUUID: params.AuthorUUID,
-		CategoryID: categoryID,
-		TagIDs:     params.TagIDs, // 直接存储标签 ID 数组
-		Status:     params.Status,
-		Visibility: visibility,
-		IsFeatured: params.IsFeatured,
+		Title:         params.Title,
+		Slug:          slug,
+		Excerpt:       params.Excerpt,
+		Content:       params.Content,
+		AuthorUUID:    params.AuthorUUID,
+		CoAuthorUUIDs: coAuthors,
+		CategoryID:    categoryID,
+		TagIDs:        params.TagIDs, // 直接存储标签 ID 数组
+		Status:        params.Status,
+		Visibility:    visibility,
+		IsFeatured:    params.IsFeatured,
 	}
 	if params.FeaturedImage != nil {
 		article.FeaturedImage = params.FeaturedImage
@@ -155,11 +180,15 @@ func (u *useCase) CreateArticle(ctx context.Context, params input.CreateArticle)
 		article.PublishedAt = &now
 	}
 
-	_, err := u.articles.Create(ctx, article)
+	_, err = u.articles.Create(ctx, article)
 	if err != nil {
 		return "", fmt.Errorf("%w: %v", ErrRepo, err)
 	}
 
+	if article.Status == entity.ArticleStatusPublished {
+		u.invalidateArchive(ctx)
+	}
+
 	return slug, nil
 }
 
@@ -200,6 +229,14 @@ func (u *useCase) UpdateArticle(ctx context.Context, params input.UpdateArticle)
 	if params.FeaturedImage != nil {
 		article.FeaturedImage = params.FeaturedImage
 	}
+	// 只在客户端传入时更新共同作者，未传时保留原值
+	if params.CoAuthorUUIDs != nil {
+		coAuthors, err := u.normalizeCoAuthors(ctx, existing.AuthorUUID, *params.CoAuthorUUIDs)
+		if err != nil {
+			return err
+		}
+		article.CoAuthorUUIDs = coAuthors
+	}
 
 	// 检查是否首次发布
 	if existing.PublishedAt == nil && params.Status == entity.ArticleStatusPublished {
@@ -213,6 +250,11 @@ func (u *useCase) UpdateArticle(ctx context.Context, params input.UpdateArticle)
 		return fmt.Errorf("%w: %v", ErrRepo, err)
 	}
 
+	// 发布、下线或修改已发布文章都会影响归档
+	if existing.Status == entity.ArticleStatusPublished || params.Status == entity.ArticleStatusPublished {
+		u.invalidateArchive(ctx)
+	}
+
 	return nil
 }
 
@@ -220,6 +262,7 @@ func (u *useCase) DeleteArticle(ctx context.Context, id int64) error {
 	if err := u.articles.Delete(ctx, id); err != nil {
 		return fmt.Errorf("%w: %v", ErrRepo, err)
 	}
+	u.invalidateArchive(ctx)
 	return nil
 }
 
@@ -239,6 +282,9 @@ func (u *useCase) DeleteArticleBySlug(ctx context.Context, slug string) error {
 	if err := u.articles.Delete(ctx, article.ID); err != nil {
 		return fmt.Errorf("%w: %v", ErrRepo, err)
 	}
+	if article.Status == entity.ArticleStatusPublished {
+		u.invalidateArchive(ctx)
+	}
 	return nil
 }
 
@@ -256,17 +302,46 @@ func (u *useCase) ListPublicArticles(ctx context.Context, params input.ListPubli
 		sortBy = &params.Sort.SortBy
 		order = &params.Sort.Order
 	}
-	var categoryID, tagID *int
-	if params.CategoryID != nil {
-		categoryID = (*int)(params.CategoryID)
-	}
+	var tagID *int
 	if params.TagID != nil {
 		tagID = (*int)(params.TagID)
 	}
+	categoryIDs, err := u.resolveCategoryFilter(ctx, params.CategoryID, params.IncludeDescendants)
+	if err != nil {
+		return nil, err
+	}
 
 	published := entity.ArticleStatusPublished
 	public := entity.ArticleVisibilityPublic
-	articles, total, err := u.articles.List(ctx, offset, params.PageSize, keyword, sortBy, order, categoryID, tagID, &published, &public)
+
+	// 配置为 postgres 时关键字搜索走全文检索，按相关度排序并返回命中片段
+	if keyword != nil && *keyword != "" && u.cfg.Search.Engine == config.SearchEnginePostgres {
+		hits, total, err := u.articles.Search(ctx, offset, params.PageSize, *keyword, categoryIDs, tagID, &published, &public, params.AuthorUUID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrRepo, err)
+		}
+
+		articles := make([]*entity.Article, len(hits))
+		for i, h := range hits {
+			articles[i] = h.Article
+		}
+		items, err := u.toArticleSummaries(ctx, articles, userUUID)
+		if err != nil {
+			return nil, err
+		}
+		for i := range items {
+			items[i].Highlight = hits[i].Headline
+		}
+
+		return &output.ListResult[output.ArticleSummary]{
+			Items:    items,
+			Page:     params.Page,
+			PageSize: params.PageSize,
+			Total:    total,
+		}, nil
+	}
+
+	articles, total, err := u.articles.List(ctx, offset, params.PageSize, keyword, sortBy, order, categoryIDs, tagID, &published, &public, params.AuthorUUID)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrRepo, err)
 	}
@@ -323,6 +398,90 @@ func (u *useCase) RecordView(ctx context.Context, articleSlug string, ip, userAg
 	_ = u.articleViews.IncrViews(ctx, articleSlug)
 }
 
+// ==================== 作者 ====================
+
+// GetAuthorProfile 获取作者主页信息，文章数包含共同署名的已发布公开文章。
+func (u *useCase) GetAuthorProfile(ctx context.Context, authorUUID string) (*output.AuthorProfile, error) {
+	user, err := u.users.GetByUUID(ctx, authorUUID)
+	if err != nil || user == nil {
+		return nil, ErrNotFound
+	}
+
+	published := entity.ArticleStatusPublished
+	public := entity.ArticleVisibilityPublic
+	_, total, err := u.articles.List(ctx, 0, 1, nil, nil, nil, nil, nil, &published, &public, &authorUUID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRepo, err)
+	}
+
+	return &output.AuthorProfile{
+		AuthorInfo: output.AuthorInfo{
+			UUID:     user.UUID,
+			Nickname: user.Nickname,
+			Avatar:   urlutil.ResolveImageURL(u.cfg, user.Avatar),
+		},
+		Bio:          user.Bio,
+		ArticleCount: total,
+		JoinedAt:     user.CreatedAt,
+	}, nil
+}
+
+// ==================== 归档 ====================
+
+// GetArchive 获取按年月分组的文章归档。
+// 结果缓存在 Redis 中，直到下一次发布变更刷新版本号。
+func (u *useCase) GetArchive(ctx context.Context, params input.GetArchive) ([]output.ArchiveYear, error) {
+	var categoryID, tagID *int
+	if params.CategoryID != nil {
+		categoryID = (*int)(params.CategoryID)
+	}
+	if params.TagID != nil {
+		tagID = (*int)(params.TagID)
+	}
+
+	cacheKey := u.archiveCacheKey(ctx, categoryID, tagID)
+	if cached, err := u.redis.Get(ctx, cacheKey); err == nil && cached != "" {
+		var years []output.ArchiveYear
+		if err := json.Unmarshal([]byte(cached), &years); err == nil {
+			return years, nil
+		}
+	}
+
+	buckets, err := u.articles.ListArchive(ctx, categoryID, tagID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRepo, err)
+	}
+
+	years := toArchiveYears(buckets)
+
+	if data, err := json.Marshal(years); err == nil {
+		_ = u.redis.Set(ctx, cacheKey, string(data), ArchiveCacheTTL)
+	}
+
+	return years, nil
+}
+
+// archiveCacheKey 生成归档缓存 key（包含当前版本号和筛选条件）。
+func (u *useCase) archiveCacheKey(ctx context.Context, categoryID, tagID *int) string {
+	version, err := u.redis.Get(ctx, RedisKeyArchiveVersion)
+	if err != nil || version == "" {
+		version = "0"
+	}
+	key := RedisKeyArchivePrefix + version
+	if categoryID != nil {
+		key += ":c" + strconv.Itoa(*categoryID)
+	}
+	if tagID != nil {
+		key += ":t" + strconv.Itoa(*tagID)
+	}
+	return key
+}
+
+// invalidateArchive 刷新归档缓存版本号，使所有筛选条件下的缓存失效。
+func (u *useCase) invalidateArchive(ctx context.Context) {
+	_ = u.redis.Set(ctx, RedisKeyArchiveVersion, strconv.FormatInt(time.Now().UnixNano(), 10), 0)
+}
+
 // ==================== 点赞 ====================
 
 func (u *useCase) ToggleLikeOnArticle(ctx context.Context, articleSlug, userUUID string) (bool, int32, error) {
@@ -395,23 +554,42 @@ func (u *useCase) ListCategories(ctx context.Context, params input.ListCategorie
 	}, nil
 }
 
-func (u *useCase) GetAllPublicCategories(ctx context.Context) (*output.AllResult[output.CategoryDetail], error) {
+// GetAllPublicCategories 获取分类树，文章数按子树聚合。
+func (u *useCase) GetAllPublicCategories(ctx context.Context) (*output.AllResult[output.CategoryTreeNode], error) {
 	categories, err := u.categories.ListAll(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrRepo, err)
 	}
-	items := make([]output.CategoryDetail, len(categories))
-	for i, c := range categories {
-		items[i] = toCategoryDetail(c)
+	counts, err := u.articles.CountPublishedByCategory(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRepo, err)
 	}
-	return &output.AllResult[output.CategoryDetail]{
-		Items: items,
-		Total: int64(len(items)),
+	for _, c := range categories {
+		c.ArticleCount = counts[c.ID]
+	}
+
+	tree := newCategoryTree(categories)
+	return &output.AllResult[output.CategoryTreeNode]{
+		Items: tree.nodes(0),
+		Total: int64(len(categories)),
 	}, nil
 }
 
 func (u *useCase) CreateCategory(ctx context.Context, params input.CreateCategory) (int64, error) {
-	id, err := u.categories.Create(ctx, entity.Category{Name: params.Name, Slug: params.Slug})
+	if params.ParentID != nil {
+		if _, err := u.categories.GetByID(ctx, *params.ParentID); err != nil {
+			if errors.Is(err, repo.ErrNotFound) {
+				return 0, ErrInvalidParent
+			}
+			return 0, fmt.Errorf("%w: %v", ErrRepo, err)
+		}
+	}
+	id, err := u.categories.Create(ctx, entity.Category{
+		Name:      params.Name,
+		Slug:      params.Slug,
+		ParentID:  params.ParentID,
+		SortOrder: params.SortOrder,
+	})
 	if err != nil {
 		return 0, fmt.Errorf("%w: %v", ErrRepo, err)
 	}
@@ -420,6 +598,36 @@ func (u *useCase) CreateCategory(ctx context.Context, params input.CreateCategor
 
 func (u *useCase) UpdateCategory(ctx context.Context, params input.UpdateCategory) error {
 	if err := u.categories.Update(ctx, entity.Category{ID: params.ID, Name: params.Name, Slug: params.Slug}); err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("%w: %v", ErrRepo, err)
+	}
+	return nil
+}
+
+// MoveCategory 移动分类（连同子树），禁止移动到自身或子孙分类下。
+func (u *useCase) MoveCategory(ctx context.Context, params input.MoveCategory) error {
+	categories, err := u.categories.ListAll(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrRepo, err)
+	}
+	tree := newCategoryTree(categories)
+	if _, ok := tree.byID[params.ID]; !ok {
+		return ErrNotFound
+	}
+	if params.ParentID != nil {
+		if _, ok := tree.byID[*params.ParentID]; !ok {
+			return ErrInvalidParent
+		}
+		for _, id := range tree.descendantIDs(params.ID) {
+			if id == *params.ParentID {
+				return ErrInvalidParent
+			}
+		}
+	}
+
+	if err := u.categories.Move(ctx, params.ID, params.ParentID, params.SortOrder); err != nil {
 		return fmt.Errorf("%w: %v", ErrRepo, err)
 	}
 	return nil
@@ -502,8 +710,201 @@ func (u *useCase) DeleteTag(ctx context.Context, id int64) error {
 	return nil
 }
 
+// ResolveTag 按 slug 查找标签，未命中时按别名查找（旧 slug 跳转到新标签）。
+func (u *useCase) ResolveTag(ctx context.Context, slug string) (*output.ResolvedTag, error) {
+	if tag, err := u.tags.GetBySlug(ctx, slug); err == nil {
+		return &output.ResolvedTag{TagDetail: toTagDetail(tag)}, nil
+	}
+	tag, err := u.tags.GetByAlias(ctx, slug)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+	return &output.ResolvedTag{TagDetail: toTagDetail(tag), Redirected: true}, nil
+}
+
+func (u *useCase) ListTagAliases(ctx context.Context, tagID int64) ([]output.TagAlias, error) {
+	aliases, err := u.tags.ListAliases(ctx, tagID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRepo, err)
+	}
+	items := make([]output.TagAlias, len(aliases))
+	for i, a := range aliases {
+		items[i] = output.TagAlias{ID: a.ID, Slug: a.Slug, TagID: a.TagID, CreatedAt: a.CreatedAt}
+	}
+	return items, nil
+}
+
+func (u *useCase) DeleteTagAlias(ctx context.Context, id int64) error {
+	if err := u.tags.DeleteAlias(ctx, id); err != nil {
+		return fmt.Errorf("%w: %v", ErrRepo, err)
+	}
+	return nil
+}
+
+// MergeTags 将多个标签合并到目标标签，返回受影响的文章数。
+func (u *useCase) MergeTags(ctx context.Context, params input.MergeTags) (int64, error) {
+	sourceIDs := make([]int64, 0, len(params.SourceIDs))
+	for _, id := range params.SourceIDs {
+		if id == params.TargetID {
+			return 0, fmt.Errorf("%w: target tag cannot be merged into itself", ErrInvalidParam)
+		}
+		if !slices.Contains(sourceIDs, id) {
+			sourceIDs = append(sourceIDs, id)
+		}
+	}
+	if len(sourceIDs) == 0 {
+		return 0, fmt.Errorf("%w: no source tags", ErrInvalidParam)
+	}
+
+	affected, err := u.tags.Merge(ctx, sourceIDs, params.TargetID)
+	if errors.Is(err, repo.ErrNotFound) {
+		return 0, ErrNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrRepo, err)
+	}
+	u.invalidateArchive(ctx)
+	return affected, nil
+}
+
+// BulkRetag 为筛选出的文章批量添加或移除标签，返回受影响的文章数。
+func (u *useCase) BulkRetag(ctx context.Context, params input.BulkRetag) (int64, error) {
+	if params.Action != input.RetagActionAdd && params.Action != input.RetagActionRemove {
+		return 0, fmt.Errorf("%w: unknown action %q", ErrInvalidParam, params.Action)
+	}
+	if _, err := u.tags.GetByID(ctx, params.TagID); err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return 0, ErrNotFound
+		}
+		return 0, fmt.Errorf("%w: %v", ErrRepo, err)
+	}
+
+	categoryIDs, err := u.resolveCategoryFilter(ctx, params.CategoryID, params.IncludeDescendants)
+	if err != nil {
+		return 0, err
+	}
+	filter := entity.ArticleFilter{
+		IDs:         params.ArticleIDs,
+		CategoryIDs: categoryIDs,
+		Status:      params.Status,
+		Keyword:     params.Keyword,
+	}
+	if params.FilterTagID != nil {
+		tagID := int64(*params.FilterTagID)
+		filter.TagID = &tagID
+	}
+	// 不允许无条件改写全部文章
+	if len(filter.IDs) == 0 && len(filter.CategoryIDs) == 0 && filter.TagID == nil &&
+		filter.Status == nil && (filter.Keyword == nil || *filter.Keyword == "") {
+		return 0, fmt.Errorf("%w: at least one article filter is required", ErrInvalidParam)
+	}
+
+	affected, err := u.articles.BulkUpdateTag(ctx, params.TagID, params.Action == input.RetagActionRemove, filter)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrRepo, err)
+	}
+	if affected > 0 {
+		u.invalidateArchive(ctx)
+	}
+	return affected, nil
+}
+
 // ==================== 辅助函数 ====================
 
+// resolveCategoryFilter 将分类筛选展开为分类 ID 列表（可选包含子孙分类）。
+func (u *useCase) resolveCategoryFilter(ctx context.Context, categoryID input.IntFilterParam, includeDescendants bool) ([]int64, error) {
+	if categoryID == nil {
+		return nil, nil
+	}
+	id := int64(*categoryID)
+	if !includeDescendants {
+		return []int64{id}, nil
+	}
+	categories, err := u.categories.ListAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRepo, err)
+	}
+	ids := newCategoryTree(categories).descendantIDs(id)
+	if len(ids) == 0 {
+		return []int64{id}, nil
+	}
+	return ids, nil
+}
+
+// categoryTree 内存中的分类树（分类数量有限，直接全量构建）。
+type categoryTree struct {
+	byID     map[int64]*entity.Category
+	children map[int64][]*entity.Category // key 为父分类 ID，0 表示顶级
+}
+
+func newCategoryTree(categories []*entity.Category) *categoryTree {
+	t := &categoryTree{
+		byID:     make(map[int64]*entity.Category, len(categories)),
+		children: make(map[int64][]*entity.Category),
+	}
+	for _, c := range categories {
+		t.byID[c.ID] = c
+	}
+	// categories 已按 sort_order, id 排序，子节点保持同样顺序
+	for _, c := range categories {
+		var parentID int64
+		if c.ParentID != nil {
+			if _, ok := t.byID[*c.ParentID]; ok {
+				parentID = *c.ParentID
+			}
+		}
+		t.children[parentID] = append(t.children[parentID], c)
+	}
+	return t
+}
+
+// descendantIDs 返回节点自身及全部子孙节点 ID。
+func (t *categoryTree) descendantIDs(id int64) []int64 {
+	if _, ok := t.byID[id]; !ok {
+		return nil
+	}
+	ids := []int64{id}
+	for i := 0; i < len(ids); i++ {
+		for _, c := range t.children[ids[i]] {
+			ids = append(ids, c.ID)
+		}
+	}
+	return ids
+}
+
+// breadcrumb 返回从顶级分类到指定分类的路径。
+func (t *categoryTree) breadcrumb(id int64) []output.BaseCategory {
+	var path []output.BaseCategory
+	visited := make(map[int64]bool)
+	for c, ok := t.byID[id]; ok && !visited[c.ID]; {
+		visited[c.ID] = true
+		path = append([]output.BaseCategory{{ID: c.ID, Name: c.Name, Slug: c.Slug}}, path...)
+		if c.ParentID == nil {
+			break
+		}
+		c, ok = t.byID[*c.ParentID]
+	}
+	return path
+}
+
+// nodes 构建指定父节点下的子树，TotalArticleCount 为子树文章数之和。
+func (t *categoryTree) nodes(parentID int64) []output.CategoryTreeNode {
+	children := t.children[parentID]
+	nodes := make([]output.CategoryTreeNode, len(children))
+	for i, c := range children {
+		node := output.CategoryTreeNode{
+			CategoryDetail:    toCategoryDetail(c),
+			TotalArticleCount: c.ArticleCount,
+			Children:          t.nodes(c.ID),
+		}
+		for _, child := range node.Children {
+			node.TotalArticleCount += child.TotalArticleCount
+		}
+		nodes[i] = node
+	}
+	return nodes
+}
+
 func (u *useCase) toArticleSummaries(ctx context.Context, articles []*entity.Article, userUUID *string) ([]output.ArticleSummary, error) {
 	items := make([]output.ArticleSummary, len(articles))
 	for i, a := range articles {
@@ -534,6 +935,16 @@ func (u *useCase) toArticleDetail(ctx context.Context, a *entity.Article, userUU
 		Category:    category,
 		Tags:        toBaseTags(tags),
 		Content:     a.Content,
+		Breadcrumb:  []output.BaseCategory{},
+		Byline:      []output.AuthorInfo{author},
+	}
+	for _, uuid := range a.CoAuthorUUIDs {
+		detail.Byline = append(detail.Byline, u.getAuthorInfo(ctx, uuid))
+	}
+	if cat != nil {
+		if categories, err := u.categories.ListAll(ctx); err == nil {
+			detail.Breadcrumb = newCategoryTree(categories).breadcrumb(cat.ID)
+		}
 	}
 	if a.MetaTitle != nil {
 		detail.MetaTitle = *a.MetaTitle
@@ -556,6 +967,24 @@ func (u *useCase) getAuthorInfo(ctx context.Context, authorUUID string) output.A
 	}
 }
 
+// normalizeCoAuthors 去重并剔除主作者，校验共同作者均为已存在的用户。
+func (u *useCase) normalizeCoAuthors(ctx context.Context, authorUUID string, uuids []string) ([]string, error) {
+	coAuthors := make([]string, 0, len(uuids))
+	for _, uuid := range uuids {
+		if uuid == "" || uuid == authorUUID || slices.Contains(coAuthors, uuid) {
+			continue
+		}
+		if _, err := u.users.GetByUUID(ctx, uuid); err != nil {
+			if errors.Is(err, repo.ErrNotFound) {
+				return nil, fmt.Errorf("%w: co-author %s not found", ErrInvalidParam, uuid)
+			}
+			return nil, fmt.Errorf("%w: %v", ErrRepo, err)
+		}
+		coAuthors = append(coAuthors, uuid)
+	}
+	return coAuthors, nil
+}
+
 func (u *useCase) getLikeInfo(ctx context.Context, articleSlug string, likes int32, userUUID *string) output.LikeInfo {
 	if userUUID == nil {
 		return output.LikeInfo{Likes: likes}
@@ -569,17 +998,18 @@ func (u *useCase) getLikeInfo(ctx context.Context, articleSlug string, likes int
 
 func (u *useCase) toBaseArticle(a *entity.Article) output.BaseArticle {
 	bp := output.BaseArticle{
-		ID:          a.ID,
-		Title:       a.Title,
-		Slug:        a.Slug,
-		AuthorUUID:  a.AuthorUUID,
-		Status:      a.Status,
-		Visibility:  a.Visibility,
-		Views:       a.Views,
-		IsFeatured:  a.IsFeatured,
-		PublishedAt: a.PublishedAt,
-		CreatedAt:   a.CreatedAt,
-		UpdatedAt:   a.UpdatedAt,
+		ID:            a.ID,
+		Title:         a.Title,
+		Slug:          a.Slug,
+		AuthorUUID:    a.AuthorUUID,
+		CoAuthorUUIDs: a.CoAuthorUUIDs,
+		Status:        a.Status,
+		Visibility:    a.Visibility,
+		Views:         a.Views,
+		IsFeatured:    a.IsFeatured,
+		PublishedAt:   a.PublishedAt,
+		CreatedAt:     a.CreatedAt,
+		UpdatedAt:     a.UpdatedAt,
 	}
 	if a.Excerpt != nil {
 		bp.Excerpt = *a.Excerpt
@@ -607,6 +1037,25 @@ func (u *useCase) toArticleSummary(a *entity.Article, author output.AuthorInfo,
 	}
 }
 
+// toArchiveYears 将年月桶（已按时间倒序）合并为年份分组。
+func toArchiveYears(buckets []*entity.ArchiveBucket) []output.ArchiveYear {
+	years := make([]output.ArchiveYear, 0)
+	for _, b := range buckets {
+		if len(years) == 0 || years[len(years)-1].Year != b.Year {
+			years = append(years, output.ArchiveYear{Year: b.Year, Months: []output.ArchiveMonth{}})
+		}
+		year := &years[len(years)-1]
+
+		articles := make([]output.ArchiveArticle, len(b.Articles))
+		for i, a := range b.Articles {
+			articles[i] = output.ArchiveArticle{ID: a.ID, Title: a.Title, Slug: a.Slug, PublishedAt: a.PublishedAt}
+		}
+		year.Months = append(year.Months, output.ArchiveMonth{Month: b.Month, Count: b.Count, Articles: articles})
+		year.Count += b.Count
+	}
+	return years
+}
+
 func toBaseTags(tags []*entity.Tag) []output.BaseTag {
 	bt := make([]output.BaseTag, len(tags))
 	for i, t := range tags {
@@ -618,6 +1067,8 @@ func toBaseTags(tags []*entity.Tag) []output.BaseTag {
 func toCategoryDetail(c *entity.Category) output.CategoryDetail {
 	return output.CategoryDetail{
 		BaseCategory: output.BaseCategory{ID: c.ID, Name: c.Name, Slug: c.Slug},
+		ParentID:     c.ParentID,
+		SortOrder:    c.SortOrder,
 		ArticleCount: c.ArticleCount,
 		CreatedAt:    c.CreatedAt,
 		UpdatedAt:    c.UpdatedAt,
@@ -24,6 +24,8 @@ type Content interface {
 	ListPublicArticles(ctx context.Context, params input.ListPublicArticles, userUUID *string) (*output.ListResult[output.ArticleSummary], error)
 	GetPublicArticleBySlug(ctx context.Context, slug string, userUUID *string) (*output.ArticleDetail, error)
 	RecordView(ctx context.Context, articleSlug string, ip, userAgent, referer string)
+	GetArchive(ctx context.Context, params input.GetArchive) ([]output.ArchiveYear, error)
+	GetAuthorProfile(ctx context.Context, authorUUID string) (*output.AuthorProfile, error)
 
 	// 点赞
 	ToggleLikeOnArticle(ctx context.Context, articleSlug, userUUID string) (liked bool, count int32, err error)
@@ -32,9 +34,10 @@ type Content interface {
 
 	// 分类
 	ListCategories(ctx context.Context, params input.ListCategories) (*output.ListResult[output.CategoryDetail], error)
-	GetAllPublicCategories(ctx context.Context) (*output.AllResult[output.CategoryDetail], error)
+	GetAllPublicCategories(ctx context.Context) (*output.AllResult[output.CategoryTreeNode], error)
 	CreateCategory(ctx context.Context, params input.CreateCategory) (int64, error)
 	UpdateCategory(ctx context.Context, params input.UpdateCategory) error
+	MoveCategory(ctx context.Context, params input.MoveCategory) error
 	DeleteCategory(ctx context.Context, id int64) error
 
 	// 标签
@@ -43,6 +46,11 @@ type Content interface {
 	CreateTag(ctx context.Context, params input.CreateTag) (int64, error)
 	UpdateTag(ctx context.Context, params input.UpdateTag) error
 	DeleteTag(ctx context.Context, id int64) error
+	ResolveTag(ctx context.Context, slug string) (*output.ResolvedTag, error)
+	ListTagAliases(ctx context.Context, tagID int64) ([]output.TagAlias, error)
+	DeleteTagAlias(ctx context.Context, id int64) error
+	MergeTags(ctx context.Context, params input.MergeTags) (int64, error)
+	BulkRetag(ctx context.Context, params input.BulkRetag) (int64, error)
 }
 
 // ==================== 评论 ====================
@@ -117,12 +125,20 @@ type File interface {
 	Delete(ctx context.Context, key string) error
 	List(ctx context.Context, params input.ListFiles) (*output.ListResult[output.FileInfo], error)
 	DeleteByIDs(ctx context.Context, ids []int64) error
+	// SetAccess 批量修改文件访问级别（public/private），对象及图片版本随之移动
+	SetAccess(ctx context.Context, ids []int64, access string) error
 }
 
 // Resource 资源管理用例。
 type Resource interface {
 	List(ctx context.Context, userUUID *string, params input.ListResources) (*output.ListResult[output.ResourceInfo], error)
 	DeleteByIDs(ctx context.Context, ids []int64) error
+	// SetAccess 批量修改资源访问级别（public/private），对象随之移动
+	SetAccess(ctx context.Context, ids []int64, access string) error
+	// PrivateURL 为私有对象（private/ 前缀，含 files 中的私有图片）签发短期访问地址
+	PrivateURL(key string) (string, error)
+	// PrivatePlaylist 返回分片地址已签名的私有 HLS 播放列表
+	PrivatePlaylist(ctx context.Context, key string) ([]byte, error)
 	// 分片上传相关
 	GetMaxFileSize(ctx context.Context) int64
 	Check(ctx context.Context, userUUID string, params input.ResourceCheck) (*output.ResourceCheckResponse, error)
@@ -133,6 +149,38 @@ type Resource interface {
 	Progress(ctx context.Context, userUUID string, params input.ResourceProgress) (*output.ResourceProgressResponse, error)
 	// 七牛云回调
 	HandleQiniuCallback(ctx context.Context, inputKey string, code int, items []input.QiniuCallbackItem) error
+	// 过期任务清理
+	ReapExpiredTasks(ctx context.Context) (*output.UploadReapResult, error)
+	GetReapStats(ctx context.Context) (*output.UploadReapStats, error)
+	// 存储配额与上传策略
+	GetStorageUsage(ctx context.Context, userUUID string) (*output.StorageUsage, error)
+	ListUploadPolicies(ctx context.Context) (*output.AllResult[output.UploadPolicyInfo], error)
+	SaveUploadPolicy(ctx context.Context, params input.SaveUploadPolicy) (int64, error)
+	DeleteUploadPolicy(ctx context.Context, id int64) error
+}
+
+// MediaGC 孤儿媒体回收用例。
+type MediaGC interface {
+	// ScanOrphans 扫描未被任何内容引用、且超过宽限期的文件与资源
+	ScanOrphans(ctx context.Context, params input.ScanOrphans) (*output.OrphanReport, error)
+	// PurgeOrphans 删除管理员确认的孤儿记录（删除前重新校验），同时删除对象存储中的文件
+	PurgeOrphans(ctx context.Context, params input.PurgeOrphans) (*output.OrphanPurgeResult, error)
+}
+
+// MediaLibrary 媒体库用例：统一浏览 files 与 resources，管理文件夹、标签与引用情况。
+type MediaLibrary interface {
+	ListItems(ctx context.Context, params input.ListMediaItems) (*output.ListResult[output.MediaItemInfo], error)
+	UpdateItem(ctx context.Context, params input.UpdateMediaItem) error
+	// MoveItems 移动条目到文件夹，只修改媒体库元数据，不改变对象 Key
+	MoveItems(ctx context.Context, params input.MoveMediaItems) error
+	ListTags(ctx context.Context) (*output.AllResult[output.MediaTagCount], error)
+	// GetItemUsage 列出引用该条目的文章
+	GetItemUsage(ctx context.Context, source string, id int64) (*output.AllResult[output.MediaUsageInfo], error)
+	// 文件夹
+	ListFolders(ctx context.Context) (*output.AllResult[output.MediaFolderInfo], error)
+	CreateFolder(ctx context.Context, params input.SaveMediaFolder) (int64, error)
+	UpdateFolder(ctx context.Context, id int64, params input.SaveMediaFolder) error
+	DeleteFolder(ctx context.Context, id int64) error
 }
 
 // ==================== 用户 ====================
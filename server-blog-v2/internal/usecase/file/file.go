@@ -8,10 +8,16 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
 	"time"
 
 	"server-blog-v2/internal/entity"
+	"server-blog-v2/internal/pkg/filetype"
+	"server-blog-v2/internal/pkg/imageproc"
 	"server-blog-v2/internal/repo"
 	"server-blog-v2/internal/usecase"
 	"server-blog-v2/internal/usecase/input"
@@ -20,67 +26,180 @@ import (
 	"github.com/google/uuid"
 )
 
-var ErrRepo = errors.New("repo")
+var (
+	ErrRepo          = errors.New("repo")
+	ErrStorage       = errors.New("storage")
+	ErrNotFound      = errors.New("not found")
+	ErrInvalidAccess = errors.New("不支持的访问级别")
+)
 
 type useCase struct {
 	files       repo.FileRepo
 	objectStore repo.ObjectStore
+	images      *imageproc.Processor // 为 nil 时不处理图片
 }
 
 // New 创建 File UseCase。
-func New(files repo.FileRepo, objectStore repo.ObjectStore) usecase.File {
+func New(files repo.FileRepo, objectStore repo.ObjectStore, images *imageproc.Processor) usecase.File {
 	return &useCase{
 		files:       files,
 		objectStore: objectStore,
+		images:      images,
 	}
 }
 
 func (u *useCase) Upload(ctx context.Context, params input.UploadFile) (*output.UploadResult, error) {
-	// 读取文件内容并计算哈希
+	access, err := normalizeAccess(params.Access)
+	if err != nil {
+		return nil, err
+	}
+
+	// 读取文件内容
 	content, err := io.ReadAll(params.File)
 	if err != nil {
 		return nil, fmt.Errorf("read file error: %w", err)
 	}
 
-	hash := sha256.Sum256(content)
-	fileHash := hex.EncodeToString(hash[:])
+	// 以内容检测为准：拒绝 HTML 等可执行脚本的类型与 polyglot 文件
+	if filetype.IsActiveContent(params.ContentType) {
+		return nil, filetype.ErrActiveContent
+	}
+	detected, matched, err := filetype.VerifyMimeType(bytes.NewReader(content), params.ContentType)
+	if err != nil {
+		return nil, fmt.Errorf("detect mime type error: %w", err)
+	}
+	if err := filetype.CheckContent(content, detected); err != nil {
+		return nil, err
+	}
+	if !matched {
+		params.ContentType = detected
+	}
 
-	// 生成唯一的文件 key
+	// SVG：删除脚本、事件属性与外部引用后再存储
+	if detected == filetype.MimeTypeSVG {
+		cleaned, _, err := filetype.SanitizeSVG(content)
+		if err != nil {
+			return nil, err
+		}
+		content = cleaned
+		params.Size = int64(len(content))
+		params.ContentType = filetype.MimeTypeSVG
+	}
+
+	// 生成唯一的文件 key，私有文件带 private/ 前缀，图片版本由此派生
 	ext := filepath.Ext(params.Filename)
-	key := fmt.Sprintf("%s/%s/%s%s",
+	key := entity.ObjectKeyForAccess(fmt.Sprintf("%s/%s/%s%s",
 		params.Usage,
 		time.Now().Format("2006/01/02"),
 		uuid.New().String(),
 		ext,
-	)
-
-	// 上传到对象存储（使用 bytes.Reader 重新包装内容）
-	url, err := u.objectStore.Upload(ctx, key, bytes.NewReader(content), params.Size, params.ContentType)
-	if err != nil {
-		return nil, fmt.Errorf("upload error: %w", err)
-	}
+	), access)
 
-	// 保存文件记录
 	file := &entity.File{
 		Key:      key,
 		Filename: params.Filename,
-		FileHash: fileHash,
 		Size:     params.Size,
 		MimeType: params.ContentType,
 		Usage:    params.Usage,
 		UserUUID: params.UserUUID,
+		Access:   access,
+	}
+
+	// 图片：纠正方向、去除元数据，并生成缩放与 WebP 版本
+	var renditions []imageproc.Rendition
+	if mimeType := http.DetectContentType(content); u.images != nil && imageproc.Supported(mimeType) {
+		processed, err := u.images.Process(ctx, content, mimeType)
+		if err != nil {
+			return nil, fmt.Errorf("process image error: %w", err)
+		}
+		content = processed.Original.Data
+		file.Size = int64(len(content))
+		file.MimeType = mimeType
+		file.Width = processed.Original.Width
+		file.Height = processed.Original.Height
+		renditions = processed.Variants
 	}
 
+	hash := sha256.Sum256(content)
+	file.FileHash = hex.EncodeToString(hash[:])
+
+	// 上传到对象存储（使用 bytes.Reader 重新包装内容）
+	url, err := u.objectStore.Upload(ctx, key, bytes.NewReader(content), file.Size, file.MimeType)
+	if err != nil {
+		return nil, fmt.Errorf("upload error: %w", err)
+	}
+
+	base := strings.TrimSuffix(key, ext)
+	for _, r := range renditions {
+		variant := entity.FileVariant{
+			Key:      base + r.Suffix + r.Ext,
+			Width:    r.Width,
+			Height:   r.Height,
+			Size:     int64(len(r.Data)),
+			MimeType: r.MimeType,
+		}
+		if _, err := u.objectStore.Upload(ctx, variant.Key, bytes.NewReader(r.Data), variant.Size, variant.MimeType); err != nil {
+			u.deleteObjects(ctx, file)
+			return nil, fmt.Errorf("upload variant error: %w", err)
+		}
+		file.Variants = append(file.Variants, variant)
+	}
+
+	// 保存文件记录
 	_, err = u.files.Create(ctx, file)
 	if err != nil {
 		// 上传成功但保存记录失败，尝试删除已上传的文件
-		_ = u.objectStore.Delete(ctx, key)
+		u.deleteObjects(ctx, file)
 		return nil, fmt.Errorf("%w: %v", ErrRepo, err)
 	}
 
-	return &output.UploadResult{
-		URL: url,
-	}, nil
+	result := &output.UploadResult{
+		URL:    url,
+		Width:  file.Width,
+		Height: file.Height,
+	}
+	u.fillVariants(result, file)
+	return result, nil
+}
+
+// fillVariants 填充图片版本及 srcset（按宽度升序，原图在原格式 srcset 末尾）。
+func (u *useCase) fillVariants(result *output.UploadResult, file *entity.File) {
+	if len(file.Variants) == 0 {
+		return
+	}
+
+	variants := make([]entity.FileVariant, len(file.Variants))
+	copy(variants, file.Variants)
+	sort.SliceStable(variants, func(i, j int) bool { return variants[i].Width < variants[j].Width })
+
+	var srcset, webpSrcset []string
+	for _, v := range variants {
+		url := u.objectStore.GetURL(v.Key)
+		result.Variants = append(result.Variants, output.ImageVariant{
+			URL:      url,
+			Width:    v.Width,
+			Height:   v.Height,
+			MimeType: v.MimeType,
+		})
+		entry := fmt.Sprintf("%s %dw", url, v.Width)
+		if v.MimeType == "image/webp" {
+			webpSrcset = append(webpSrcset, entry)
+		} else {
+			srcset = append(srcset, entry)
+		}
+	}
+	srcset = append(srcset, fmt.Sprintf("%s %dw", result.URL, file.Width))
+
+	result.SrcSet = strings.Join(srcset, ", ")
+	result.WebPSrcSet = strings.Join(webpSrcset, ", ")
+}
+
+// deleteObjects 删除文件及其所有图片版本，忽略单个对象的删除错误。
+func (u *useCase) deleteObjects(ctx context.Context, file *entity.File) {
+	_ = u.objectStore.Delete(ctx, file.Key)
+	for _, v := range file.Variants {
+		_ = u.objectStore.Delete(ctx, v.Key)
+	}
 }
 
 func (u *useCase) Delete(ctx context.Context, key string) error {
@@ -89,6 +208,13 @@ func (u *useCase) Delete(ctx context.Context, key string) error {
 		return fmt.Errorf("delete object error: %w", err)
 	}
 
+	// 删除图片版本
+	if file, err := u.files.GetByKey(ctx, key); err == nil {
+		for _, v := range file.Variants {
+			_ = u.objectStore.Delete(ctx, v.Key)
+		}
+	}
+
 	// 删除文件记录
 	if err := u.files.Delete(ctx, key); err != nil {
 		return fmt.Errorf("%w: %v", ErrRepo, err)
@@ -121,6 +247,7 @@ func (u *useCase) List(ctx context.Context, params input.ListFiles) (*output.Lis
 			URL:       u.objectStore.GetURL(f.Key),
 			Size:      f.Size,
 			MimeType:  f.MimeType,
+			Access:    f.Access,
 			CreatedAt: f.CreatedAt,
 		}
 	}
@@ -142,10 +269,8 @@ func (u *useCase) DeleteByIDs(ctx context.Context, ids []int64) error {
 
 	// 删除对象存储中的文件
 	for _, f := range files {
-		if err := u.objectStore.Delete(ctx, f.Key); err != nil {
-			// 记录错误但继续删除其他文件
-			continue
-		}
+		// 忽略单个文件的错误，继续删除其他文件
+		u.deleteObjects(ctx, f)
 	}
 
 	// 批量删除文件记录
@@ -155,3 +280,76 @@ func (u *useCase) DeleteByIDs(ctx context.Context, ids []int64) error {
 
 	return nil
 }
+
+func (u *useCase) SetAccess(ctx context.Context, ids []int64, access string) error {
+	access, err := normalizeAccess(access)
+	if err != nil {
+		return err
+	}
+
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+	files, err := u.files.GetByIDs(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrRepo, err)
+	}
+	if len(files) != len(ids) {
+		return ErrNotFound
+	}
+
+	for _, f := range files {
+		if f.Access == access && entity.IsPrivateKey(f.Key) == (access == entity.ResourceAccessPrivate) {
+			continue
+		}
+		if err := u.moveFile(ctx, f, access); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// moveFile 将文件及其图片版本复制到目标访问级别对应的 Key，更新记录后删除旧对象。
+func (u *useCase) moveFile(ctx context.Context, f *entity.File, access string) error {
+	moved := *f
+	moved.Access = access
+	moved.Key = entity.ObjectKeyForAccess(f.Key, access)
+	moved.Variants = make([]entity.FileVariant, len(f.Variants))
+
+	copied := []string{moved.Key}
+	if err := u.objectStore.Copy(ctx, f.Key, moved.Key); err != nil {
+		return fmt.Errorf("%w: %v", ErrStorage, err)
+	}
+	for i, v := range f.Variants {
+		v.Key = entity.ObjectKeyForAccess(v.Key, access)
+		if err := u.objectStore.Copy(ctx, f.Variants[i].Key, v.Key); err != nil {
+			for _, key := range copied {
+				_ = u.objectStore.Delete(ctx, key)
+			}
+			return fmt.Errorf("%w: %v", ErrStorage, err)
+		}
+		copied = append(copied, v.Key)
+		moved.Variants[i] = v
+	}
+
+	if err := u.files.UpdateAccess(ctx, &moved); err != nil {
+		for _, key := range copied {
+			_ = u.objectStore.Delete(ctx, key)
+		}
+		return fmt.Errorf("%w: %v", ErrRepo, err)
+	}
+
+	u.deleteObjects(ctx, f)
+	return nil
+}
+
+// normalizeAccess 校验访问级别，空值视为 public。
+func normalizeAccess(access string) (string, error) {
+	switch access {
+	case "":
+		return entity.ResourceAccessPublic, nil
+	case entity.ResourceAccessPublic, entity.ResourceAccessPrivate:
+		return access, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrInvalidAccess, access)
+	}
+}
@@ -10,6 +10,9 @@ type ListArticles struct {
 	Status     *string
 	Visibility *string // 可见性筛选：public, private
 	IsFeatured *bool
+	AuthorUUID *string // 主作者或共同作者
+	// IncludeDescendants 按分类筛选时是否包含子孙分类
+	IncludeDescendants bool
 }
 
 // ListPublicArticles 文章列表参数（公开端）。
@@ -19,6 +22,15 @@ type ListPublicArticles struct {
 	Sort       *SortParams
 	CategoryID IntFilterParam
 	TagID      IntFilterParam
+	AuthorUUID *string // 主作者或共同作者
+	// IncludeDescendants 按分类筛选时是否包含子孙分类
+	IncludeDescendants bool
+}
+
+// GetArchive 文章归档参数。
+type GetArchive struct {
+	CategoryID IntFilterParam
+	TagID      IntFilterParam
 }
 
 // ListUserLikedArticles 用户点赞文章列表参数。
@@ -34,6 +46,7 @@ type CreateArticle struct {
 	Content       string
 	FeaturedImage *string
 	AuthorUUID    string
+	CoAuthorUUIDs []string // 共同作者
 	CategoryID    int64
 	TagIDs        []int64
 	Status        string
@@ -49,6 +62,7 @@ type UpdateArticle struct {
 	Content       string
 	FeaturedImage *string
 	AuthorUUID    string
+	CoAuthorUUIDs *[]string // 共同作者，nil 表示不修改
 	CategoryID    int64
 	TagIDs        []int64
 	Status        string
@@ -67,8 +81,10 @@ type ListCategories struct {
 
 // CreateCategory 创建分类参数。
 type CreateCategory struct {
-	Name string
-	Slug string
+	Name      string
+	Slug      string
+	ParentID  *int64
+	SortOrder int32
 }
 
 // UpdateCategory 更新分类参数。
@@ -78,6 +94,13 @@ type UpdateCategory struct {
 	Slug string
 }
 
+// MoveCategory 移动分类参数（连同子树）。
+type MoveCategory struct {
+	ID        int64
+	ParentID  *int64 // nil 表示移动到顶级
+	SortOrder int32
+}
+
 // ==================== 标签 ====================
 
 // ListTags 标签列表参数。
@@ -99,3 +122,27 @@ type UpdateTag struct {
 	Name string
 	Slug string
 }
+
+// MergeTags 合并标签参数。
+type MergeTags struct {
+	SourceIDs []int64
+	TargetID  int64
+}
+
+// 批量打标签操作
+const (
+	RetagActionAdd    = "add"
+	RetagActionRemove = "remove"
+)
+
+// BulkRetag 批量打标签参数，筛选条件至少指定一项。
+type BulkRetag struct {
+	TagID              int64
+	Action             string // add, remove
+	ArticleIDs         []int64
+	CategoryID         IntFilterParam
+	IncludeDescendants bool
+	FilterTagID        IntFilterParam // 仅处理已带有该标签的文章
+	Status             *string
+	Keyword            *string
+}
@@ -10,6 +10,7 @@ type UploadFile struct {
 	ContentType string
 	Usage       string // post_cover, post_content, avatar
 	UserUUID    string
+	Access      string // public（默认）/private
 }
 
 // ListFiles 文件列表参数。
@@ -0,0 +1,50 @@
+package input
+
+// ScanOrphans 孤儿媒体扫描参数。
+type ScanOrphans struct {
+	GraceDays int // 宽限期（天），上传时间在宽限期内的不视为孤儿
+}
+
+// PurgeOrphans 孤儿媒体删除参数，ID 来自扫描报告。
+type PurgeOrphans struct {
+	GraceDays   int
+	FileIDs     []int64
+	ResourceIDs []int64
+}
+
+// ListMediaItems 媒体库列表参数，零值表示不过滤。
+type ListMediaItems struct {
+	PageParams
+	Source     string // file | resource
+	FolderID   *int64 // 0 表示根目录
+	Tag        string
+	Keyword    string
+	MimeFamily string
+}
+
+// UpdateMediaItem 更新媒体库条目的替代文本、说明与标签。
+type UpdateMediaItem struct {
+	Source  string
+	ID      int64
+	AltText string
+	Caption string
+	Tags    []string
+}
+
+// MediaItemRef 媒体库条目引用。
+type MediaItemRef struct {
+	Source string
+	ID     int64
+}
+
+// MoveMediaItems 移动媒体库条目，FolderID 为 nil 表示移回根目录。
+type MoveMediaItems struct {
+	Items    []MediaItemRef
+	FolderID *int64
+}
+
+// SaveMediaFolder 创建或修改文件夹，ParentID 为 nil 表示根目录。
+type SaveMediaFolder struct {
+	Name     string
+	ParentID *int64
+}
@@ -2,20 +2,22 @@ package input
 
 import "io"
 
-// ResourceCheck 检查文件请求。
+// ResourceCheck 检查文件请求，Access 为空时为 public。
 type ResourceCheck struct {
 	FileHash string `json:"file_hash"`
 	FileName string `json:"file_name"`
 	FileSize int64  `json:"file_size"`
 	MimeType string `json:"mime_type"`
+	Access   string `json:"access"`
 }
 
-// ResourceInit 初始化上传任务请求。
+// ResourceInit 初始化上传任务请求，Access 为空时为 public。
 type ResourceInit struct {
 	FileName string `json:"file_name"`
 	FileSize int64  `json:"file_size"`
 	FileHash string `json:"file_hash"`
 	MimeType string `json:"mime_type"`
+	Access   string `json:"access"`
 }
 
 // ResourceUploadChunk 上传分片请求。
@@ -71,3 +73,13 @@ type QiniuCallback struct {
 	Items        []QiniuCallbackItem `json:"items"`
 	CreationDate string              `json:"creationDate"`
 }
+
+// SaveUploadPolicy 保存上传策略，RoleID 与 UserUUID 二选一；指针字段为 nil 表示不设置该项。
+type SaveUploadPolicy struct {
+	RoleID              *int
+	UserUUID            *string
+	QuotaBytes          *int64
+	MaxFileSize         *int64
+	DailyUploadBytes    *int64
+	AllowedMimeFamilies []string
+}
@@ -4,6 +4,7 @@ package input
 type UpdateProfile struct {
 	Nickname string
 	Avatar   string
+	Bio      *string // 为空表示不修改
 }
 
 // ListUsers 用户列表参数（管理端）。
@@ -0,0 +1,286 @@
+package mediagc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"server-blog-v2/internal/entity"
+	"server-blog-v2/internal/repo"
+	"server-blog-v2/internal/usecase"
+	"server-blog-v2/internal/usecase/input"
+	"server-blog-v2/internal/usecase/output"
+)
+
+var ErrRepo = errors.New("repo")
+
+// DefaultGraceDays 默认宽限期，避免误删刚上传、尚未保存到文章中的文件。
+const DefaultGraceDays = 7
+
+// tokenPattern 匹配文本中的 URL/路径片段（Markdown、HTML 属性、JSON 配置均适用）。
+var tokenPattern = regexp.MustCompile(`[^\s"'()<>\[\]{},\\]+`)
+
+type useCase struct {
+	files       repo.FileRepo
+	resources   repo.ResourceRepo
+	references  repo.MediaReferenceRepo
+	objectStore repo.ObjectStore
+	transcoder  repo.Transcoder // 为 nil 时直接删除转码 Key
+}
+
+// New 创建 MediaGC UseCase。
+func New(files repo.FileRepo, resources repo.ResourceRepo, references repo.MediaReferenceRepo, objectStore repo.ObjectStore, transcoder repo.Transcoder) usecase.MediaGC {
+	return &useCase{
+		files:       files,
+		resources:   resources,
+		references:  references,
+		objectStore: objectStore,
+		transcoder:  transcoder,
+	}
+}
+
+func (u *useCase) ScanOrphans(ctx context.Context, params input.ScanOrphans) (*output.OrphanReport, error) {
+	graceDays := normalizeGraceDays(params.GraceDays)
+
+	files, resources, err := u.findOrphans(ctx, graceDays)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &output.OrphanReport{
+		GraceDays: graceDays,
+		ScannedAt: time.Now(),
+		Files:     make([]output.OrphanFile, len(files)),
+		Resources: make([]output.OrphanResource, len(resources)),
+	}
+	for i, f := range files {
+		report.Files[i] = output.OrphanFile{
+			ID:        f.ID,
+			Key:       f.Key,
+			Filename:  f.Filename,
+			URL:       u.objectStore.GetURL(f.Key),
+			Size:      f.Size,
+			MimeType:  f.MimeType,
+			Usage:     f.Usage,
+			CreatedAt: f.CreatedAt,
+		}
+		report.TotalSize += f.Size
+		for _, v := range f.Variants {
+			report.TotalSize += v.Size
+		}
+	}
+	for i, r := range resources {
+		report.Resources[i] = output.OrphanResource{
+			ID:        r.ID,
+			FileKey:   r.FileKey,
+			FileName:  r.FileName,
+			URL:       u.objectStore.GetURL(r.FileKey),
+			FileSize:  r.FileSize,
+			MimeType:  r.MimeType,
+			CreatedAt: r.CreatedAt,
+		}
+		report.TotalSize += r.FileSize
+	}
+
+	return report, nil
+}
+
+func (u *useCase) PurgeOrphans(ctx context.Context, params input.PurgeOrphans) (*output.OrphanPurgeResult, error) {
+	graceDays := normalizeGraceDays(params.GraceDays)
+
+	// 重新扫描，防止报告生成后文件又被引用
+	files, resources, err := u.findOrphans(ctx, graceDays)
+	if err != nil {
+		return nil, err
+	}
+
+	orphanFiles := make(map[int64]*entity.File, len(files))
+	for _, f := range files {
+		orphanFiles[f.ID] = f
+	}
+	orphanResources := make(map[int64]*entity.Resource, len(resources))
+	for _, r := range resources {
+		orphanResources[r.ID] = r
+	}
+
+	result := &output.OrphanPurgeResult{
+		SkippedFileIDs:   []int64{},
+		SkippedResources: []int64{},
+		FailedKeys:       []string{},
+	}
+
+	// 对象全部删除成功后才删除记录，失败的记录保留，下次清理时重试
+	var fileIDs []int64
+	for _, id := range params.FileIDs {
+		f, ok := orphanFiles[id]
+		if !ok {
+			result.SkippedFileIDs = append(result.SkippedFileIDs, id)
+			continue
+		}
+		keys := []string{f.Key}
+		size := f.Size
+		for _, v := range f.Variants {
+			keys = append(keys, v.Key)
+			size += v.Size
+		}
+		if failed := u.deleteObjects(ctx, keys); len(failed) > 0 {
+			result.FailedKeys = append(result.FailedKeys, failed...)
+			result.Failed++
+			continue
+		}
+		result.FreedSize += size
+		fileIDs = append(fileIDs, id)
+	}
+	if len(fileIDs) > 0 {
+		if err := u.files.DeleteByIDs(ctx, fileIDs); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrRepo, err)
+		}
+	}
+	result.DeletedFiles = len(fileIDs)
+
+	var resourceIDs []int64
+	for _, id := range params.ResourceIDs {
+		r, ok := orphanResources[id]
+		if !ok {
+			result.SkippedResources = append(result.SkippedResources, id)
+			continue
+		}
+		failed := u.deleteObjects(ctx, []string{r.FileKey})
+		if u.transcoder != nil {
+			if err := u.transcoder.DeleteOutputs(ctx, r); err != nil {
+				failed = append(failed, outputKeys(r)...)
+			}
+		} else {
+			failed = append(failed, u.deleteObjects(ctx, outputKeys(r))...)
+		}
+		if len(failed) > 0 {
+			result.FailedKeys = append(result.FailedKeys, failed...)
+			result.Failed++
+			continue
+		}
+		result.FreedSize += r.FileSize
+		resourceIDs = append(resourceIDs, id)
+	}
+	if len(resourceIDs) > 0 {
+		if err := u.resources.DeleteByIDs(ctx, resourceIDs); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrRepo, err)
+		}
+	}
+	result.DeletedResources = len(resourceIDs)
+
+	return result, nil
+}
+
+// deleteObjects 逐个删除对象，返回删除失败的 Key。
+func (u *useCase) deleteObjects(ctx context.Context, keys []string) []string {
+	var failed []string
+	for _, key := range keys {
+		if err := u.objectStore.Delete(ctx, key); err != nil {
+			failed = append(failed, key)
+		}
+	}
+	return failed
+}
+
+// outputKeys 资源的转码产物 Key（不含 HLS 分片，分片由转码后端按播放列表删除）。
+func outputKeys(r *entity.Resource) []string {
+	var keys []string
+	if r.TranscodeKey != "" {
+		keys = append(keys, r.TranscodeKey)
+	}
+	if r.ThumbnailKey != "" {
+		keys = append(keys, r.ThumbnailKey)
+	}
+	return keys
+}
+
+// findOrphans 找出宽限期之前上传、且没有任何引用的文件与资源。
+func (u *useCase) findOrphans(ctx context.Context, graceDays int) ([]*entity.File, []*entity.Resource, error) {
+	referenced, err := u.collectReferences(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	before := time.Now().AddDate(0, 0, -graceDays)
+
+	files, err := u.files.ListCreatedBefore(ctx, before)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrRepo, err)
+	}
+	var orphanFiles []*entity.File
+	for _, f := range files {
+		keys := []string{f.Key}
+		for _, v := range f.Variants {
+			keys = append(keys, v.Key)
+		}
+		if !anyReferenced(referenced, keys...) {
+			orphanFiles = append(orphanFiles, f)
+		}
+	}
+
+	resources, err := u.resources.ListCreatedBefore(ctx, before)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrRepo, err)
+	}
+	var orphanResources []*entity.Resource
+	for _, r := range resources {
+		if !anyReferenced(referenced, r.FileKey, r.TranscodeKey, r.ThumbnailKey) {
+			orphanResources = append(orphanResources, r)
+		}
+	}
+
+	return orphanFiles, orphanResources, nil
+}
+
+// collectReferences 从所有引用来源中提取可能的对象 Key。
+// 对每个含 '/' 的片段，去掉查询参数后记录其自身及每个 '/' 之后的后缀，
+// 这样无论是完整 URL（任意域名、带签名）还是相对 Key 都能命中。
+func (u *useCase) collectReferences(ctx context.Context) (map[string]struct{}, error) {
+	referenced := make(map[string]struct{})
+	err := u.references.ScanTexts(ctx, func(text string) {
+		for _, token := range tokenPattern.FindAllString(text, -1) {
+			if !strings.Contains(token, "/") {
+				continue
+			}
+			token, _, _ = strings.Cut(token, "?")
+			token, _, _ = strings.Cut(token, "#")
+			if unescaped, err := url.PathUnescape(token); err == nil {
+				token = unescaped
+			}
+
+			referenced[token] = struct{}{}
+			for i := 0; i < len(token); i++ {
+				if token[i] == '/' && i+1 < len(token) {
+					referenced[token[i+1:]] = struct{}{}
+				}
+			}
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRepo, err)
+	}
+	return referenced, nil
+}
+
+func anyReferenced(referenced map[string]struct{}, keys ...string) bool {
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		if _, ok := referenced[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeGraceDays(days int) int {
+	if days <= 0 {
+		return DefaultGraceDays
+	}
+	return days
+}
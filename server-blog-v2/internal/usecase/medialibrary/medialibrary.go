@@ -0,0 +1,469 @@
+package medialibrary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"server-blog-v2/internal/entity"
+	"server-blog-v2/internal/repo"
+	"server-blog-v2/internal/usecase"
+	"server-blog-v2/internal/usecase/input"
+	"server-blog-v2/internal/usecase/output"
+)
+
+var (
+	ErrRepo     = errors.New("repo")
+	ErrNotFound = errors.New("not found")
+	// ErrInvalidParam 参数校验失败
+	ErrInvalidParam = errors.New("invalid param")
+	// ErrFolderConflict 同一上级文件夹下已有同名文件夹
+	ErrFolderConflict = errors.New("folder name conflict")
+)
+
+const (
+	// MaxTags 单个条目最多标签数
+	MaxTags = 20
+	// MaxTagLength 标签最大长度（字符）
+	MaxTagLength = 50
+	// MaxAltTextLength 替代文本最大长度（字符）
+	MaxAltTextLength = 255
+	// MaxFolderNameLength 文件夹名称最大长度（字符）
+	MaxFolderNameLength = 100
+)
+
+type useCase struct {
+	library     repo.MediaLibraryRepo
+	folders     repo.MediaFolderRepo
+	files       repo.FileRepo
+	resources   repo.ResourceRepo
+	objectStore repo.ObjectStore
+	signedTTL   time.Duration
+}
+
+// New 创建 MediaLibrary UseCase。
+func New(
+	library repo.MediaLibraryRepo,
+	folders repo.MediaFolderRepo,
+	files repo.FileRepo,
+	resources repo.ResourceRepo,
+	objectStore repo.ObjectStore,
+	signedURLTTL time.Duration,
+) usecase.MediaLibrary {
+	if signedURLTTL <= 0 {
+		signedURLTTL = entity.DefaultSignedURLTTL
+	}
+	return &useCase{
+		library:     library,
+		folders:     folders,
+		files:       files,
+		resources:   resources,
+		objectStore: objectStore,
+		signedTTL:   signedURLTTL,
+	}
+}
+
+func (u *useCase) ListItems(ctx context.Context, params input.ListMediaItems) (*output.ListResult[output.MediaItemInfo], error) {
+	if err := validateSource(params.Source, true); err != nil {
+		return nil, err
+	}
+
+	offset := (params.Page - 1) * params.PageSize
+	items, total, err := u.library.List(ctx, offset, params.PageSize, repo.MediaListFilter{
+		Source:     params.Source,
+		FolderID:   params.FolderID,
+		Tag:        strings.TrimSpace(params.Tag),
+		Keyword:    strings.TrimSpace(params.Keyword),
+		MimeFamily: strings.ToLower(strings.TrimSpace(params.MimeFamily)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRepo, err)
+	}
+
+	infos := make([]output.MediaItemInfo, len(items))
+	for i, item := range items {
+		infos[i] = output.MediaItemInfo{
+			Source:    item.Source,
+			ID:        item.SourceID,
+			Key:       item.Key,
+			Name:      item.Name,
+			URL:       u.itemURL(item, item.Key),
+			Size:      item.Size,
+			MimeType:  item.MimeType,
+			Access:    item.Access,
+			FolderID:  item.FolderID,
+			AltText:   item.AltText,
+			Caption:   item.Caption,
+			Tags:      item.Tags,
+			CreatedAt: item.CreatedAt,
+		}
+		if item.ThumbnailKey != "" {
+			url := u.itemURL(item, item.ThumbnailKey)
+			infos[i].ThumbnailURL = &url
+		}
+	}
+
+	return &output.ListResult[output.MediaItemInfo]{
+		Items:    infos,
+		Page:     params.Page,
+		PageSize: params.PageSize,
+		Total:    total,
+	}, nil
+}
+
+func (u *useCase) UpdateItem(ctx context.Context, params input.UpdateMediaItem) error {
+	if err := validateSource(params.Source, false); err != nil {
+		return err
+	}
+	if utf8.RuneCountInString(params.AltText) > MaxAltTextLength {
+		return fmt.Errorf("%w: 替代文本不能超过 %d 个字符", ErrInvalidParam, MaxAltTextLength)
+	}
+	tags, err := normalizeTags(params.Tags)
+	if err != nil {
+		return err
+	}
+
+	ref := entity.MediaRef{Source: params.Source, SourceID: params.ID}
+	if err := u.ensureItemsExist(ctx, []entity.MediaRef{ref}); err != nil {
+		return err
+	}
+
+	if err := u.library.UpdateMeta(ctx, ref, strings.TrimSpace(params.AltText), strings.TrimSpace(params.Caption), tags); err != nil {
+		return fmt.Errorf("%w: %v", ErrRepo, err)
+	}
+	return nil
+}
+
+func (u *useCase) MoveItems(ctx context.Context, params input.MoveMediaItems) error {
+	if len(params.Items) == 0 {
+		return nil
+	}
+
+	refs := make([]entity.MediaRef, len(params.Items))
+	for i, item := range params.Items {
+		if err := validateSource(item.Source, false); err != nil {
+			return err
+		}
+		refs[i] = entity.MediaRef{Source: item.Source, SourceID: item.ID}
+	}
+
+	if params.FolderID != nil {
+		if err := u.ensureFolderExists(ctx, *params.FolderID); err != nil {
+			return err
+		}
+	}
+	if err := u.ensureItemsExist(ctx, refs); err != nil {
+		return err
+	}
+
+	if err := u.library.Move(ctx, refs, params.FolderID); err != nil {
+		return fmt.Errorf("%w: %v", ErrRepo, err)
+	}
+	return nil
+}
+
+func (u *useCase) ListTags(ctx context.Context) (*output.AllResult[output.MediaTagCount], error) {
+	tags, err := u.library.ListTags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRepo, err)
+	}
+
+	items := make([]output.MediaTagCount, len(tags))
+	for i, t := range tags {
+		items[i] = output.MediaTagCount{Tag: t.Tag, Count: t.Count}
+	}
+	return &output.AllResult[output.MediaTagCount]{
+		Items: items,
+		Total: int64(len(items)),
+	}, nil
+}
+
+func (u *useCase) GetItemUsage(ctx context.Context, source string, id int64) (*output.AllResult[output.MediaUsageInfo], error) {
+	if err := validateSource(source, false); err != nil {
+		return nil, err
+	}
+
+	// 收集条目的全部对象 Key：原文件、图片版本、转码产物与封面
+	var keys []string
+	switch source {
+	case entity.MediaSourceFile:
+		files, err := u.files.GetByIDs(ctx, []int64{id})
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrRepo, err)
+		}
+		if len(files) == 0 {
+			return nil, ErrNotFound
+		}
+		keys = append(keys, files[0].Key)
+		for _, v := range files[0].Variants {
+			keys = append(keys, v.Key)
+		}
+	case entity.MediaSourceResource:
+		r, err := u.resources.GetByID(ctx, id)
+		if errors.Is(err, repo.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrRepo, err)
+		}
+		keys = append(keys, r.FileKey, r.TranscodeKey, r.ThumbnailKey)
+	}
+
+	usages, err := u.library.ListUsage(ctx, keys)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRepo, err)
+	}
+
+	items := make([]output.MediaUsageInfo, len(usages))
+	for i, usage := range usages {
+		items[i] = output.MediaUsageInfo{
+			ArticleID: usage.ArticleID,
+			Title:     usage.Title,
+			Slug:      usage.Slug,
+			Status:    usage.Status,
+			InCover:   usage.InCover,
+			InContent: usage.InContent,
+		}
+	}
+	return &output.AllResult[output.MediaUsageInfo]{
+		Items: items,
+		Total: int64(len(items)),
+	}, nil
+}
+
+func (u *useCase) ListFolders(ctx context.Context) (*output.AllResult[output.MediaFolderInfo], error) {
+	folders, err := u.folders.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRepo, err)
+	}
+
+	items := make([]output.MediaFolderInfo, len(folders))
+	for i, f := range folders {
+		items[i] = output.MediaFolderInfo{
+			ID:        f.ID,
+			Name:      f.Name,
+			ParentID:  f.ParentID,
+			CreatedAt: f.CreatedAt,
+			UpdatedAt: f.UpdatedAt,
+		}
+	}
+	return &output.AllResult[output.MediaFolderInfo]{
+		Items: items,
+		Total: int64(len(items)),
+	}, nil
+}
+
+func (u *useCase) CreateFolder(ctx context.Context, params input.SaveMediaFolder) (int64, error) {
+	name, err := normalizeFolderName(params.Name)
+	if err != nil {
+		return 0, err
+	}
+	folders, err := u.folders.List(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrRepo, err)
+	}
+	if params.ParentID != nil && findFolder(folders, *params.ParentID) == nil {
+		return 0, fmt.Errorf("%w: 上级文件夹 %d", ErrNotFound, *params.ParentID)
+	}
+	if folderNameTaken(folders, params.ParentID, name, 0) {
+		return 0, fmt.Errorf("%w: %s", ErrFolderConflict, name)
+	}
+
+	id, err := u.folders.Create(ctx, &entity.MediaFolder{Name: name, ParentID: params.ParentID})
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrRepo, err)
+	}
+	return id, nil
+}
+
+func (u *useCase) UpdateFolder(ctx context.Context, id int64, params input.SaveMediaFolder) error {
+	name, err := normalizeFolderName(params.Name)
+	if err != nil {
+		return err
+	}
+
+	folders, err := u.folders.List(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrRepo, err)
+	}
+	parents := make(map[int64]*int64, len(folders))
+	for _, f := range folders {
+		parents[f.ID] = f.ParentID
+	}
+	if _, ok := parents[id]; !ok {
+		return ErrNotFound
+	}
+
+	// 新的上级不能是自己或自己的子孙文件夹
+	if params.ParentID != nil {
+		for p := params.ParentID; p != nil; p = parents[*p] {
+			if *p == id {
+				return fmt.Errorf("%w: 不能移动到自身或其子文件夹下", ErrInvalidParam)
+			}
+			if _, ok := parents[*p]; !ok {
+				return fmt.Errorf("%w: 上级文件夹 %d", ErrNotFound, *p)
+			}
+		}
+	}
+	if folderNameTaken(folders, params.ParentID, name, id) {
+		return fmt.Errorf("%w: %s", ErrFolderConflict, name)
+	}
+
+	if err := u.folders.Update(ctx, &entity.MediaFolder{ID: id, Name: name, ParentID: params.ParentID}); err != nil {
+		return fmt.Errorf("%w: %v", ErrRepo, err)
+	}
+	return nil
+}
+
+func (u *useCase) DeleteFolder(ctx context.Context, id int64) error {
+	folders, err := u.folders.List(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrRepo, err)
+	}
+	folder := findFolder(folders, id)
+	if folder == nil {
+		return ErrNotFound
+	}
+
+	// 子文件夹移到上级文件夹后不能与上级中已有的文件夹重名（唯一索引 (COALESCE(parent_id, 0), name)）
+	for _, f := range folders {
+		if f.ParentID != nil && *f.ParentID == id && folderNameTaken(folders, folder.ParentID, f.Name, id) {
+			return fmt.Errorf("%w: %s", ErrFolderConflict, f.Name)
+		}
+	}
+
+	if err := u.folders.Delete(ctx, id); err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("%w: %v", ErrRepo, err)
+	}
+	return nil
+}
+
+// ensureFolderExists 校验文件夹存在。
+func (u *useCase) ensureFolderExists(ctx context.Context, id int64) error {
+	if _, err := u.folders.GetByID(ctx, id); err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return fmt.Errorf("%w: 文件夹 %d", ErrNotFound, id)
+		}
+		return fmt.Errorf("%w: %v", ErrRepo, err)
+	}
+	return nil
+}
+
+func findFolder(folders []*entity.MediaFolder, id int64) *entity.MediaFolder {
+	for _, f := range folders {
+		if f.ID == id {
+			return f
+		}
+	}
+	return nil
+}
+
+// folderNameTaken 判断上级文件夹下是否已有同名文件夹（不含 excludeID 自身）。
+func folderNameTaken(folders []*entity.MediaFolder, parentID *int64, name string, excludeID int64) bool {
+	for _, f := range folders {
+		if f.ID == excludeID || f.Name != name {
+			continue
+		}
+		if (f.ParentID == nil && parentID == nil) || (f.ParentID != nil && parentID != nil && *f.ParentID == *parentID) {
+			return true
+		}
+	}
+	return false
+}
+
+// itemURL 返回条目对象的访问地址。私有对象返回鉴权访问的稳定地址，
+// 仅旧数据（私有但对象不在 private/ 前缀下）使用带有效期的签名地址。
+func (u *useCase) itemURL(item *entity.MediaItem, key string) string {
+	if item.Access == entity.ResourceAccessPrivate && !entity.IsPrivateKey(key) {
+		return u.objectStore.SignedURL(key, u.signedTTL)
+	}
+	return u.objectStore.GetURL(key)
+}
+
+// ensureItemsExist 校验条目对应的文件或资源存在。
+func (u *useCase) ensureItemsExist(ctx context.Context, refs []entity.MediaRef) error {
+	var fileIDs, resourceIDs []int64
+	for _, ref := range refs {
+		if ref.Source == entity.MediaSourceFile {
+			fileIDs = append(fileIDs, ref.SourceID)
+		} else {
+			resourceIDs = append(resourceIDs, ref.SourceID)
+		}
+	}
+	slices.Sort(fileIDs)
+	fileIDs = slices.Compact(fileIDs)
+	slices.Sort(resourceIDs)
+	resourceIDs = slices.Compact(resourceIDs)
+
+	if len(fileIDs) > 0 {
+		files, err := u.files.GetByIDs(ctx, fileIDs)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrRepo, err)
+		}
+		if len(files) != len(fileIDs) {
+			return ErrNotFound
+		}
+	}
+	if len(resourceIDs) > 0 {
+		resources, err := u.resources.GetByIDs(ctx, resourceIDs)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrRepo, err)
+		}
+		if len(resources) != len(resourceIDs) {
+			return ErrNotFound
+		}
+	}
+	return nil
+}
+
+func validateSource(source string, allowEmpty bool) error {
+	switch source {
+	case entity.MediaSourceFile, entity.MediaSourceResource:
+		return nil
+	case "":
+		if allowEmpty {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: 不支持的媒体来源 %q", ErrInvalidParam, source)
+}
+
+// normalizeTags 去除首尾空白、空标签与重复标签。
+func normalizeTags(tags []string) ([]string, error) {
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || slices.Contains(result, tag) {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > MaxTagLength {
+			return nil, fmt.Errorf("%w: 标签不能超过 %d 个字符: %s", ErrInvalidParam, MaxTagLength, tag)
+		}
+		result = append(result, tag)
+	}
+	if len(result) > MaxTags {
+		return nil, fmt.Errorf("%w: 标签不能超过 %d 个", ErrInvalidParam, MaxTags)
+	}
+	return result, nil
+}
+
+func normalizeFolderName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("%w: 文件夹名称不能为空", ErrInvalidParam)
+	}
+	if utf8.RuneCountInString(name) > MaxFolderNameLength {
+		return "", fmt.Errorf("%w: 文件夹名称不能超过 %d 个字符", ErrInvalidParam, MaxFolderNameLength)
+	}
+	if strings.ContainsAny(name, "/\\") {
+		return "", fmt.Errorf("%w: 文件夹名称不能包含斜杠", ErrInvalidParam)
+	}
+	return name, nil
+}
@@ -10,6 +10,7 @@ type BaseArticle struct {
 	Excerpt       string     `json:"excerpt"`
 	FeaturedImage string     `json:"featured_image"`
 	AuthorUUID    string     `json:"author_uuid"`
+	CoAuthorUUIDs []string   `json:"co_author_uuids"`
 	Status        string     `json:"status"`
 	Visibility    string     `json:"visibility"` // 可见性：public, private
 	ReadTime      string     `json:"read_time"`
@@ -54,6 +55,8 @@ type ArticleSummary struct {
 	Like     LikeInfo     `json:"like"`
 	Category BaseCategory `json:"category"`
 	Tags     []BaseTag    `json:"tags"`
+	// Highlight 全文检索命中片段（仅 postgres 搜索引擎返回）
+	Highlight string `json:"highlight,omitempty"`
 }
 
 // ArticleDetail 文章详情。
@@ -66,6 +69,42 @@ type ArticleDetail struct {
 	Content         string       `json:"content"`
 	MetaTitle       string       `json:"meta_title"`
 	MetaDescription string       `json:"meta_description"`
+	// Breadcrumb 分类路径（从顶级到当前分类）
+	Breadcrumb []BaseCategory `json:"breadcrumb"`
+	// Byline 署名（主作者在前，随后为共同作者）
+	Byline []AuthorInfo `json:"byline"`
+}
+
+// AuthorProfile 作者主页信息。
+type AuthorProfile struct {
+	AuthorInfo
+	Bio          string    `json:"bio"`
+	ArticleCount int64     `json:"article_count"`
+	JoinedAt     time.Time `json:"joined_at"`
+}
+
+// ==================== 归档 ====================
+
+// ArchiveArticle 归档文章条目。
+type ArchiveArticle struct {
+	ID          int64     `json:"id"`
+	Title       string    `json:"title"`
+	Slug        string    `json:"slug"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+// ArchiveMonth 归档月份。
+type ArchiveMonth struct {
+	Month    int              `json:"month"`
+	Count    int64            `json:"count"`
+	Articles []ArchiveArticle `json:"articles"`
+}
+
+// ArchiveYear 归档年份。
+type ArchiveYear struct {
+	Year   int            `json:"year"`
+	Count  int64          `json:"count"`
+	Months []ArchiveMonth `json:"months"`
 }
 
 // ==================== 分类 ====================
@@ -73,11 +112,20 @@ type ArticleDetail struct {
 // CategoryDetail 分类详情。
 type CategoryDetail struct {
 	BaseCategory
+	ParentID     *int64    `json:"parent_id"`
+	SortOrder    int32     `json:"sort_order"`
 	ArticleCount int32     `json:"article_count"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 }
 
+// CategoryTreeNode 分类树节点。
+type CategoryTreeNode struct {
+	CategoryDetail
+	TotalArticleCount int32              `json:"total_article_count"` // 包含子孙分类的文章数
+	Children          []CategoryTreeNode `json:"children"`
+}
+
 // ==================== 标签 ====================
 
 // TagDetail 标签详情。
@@ -87,3 +135,17 @@ type TagDetail struct {
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 }
+
+// ResolvedTag 按 slug 解析的标签，Redirected 表示通过旧 slug（别名）命中。
+type ResolvedTag struct {
+	TagDetail
+	Redirected bool `json:"redirected"`
+}
+
+// TagAlias 标签别名。
+type TagAlias struct {
+	ID        int64     `json:"id"`
+	Slug      string    `json:"slug"`
+	TagID     int64     `json:"tag_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -5,6 +5,20 @@ import "time"
 // UploadResult 上传结果。
 type UploadResult struct {
 	URL string `json:"url"` // 完整 URL（包含 CDN 域名），前端直接使用
+	// 以下仅图片返回
+	Width      int            `json:"width,omitempty"`
+	Height     int            `json:"height,omitempty"`
+	Variants   []ImageVariant `json:"variants,omitempty"`
+	SrcSet     string         `json:"srcset,omitempty"`      // 原格式 srcset（含原图）
+	WebPSrcSet string         `json:"webp_srcset,omitempty"` // WebP srcset，用于 <picture><source type="image/webp">
+}
+
+// ImageVariant 图片版本。
+type ImageVariant struct {
+	URL      string `json:"url"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+	MimeType string `json:"mime_type"`
 }
 
 // FileInfo 文件信息。
@@ -14,5 +28,6 @@ type FileInfo struct {
 	URL       string    `json:"url"`
 	Size      int64     `json:"size"`
 	MimeType  string    `json:"mime_type"`
+	Access    string    `json:"access"`
 	CreatedAt time.Time `json:"created_at"`
 }
@@ -0,0 +1,89 @@
+package output
+
+import "time"
+
+// OrphanFile 未被引用的文件。
+type OrphanFile struct {
+	ID        int64     `json:"id"`
+	Key       string    `json:"key"`
+	Filename  string    `json:"filename"`
+	URL       string    `json:"url"`
+	Size      int64     `json:"size"`
+	MimeType  string    `json:"mime_type"`
+	Usage     string    `json:"usage"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// OrphanResource 未被引用的资源。
+type OrphanResource struct {
+	ID        int64     `json:"id"`
+	FileKey   string    `json:"file_key"`
+	FileName  string    `json:"file_name"`
+	URL       string    `json:"url"`
+	FileSize  int64     `json:"file_size"`
+	MimeType  string    `json:"mime_type"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// OrphanReport 孤儿媒体扫描报告。
+type OrphanReport struct {
+	GraceDays int              `json:"grace_days"`
+	ScannedAt time.Time        `json:"scanned_at"`
+	Files     []OrphanFile     `json:"files"`
+	Resources []OrphanResource `json:"resources"`
+	TotalSize int64            `json:"total_size"` // 可释放的空间（字节）
+}
+
+// OrphanPurgeResult 孤儿媒体删除结果。
+type OrphanPurgeResult struct {
+	DeletedFiles     int      `json:"deleted_files"`
+	DeletedResources int      `json:"deleted_resources"`
+	FreedSize        int64    `json:"freed_size"`
+	SkippedFileIDs   []int64  `json:"skipped_file_ids"`     // 已被引用或仍在宽限期内
+	SkippedResources []int64  `json:"skipped_resource_ids"` // 已被引用或仍在宽限期内
+	Failed           int      `json:"failed"`               // 对象删除失败而保留的记录数，可再次提交重试
+	FailedKeys       []string `json:"failed_keys"`          // 删除失败的对象 Key
+}
+
+// MediaItemInfo 媒体库条目。
+type MediaItemInfo struct {
+	Source       string    `json:"source"`
+	ID           int64     `json:"id"`
+	Key          string    `json:"key"`
+	Name         string    `json:"name"`
+	URL          string    `json:"url"`
+	ThumbnailURL *string   `json:"thumbnail_url,omitempty"`
+	Size         int64     `json:"size"`
+	MimeType     string    `json:"mime_type"`
+	Access       string    `json:"access"`
+	FolderID     *int64    `json:"folder_id"`
+	AltText      string    `json:"alt_text"`
+	Caption      string    `json:"caption"`
+	Tags         []string  `json:"tags"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// MediaFolderInfo 媒体库文件夹。
+type MediaFolderInfo struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	ParentID  *int64    `json:"parent_id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// MediaTagCount 媒体标签及使用次数。
+type MediaTagCount struct {
+	Tag   string `json:"tag"`
+	Count int64  `json:"count"`
+}
+
+// MediaUsageInfo 引用媒体的文章。
+type MediaUsageInfo struct {
+	ArticleID int64  `json:"article_id"`
+	Title     string `json:"title"`
+	Slug      string `json:"slug"`
+	Status    string `json:"status"`
+	InCover   bool   `json:"in_cover"`
+	InContent bool   `json:"in_content"`
+}
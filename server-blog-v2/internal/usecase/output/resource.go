@@ -51,5 +51,48 @@ type ResourceInfo struct {
 	TranscodeStatus int8      `json:"transcode_status"`
 	TranscodeURL    *string   `json:"transcode_url,omitempty"`
 	ThumbnailURL    *string   `json:"thumbnail_url,omitempty"`
+	Access          string    `json:"access"`
 	CreatedAt       time.Time `json:"created_at"`
 }
+
+// UploadReapResult 一次过期任务清理的结果。
+type UploadReapResult struct {
+	Failed        int       `json:"failed"`         // 上传中过期，标记为失败
+	Cancelled     int       `json:"cancelled"`      // 未上传任何块即过期，标记为取消
+	DeletedBlocks int       `json:"deleted_blocks"` // 删除的临时块数量
+	StartedAt     time.Time `json:"started_at"`
+	FinishedAt    time.Time `json:"finished_at"`
+}
+
+// UploadReapStats 过期任务清理统计，供管理端资源页展示。
+type UploadReapStats struct {
+	LastRun        *UploadReapResult `json:"last_run"` // 从未运行时为 null
+	TotalFailed    int64             `json:"total_failed"`
+	TotalCancelled int64             `json:"total_cancelled"`
+	TotalBlocks    int64             `json:"total_blocks"`
+}
+
+// UploadPolicyInfo 上传策略。
+type UploadPolicyInfo struct {
+	ID                  int64     `json:"id"`
+	RoleID              *int      `json:"role_id"`
+	UserUUID            *string   `json:"user_uuid"`
+	QuotaBytes          *int64    `json:"quota_bytes"`
+	MaxFileSize         *int64    `json:"max_file_size"`
+	DailyUploadBytes    *int64    `json:"daily_upload_bytes"`
+	AllowedMimeFamilies []string  `json:"allowed_mime_families"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// StorageUsage 用户存储用量与生效的上传策略，限制项为 0 表示不限制。
+type StorageUsage struct {
+	UserUUID            string   `json:"user_uuid"`
+	RoleID              int      `json:"role_id"`
+	UsedBytes           int64    `json:"used_bytes"`
+	ReservedBytes       int64    `json:"reserved_bytes"` // 未完成上传任务预留的空间
+	QuotaBytes          int64    `json:"quota_bytes"`
+	TodayUploadBytes    int64    `json:"today_upload_bytes"`
+	DailyUploadBytes    int64    `json:"daily_upload_bytes"`
+	MaxFileSize         int64    `json:"max_file_size"`
+	AllowedMimeFamilies []string `json:"allowed_mime_families"`
+}
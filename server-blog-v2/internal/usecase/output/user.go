@@ -10,6 +10,7 @@ type UserProfile struct {
 	Email     string `json:"email"`
 	Avatar    string `json:"avatar"`
 	Signature string `json:"signature"`
+	Bio       string `json:"bio"` // 作者简介，与 SSO 签名相互独立
 	RoleID    int    `json:"role_id"`
 }
 
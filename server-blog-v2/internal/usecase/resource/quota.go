@@ -0,0 +1,245 @@
+package resource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"server-blog-v2/internal/entity"
+	"server-blog-v2/internal/repo"
+	"server-blog-v2/internal/usecase/input"
+	"server-blog-v2/internal/usecase/output"
+)
+
+// mimeFamilies 可在策略中使用的 MIME 大类。
+var mimeFamilies = []string{"image", "video", "audio", "application", "text"}
+
+// uploadPolicy 合并角色与用户策略后的生效策略，0 表示不限制。
+type uploadPolicy struct {
+	roleID           int
+	quotaBytes       int64
+	maxFileSize      int64
+	dailyUploadBytes int64
+	mimeFamilies     []string // 为空表示不限制
+}
+
+// resolvePolicy 计算用户的生效策略：用户策略中设置的项优先，其余继承角色策略，
+// 单文件大小不超过全局上限。
+func (u *useCase) resolvePolicy(ctx context.Context, userUUID string) (*uploadPolicy, error) {
+	roleID, err := u.users.GetRoleByUUID(ctx, userUUID)
+	if errors.Is(err, repo.ErrNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRepo, err)
+	}
+
+	policies, err := u.policies.ListFor(ctx, roleID, userUUID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRepo, err)
+	}
+
+	// 先应用角色策略，再用用户策略覆盖
+	slices.SortFunc(policies, func(a, b *entity.UploadPolicy) int {
+		if a.UserUUID == nil && b.UserUUID != nil {
+			return -1
+		}
+		if a.UserUUID != nil && b.UserUUID == nil {
+			return 1
+		}
+		return 0
+	})
+
+	p := &uploadPolicy{roleID: roleID}
+	for _, policy := range policies {
+		if policy.QuotaBytes != nil {
+			p.quotaBytes = *policy.QuotaBytes
+		}
+		if policy.MaxFileSize != nil {
+			p.maxFileSize = *policy.MaxFileSize
+		}
+		if policy.DailyUploadBytes != nil {
+			p.dailyUploadBytes = *policy.DailyUploadBytes
+		}
+		if policy.AllowedMimeFamilies != nil {
+			p.mimeFamilies = policy.AllowedMimeFamilies
+		}
+	}
+
+	globalMax := u.GetMaxFileSize(ctx)
+	if p.maxFileSize <= 0 || p.maxFileSize > globalMax {
+		p.maxFileSize = globalMax
+	}
+
+	return p, nil
+}
+
+// checkFile 校验文件类型与单文件大小。
+func (p *uploadPolicy) checkFile(mimeType string, fileSize int64) error {
+	if len(p.mimeFamilies) > 0 && !slices.Contains(p.mimeFamilies, mimeFamily(mimeType)) {
+		return fmt.Errorf("当前角色不允许上传该类型文件: %s", mimeType)
+	}
+	if fileSize > p.maxFileSize {
+		return errors.New("文件大小超过限制")
+	}
+	return nil
+}
+
+// reserveUpload 校验文件并计入当天上传量，返回计入的日期，
+// 之后创建任务或资源失败时需调用 releaseDaily 退回。
+func (u *useCase) reserveUpload(ctx context.Context, userUUID, mimeType string, fileSize int64) (*uploadPolicy, time.Time, error) {
+	p, err := u.resolvePolicy(ctx, userUUID)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	if err := p.checkFile(mimeType, fileSize); err != nil {
+		return nil, time.Time{}, err
+	}
+
+	day := startOfDay(time.Now())
+	ok, err := u.usage.ReserveDaily(ctx, userUUID, day, fileSize, p.dailyUploadBytes)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("%w: %v", ErrRepo, err)
+	}
+	if !ok {
+		return nil, time.Time{}, fmt.Errorf("今日上传量超过限制: 上限 %d 字节", p.dailyUploadBytes)
+	}
+	return p, day, nil
+}
+
+// releaseDaily 退回计入 day 的上传量，失败时仅少计额度，不影响当前操作。
+func (u *useCase) releaseDaily(ctx context.Context, userUUID string, day time.Time, fileSize int64) {
+	_ = u.usage.ReleaseDaily(ctx, userUUID, startOfDay(day), fileSize)
+}
+
+// errQuotaExceeded 存储空间不足（已有资源与未完成任务合计超出配额）。
+func errQuotaExceeded(quotaBytes int64) error {
+	return fmt.Errorf("存储空间不足: 配额 %d 字节", quotaBytes)
+}
+
+// GetStorageUsage 获取用户存储用量与生效策略。
+func (u *useCase) GetStorageUsage(ctx context.Context, userUUID string) (*output.StorageUsage, error) {
+	p, err := u.resolvePolicy(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	used, err := u.resources.SumFileSize(ctx, userUUID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRepo, err)
+	}
+	now := time.Now()
+	reserved, err := u.usage.SumPending(ctx, userUUID, now)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRepo, err)
+	}
+	usedToday, err := u.usage.GetDaily(ctx, userUUID, startOfDay(now))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRepo, err)
+	}
+
+	families := p.mimeFamilies
+	if families == nil {
+		families = []string{}
+	}
+
+	return &output.StorageUsage{
+		UserUUID:            userUUID,
+		RoleID:              p.roleID,
+		UsedBytes:           used,
+		ReservedBytes:       reserved,
+		QuotaBytes:          p.quotaBytes,
+		TodayUploadBytes:    usedToday,
+		DailyUploadBytes:    p.dailyUploadBytes,
+		MaxFileSize:         p.maxFileSize,
+		AllowedMimeFamilies: families,
+	}, nil
+}
+
+// ListUploadPolicies 列出全部上传策略。
+func (u *useCase) ListUploadPolicies(ctx context.Context) (*output.AllResult[output.UploadPolicyInfo], error) {
+	policies, err := u.policies.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRepo, err)
+	}
+
+	items := make([]output.UploadPolicyInfo, len(policies))
+	for i, p := range policies {
+		items[i] = output.UploadPolicyInfo{
+			ID:                  p.ID,
+			RoleID:              p.RoleID,
+			UserUUID:            p.UserUUID,
+			QuotaBytes:          p.QuotaBytes,
+			MaxFileSize:         p.MaxFileSize,
+			DailyUploadBytes:    p.DailyUploadBytes,
+			AllowedMimeFamilies: p.AllowedMimeFamilies,
+			UpdatedAt:           p.UpdatedAt,
+		}
+	}
+
+	return &output.AllResult[output.UploadPolicyInfo]{
+		Items: items,
+		Total: int64(len(items)),
+	}, nil
+}
+
+// SaveUploadPolicy 创建或覆盖角色/用户的上传策略。
+func (u *useCase) SaveUploadPolicy(ctx context.Context, params input.SaveUploadPolicy) (int64, error) {
+	if (params.RoleID == nil) == (params.UserUUID == nil) {
+		return 0, errors.New("role_id 与 user_uuid 必须且只能指定一个")
+	}
+	for _, v := range []*int64{params.QuotaBytes, params.MaxFileSize, params.DailyUploadBytes} {
+		if v != nil && *v < 0 {
+			return 0, errors.New("限制值不能为负数")
+		}
+	}
+
+	var families []string
+	if params.AllowedMimeFamilies != nil {
+		families = []string{}
+		for _, f := range params.AllowedMimeFamilies {
+			f = strings.ToLower(strings.TrimSpace(f))
+			if !slices.Contains(mimeFamilies, f) {
+				return 0, fmt.Errorf("不支持的 MIME 大类: %s", f)
+			}
+			if !slices.Contains(families, f) {
+				families = append(families, f)
+			}
+		}
+	}
+
+	id, err := u.policies.Upsert(ctx, &entity.UploadPolicy{
+		RoleID:              params.RoleID,
+		UserUUID:            params.UserUUID,
+		QuotaBytes:          params.QuotaBytes,
+		MaxFileSize:         params.MaxFileSize,
+		DailyUploadBytes:    params.DailyUploadBytes,
+		AllowedMimeFamilies: families,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrRepo, err)
+	}
+	return id, nil
+}
+
+// DeleteUploadPolicy 删除上传策略。
+func (u *useCase) DeleteUploadPolicy(ctx context.Context, id int64) error {
+	if err := u.policies.Delete(ctx, id); err != nil {
+		return fmt.Errorf("%w: %v", ErrRepo, err)
+	}
+	return nil
+}
+
+// mimeFamily 返回 MIME 大类，如 video/mp4 -> video。
+func mimeFamily(mimeType string) string {
+	family, _, _ := strings.Cut(mimeType, "/")
+	return family
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
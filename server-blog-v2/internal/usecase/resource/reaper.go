@@ -0,0 +1,110 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"server-blog-v2/internal/entity"
+	"server-blog-v2/internal/usecase/output"
+)
+
+const (
+	// RedisKeyReapStats Redis 中过期任务清理统计的 key
+	RedisKeyReapStats = "upload:reaper:stats"
+	// reapBatchSize 每批处理的过期任务数量
+	reapBatchSize = 100
+)
+
+// ReapExpiredTasks 清理过期任务：仍在上传中的标记为失败，未上传任何块的标记为取消，
+// 同时删除已上传但未合并的块，并累计统计写入 Redis。
+func (u *useCase) ReapExpiredTasks(ctx context.Context) (*output.UploadReapResult, error) {
+	result := &output.UploadReapResult{StartedAt: time.Now()}
+
+	for {
+		tasks, err := u.tasks.ListExpired(ctx, result.StartedAt, reapBatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrRepo, err)
+		}
+
+		for _, task := range tasks {
+			status := entity.TaskStatusFailed
+			if task.Status == entity.TaskStatusInit {
+				status = entity.TaskStatusCancelled
+			}
+
+			ok, err := u.tasks.Expire(ctx, task.TaskID, status, result.StartedAt)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrRepo, err)
+			}
+			if !ok {
+				// 已被并发的 Complete/Cancel 处理
+				continue
+			}
+
+			u.releaseDaily(ctx, task.UserUUID, task.CreatedAt, task.FileSize)
+
+			if status == entity.TaskStatusFailed {
+				result.Failed++
+			} else {
+				result.Cancelled++
+			}
+
+			blocks := uploadedContexts(task.QiniuContexts)
+			if err := u.objectStore.DeleteBlocks(ctx, blocks); err == nil {
+				result.DeletedBlocks += len(blocks)
+			}
+		}
+
+		if len(tasks) < reapBatchSize {
+			break
+		}
+	}
+
+	result.FinishedAt = time.Now()
+
+	stats, err := u.GetReapStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stats.LastRun = result
+	stats.TotalFailed += int64(result.Failed)
+	stats.TotalCancelled += int64(result.Cancelled)
+	stats.TotalBlocks += int64(result.DeletedBlocks)
+
+	data, _ := json.Marshal(stats)
+	_ = u.redis.Set(ctx, RedisKeyReapStats, string(data), 0)
+
+	return result, nil
+}
+
+// GetReapStats 获取过期任务清理统计。
+func (u *useCase) GetReapStats(ctx context.Context) (*output.UploadReapStats, error) {
+	stats := &output.UploadReapStats{}
+	val, err := u.redis.Get(ctx, RedisKeyReapStats)
+	if err != nil || val == "" {
+		// 从未运行（redis.Nil）
+		return stats, nil
+	}
+	if err := json.Unmarshal([]byte(val), stats); err != nil {
+		return nil, fmt.Errorf("解析清理统计失败: %w", err)
+	}
+	return stats, nil
+}
+
+// uploadedContexts 返回任务中已上传块的 Context。
+func uploadedContexts(contextsJSON string) []string {
+	var contexts []string
+	if err := json.Unmarshal([]byte(contextsJSON), &contexts); err != nil {
+		return nil
+	}
+
+	var uploaded []string
+	for _, c := range contexts {
+		if c != "" {
+			uploaded = append(uploaded, c)
+		}
+	}
+	return uploaded
+}
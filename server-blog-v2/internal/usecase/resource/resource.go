@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"math"
 	"strconv"
 	"strings"
@@ -19,6 +20,7 @@ import (
 	"server-blog-v2/internal/usecase"
 	"server-blog-v2/internal/usecase/input"
 	"server-blog-v2/internal/usecase/output"
+	"server-blog-v2/pkg/hls"
 	"server-blog-v2/pkg/redis"
 )
 
@@ -33,22 +35,53 @@ const (
 	RedisKeyMaxFileSize = "upload:max_size"
 )
 
-var ErrRepo = errors.New("repo")
+var (
+	ErrRepo     = errors.New("repo")
+	ErrStorage  = errors.New("storage")
+	ErrNotFound = errors.New("not found")
+	// ErrInvalidAccess 不支持的访问级别
+	ErrInvalidAccess = errors.New("不支持的访问级别")
+	// ErrTranscoding 视频转码中，产物尚未生成，暂不能移动
+	ErrTranscoding = errors.New("resource is transcoding")
+)
 
 type useCase struct {
 	resources   repo.ResourceRepo
 	tasks       repo.ResourceUploadTaskRepo
 	objectStore repo.ObjectStore
+	transcoder  repo.Transcoder // 为 nil 时视频不转码
+	users       repo.UserRepo
+	policies    repo.UploadPolicyRepo
+	usage       repo.UploadUsageRepo
 	redis       redis.Client
+	signedTTL   time.Duration
 }
 
 // New 创建 Resource UseCase。
-func New(resources repo.ResourceRepo, tasks repo.ResourceUploadTaskRepo, objectStore repo.ObjectStore, rdb redis.Client) usecase.Resource {
+func New(
+	resources repo.ResourceRepo,
+	tasks repo.ResourceUploadTaskRepo,
+	objectStore repo.ObjectStore,
+	transcoder repo.Transcoder,
+	users repo.UserRepo,
+	policies repo.UploadPolicyRepo,
+	usage repo.UploadUsageRepo,
+	rdb redis.Client,
+	signedURLTTL time.Duration,
+) usecase.Resource {
+	if signedURLTTL <= 0 {
+		signedURLTTL = entity.DefaultSignedURLTTL
+	}
 	return &useCase{
 		resources:   resources,
 		tasks:       tasks,
 		objectStore: objectStore,
+		transcoder:  transcoder,
+		users:       users,
+		policies:    policies,
+		usage:       usage,
 		redis:       rdb,
+		signedTTL:   signedURLTTL,
 	}
 }
 
@@ -74,18 +107,19 @@ func (u *useCase) List(ctx context.Context, userUUID *string, params input.ListR
 			ID:              r.ID,
 			FileKey:         r.FileKey,
 			FileName:        r.FileName,
-			FileURL:         u.objectStore.GetURL(r.FileKey),
+			FileURL:         u.resourceURL(r, r.FileKey),
 			FileSize:        r.FileSize,
 			MimeType:        r.MimeType,
 			TranscodeStatus: int8(r.TranscodeStatus),
+			Access:          r.Access,
 			CreatedAt:       r.CreatedAt,
 		}
 		if r.TranscodeKey != "" {
-			url := u.objectStore.GetURL(r.TranscodeKey)
+			url := u.resourceURL(r, r.TranscodeKey)
 			items[i].TranscodeURL = &url
 		}
 		if r.ThumbnailKey != "" {
-			url := u.objectStore.GetURL(r.ThumbnailKey)
+			url := u.resourceURL(r, r.ThumbnailKey)
 			items[i].ThumbnailURL = &url
 		}
 	}
@@ -108,12 +142,7 @@ func (u *useCase) DeleteByIDs(ctx context.Context, ids []int64) error {
 	// 删除对象存储中的文件
 	for _, r := range resources {
 		_ = u.objectStore.Delete(ctx, r.FileKey)
-		if r.TranscodeKey != "" {
-			_ = u.objectStore.Delete(ctx, r.TranscodeKey)
-		}
-		if r.ThumbnailKey != "" {
-			_ = u.objectStore.Delete(ctx, r.ThumbnailKey)
-		}
+		u.deleteTranscodeOutputs(ctx, r)
 	}
 
 	// 批量删除资源记录
@@ -124,6 +153,98 @@ func (u *useCase) DeleteByIDs(ctx context.Context, ids []int64) error {
 	return nil
 }
 
+// SetAccess 批量修改资源访问级别。对象与转码产物随之移动到公开或私有位置（private/ 前缀），
+// 旧对象删除后已发出的永久地址随之失效。
+func (u *useCase) SetAccess(ctx context.Context, ids []int64, access string) error {
+	access, err := normalizeAccess(access)
+	if err != nil {
+		return err
+	}
+
+	unique := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		unique[id] = true
+	}
+	resources, err := u.resources.GetByIDs(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrRepo, err)
+	}
+	if len(resources) != len(unique) {
+		return ErrNotFound
+	}
+	for _, r := range resources {
+		if r.TranscodeStatus == entity.TranscodeStatusProcessing {
+			return ErrTranscoding
+		}
+	}
+
+	for _, r := range resources {
+		if r.Access == access && r.FileKey == entity.ObjectKeyForAccess(r.FileKey, access) {
+			continue
+		}
+		if err := u.moveResource(ctx, r, access); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// moveResource 将资源对象与转码产物复制到新访问级别对应的位置并更新记录，
+// 旧对象没有其它资源引用时删除（秒传的资源共用同一对象）。
+func (u *useCase) moveResource(ctx context.Context, r *entity.Resource, access string) error {
+	moved := *r
+	moved.Access = access
+	moved.FileKey = entity.ObjectKeyForAccess(r.FileKey, access)
+	if moved.FileKey == r.FileKey {
+		return u.updateAccess(ctx, &moved)
+	}
+
+	if err := u.objectStore.Copy(ctx, r.FileKey, moved.FileKey); err != nil {
+		return fmt.Errorf("%w: %v", ErrStorage, err)
+	}
+	var err error
+	if u.transcoder != nil {
+		moved.TranscodeKey, moved.ThumbnailKey, err = u.transcoder.CopyOutputs(ctx, r, access)
+	} else {
+		moved.TranscodeKey, moved.ThumbnailKey, err = u.copyOutputs(ctx, r, access)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrStorage, err)
+	}
+
+	if err := u.updateAccess(ctx, &moved); err != nil {
+		return err
+	}
+
+	if n, err := u.resources.CountByFileKey(ctx, r.FileKey); err == nil && n == 0 {
+		_ = u.objectStore.Delete(ctx, r.FileKey)
+	}
+	u.deleteTranscodeOutputs(ctx, r)
+	return nil
+}
+
+func (u *useCase) updateAccess(ctx context.Context, r *entity.Resource) error {
+	if err := u.resources.UpdateAccess(ctx, r); err != nil {
+		return fmt.Errorf("%w: %v", ErrRepo, err)
+	}
+	return nil
+}
+
+// copyOutputs 未启用转码器时复制已有的转码产物与封面。
+func (u *useCase) copyOutputs(ctx context.Context, r *entity.Resource, access string) (string, string, error) {
+	var keys [2]string
+	for i, key := range []string{r.TranscodeKey, r.ThumbnailKey} {
+		if key == "" {
+			continue
+		}
+		keys[i] = entity.ObjectKeyForAccess(key, access)
+		if err := u.objectStore.Copy(ctx, key, keys[i]); err != nil {
+			return "", "", err
+		}
+	}
+	return keys[0], keys[1], nil
+}
+
 func (u *useCase) CheckFileHash(ctx context.Context, fileHash, userUUID string) (*entity.Resource, error) {
 	resource, err := u.resources.GetByFileHash(ctx, fileHash, userUUID)
 	if err != nil {
@@ -158,6 +279,11 @@ func (u *useCase) Check(ctx context.Context, userUUID string, params input.Resou
 		return nil, err
 	}
 
+	access, err := normalizeAccess(params.Access)
+	if err != nil {
+		return nil, err
+	}
+
 	// 验证文件大小
 	if params.FileSize > u.GetMaxFileSize(ctx) {
 		return nil, errors.New("文件大小超过限制")
@@ -168,28 +294,47 @@ func (u *useCase) Check(ctx context.Context, userUUID string, params input.Resou
 	if err == nil && resource != nil {
 		return &output.ResourceCheckResponse{
 			Exists:  true,
-			FileURL: u.objectStore.GetURL(resource.FileKey),
+			FileURL: u.resourceURL(resource, resource.FileKey),
 		}, nil
 	}
 
 	// 2. 检查其他用户是否已上传相同 hash 的资源（秒传）
 	existingResource, err := u.resources.GetByFileHashAny(ctx, params.FileHash)
 	if err == nil && existingResource != nil {
-		// 为当前用户创建新记录指向同一物理文件，使用新的 MimeType
+		// 秒传同样计入当前用户的配额与当天上传量
+		p, day, err := u.reserveUpload(ctx, userUUID, params.MimeType, params.FileSize)
+		if err != nil {
+			return nil, err
+		}
+
+		// 为当前用户创建新记录指向同一物理文件，使用新的 MimeType；访问级别不同时复制到对应位置
+		fileKey := entity.ObjectKeyForAccess(existingResource.FileKey, access)
+		if fileKey != existingResource.FileKey {
+			if err := u.objectStore.Copy(ctx, existingResource.FileKey, fileKey); err != nil {
+				u.releaseDaily(ctx, userUUID, day, params.FileSize)
+				return nil, fmt.Errorf("复制文件失败: %w", err)
+			}
+		}
 		newResource := &entity.Resource{
-			FileKey:  existingResource.FileKey,
+			FileKey:  fileKey,
 			FileName: params.FileName,
 			FileHash: params.FileHash,
 			FileSize: params.FileSize,
 			MimeType: params.MimeType,
 			UserUUID: userUUID,
+			Access:   access,
 		}
-		if _, err := u.resources.Create(ctx, newResource); err != nil {
-			return nil, fmt.Errorf("创建资源记录失败: %w", err)
+		_, ok, err := u.usage.CreateResource(ctx, newResource, p.quotaBytes)
+		if err != nil || !ok {
+			u.releaseDaily(ctx, userUUID, day, params.FileSize)
+			if err != nil {
+				return nil, fmt.Errorf("创建资源记录失败: %w", err)
+			}
+			return nil, errQuotaExceeded(p.quotaBytes)
 		}
 		return &output.ResourceCheckResponse{
 			Exists:  true,
-			FileURL: u.objectStore.GetURL(existingResource.FileKey),
+			FileURL: u.resourceURL(newResource, newResource.FileKey),
 		}, nil
 	}
 
@@ -222,9 +367,15 @@ func (u *useCase) Init(ctx context.Context, userUUID string, params input.Resour
 		return nil, err
 	}
 
-	// 验证文件大小
-	if params.FileSize > u.GetMaxFileSize(ctx) {
-		return nil, errors.New("文件大小超过限制")
+	access, err := normalizeAccess(params.Access)
+	if err != nil {
+		return nil, err
+	}
+
+	// 验证上传策略（类型、大小）并计入当天上传量，配额在创建任务时预留
+	p, day, err := u.reserveUpload(ctx, userUUID, params.MimeType, params.FileSize)
+	if err != nil {
+		return nil, err
 	}
 
 	// 计算总块数
@@ -240,6 +391,7 @@ func (u *useCase) Init(ctx context.Context, userUUID string, params input.Resour
 	// 生成任务 ID
 	taskUUID, err := uuid.NewV4()
 	if err != nil {
+		u.releaseDaily(ctx, userUUID, day, params.FileSize)
 		return nil, fmt.Errorf("生成任务ID失败: %w", err)
 	}
 
@@ -256,10 +408,17 @@ func (u *useCase) Init(ctx context.Context, userUUID string, params input.Resour
 		UserUUID:      userUUID,
 		ExpiresAt:     time.Now().Add(TaskExpireHours * time.Hour),
 		QiniuContexts: string(contextsJSON),
+		Access:        access,
 	}
 
-	if _, err := u.tasks.Create(ctx, task); err != nil {
-		return nil, fmt.Errorf("创建任务失败: %w", err)
+	// 未完成的任务计入已用空间，直到完成、取消或过期
+	created, err := u.usage.CreateTask(ctx, task, p.quotaBytes)
+	if err != nil || !created {
+		u.releaseDaily(ctx, userUUID, day, params.FileSize)
+		if err != nil {
+			return nil, fmt.Errorf("创建任务失败: %w", err)
+		}
+		return nil, errQuotaExceeded(p.quotaBytes)
 	}
 
 	return &output.ResourceInitResponse{
@@ -284,6 +443,9 @@ func (u *useCase) UploadChunk(ctx context.Context, userUUID string, params input
 	if task.Status == entity.TaskStatusCancelled {
 		return nil, errors.New("任务已取消")
 	}
+	if time.Now().After(task.ExpiresAt) {
+		return nil, errors.New("任务已过期")
+	}
 
 	// 检查块号有效性
 	if params.ChunkNumber < 0 || params.ChunkNumber >= task.TotalChunks {
@@ -307,7 +469,7 @@ func (u *useCase) UploadChunk(ctx context.Context, userUUID string, params input
 	// 验证第一个块的文件类型（基于真实内容）
 	if params.ChunkNumber == 0 {
 		// 创建可重读的 reader（先检测类型，再上传）
-		buffer, rewindableReader, err := filetype.CreateRewindableReader(params.ChunkData, 512)
+		buffer, rewindableReader, err := filetype.CreateRewindableReader(params.ChunkData, 1024)
 		if err != nil {
 			return nil, fmt.Errorf("读取文件失败: %w", err)
 		}
@@ -318,6 +480,11 @@ func (u *useCase) UploadChunk(ctx context.Context, userUUID string, params input
 			return nil, fmt.Errorf("检测文件类型失败: %w", err)
 		}
 
+		// 拒绝可执行脚本的类型与混入 HTML 的二进制文件
+		if err := filetype.CheckContent(buffer, detectedType); err != nil {
+			return nil, err
+		}
+
 		// 如果类型不匹配，使用检测到的真实类型
 		if !matched {
 			// 验证检测到的类型是否在白名单中
@@ -337,6 +504,23 @@ func (u *useCase) UploadChunk(ctx context.Context, userUUID string, params input
 
 		// 使用 rewindableReader 继续上传
 		params.ChunkData = rewindableReader
+
+		// SVG：分片上传的内容与哈希已由客户端确定，无法改写，只接受本身已是安全的文件
+		if detectedType == filetype.MimeTypeSVG {
+			if task.TotalChunks != 1 {
+				return nil, errors.New("SVG 文件过大，无法进行安全检查")
+			}
+			data, err := io.ReadAll(rewindableReader)
+			if err != nil {
+				return nil, fmt.Errorf("读取文件失败: %w", err)
+			}
+			if _, changed, err := filetype.SanitizeSVG(data); err != nil {
+				return nil, err
+			} else if changed {
+				return nil, errors.New("SVG 文件包含脚本、事件属性或外部引用，请清理后重新上传")
+			}
+			params.ChunkData = bytes.NewReader(data)
+		}
 	}
 
 	// 流式上传块到七牛云
@@ -370,12 +554,15 @@ func (u *useCase) Complete(ctx context.Context, userUUID string, params input.Re
 		resource, err := u.resources.GetByFileHash(ctx, task.FileHash, userUUID)
 		if err == nil && resource != nil {
 			return &output.ResourceCompleteResponse{
-				FileURL: u.objectStore.GetURL(resource.FileKey),
+				FileURL: u.resourceURL(resource, resource.FileKey),
 				FileKey: resource.FileKey,
 			}, nil
 		}
 		return nil, errors.New("任务已完成")
 	}
+	if time.Now().After(task.ExpiresAt) {
+		return nil, errors.New("任务已过期")
+	}
 
 	// 解析 contexts
 	var contexts []string
@@ -399,30 +586,42 @@ func (u *useCase) Complete(ctx context.Context, userUUID string, params input.Re
 		return nil, fmt.Errorf("还有 %d 个分片未上传: %v", len(missingChunks), missingChunks)
 	}
 
-	// 生成文件 Key
-	fileKey := u.objectStore.GenerateFileKey(task.FileName, task.FileHash)
+	// 首块检测后文件类型可能已变化，重新校验类型；配额与当天上传量已在创建任务时计入
+	p, err := u.resolvePolicy(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkFile(task.MimeType, task.FileSize); err != nil {
+		return nil, err
+	}
+
+	// 生成文件 Key，私有资源放在 private/ 前缀下
+	fileKey := entity.ObjectKeyForAccess(u.objectStore.GenerateFileKey(task.FileName, task.FileHash), task.Access)
 
 	// 合并文件
 	_, err = u.objectStore.MergeBlocks(ctx, task.FileSize, fileKey, validContexts)
 	if err != nil {
 		_ = u.tasks.UpdateStatus(ctx, task.TaskID, entity.TaskStatusFailed)
+		u.releaseDaily(ctx, userUUID, task.CreatedAt, task.FileSize)
 		return nil, fmt.Errorf("合并文件失败: %w", err)
 	}
 
 	// 验证文件 Hash（前端传的是 qetag）
+	// 校验失败同样作废任务并退还当天上传量，与合并失败一致
 	matched, err := u.objectStore.VerifyHash(ctx, task.FileHash, fileKey)
-	if err != nil {
-		_ = u.objectStore.Delete(ctx, fileKey)
-		return nil, fmt.Errorf("验证文件Hash失败: %w", err)
-	}
-	if !matched {
+	if err != nil || !matched {
 		_ = u.objectStore.Delete(ctx, fileKey)
+		_ = u.tasks.UpdateStatus(ctx, task.TaskID, entity.TaskStatusFailed)
+		u.releaseDaily(ctx, userUUID, task.CreatedAt, task.FileSize)
+		if err != nil {
+			return nil, fmt.Errorf("验证文件Hash失败: %w", err)
+		}
 		return nil, errors.New("文件Hash校验失败")
 	}
 
 	// 判断是否需要转码（视频文件）
 	transcodeStatus := entity.TranscodeStatusNone
-	if isVideoMimeType(task.MimeType) {
+	if isVideoMimeType(task.MimeType) && u.transcoder != nil {
 		transcodeStatus = entity.TranscodeStatusProcessing
 	}
 
@@ -435,18 +634,28 @@ func (u *useCase) Complete(ctx context.Context, userUUID string, params input.Re
 		MimeType:        task.MimeType,
 		UserUUID:        userUUID,
 		TranscodeStatus: transcodeStatus,
+		Access:          task.Access,
 	}
-	if _, err := u.resources.Create(ctx, resourceRecord); err != nil {
+	resourceID, err := u.resources.Create(ctx, resourceRecord)
+	if err != nil {
 		// 回滚：删除已合并的文件
 		_ = u.objectStore.Delete(ctx, fileKey)
 		return nil, fmt.Errorf("创建资源记录失败: %w", err)
 	}
 
+	// 提交转码，失败时标记为转码失败，不影响上传结果
+	if transcodeStatus == entity.TranscodeStatusProcessing {
+		resourceRecord.ID = resourceID
+		if err := u.transcoder.Submit(ctx, resourceRecord); err != nil {
+			_ = u.resources.UpdateTranscodeStatus(ctx, resourceID, entity.TranscodeStatusFailed, "", "")
+		}
+	}
+
 	// 更新任务状态
 	_ = u.tasks.UpdateStatus(ctx, task.TaskID, entity.TaskStatusCompleted)
 
 	return &output.ResourceCompleteResponse{
-		FileURL: u.objectStore.GetURL(fileKey),
+		FileURL: u.resourceURL(resourceRecord, fileKey),
 		FileKey: fileKey,
 	}, nil
 }
@@ -462,7 +671,16 @@ func (u *useCase) Cancel(ctx context.Context, userUUID string, params input.Reso
 		return errors.New("任务已完成，无法取消")
 	}
 
-	return u.tasks.UpdateStatus(ctx, params.TaskID, entity.TaskStatusCancelled)
+	if err := u.tasks.UpdateStatus(ctx, params.TaskID, entity.TaskStatusCancelled); err != nil {
+		return err
+	}
+	if task.Status == entity.TaskStatusInit || task.Status == entity.TaskStatusUploading {
+		u.releaseDaily(ctx, userUUID, task.CreatedAt, task.FileSize)
+	}
+
+	// 删除已上传但未合并的块
+	_ = u.objectStore.DeleteBlocks(ctx, uploadedContexts(task.QiniuContexts))
+	return nil
 }
 
 // Progress 查询上传进度。
@@ -508,6 +726,20 @@ func (u *useCase) HandleQiniuCallback(ctx context.Context, inputKey string, code
 	return u.resources.UpdateTranscodeStatusByFileKey(ctx, inputKey, entity.TranscodeStatusSuccess, transcodeKey, thumbnailKey)
 }
 
+// deleteTranscodeOutputs 删除转码产物（包括 HLS 分片）。
+func (u *useCase) deleteTranscodeOutputs(ctx context.Context, r *entity.Resource) {
+	if u.transcoder != nil {
+		_ = u.transcoder.DeleteOutputs(ctx, r)
+		return
+	}
+	if r.TranscodeKey != "" {
+		_ = u.objectStore.Delete(ctx, r.TranscodeKey)
+	}
+	if r.ThumbnailKey != "" {
+		_ = u.objectStore.Delete(ctx, r.ThumbnailKey)
+	}
+}
+
 // parseContexts 解析 QiniuContexts，返回已上传和缺失的块号。
 func parseContexts(contextsJSON string, totalChunks int) ([]int, []int) {
 	var contexts []string
@@ -599,3 +831,53 @@ func validateMimeType(mimeType string) error {
 
 	return nil
 }
+
+// PrivateURL 为私有对象签发新的短期访问地址，供文章中嵌入的稳定地址（/api/v1/media/{key}）跳转。
+func (u *useCase) PrivateURL(key string) (string, error) {
+	if !entity.IsPrivateKey(key) {
+		return "", ErrNotFound
+	}
+	return u.objectStore.SignedURL(key, u.signedTTL), nil
+}
+
+// PrivatePlaylist 读取私有 HLS 播放列表，分片地址替换为新签发的签名地址。
+func (u *useCase) PrivatePlaylist(ctx context.Context, key string) ([]byte, error) {
+	if !entity.IsPrivateKey(key) || !strings.HasSuffix(key, ".m3u8") {
+		return nil, ErrNotFound
+	}
+
+	body, _, err := u.objectStore.Get(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrStorage, err)
+	}
+	defer body.Close()
+	playlist, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrStorage, err)
+	}
+
+	return hls.Rewrite(playlist, key, func(segment string) string {
+		return u.objectStore.SignedURL(segment, u.signedTTL)
+	}), nil
+}
+
+// resourceURL 返回资源对象的访问地址。私有对象的 GetURL 是需登录的稳定地址；
+// 访问级别为私有但对象尚未移动到 private/ 前缀的旧资源返回签名地址。
+func (u *useCase) resourceURL(r *entity.Resource, key string) string {
+	if r.Access == entity.ResourceAccessPrivate && !entity.IsPrivateKey(key) {
+		return u.objectStore.SignedURL(key, u.signedTTL)
+	}
+	return u.objectStore.GetURL(key)
+}
+
+// normalizeAccess 校验访问级别，为空时默认为 public。
+func normalizeAccess(access string) (string, error) {
+	switch access {
+	case "":
+		return entity.ResourceAccessPublic, nil
+	case entity.ResourceAccessPublic, entity.ResourceAccessPrivate:
+		return access, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrInvalidAccess, access)
+	}
+}
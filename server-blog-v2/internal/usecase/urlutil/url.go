@@ -1,9 +1,11 @@
 package urlutil
 
 import (
+	"net/url"
 	"strings"
 
 	"server-blog-v2/config"
+	"server-blog-v2/pkg/urlsign"
 )
 
 // ResolveImageURL 将数据库中的相对路径转换为完整 URL。
@@ -16,6 +18,23 @@ func ResolveImageURL(cfg *config.Config, path string) string {
 	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
 		return path
 	}
+	// 按当前存储类型拼接访问地址
+	switch cfg.System.OssType {
+	case config.OssTypeLocal:
+		return strings.TrimRight(cfg.Local.BaseURL, "/") + "/" + path + "?sign=" + urlsign.Sign(cfg.Local.SignSecret, path, 0)
+	case config.OssTypeS3:
+		if cfg.S3.PublicURL != "" {
+			return strings.TrimRight(cfg.S3.PublicURL, "/") + "/" + path
+		}
+		endpoint := strings.TrimRight(cfg.S3.Endpoint, "/")
+		if !cfg.S3.PathStyle {
+			if u, err := url.Parse(endpoint); err == nil {
+				u.Host = cfg.S3.Bucket + "." + u.Host
+				return u.String() + "/" + path
+			}
+		}
+		return endpoint + "/" + cfg.S3.Bucket + "/" + path
+	}
 	// 拼接七牛域名
 	domain := cfg.Qiniu.Domain
 	if domain == "" {
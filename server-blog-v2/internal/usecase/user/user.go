@@ -44,6 +44,7 @@ func (u *useCase) GetProfile(ctx context.Context, userUUID string) (*output.User
 		Nickname:  user.Nickname,
 		Avatar:    urlutil.ResolveImageURL(u.cfg, user.Avatar),
 		Signature: "签名是空白的，这位用户似乎比较低调。",
+		Bio:       user.Bio,
 		RoleID:    user.RoleID,
 	}
 	if user.Email != nil {
@@ -69,6 +70,9 @@ func (u *useCase) UpdateProfile(ctx context.Context, userUUID string, params inp
 	if params.Avatar != "" {
 		user.Avatar = params.Avatar
 	}
+	if params.Bio != nil {
+		user.Bio = *params.Bio
+	}
 
 	if err := u.users.Update(ctx, user); err != nil {
 		return fmt.Errorf("%w: %v", ErrRepo, err)
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_article_categories_parent_id;
+
+ALTER TABLE article_categories DROP COLUMN IF EXISTS sort_order;
+ALTER TABLE article_categories DROP COLUMN IF EXISTS parent_id;
@@ -0,0 +1,6 @@
+-- ==================== 分类层级 ====================
+-- parent_id 为空表示顶级分类；sort_order 控制同级排序（升序）
+ALTER TABLE article_categories ADD COLUMN IF NOT EXISTS parent_id BIGINT REFERENCES article_categories(id);
+ALTER TABLE article_categories ADD COLUMN IF NOT EXISTS sort_order INT DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_article_categories_parent_id ON article_categories(parent_id);
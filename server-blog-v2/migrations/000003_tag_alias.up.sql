@@ -0,0 +1,11 @@
+-- ==================== 标签别名 ====================
+-- 标签重命名/合并后保留旧 slug，访问旧 slug 时跳转到新标签
+CREATE TABLE IF NOT EXISTS article_tag_aliases (
+    id BIGSERIAL PRIMARY KEY,
+    slug VARCHAR(50) NOT NULL UNIQUE,
+    tag_id BIGINT NOT NULL REFERENCES article_tags(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_article_tag_aliases_tag_id ON article_tag_aliases(tag_id);
+
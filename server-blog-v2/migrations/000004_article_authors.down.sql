@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_articles_co_author_uuids;
+
+ALTER TABLE articles DROP COLUMN IF EXISTS co_author_uuids;
+ALTER TABLE users DROP COLUMN IF EXISTS bio;
@@ -0,0 +1,6 @@
+-- ==================== 作者主页与多作者 ====================
+-- bio 为作者简介；co_author_uuids 记录文章的共同作者（不含主作者）
+ALTER TABLE users ADD COLUMN IF NOT EXISTS bio VARCHAR(500);
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS co_author_uuids UUID[] DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_articles_co_author_uuids ON articles USING GIN (co_author_uuids);
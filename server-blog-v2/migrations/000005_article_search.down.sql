@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_articles_search_vector;
+
+ALTER TABLE articles DROP COLUMN IF EXISTS search_vector;
+
+DROP FUNCTION IF EXISTS blog_cjk_split(TEXT);
+DROP FUNCTION IF EXISTS blog_bigram(TEXT);
@@ -0,0 +1,39 @@
+-- ==================== 文章全文检索（search.engine = postgres） ====================
+-- 不依赖 zhparser 等扩展：中文按相邻两字切分（bigram），英文/数字按单词保留，统一使用 simple 配置
+
+-- blog_bigram 将文本转为空格分隔的检索词，用于 tsvector 与查询
+CREATE OR REPLACE FUNCTION blog_bigram(input TEXT) RETURNS TEXT AS $$
+DECLARE
+    result TEXT := '';
+    token TEXT;
+    i INT;
+BEGIN
+    IF input IS NULL THEN
+        RETURN '';
+    END IF;
+    FOR token IN SELECT m[1] FROM regexp_matches(lower(input), '([一-鿿]+|[a-z0-9]+)', 'g') AS m LOOP
+        IF token ~ '^[a-z0-9]+$' OR char_length(token) = 1 THEN
+            result := result || ' ' || token;
+        ELSE
+            FOR i IN 1 .. char_length(token) - 1 LOOP
+                result := result || ' ' || substr(token, i, 2);
+            END LOOP;
+        END IF;
+    END LOOP;
+    RETURN result;
+END;
+$$ LANGUAGE plpgsql IMMUTABLE PARALLEL SAFE;
+
+-- blog_cjk_split 在每个中文字符两侧插入空格，仅用于 ts_headline 生成摘要片段
+CREATE OR REPLACE FUNCTION blog_cjk_split(input TEXT) RETURNS TEXT AS $$
+    SELECT regexp_replace(COALESCE(input, ''), '([一-鿿])', ' \1 ', 'g');
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE;
+
+-- 标题权重 A，摘要 B，正文 C
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', blog_bigram(title)), 'A') ||
+    setweight(to_tsvector('simple', blog_bigram(excerpt)), 'B') ||
+    setweight(to_tsvector('simple', blog_bigram(content)), 'C')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_articles_search_vector ON articles USING GIN (search_vector);
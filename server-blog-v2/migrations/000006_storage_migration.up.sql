@@ -0,0 +1,16 @@
+-- ==================== 存储迁移断点 ====================
+-- cmd/storage-migrate 记录每个对象的迁移结果，中断后重新执行会跳过已完成的对象
+CREATE TABLE IF NOT EXISTS storage_migration_checkpoints (
+    id BIGSERIAL PRIMARY KEY,
+    source VARCHAR(20) NOT NULL,
+    target VARCHAR(20) NOT NULL,
+    object_key VARCHAR(500) NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    error TEXT DEFAULT '',
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (source, target, object_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_storage_migration_checkpoints_status ON storage_migration_checkpoints(source, target, status);
@@ -0,0 +1,3 @@
+ALTER TABLE files DROP COLUMN IF EXISTS variants;
+ALTER TABLE files DROP COLUMN IF EXISTS height;
+ALTER TABLE files DROP COLUMN IF EXISTS width;
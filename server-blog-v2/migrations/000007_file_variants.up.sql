@@ -0,0 +1,5 @@
+-- ==================== 图片处理版本 ====================
+-- 上传图片时生成的缩放版本与 WebP 版本：[{key, width, height, size, mime_type}]
+ALTER TABLE files ADD COLUMN IF NOT EXISTS width INT;
+ALTER TABLE files ADD COLUMN IF NOT EXISTS height INT;
+ALTER TABLE files ADD COLUMN IF NOT EXISTS variants JSONB DEFAULT '[]';
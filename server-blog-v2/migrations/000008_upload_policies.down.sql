@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_resources_user_uuid_created_at;
+DROP TABLE IF EXISTS upload_policies;
@@ -0,0 +1,17 @@
+-- ==================== 上传策略与存储配额 ====================
+-- 每行对应一个角色（role_id）或一个用户（user_uuid），用户策略中未设置（NULL）的字段继承角色策略，
+-- 两者都未设置时不限制（单文件大小仍受全局上限约束）
+CREATE TABLE IF NOT EXISTS upload_policies (
+    id BIGSERIAL PRIMARY KEY,
+    role_id INTEGER UNIQUE,
+    user_uuid UUID UNIQUE,
+    quota_bytes BIGINT,                          -- 存储配额（资源总大小）
+    max_file_size BIGINT,                        -- 单文件大小上限
+    daily_upload_bytes BIGINT,                   -- 每日上传量上限
+    allowed_mime_families VARCHAR(255),          -- 允许的 MIME 大类，逗号分隔，如 image,video
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((role_id IS NULL) <> (user_uuid IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_resources_user_uuid_created_at ON resources(user_uuid, created_at);
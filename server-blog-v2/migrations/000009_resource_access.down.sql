@@ -0,0 +1,2 @@
+ALTER TABLE resource_upload_tasks DROP COLUMN IF EXISTS access;
+ALTER TABLE resources DROP COLUMN IF EXISTS access;
@@ -0,0 +1,5 @@
+-- ==================== 资源访问级别 ====================
+-- public：返回永久地址；private：返回带有效期的签名地址
+-- 上传任务记录初始化时选择的访问级别，完成时写入资源
+ALTER TABLE resources ADD COLUMN IF NOT EXISTS access VARCHAR(10) NOT NULL DEFAULT 'public';
+ALTER TABLE resource_upload_tasks ADD COLUMN IF NOT EXISTS access VARCHAR(10) NOT NULL DEFAULT 'public';
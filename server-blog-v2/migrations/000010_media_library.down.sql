@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_resources_file_name_search;
+DROP INDEX IF EXISTS idx_files_filename_search;
+DROP TABLE IF EXISTS media_items;
+DROP TABLE IF EXISTS media_folders;
@@ -0,0 +1,33 @@
+-- ==================== 媒体库 ====================
+-- 虚拟文件夹，只影响媒体库中的展示，不改变对象 Key
+CREATE TABLE IF NOT EXISTS media_folders (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    parent_id BIGINT REFERENCES media_folders(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 同一父文件夹下名称唯一（根目录 parent_id 为 NULL）
+CREATE UNIQUE INDEX IF NOT EXISTS idx_media_folders_parent_name ON media_folders(COALESCE(parent_id, 0), name);
+
+-- files 与 resources 的媒体库元数据：source 为 file 或 resource，source_id 为对应表的 ID
+CREATE TABLE IF NOT EXISTS media_items (
+    id BIGSERIAL PRIMARY KEY,
+    source VARCHAR(10) NOT NULL,
+    source_id BIGINT NOT NULL,
+    folder_id BIGINT REFERENCES media_folders(id) ON DELETE SET NULL,
+    alt_text VARCHAR(255) NOT NULL DEFAULT '',
+    caption TEXT NOT NULL DEFAULT '',
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (source, source_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_media_items_folder_id ON media_items(folder_id);
+CREATE INDEX IF NOT EXISTS idx_media_items_tags ON media_items USING GIN (tags);
+
+-- 文件名全文检索，复用文章检索的 blog_bigram 切分
+CREATE INDEX IF NOT EXISTS idx_files_filename_search ON files USING GIN (to_tsvector('simple', blog_bigram(filename)));
+CREATE INDEX IF NOT EXISTS idx_resources_file_name_search ON resources USING GIN (to_tsvector('simple', blog_bigram(file_name)));
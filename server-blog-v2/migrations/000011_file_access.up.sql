@@ -0,0 +1,3 @@
+-- ==================== 文件访问级别 ====================
+-- 与 resources.access 一致：private 文件的对象 Key 带 private/ 前缀，经鉴权接口访问
+ALTER TABLE files ADD COLUMN IF NOT EXISTS access VARCHAR(10) NOT NULL DEFAULT 'public';
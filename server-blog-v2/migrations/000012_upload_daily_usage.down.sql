@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_resource_upload_tasks_user_uuid_status;
+DROP TABLE IF EXISTS upload_daily_usage;
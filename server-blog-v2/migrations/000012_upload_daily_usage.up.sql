@@ -0,0 +1,12 @@
+-- ==================== 每日上传量 ====================
+-- 按用户、按天累计上传量，创建上传任务或秒传时计入，任务取消、失败或过期时退回；
+-- 与资源表分开记录，删除资源不会释放当天的上传额度
+CREATE TABLE IF NOT EXISTS upload_daily_usage (
+    user_uuid UUID NOT NULL,
+    day DATE NOT NULL,
+    bytes BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_uuid, day)
+);
+
+CREATE INDEX IF NOT EXISTS idx_resource_upload_tasks_user_uuid_status ON resource_upload_tasks(user_uuid, status);
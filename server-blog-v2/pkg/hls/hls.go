@@ -0,0 +1,53 @@
+// Package hls HLS 播放列表解析与改写。分片与播放列表位于同一前缀下。
+package hls
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// SegmentKeys 读取播放列表，返回分片的对象 Key。
+func SegmentKeys(playlist io.Reader, playlistKey string) []string {
+	prefix := path.Dir(playlistKey) + "/"
+	var keys []string
+	scanner := bufio.NewScanner(playlist)
+	for scanner.Scan() {
+		if name := segmentName(scanner.Text()); name != "" {
+			keys = append(keys, prefix+name)
+		}
+	}
+	return keys
+}
+
+// Rewrite 将播放列表中的分片地址替换为 segmentURL 返回的地址。
+func Rewrite(playlist []byte, playlistKey string, segmentURL func(key string) string) []byte {
+	prefix := path.Dir(playlistKey) + "/"
+	var buf bytes.Buffer
+	scanner := bufio.NewScanner(bytes.NewReader(playlist))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if name := segmentName(line); name != "" {
+			line = segmentURL(prefix + name)
+		}
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+// segmentName 返回播放列表行中的分片文件名，注释与空行返回空。
+func segmentName(line string) string {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ""
+	}
+	// 分片地址可能是完整 URL（带签名），只取文件名
+	if u, err := url.Parse(line); err == nil {
+		line = u.Path
+	}
+	return path.Base(line)
+}
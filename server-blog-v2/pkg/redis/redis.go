@@ -7,6 +7,7 @@ import (
 	"log"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -109,6 +110,8 @@ const (
 	RefreshTokenField = "refresh_token"
 	// RefreshTokenExpiresAtField refresh_token 过期时间字段名。
 	RefreshTokenExpiresAtField = "refresh_token_expires_at"
+	// AccessTokenField 最近一次刷新得到的 access_token 字段名。
+	AccessTokenField = "access_token"
 	// DefaultSessionExpiration 默认 Session 过期时间（7天）。
 	DefaultSessionExpiration = 7 * 24 * time.Hour
 )
@@ -117,6 +120,8 @@ const (
 type SessionStore interface {
 	GetRefreshToken(ctx context.Context, sessionID string) (string, error)
 	SetRefreshToken(ctx context.Context, sessionID string, refreshToken string, expiration time.Duration) error
+	GetAccessToken(ctx context.Context, sessionID string) (string, error)
+	SetAccessToken(ctx context.Context, sessionID string, accessToken string) error
 	DeleteSession(ctx context.Context, sessionID string) error
 }
 
@@ -137,8 +142,54 @@ func (r *Redis) SetRefreshToken(ctx context.Context, sessionID string, refreshTo
 	return err
 }
 
+// GetAccessToken 从 Session 获取缓存的 access_token。
+func (r *Redis) GetAccessToken(ctx context.Context, sessionID string) (string, error) {
+	key := SessionKeyPrefix + sessionID
+	return r.RDB.HGet(ctx, key, AccessTokenField).Result()
+}
+
+// SetAccessToken 缓存 access_token 到 Session，随 Session 一起过期和删除。
+func (r *Redis) SetAccessToken(ctx context.Context, sessionID string, accessToken string) error {
+	key := SessionKeyPrefix + sessionID
+	return r.RDB.HSet(ctx, key, AccessTokenField, accessToken).Err()
+}
+
 // DeleteSession 删除 Session。
 func (r *Redis) DeleteSession(ctx context.Context, sessionID string) error {
 	key := SessionKeyPrefix + sessionID
 	return r.RDB.Del(ctx, key).Err()
 }
+
+// ==================== 分布式锁 ====================
+
+// LockKeyPrefix 分布式锁 Key 前缀。
+const LockKeyPrefix = "blog:lock:"
+
+// Locker 分布式锁接口。
+type Locker interface {
+	TryLock(ctx context.Context, name string, ttl time.Duration) (string, bool, error)
+	Unlock(ctx context.Context, name, token string) error
+}
+
+// unlockScript 仅当锁仍由自己持有时才删除，避免误删其他实例在锁过期后获得的锁。
+var unlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// TryLock 尝试获取锁，成功时返回用于释放锁的 token。
+func (r *Redis) TryLock(ctx context.Context, name string, ttl time.Duration) (string, bool, error) {
+	token := uuid.New().String()
+	ok, err := r.RDB.SetNX(ctx, LockKeyPrefix+name, token, ttl).Result()
+	if err != nil || !ok {
+		return "", false, err
+	}
+	return token, true, nil
+}
+
+// Unlock 释放锁。
+func (r *Redis) Unlock(ctx context.Context, name, token string) error {
+	return unlockScript.Run(ctx, r.RDB, []string{LockKeyPrefix + name}, token).Err()
+}
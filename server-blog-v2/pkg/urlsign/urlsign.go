@@ -0,0 +1,29 @@
+package urlsign
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+)
+
+// Sign 计算 key 的签名，expires 为过期时间戳（秒），0 表示永不过期。
+func Sign(secret, key string, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(key))
+	mac.Write([]byte{'\n'})
+	mac.Write([]byte(strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify 校验签名，同时检查是否过期。密钥为空时一律拒绝，避免签名可被伪造。
+func Verify(secret, key string, expires int64, sign string) bool {
+	if secret == "" {
+		return false
+	}
+	if expires > 0 && time.Now().Unix() > expires {
+		return false
+	}
+	return hmac.Equal([]byte(Sign(secret, key, expires)), []byte(sign))
+}
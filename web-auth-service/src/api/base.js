@@ -5,13 +5,13 @@ export const getCaptcha = () => {
   return request.get('/base/captcha')
 }
 
-// 获取QQ登录URL
-export const getQQLoginURL = (appId, state) => {
-  const params = { app_id: appId }
-  if (state) {
-    params.state = state
+// 获取第三方登录URL（provider: qq / github / wechat；oidcRequest 为 OIDC 授权请求 ID，可选）
+export const getOAuthLoginURL = (provider, appId, state, oidcRequest) => {
+  const params = { app_id: appId, state }
+  if (oidcRequest) {
+    params.oidc_request = oidcRequest
   }
-  return request.get('/base/qqLoginURL', { params })
+  return request.get(`/auth/oauth/${provider}/login`, { params })
 }
 
 // 发送邮箱验证码
@@ -4,3 +4,13 @@ import request from '../utils/request'
 export const getPublicApplications = () => {
   return request.get('/oauth/applications')
 }
+
+// 获取 OIDC 授权同意页信息
+export const getConsent = (requestId) => {
+  return request.get('/oauth/consent', { params: { request_id: requestId } })
+}
+
+// 同意或拒绝 OIDC 授权
+export const submitConsent = (data) => {
+  return request.post('/oauth/consent', data)
+}
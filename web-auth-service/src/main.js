@@ -9,7 +9,7 @@ import Home from './views/Home.vue'
 import Login from './views/Login.vue'
 import Register from './views/Register.vue'
 import ForgotPassword from './views/ForgotPassword.vue'
-import QQCallback from './views/QQCallback.vue'
+import Consent from './views/Consent.vue'
 import ManageLayout from './views/manage/Layout.vue'
 import Devices from './views/manage/Devices.vue'
 import Security from './views/manage/Security.vue'
@@ -20,7 +20,7 @@ const routes = [
   { path: '/login', component: Login },
   { path: '/register', component: Register },
   { path: '/forgot-password', component: ForgotPassword },
-  { path: '/oauth/qq/callback', component: QQCallback },
+  { path: '/consent', component: Consent },
   
   // 管理后台路由
   {
@@ -49,7 +49,8 @@ router.beforeEach((to, from, next) => {
     const validAppIds = ['blog', 'mcp', 'manage']
     
     // 没有 app_id 或 app_id 不在有效列表中，重定向到主页
-    if (!appId || !validAppIds.includes(appId)) {
+    // OIDC 授权请求由服务端校验过 client_id，不受此列表限制
+    if (!appId || (!validAppIds.includes(appId) && !to.query.oidc_request)) {
       next('/')
       return
     }